@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	shipmentModels "go-starter/internal/modules/shipments/models"
+	"go-starter/internal/modules/shipments/repositories"
+	"go-starter/internal/modules/shipments/services"
+	"go-starter/pkg/config"
+	"go-starter/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// import-locodes imports the CSV files of a UN/LOCODE release, e.g.
+//
+//	go run ./cmd/import-locodes "2024-2 UNLOCODE CodeListPart1.csv" "2024-2 UNLOCODE CodeListPart2.csv" "2024-2 UNLOCODE CodeListPart3.csv"
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s <CodeListPart1.csv> [CodeListPart2.csv ...]\n", os.Args[0])
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := config.New()
+
+	database, err := db.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer func() {
+		if err := database.Close(); err != nil {
+			log.Printf("Error closing database: %v", err)
+		}
+	}()
+
+	if err := shipmentModels.DropUniqueNameIndex(database.DB); err != nil {
+		log.Fatalf("Failed to drop the unique location name index: %v", err)
+	}
+	if err := database.AutoMigrate(&shipmentModels.Location{}, &shipmentModels.UnLocode{}); err != nil {
+		log.Fatalf("Failed to run database migrations: %v", err)
+	}
+
+	// The release has over 100,000 entries, don't log every batch insert
+	database.DB = database.DB.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Warn)})
+
+	importService := services.NewUnLocodeImportService(repositories.NewUnLocodeRepository(database))
+	result, err := importService.ImportFiles(context.Background(), flag.Args()...)
+	if err != nil {
+		log.Fatalf("Failed to import UN/LOCODE release: %v", err)
+	}
+
+	log.Printf("Imported %d UN/LOCODE entries, enriched %d locations, %d with provider mismatches", result.Entries, result.Matched, result.Flagged)
+}
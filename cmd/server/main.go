@@ -1,9 +1,17 @@
 package main
 
 import (
+	"go-starter/internal/jobs"
+	alertModels "go-starter/internal/modules/alerts/models"
 	"go-starter/internal/modules/auth/models"
+	demurrageModels "go-starter/internal/modules/demurrage/models"
+	digestModels "go-starter/internal/modules/digests/models"
+	emissionModels "go-starter/internal/modules/emissions/models"
 	filterModels "go-starter/internal/modules/filters/models"
+	geofenceModels "go-starter/internal/modules/geofences/models"
+	notificationModels "go-starter/internal/modules/notifications/models"
 	shipmentModels "go-starter/internal/modules/shipments/models"
+	webhookModels "go-starter/internal/modules/webhooks/models"
 	"go-starter/internal/server"
 	"go-starter/pkg/config"
 	"go-starter/pkg/db"
@@ -23,6 +31,11 @@ func main() {
 		}
 	}()
 
+	// Locations used to be unique by name, they are identified by their LOCODE now
+	if err := shipmentModels.DropUniqueNameIndex(database.DB); err != nil {
+		log.Fatalf("Failed to drop the unique location name index: %v", err)
+	}
+
 	// Run GORM auto-migration with cleanup
 	if err := database.AutoMigrate(
 		&models.User{},
@@ -30,6 +43,8 @@ func main() {
 		&shipmentModels.Shipment{},
 		&shipmentModels.UserShipment{},
 		&shipmentModels.Location{},
+		&shipmentModels.UnLocode{},
+		&shipmentModels.Carrier{},
 		&shipmentModels.ShipmentLocation{},
 		&shipmentModels.ShipmentRoute{},
 		&shipmentModels.Vessel{},
@@ -39,15 +54,40 @@ func main() {
 		&shipmentModels.Container{},
 		&shipmentModels.ShipmentContainer{},
 		&shipmentModels.ContainerEvent{},
+		&shipmentModels.ContainerMilestone{},
+		&shipmentModels.ShipmentMilestone{},
+		&shipmentModels.VoyageLeg{},
+		&shipmentModels.VesselPosition{},
 		&shipmentModels.RouteSegment{},
 		&shipmentModels.RouteSegmentPoint{},
 		&shipmentModels.Coordinate{},
 		&shipmentModels.Ais{},
+		&shipmentModels.PendingSync{},
+		&alertModels.AlertRule{},
+		&alertModels.Alert{},
+		&alertModels.AlertMute{},
+		&alertModels.AlertETABaseline{},
+		&notificationModels.NotificationPreference{},
+		&notificationModels.NotificationDelivery{},
+		&notificationModels.NotificationChannel{},
+		&notificationModels.Notification{},
+		&webhookModels.WebhookSubscription{},
+		&webhookModels.WebhookDelivery{},
+		&digestModels.DigestSchedule{},
+		&demurrageModels.FreeTimeAgreement{},
+		&geofenceModels.Geofence{},
+		&geofenceModels.GeofenceEvent{},
+		&emissionModels.EmissionFactor{},
+		&jobs.ShipmentRefreshSettings{},
 	); err != nil {
 		log.Fatalf("Failed to run database migrations: %v", err)
 	}
 	log.Println("Database migrations completed successfully")
 
+	if err := shipmentModels.SeedCarriers(database.DB); err != nil {
+		log.Printf("Failed to seed the carrier registry: %v", err)
+	}
+
 	srv := server.New(cfg, database)
 	srv.Start()
 }
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// DigestSender sends the scheduled shipment digests that are due
+type DigestSender interface {
+	SendDueDigests(ctx context.Context, now time.Time) (int, error)
+}
+
+// DigestDeliveryJob checks the digest schedules every interval and sends the due digests
+type DigestDeliveryJob struct {
+	sender   DigestSender
+	interval time.Duration
+}
+
+// NewDigestDeliveryJob creates a new digest delivery job
+func NewDigestDeliveryJob(sender DigestSender, interval time.Duration) *DigestDeliveryJob {
+	return &DigestDeliveryJob{
+		sender:   sender,
+		interval: interval,
+	}
+}
+
+// Start runs the job until ctx is done
+func (j *DigestDeliveryJob) Start(ctx context.Context) {
+	log.Printf("Starting digest delivery job with %v interval", j.interval)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.RunOnce(ctx, time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Digest delivery job stopped")
+			return
+		case now := <-ticker.C:
+			j.RunOnce(ctx, now)
+		}
+	}
+}
+
+// RunOnce sends the digests due at now
+func (j *DigestDeliveryJob) RunOnce(ctx context.Context, now time.Time) {
+	sent, err := j.sender.SendDueDigests(ctx, now)
+	if err != nil {
+		log.Printf("Failed to send digests: %v", err)
+	}
+	if sent > 0 {
+		log.Printf("Sent %d shipment digests", sent)
+	}
+}
@@ -0,0 +1,70 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeDigestSender struct {
+	mu    sync.Mutex
+	calls []time.Time
+	errs  []error
+}
+
+// SendDueDigests fails with the queued errors one call at a time, then succeeds
+func (f *fakeDigestSender) SendDueDigests(ctx context.Context, now time.Time) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, now)
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return 0, err
+	}
+	return 1, nil
+}
+
+func (f *fakeDigestSender) callTimes() []time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]time.Time(nil), f.calls...)
+}
+
+func TestDigestDeliveryJob_RunOncePassesTime(t *testing.T) {
+	sender := &fakeDigestSender{}
+	job := NewDigestDeliveryJob(sender, time.Minute)
+	now := time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)
+
+	job.RunOnce(context.Background(), now)
+
+	if calls := sender.callTimes(); len(calls) != 1 || !calls[0].Equal(now) {
+		t.Errorf("Expected one call at %v, got %v", now, calls)
+	}
+}
+
+func TestDigestDeliveryJob_RetriesAfterFailedRun(t *testing.T) {
+	// Failed digests are released by the service, the job has to come back for them
+	sender := &fakeDigestSender{errs: []error{errors.New("database unavailable")}}
+	job := NewDigestDeliveryJob(sender, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go job.Start(ctx)
+
+	deadline := time.Now().Add(time.Second)
+	for len(sender.callTimes()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected another run after the failed one, got %d runs", len(sender.callTimes()))
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	calls := sender.callTimes()
+	if !calls[1].After(calls[0]) {
+		t.Errorf("Expected the retry to check schedules at a later time, got %v then %v", calls[0], calls[1])
+	}
+}
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// FreeTimeChecker raises alerts for containers whose demurrage or detention free time runs out
+type FreeTimeChecker interface {
+	CheckFreeTime(ctx context.Context, now time.Time) (int, error)
+}
+
+// FreeTimeAlertJob checks the free time of all containers every interval
+type FreeTimeAlertJob struct {
+	checker  FreeTimeChecker
+	interval time.Duration
+}
+
+// NewFreeTimeAlertJob creates a new free time alert job
+func NewFreeTimeAlertJob(checker FreeTimeChecker, interval time.Duration) *FreeTimeAlertJob {
+	return &FreeTimeAlertJob{
+		checker:  checker,
+		interval: interval,
+	}
+}
+
+// Start runs the job until ctx is done
+func (j *FreeTimeAlertJob) Start(ctx context.Context) {
+	log.Printf("Starting free time alert job with %v interval", j.interval)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.RunOnce(ctx, time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Free time alert job stopped")
+			return
+		case now := <-ticker.C:
+			j.RunOnce(ctx, now)
+		}
+	}
+}
+
+// RunOnce checks the free time at now
+func (j *FreeTimeAlertJob) RunOnce(ctx context.Context, now time.Time) {
+	raised, err := j.checker.CheckFreeTime(ctx, now)
+	if err != nil {
+		log.Printf("Failed to check free time: %v", err)
+	}
+	if raised > 0 {
+		log.Printf("Raised %d free time alerts", raised)
+	}
+}
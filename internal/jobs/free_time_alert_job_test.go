@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeFreeTimeChecker struct {
+	checked chan struct{}
+}
+
+func (f *fakeFreeTimeChecker) CheckFreeTime(ctx context.Context, now time.Time) (int, error) {
+	f.checked <- struct{}{}
+	return 0, nil
+}
+
+func TestFreeTimeAlertJob_ChecksOnStart(t *testing.T) {
+	// Free time can run out while the server is down, the check must not wait for the first tick
+	checker := &fakeFreeTimeChecker{checked: make(chan struct{}, 1)}
+	job := NewFreeTimeAlertJob(checker, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go job.Start(ctx)
+
+	select {
+	case <-checker.checked:
+	case <-time.After(time.Second):
+		t.Fatal("Expected free time to be checked when the job starts")
+	}
+}
@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// inboxCleanupBatchSize limits how many notifications are deleted per statement
+const inboxCleanupBatchSize = 1000
+
+// InboxCleaner deletes old in-app notifications
+type InboxCleaner interface {
+	DeleteExpired(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+// InboxCleanupJob deletes in-app notifications once they are older than the retention
+type InboxCleanupJob struct {
+	cleaner   InboxCleaner
+	interval  time.Duration
+	retention time.Duration
+}
+
+// NewInboxCleanupJob creates a new inbox cleanup job
+func NewInboxCleanupJob(cleaner InboxCleaner, interval, retention time.Duration) *InboxCleanupJob {
+	return &InboxCleanupJob{
+		cleaner:   cleaner,
+		interval:  interval,
+		retention: retention,
+	}
+}
+
+// Start runs the job until ctx is done
+func (j *InboxCleanupJob) Start(ctx context.Context) {
+	log.Printf("Starting inbox cleanup job with %v interval and %v retention", j.interval, j.retention)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.RunOnce(ctx, time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Inbox cleanup job stopped")
+			return
+		case now := <-ticker.C:
+			j.RunOnce(ctx, now)
+		}
+	}
+}
+
+// RunOnce deletes every notification that is older than the retention at now
+func (j *InboxCleanupJob) RunOnce(ctx context.Context, now time.Time) {
+	before := now.Add(-j.retention)
+	total := 0
+
+	// Delete in batches so a large backlog doesn't hold locks for long
+	for ctx.Err() == nil {
+		deleted, err := j.cleaner.DeleteExpired(ctx, before, inboxCleanupBatchSize)
+		if err != nil {
+			log.Printf("Failed to delete expired notifications: %v", err)
+			break
+		}
+		total += deleted
+		if deleted < inboxCleanupBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("Deleted %d notifications older than %v", total, j.retention)
+	}
+}
@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeInboxCleaner struct {
+	calls   int
+	expired int
+	before  time.Time
+}
+
+func (f *fakeInboxCleaner) DeleteExpired(ctx context.Context, before time.Time, limit int) (int, error) {
+	f.calls++
+	f.before = before
+	deleted := min(f.expired, limit)
+	f.expired -= deleted
+	return deleted, nil
+}
+
+func TestInboxCleanupJob_DeletesBacklogInBatches(t *testing.T) {
+	cleaner := &fakeInboxCleaner{expired: 2*inboxCleanupBatchSize + 1}
+	job := NewInboxCleanupJob(cleaner, time.Hour, 30*24*time.Hour)
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	job.RunOnce(context.Background(), now)
+
+	if cleaner.expired != 0 {
+		t.Errorf("Expected the backlog to be deleted in one run, %d left", cleaner.expired)
+	}
+	if cleaner.calls != 3 {
+		t.Errorf("Expected 3 delete rounds, got %d", cleaner.calls)
+	}
+	if want := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC); !cleaner.before.Equal(want) {
+		t.Errorf("Expected notifications before %v to be deleted, got %v", want, cleaner.before)
+	}
+}
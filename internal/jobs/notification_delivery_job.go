@@ -0,0 +1,95 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// notificationDeliveryBatchSize limits how many deliveries are sent per run
+const notificationDeliveryBatchSize = 100
+
+// NotificationDeliverer sends queued notifications
+type NotificationDeliverer interface {
+	CombineBatches(ctx context.Context) (int, error)
+	SendDailySummaries(ctx context.Context, now time.Time) (int, error)
+	DeliverDue(ctx context.Context, limit int) (int, error)
+}
+
+// NotificationDeliveryJob sends pending notifications and retries failed ones.
+// Batched notifications are combined into one message per user every BatchInterval,
+// daily chat summaries are queued once they are due.
+type NotificationDeliveryJob struct {
+	deliverer     NotificationDeliverer
+	interval      time.Duration
+	batchInterval time.Duration
+	lastBatch     time.Time
+}
+
+// NewNotificationDeliveryJob creates a new notification delivery job
+func NewNotificationDeliveryJob(deliverer NotificationDeliverer, interval, batchInterval time.Duration) *NotificationDeliveryJob {
+	return &NotificationDeliveryJob{
+		deliverer:     deliverer,
+		interval:      interval,
+		batchInterval: batchInterval,
+		// The first batch is combined one batch interval after start
+		lastBatch: time.Now(),
+	}
+}
+
+// Start runs the job until ctx is done
+func (j *NotificationDeliveryJob) Start(ctx context.Context) {
+	log.Printf("Starting notification delivery job with %v interval, batches every %v", j.interval, j.batchInterval)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.RunOnce(ctx, time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Notification delivery job stopped")
+			return
+		case now := <-ticker.C:
+			j.RunOnce(ctx, now)
+		}
+	}
+}
+
+// RunOnce combines batches and queues summaries when they are due, then sends everything that is pending
+func (j *NotificationDeliveryJob) RunOnce(ctx context.Context, now time.Time) {
+	if now.Sub(j.lastBatch) >= j.batchInterval {
+		batches, err := j.deliverer.CombineBatches(ctx)
+		if err != nil {
+			log.Printf("Failed to combine batched notifications: %v", err)
+		} else {
+			j.lastBatch = now
+			if batches > 0 {
+				log.Printf("Combined batched notifications into %d messages", batches)
+			}
+		}
+	}
+
+	summaries, err := j.deliverer.SendDailySummaries(ctx, now)
+	if err != nil {
+		log.Printf("Failed to queue daily summaries: %v", err)
+	} else if summaries > 0 {
+		log.Printf("Queued %d daily summaries", summaries)
+	}
+
+	// Keep sending while full batches come back, a backlog shouldn't wait for the next tick
+	for ctx.Err() == nil {
+		sent, err := j.deliverer.DeliverDue(ctx, notificationDeliveryBatchSize)
+		if err != nil {
+			log.Printf("Failed to deliver notifications: %v", err)
+			return
+		}
+		if sent > 0 {
+			log.Printf("Delivered %d notifications", sent)
+		}
+		if sent < notificationDeliveryBatchSize {
+			return
+		}
+	}
+}
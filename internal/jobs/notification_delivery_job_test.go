@@ -0,0 +1,66 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeDeliverer struct {
+	combineCalls int
+	summaryCalls int
+	deliverCalls int
+	pending      int
+}
+
+func (f *fakeDeliverer) SendDailySummaries(ctx context.Context, now time.Time) (int, error) {
+	f.summaryCalls++
+	return 0, nil
+}
+
+func (f *fakeDeliverer) CombineBatches(ctx context.Context) (int, error) {
+	f.combineCalls++
+	return 0, nil
+}
+
+func (f *fakeDeliverer) DeliverDue(ctx context.Context, limit int) (int, error) {
+	f.deliverCalls++
+	sent := min(f.pending, limit)
+	f.pending -= sent
+	return sent, nil
+}
+
+func TestNotificationDeliveryJob_CombinesBatchesOnInterval(t *testing.T) {
+	deliverer := &fakeDeliverer{}
+	job := NewNotificationDeliveryJob(deliverer, time.Minute, time.Hour)
+	start := job.lastBatch
+
+	job.RunOnce(context.Background(), start.Add(30*time.Minute))
+	if deliverer.combineCalls != 0 {
+		t.Errorf("Expected no batches before the batch interval, got %d", deliverer.combineCalls)
+	}
+
+	job.RunOnce(context.Background(), start.Add(time.Hour))
+	if deliverer.combineCalls != 1 {
+		t.Errorf("Expected batches to be combined after the batch interval, got %d", deliverer.combineCalls)
+	}
+
+	job.RunOnce(context.Background(), start.Add(90*time.Minute))
+	if deliverer.combineCalls != 1 {
+		t.Errorf("Expected the batch interval to restart, got %d combines", deliverer.combineCalls)
+	}
+}
+
+func TestNotificationDeliveryJob_DrainsBacklog(t *testing.T) {
+	deliverer := &fakeDeliverer{pending: 2*notificationDeliveryBatchSize + 10}
+	job := NewNotificationDeliveryJob(deliverer, time.Minute, time.Hour)
+
+	job.RunOnce(context.Background(), time.Now())
+
+	if deliverer.pending != 0 {
+		t.Errorf("Expected the backlog to be sent in one run, %d left", deliverer.pending)
+	}
+	if deliverer.deliverCalls != 3 {
+		t.Errorf("Expected 3 delivery rounds, got %d", deliverer.deliverCalls)
+	}
+}
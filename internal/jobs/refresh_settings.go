@@ -0,0 +1,89 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-starter/pkg/db"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// shipmentRefreshSettingsName is the job name the refresh settings are stored under
+const shipmentRefreshSettingsName = "shipment_refresh"
+
+// ShipmentRefreshSettings persists refresh job settings changed at runtime.
+// When a row exists it takes precedence over the environment configuration.
+type ShipmentRefreshSettings struct {
+	JobName             string     `json:"job_name" gorm:"type:varchar(100);primaryKey"`
+	RefreshInterval     int64      `json:"refresh_interval" gorm:"not null"`
+	ConcurrentWorkers   int        `json:"concurrent_workers" gorm:"not null"`
+	MaxShipmentsPerRun  int        `json:"max_shipments_per_run" gorm:"not null"`
+	SkipRecentlyUpdated int64      `json:"skip_recently_updated" gorm:"not null"`
+	UpdatedBy           *uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	UpdatedAt           time.Time  `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for ShipmentRefreshSettings
+func (ShipmentRefreshSettings) TableName() string {
+	return "shipment_refresh_settings"
+}
+
+// RefreshSettingsStore loads and saves the persisted refresh job settings
+type RefreshSettingsStore struct {
+	db *db.Database
+}
+
+// NewRefreshSettingsStore creates a new settings store
+func NewRefreshSettingsStore(database *db.Database) *RefreshSettingsStore {
+	return &RefreshSettingsStore{
+		db: database,
+	}
+}
+
+// Load returns the persisted configuration, or nil if it was never changed at runtime
+func (s *RefreshSettingsStore) Load(ctx context.Context) (*ShipmentRefreshConfig, error) {
+	var settings ShipmentRefreshSettings
+	err := s.db.DB.WithContext(ctx).
+		Where("job_name = ?", shipmentRefreshSettingsName).
+		First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load shipment refresh settings: %w", err)
+	}
+
+	return &ShipmentRefreshConfig{
+		RefreshInterval:     time.Duration(settings.RefreshInterval),
+		ConcurrentWorkers:   settings.ConcurrentWorkers,
+		MaxShipmentsPerRun:  settings.MaxShipmentsPerRun,
+		SkipRecentlyUpdated: time.Duration(settings.SkipRecentlyUpdated),
+	}, nil
+}
+
+// Save stores the configuration so it survives restarts
+func (s *RefreshSettingsStore) Save(ctx context.Context, config ShipmentRefreshConfig, updatedBy uuid.UUID) error {
+	settings := ShipmentRefreshSettings{
+		JobName:             shipmentRefreshSettingsName,
+		RefreshInterval:     int64(config.RefreshInterval),
+		ConcurrentWorkers:   config.ConcurrentWorkers,
+		MaxShipmentsPerRun:  config.MaxShipmentsPerRun,
+		SkipRecentlyUpdated: int64(config.SkipRecentlyUpdated),
+		UpdatedBy:           &updatedBy,
+		UpdatedAt:           time.Now(),
+	}
+
+	err := s.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&settings).Error
+	if err != nil {
+		return fmt.Errorf("failed to save shipment refresh settings: %w", err)
+	}
+
+	return nil
+}
@@ -19,12 +19,23 @@ type ShipmentRefreshJob struct {
 	shipmentRepo    repositories.ShipmentRepository
 	shipmentService services.ShipmentService
 	rateLimiter     *ratelimiter.SafeCubeAPIRateLimiter
-	config          ShipmentRefreshConfig
+	// settings holds settings saved through any replica, they are reloaded before every run
+	settings RefreshSettingsLoader
+
+	// config is read by running refreshes and replaced by UpdateConfig
+	mu            sync.RWMutex
+	config        ShipmentRefreshConfig
+	configChanged chan struct{}
+}
+
+// RefreshSettingsLoader loads the refresh settings changed at runtime, nil if there are none
+type RefreshSettingsLoader interface {
+	Load(ctx context.Context) (*ShipmentRefreshConfig, error)
 }
 
 // ShipmentRefreshConfig contains configuration for the refresh job
 type ShipmentRefreshConfig struct {
-	// RefreshInterval is how often to run the full refresh cycle
+	// RefreshInterval is how often to check for shipments that are due for a refresh
 	RefreshInterval time.Duration
 	// ConcurrentWorkers is the number of goroutines to use for parallel processing
 	ConcurrentWorkers int
@@ -37,7 +48,7 @@ type ShipmentRefreshConfig struct {
 // DefaultShipmentRefreshConfig returns a default configuration
 func DefaultShipmentRefreshConfig() ShipmentRefreshConfig {
 	return ShipmentRefreshConfig{
-		RefreshInterval:     3 * time.Hour,
+		RefreshInterval:     15 * time.Minute,
 		ConcurrentWorkers:   5,                // Conservative to respect rate limits
 		MaxShipmentsPerRun:  0,                // No limit
 		SkipRecentlyUpdated: 30 * time.Minute, // Don't refresh if updated in last 30 minutes
@@ -68,21 +79,33 @@ func NewShipmentRefreshJob(
 	shipmentRepo repositories.ShipmentRepository,
 	shipmentService services.ShipmentService,
 	rateLimiter *ratelimiter.SafeCubeAPIRateLimiter,
+	settings RefreshSettingsLoader,
 	config ShipmentRefreshConfig,
 ) *ShipmentRefreshJob {
 	return &ShipmentRefreshJob{
 		shipmentRepo:    shipmentRepo,
 		shipmentService: shipmentService,
 		rateLimiter:     rateLimiter,
+		settings:        settings,
 		config:          config,
+		configChanged:   make(chan struct{}, 1),
 	}
 }
 
 // Start begins the background refresh job with the configured interval
 func (j *ShipmentRefreshJob) Start(ctx context.Context) {
-	log.Printf("Starting shipment refresh job with %v interval", j.config.RefreshInterval)
+	interval := j.GetConfig().RefreshInterval
+	log.Printf("Starting shipment refresh job with %v interval", interval)
+
+	// Syncs interrupted by the last shutdown become due first
+	requeued, err := j.shipmentRepo.RequeuePendingSyncs(ctx)
+	if err != nil {
+		log.Printf("Failed to requeue pending syncs: %v", err)
+	} else if requeued > 0 {
+		log.Printf("Requeued %d shipment syncs interrupted by the last shutdown", requeued)
+	}
 
-	ticker := time.NewTicker(j.config.RefreshInterval)
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	// Run once immediately
@@ -96,6 +119,12 @@ func (j *ShipmentRefreshJob) Start(ctx context.Context) {
 		case <-ctx.Done():
 			log.Println("Shipment refresh job stopped")
 			return
+		case <-j.configChanged:
+			if newInterval := j.GetConfig().RefreshInterval; newInterval != interval {
+				interval = newInterval
+				ticker.Reset(interval)
+				log.Printf("Shipment refresh job interval changed to %v", interval)
+			}
 		case <-ticker.C:
 			go func() {
 				stats := j.RefreshAllShipments(ctx)
@@ -114,8 +143,12 @@ func (j *ShipmentRefreshJob) RefreshAllShipments(ctx context.Context) RefreshSta
 
 	log.Println("Starting bulk shipment refresh...")
 
+	// Use one config snapshot for the whole run, updates apply from the next run on
+	j.reloadSettings(ctx)
+	config := j.GetConfig()
+
 	// Get all shipments that need refreshing
-	shipments, err := j.getShipmentsForRefresh(ctx)
+	shipments, err := j.getShipmentsForRefresh(ctx, config)
 	if err != nil {
 		log.Printf("Failed to get shipments for refresh: %v", err)
 		stats.EndTime = time.Now()
@@ -131,9 +164,9 @@ func (j *ShipmentRefreshJob) RefreshAllShipments(ctx context.Context) RefreshSta
 	}
 
 	// Limit shipments if configured
-	if j.config.MaxShipmentsPerRun > 0 && len(shipments) > j.config.MaxShipmentsPerRun {
-		log.Printf("Limiting refresh to %d shipments (found %d)", j.config.MaxShipmentsPerRun, len(shipments))
-		shipments = shipments[:j.config.MaxShipmentsPerRun]
+	if config.MaxShipmentsPerRun > 0 && len(shipments) > config.MaxShipmentsPerRun {
+		log.Printf("Limiting refresh to %d shipments (found %d)", config.MaxShipmentsPerRun, len(shipments))
+		shipments = shipments[:config.MaxShipmentsPerRun]
 		stats.TotalShipments = len(shipments)
 	}
 
@@ -149,7 +182,7 @@ func (j *ShipmentRefreshJob) RefreshAllShipments(ctx context.Context) RefreshSta
 
 	// Start worker goroutines
 	var wg sync.WaitGroup
-	for i := 0; i < j.config.ConcurrentWorkers; i++ {
+	for i := 0; i < config.ConcurrentWorkers; i++ {
 		wg.Add(1)
 		go j.refreshWorker(ctx, shipmentChan, resultChan, &wg)
 	}
@@ -201,6 +234,11 @@ func (j *ShipmentRefreshJob) refreshWorker(
 	defer wg.Done()
 
 	for shipment := range shipmentChan {
+		// Stop picking up work on shutdown, remaining shipments stay due for the next start
+		if ctx.Err() != nil {
+			return
+		}
+
 		result := j.refreshSingleShipment(ctx, shipment)
 		resultChan <- result
 	}
@@ -241,8 +279,8 @@ func (j *ShipmentRefreshJob) refreshSingleShipment(ctx context.Context, shipment
 }
 
 // getShipmentsForRefresh gets all shipments that need to be refreshed
-func (j *ShipmentRefreshJob) getShipmentsForRefresh(ctx context.Context) ([]ShipmentForRefresh, error) {
-	return j.shipmentRepo.GetAllShipmentsForRefresh(ctx, j.config.SkipRecentlyUpdated)
+func (j *ShipmentRefreshJob) getShipmentsForRefresh(ctx context.Context, config ShipmentRefreshConfig) ([]ShipmentForRefresh, error) {
+	return j.shipmentRepo.GetAllShipmentsForRefresh(ctx, config.SkipRecentlyUpdated)
 }
 
 // logRefreshStats logs the statistics from a refresh run
@@ -264,15 +302,74 @@ func (j *ShipmentRefreshJob) logRefreshStats(stats RefreshStats) {
 	// Log rate limiter status
 	tokensAvailable := j.rateLimiter.TokensAvailable()
 	log.Printf("Rate limiter tokens available: %d", tokensAvailable)
+
+	// Log sync coordination counters (process lifetime)
+	syncStats := services.GetSyncStats()
+	log.Printf("Shipment syncs executed: %d, collapsed into a running sync: %d",
+		syncStats.Executed, syncStats.Collapsed)
+}
+
+// reloadSettings applies settings that were saved since the last run. Every replica runs its
+// own refresh job, this is how a change made through another replica reaches this one.
+func (j *ShipmentRefreshJob) reloadSettings(ctx context.Context) {
+	if j.settings == nil {
+		return
+	}
+
+	persisted, err := j.settings.Load(ctx)
+	if err != nil {
+		log.Printf("Failed to reload shipment refresh settings: %v", err)
+		return
+	}
+	if persisted == nil || *persisted == j.GetConfig() {
+		return
+	}
+	if err := j.UpdateConfig(*persisted); err != nil {
+		log.Printf("Ignoring invalid persisted shipment refresh settings: %v", err)
+	}
 }
 
-// GetRefreshStats returns a copy of the current refresh configuration
+// GetConfig returns a copy of the current refresh configuration
 func (j *ShipmentRefreshJob) GetConfig() ShipmentRefreshConfig {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
 	return j.config
 }
 
-// UpdateConfig allows updating the job configuration at runtime
-func (j *ShipmentRefreshJob) UpdateConfig(newConfig ShipmentRefreshConfig) {
+// UpdateConfig replaces the job configuration at runtime.
+// Running refreshes finish with the config they started with, an interval change resets the ticker.
+func (j *ShipmentRefreshJob) UpdateConfig(newConfig ShipmentRefreshConfig) error {
+	if err := newConfig.Validate(); err != nil {
+		return err
+	}
+
+	j.mu.Lock()
 	j.config = newConfig
+	j.mu.Unlock()
+
+	// Wake up Start, a pending notification already covers this change
+	select {
+	case j.configChanged <- struct{}{}:
+	default:
+	}
+
 	log.Printf("Shipment refresh job configuration updated: %+v", newConfig)
+	return nil
+}
+
+// Validate checks that the configuration can be applied to a running job
+func (c ShipmentRefreshConfig) Validate() error {
+	if c.RefreshInterval < time.Minute {
+		return fmt.Errorf("refresh interval must be at least 1m")
+	}
+	if c.ConcurrentWorkers < 1 || c.ConcurrentWorkers > 50 {
+		return fmt.Errorf("concurrent workers must be between 1 and 50")
+	}
+	if c.MaxShipmentsPerRun < 0 {
+		return fmt.Errorf("max shipments per run must not be negative")
+	}
+	if c.SkipRecentlyUpdated < 0 {
+		return fmt.Errorf("skip recently updated must not be negative")
+	}
+	return nil
 }
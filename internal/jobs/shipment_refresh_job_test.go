@@ -0,0 +1,91 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestShipmentRefreshJob_UpdateConfig(t *testing.T) {
+	job := NewShipmentRefreshJob(nil, nil, nil, nil, DefaultShipmentRefreshConfig())
+
+	newConfig := ShipmentRefreshConfig{
+		RefreshInterval:     5 * time.Minute,
+		ConcurrentWorkers:   2,
+		MaxShipmentsPerRun:  100,
+		SkipRecentlyUpdated: 10 * time.Minute,
+	}
+	if err := job.UpdateConfig(newConfig); err != nil {
+		t.Fatalf("UpdateConfig failed: %v", err)
+	}
+
+	if got := job.GetConfig(); got != newConfig {
+		t.Errorf("Expected config %+v, got %+v", newConfig, got)
+	}
+
+	select {
+	case <-job.configChanged:
+	default:
+		t.Error("Expected a config change notification")
+	}
+}
+
+func TestShipmentRefreshJob_UpdateConfigRejectsInvalid(t *testing.T) {
+	initial := DefaultShipmentRefreshConfig()
+	job := NewShipmentRefreshJob(nil, nil, nil, nil, initial)
+
+	invalid := []ShipmentRefreshConfig{
+		{RefreshInterval: 10 * time.Second, ConcurrentWorkers: 1},
+		{RefreshInterval: time.Hour, ConcurrentWorkers: 0},
+		{RefreshInterval: time.Hour, ConcurrentWorkers: 1, MaxShipmentsPerRun: -1},
+		{RefreshInterval: time.Hour, ConcurrentWorkers: 1, SkipRecentlyUpdated: -time.Minute},
+	}
+
+	for _, config := range invalid {
+		if err := job.UpdateConfig(config); err == nil {
+			t.Errorf("Expected %+v to be rejected", config)
+		}
+	}
+
+	if got := job.GetConfig(); got != initial {
+		t.Errorf("Expected config to stay %+v, got %+v", initial, got)
+	}
+}
+
+// fakeSettingsLoader returns the settings another replica saved
+type fakeSettingsLoader struct {
+	config *ShipmentRefreshConfig
+}
+
+func (f *fakeSettingsLoader) Load(ctx context.Context) (*ShipmentRefreshConfig, error) {
+	return f.config, nil
+}
+
+func TestShipmentRefreshJob_ReloadSettings(t *testing.T) {
+	initial := DefaultShipmentRefreshConfig()
+	loader := &fakeSettingsLoader{}
+	job := NewShipmentRefreshJob(nil, nil, nil, loader, initial)
+
+	job.reloadSettings(context.Background())
+	if got := job.GetConfig(); got != initial {
+		t.Errorf("Expected config to stay %+v without saved settings, got %+v", initial, got)
+	}
+
+	saved := ShipmentRefreshConfig{RefreshInterval: time.Hour, ConcurrentWorkers: 3}
+	loader.config = &saved
+	job.reloadSettings(context.Background())
+	if got := job.GetConfig(); got != saved {
+		t.Errorf("Expected saved config %+v, got %+v", saved, got)
+	}
+	select {
+	case <-job.configChanged:
+	default:
+		t.Error("Expected a config change notification for the new interval")
+	}
+
+	loader.config = &ShipmentRefreshConfig{RefreshInterval: time.Second, ConcurrentWorkers: 3}
+	job.reloadSettings(context.Background())
+	if got := job.GetConfig(); got != saved {
+		t.Errorf("Expected invalid saved settings to be ignored, got %+v", got)
+	}
+}
@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// webhookDeliveryBatchSize limits how many webhooks are sent per round
+const webhookDeliveryBatchSize = 100
+
+// WebhookDeliverer sends queued webhooks
+type WebhookDeliverer interface {
+	DeliverDue(ctx context.Context, limit int) (int, error)
+}
+
+// WebhookDeliveryJob sends pending webhooks and retries failed ones
+type WebhookDeliveryJob struct {
+	deliverer WebhookDeliverer
+	interval  time.Duration
+}
+
+// NewWebhookDeliveryJob creates a new webhook delivery job
+func NewWebhookDeliveryJob(deliverer WebhookDeliverer, interval time.Duration) *WebhookDeliveryJob {
+	return &WebhookDeliveryJob{
+		deliverer: deliverer,
+		interval:  interval,
+	}
+}
+
+// Start runs the job until ctx is done
+func (j *WebhookDeliveryJob) Start(ctx context.Context) {
+	log.Printf("Starting webhook delivery job with %v interval", j.interval)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.RunOnce(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Webhook delivery job stopped")
+			return
+		case <-ticker.C:
+			j.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce sends everything that is pending
+func (j *WebhookDeliveryJob) RunOnce(ctx context.Context) {
+	// Keep sending while full batches come back, a backlog shouldn't wait for the next tick
+	for ctx.Err() == nil {
+		sent, err := j.deliverer.DeliverDue(ctx, webhookDeliveryBatchSize)
+		if err != nil {
+			log.Printf("Failed to deliver webhooks: %v", err)
+			return
+		}
+		if sent > 0 {
+			log.Printf("Delivered %d webhooks", sent)
+		}
+		if sent < webhookDeliveryBatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeWebhookDeliverer struct {
+	calls   int
+	pending int
+	err     error
+}
+
+func (f *fakeWebhookDeliverer) DeliverDue(ctx context.Context, limit int) (int, error) {
+	f.calls++
+	if f.err != nil {
+		return 0, f.err
+	}
+	sent := min(f.pending, limit)
+	f.pending -= sent
+	return sent, nil
+}
+
+func TestWebhookDeliveryJob_DrainsBacklog(t *testing.T) {
+	deliverer := &fakeWebhookDeliverer{pending: webhookDeliveryBatchSize + 1}
+	job := NewWebhookDeliveryJob(deliverer, 0)
+
+	job.RunOnce(context.Background())
+
+	if deliverer.pending != 0 {
+		t.Errorf("Expected the backlog to be sent in one run, %d left", deliverer.pending)
+	}
+	if deliverer.calls != 2 {
+		t.Errorf("Expected 2 delivery rounds, got %d", deliverer.calls)
+	}
+}
+
+func TestWebhookDeliveryJob_StopsOnError(t *testing.T) {
+	deliverer := &fakeWebhookDeliverer{err: errors.New("database unavailable")}
+	job := NewWebhookDeliveryJob(deliverer, 0)
+
+	job.RunOnce(context.Background())
+
+	if deliverer.calls != 1 {
+		t.Errorf("Expected the run to stop after an error, got %d rounds", deliverer.calls)
+	}
+}
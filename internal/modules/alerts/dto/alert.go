@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"go-starter/internal/modules/alerts/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SaveRuleRequest represents the request to create or update an alert rule
+type SaveRuleRequest struct {
+	Name       string          `json:"name" validate:"required,min=1,max=100"`
+	Type       models.RuleType `json:"type" validate:"required"`
+	ShipmentID *uuid.UUID      `json:"shipment_id"`
+	Days       int             `json:"days" validate:"min=0,max=365"`
+	EventCode  string          `json:"event_code" validate:"max=50"`
+	Status     string          `json:"status" validate:"max=50"`
+	Enabled    *bool           `json:"enabled"`
+}
+
+// RulesListResponse represents the response when listing alert rules
+type RulesListResponse struct {
+	Rules []models.AlertRule `json:"rules"`
+	Total int                `json:"total"`
+}
+
+// AlertsListResponse represents the response when listing alerts
+type AlertsListResponse struct {
+	Alerts []models.Alert `json:"alerts"`
+	Total  int            `json:"total"`
+}
+
+// MutesListResponse represents the response when listing muted shipments
+type MutesListResponse struct {
+	Mutes []models.AlertMute `json:"mutes"`
+	Total int                `json:"total"`
+}
+
+// SnoozeAlertRequest represents the request to snooze an alert, either until
+// a point in time or for a duration in Go syntax, e.g. "24h"
+type SnoozeAlertRequest struct {
+	Until    *time.Time `json:"until"`
+	Duration string     `json:"duration"`
+}
+
+// MuteShipmentRequest represents the request to mute the alerts of a shipment.
+// Without Until the shipment stays muted until it is unmuted.
+type MuteShipmentRequest struct {
+	Until *time.Time `json:"until"`
+}
+
+// MessageResponse represents a response that only carries a message
+type MessageResponse struct {
+	Message string `json:"message"`
+}
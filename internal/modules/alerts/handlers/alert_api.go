@@ -0,0 +1,390 @@
+package handlers
+
+import (
+	"go-starter/internal/modules/alerts/dto"
+	"go-starter/internal/modules/alerts/models"
+	"go-starter/internal/modules/alerts/repositories"
+	alertServices "go-starter/internal/modules/alerts/services"
+	"go-starter/internal/modules/auth/services"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type AlertAPIHandler struct {
+	alertService *alertServices.AlertService
+	validator    *validator.Validate
+}
+
+func NewAlertAPIHandler(alertService *alertServices.AlertService) *AlertAPIHandler {
+	return &AlertAPIHandler{
+		alertService: alertService,
+		validator:    validator.New(),
+	}
+}
+
+// GetAlerts handles GET /api/alerts
+func (h *AlertAPIHandler) GetAlerts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	filter := repositories.AlertListFilter{
+		Status:         c.QueryParam("status"),
+		IncludeSnoozed: c.QueryParam("include_snoozed") == "true",
+	}
+	if filter.Status != "" && filter.Status != models.AlertStatusOpen && filter.Status != models.AlertStatusAcknowledged {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid status",
+		})
+	}
+	if shipmentIDStr := c.QueryParam("shipment_id"); shipmentIDStr != "" {
+		shipmentID, err := uuid.Parse(shipmentIDStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid shipment ID",
+			})
+		}
+		filter.ShipmentID = &shipmentID
+	}
+
+	response, err := h.alertService.GetAlerts(ctx, userID, filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve alerts: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// AcknowledgeAlert handles POST /api/alerts/:id/acknowledge
+func (h *AlertAPIHandler) AcknowledgeAlert(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	alertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid alert ID",
+		})
+	}
+
+	alert, err := h.alertService.AcknowledgeAlert(ctx, userID, alertID)
+	if err != nil {
+		return h.serviceError(c, "Failed to acknowledge alert", err)
+	}
+
+	return c.JSON(http.StatusOK, alert)
+}
+
+// SnoozeAlert handles POST /api/alerts/:id/snooze
+func (h *AlertAPIHandler) SnoozeAlert(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	alertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid alert ID",
+		})
+	}
+
+	var req dto.SnoozeAlertRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	var until time.Time
+	switch {
+	case req.Until != nil:
+		until = *req.Until
+	case req.Duration != "":
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid duration",
+			})
+		}
+		until = time.Now().Add(duration)
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Either until or duration is required",
+		})
+	}
+
+	alert, err := h.alertService.SnoozeAlert(ctx, userID, alertID, until)
+	if err != nil {
+		return h.serviceError(c, "Failed to snooze alert", err)
+	}
+
+	return c.JSON(http.StatusOK, alert)
+}
+
+// AcknowledgeShipmentAlerts handles POST /api/alerts/shipments/:shipmentId/acknowledge
+func (h *AlertAPIHandler) AcknowledgeShipmentAlerts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	shipmentID, err := uuid.Parse(c.Param("shipmentId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid shipment ID",
+		})
+	}
+
+	count, err := h.alertService.AcknowledgeShipmentAlerts(ctx, userID, shipmentID)
+	if err != nil {
+		return h.serviceError(c, "Failed to acknowledge alerts", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"acknowledged": count,
+	})
+}
+
+// MuteShipment handles POST /api/alerts/shipments/:shipmentId/mute
+func (h *AlertAPIHandler) MuteShipment(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	shipmentID, err := uuid.Parse(c.Param("shipmentId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid shipment ID",
+		})
+	}
+
+	var req dto.MuteShipmentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	mute, err := h.alertService.MuteShipment(ctx, userID, shipmentID, req.Until)
+	if err != nil {
+		return h.serviceError(c, "Failed to mute shipment", err)
+	}
+
+	return c.JSON(http.StatusOK, mute)
+}
+
+// UnmuteShipment handles DELETE /api/alerts/shipments/:shipmentId/mute
+func (h *AlertAPIHandler) UnmuteShipment(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	shipmentID, err := uuid.Parse(c.Param("shipmentId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid shipment ID",
+		})
+	}
+
+	if err := h.alertService.UnmuteShipment(ctx, userID, shipmentID); err != nil {
+		return h.serviceError(c, "Failed to unmute shipment", err)
+	}
+
+	return c.JSON(http.StatusOK, dto.MessageResponse{
+		Message: "Shipment unmuted successfully",
+	})
+}
+
+// GetMutes handles GET /api/alerts/mutes
+func (h *AlertAPIHandler) GetMutes(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	response, err := h.alertService.GetMutes(ctx, userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve mutes: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetRules handles GET /api/alerts/rules
+func (h *AlertAPIHandler) GetRules(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	response, err := h.alertService.GetRules(ctx, userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve rules: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// CreateRule handles POST /api/alerts/rules
+func (h *AlertAPIHandler) CreateRule(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.SaveRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Validation failed: " + err.Error(),
+		})
+	}
+
+	rule, err := h.alertService.CreateRule(ctx, userID, &req)
+	if err != nil {
+		return h.serviceError(c, "Failed to create rule", err)
+	}
+
+	return c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRule handles PUT /api/alerts/rules/:id
+func (h *AlertAPIHandler) UpdateRule(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	ruleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid rule ID",
+		})
+	}
+
+	var req dto.SaveRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Validation failed: " + err.Error(),
+		})
+	}
+
+	rule, err := h.alertService.UpdateRule(ctx, userID, ruleID, &req)
+	if err != nil {
+		return h.serviceError(c, "Failed to update rule", err)
+	}
+
+	return c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule handles DELETE /api/alerts/rules/:id
+func (h *AlertAPIHandler) DeleteRule(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	ruleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid rule ID",
+		})
+	}
+
+	if err := h.alertService.DeleteRule(ctx, userID, ruleID); err != nil {
+		return h.serviceError(c, "Failed to delete rule", err)
+	}
+
+	return c.JSON(http.StatusOK, dto.MessageResponse{
+		Message: "Rule deleted successfully",
+	})
+}
+
+// serviceError maps service errors to status codes, validation errors are returned as is
+func (h *AlertAPIHandler) serviceError(c echo.Context, message string, err error) error {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	case strings.HasPrefix(err.Error(), "invalid"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message + ": " + err.Error(),
+	})
+}
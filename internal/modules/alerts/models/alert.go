@@ -0,0 +1,163 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RuleType identifies what an alert rule watches for
+type RuleType string
+
+const (
+	// RuleTypeETASlip fires when the POD ETA moved later by more than Days since its baseline
+	RuleTypeETASlip RuleType = "eta_slip"
+	// RuleTypeStatusChange fires when the shipping status changes, optionally only to Status
+	RuleTypeStatusChange RuleType = "status_change"
+	// RuleTypeContainerEvent fires when a container reaches an actual event with EventCode
+	RuleTypeContainerEvent RuleType = "container_event"
+	// RuleTypeVesselArrival fires when the vessel arrives at the port of discharge
+	RuleTypeVesselArrival RuleType = "vessel_arrival"
+	// RuleTypeNoEvents fires when there were no actual events for more than Days
+	RuleTypeNoEvents RuleType = "no_events"
+)
+
+// AlertTypeFreeTime is raised by the demurrage module when a container's free time is about
+// to run out or ran out, it is not a rule type users can create rules for
+const AlertTypeFreeTime RuleType = "free_time"
+
+// AlertTypeGeofence is raised by the geofences module when a vessel enters or exits a
+// user's geofence, it is not a rule type users can create rules for
+const AlertTypeGeofence RuleType = "geofence"
+
+// IsValid reports whether the rule type is known
+func (t RuleType) IsValid() bool {
+	switch t {
+	case RuleTypeETASlip, RuleTypeStatusChange, RuleTypeContainerEvent, RuleTypeVesselArrival, RuleTypeNoEvents:
+		return true
+	}
+	return false
+}
+
+// IsNotificationType reports whether users can have alerts of the type sent to them, these
+// are the rule types and the alerts other modules raise
+func (t RuleType) IsNotificationType() bool {
+	return t.IsValid() || t == AlertTypeFreeTime || t == AlertTypeGeofence
+}
+
+// Alert statuses
+const (
+	AlertStatusOpen         = "open"
+	AlertStatusAcknowledged = "acknowledged"
+)
+
+// AlertRule is a user defined condition over shipment state that is checked after every sync
+type AlertRule struct {
+	ID     uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name   string    `json:"name" gorm:"type:varchar(100);not null"`
+	Type   RuleType  `json:"type" gorm:"type:varchar(30);not null"`
+	// ShipmentID limits the rule to one shipment, nil applies it to all tracked shipments
+	ShipmentID *uuid.UUID `json:"shipment_id" gorm:"type:uuid;index"`
+	// Days is the threshold of eta_slip and no_events rules
+	Days int `json:"days" gorm:"not null;default:0"`
+	// EventCode is the container event code of container_event rules
+	EventCode string `json:"event_code" gorm:"type:varchar(50)"`
+	// Status optionally restricts status_change rules to changes into this status
+	Status    string    `json:"status" gorm:"type:varchar(50)"`
+	Enabled   bool      `json:"enabled" gorm:"not null;default:true"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for AlertRule
+func (AlertRule) TableName() string {
+	return "alert_rules"
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (r *AlertRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// AlertETABaseline is the POD ETA an eta_slip rule measures the slip of a shipment against.
+// It starts as the first ETA seen and moves to the new ETA whenever the rule fires, so a
+// gradual drift over many syncs adds up.
+type AlertETABaseline struct {
+	RuleID     uuid.UUID `json:"rule_id" gorm:"type:uuid;primaryKey"`
+	ShipmentID uuid.UUID `json:"shipment_id" gorm:"type:uuid;primaryKey"`
+	PodETA     time.Time `json:"pod_eta" gorm:"type:timestamptz;not null"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for AlertETABaseline
+func (AlertETABaseline) TableName() string {
+	return "alert_eta_baselines"
+}
+
+// Alert is raised when a rule matches a shipment sync. DedupKey identifies the condition
+// that fired, the same condition firing again only bumps OccurrenceCount.
+type Alert struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID          uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_alerts_user_dedup"`
+	ShipmentID      uuid.UUID  `json:"shipment_id" gorm:"type:uuid;not null;index"`
+	RuleID          *uuid.UUID `json:"rule_id" gorm:"type:uuid;index"`
+	Type            RuleType   `json:"type" gorm:"type:varchar(30);not null"`
+	Title           string     `json:"title" gorm:"type:varchar(255);not null"`
+	Message         string     `json:"message" gorm:"type:text"`
+	DedupKey        string     `json:"dedup_key" gorm:"type:varchar(255);not null;uniqueIndex:idx_alerts_user_dedup"`
+	Status          string     `json:"status" gorm:"type:varchar(20);not null;default:'open';index"`
+	OccurrenceCount int        `json:"occurrence_count" gorm:"not null;default:1"`
+	LastTriggeredAt time.Time  `json:"last_triggered_at" gorm:"type:timestamptz;not null"`
+	SnoozedUntil    *time.Time `json:"snoozed_until" gorm:"type:timestamptz"`
+	AcknowledgedAt  *time.Time `json:"acknowledged_at" gorm:"type:timestamptz"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for Alert
+func (Alert) TableName() string {
+	return "alerts"
+}
+
+// BeforeCreate hook to set UUID and status if not provided
+func (a *Alert) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	if a.Status == "" {
+		a.Status = AlertStatusOpen
+	}
+	return nil
+}
+
+// IsSnoozed reports whether the alert is snoozed at the given time
+func (a *Alert) IsSnoozed(now time.Time) bool {
+	return a.SnoozedUntil != nil && a.SnoozedUntil.After(now)
+}
+
+// AlertMute silences all alerts of a shipment for a user, forever or until MutedUntil
+type AlertMute struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_alert_mutes_user_shipment"`
+	ShipmentID uuid.UUID  `json:"shipment_id" gorm:"type:uuid;not null;uniqueIndex:idx_alert_mutes_user_shipment"`
+	MutedUntil *time.Time `json:"muted_until" gorm:"type:timestamptz"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for AlertMute
+func (AlertMute) TableName() string {
+	return "alert_mutes"
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (m *AlertMute) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,330 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-starter/internal/modules/alerts/models"
+	shipmentModels "go-starter/internal/modules/shipments/models"
+	"go-starter/pkg/db"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AlertRepository struct {
+	db *db.Database
+}
+
+func NewAlertRepository(database *db.Database) *AlertRepository {
+	return &AlertRepository{
+		db: database,
+	}
+}
+
+// AlertListFilter narrows down the alerts returned for a user
+type AlertListFilter struct {
+	Status         string
+	ShipmentID     *uuid.UUID
+	IncludeSnoozed bool
+}
+
+// CreateRule creates a new alert rule
+func (r *AlertRepository) CreateRule(ctx context.Context, rule *models.AlertRule) error {
+	return r.db.DB.WithContext(ctx).Create(rule).Error
+}
+
+// GetRuleByID retrieves a rule by its ID and user ID
+func (r *AlertRepository) GetRuleByID(ctx context.Context, ruleID, userID uuid.UUID) (*models.AlertRule, error) {
+	var rule models.AlertRule
+	err := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", ruleID, userID).
+		First(&rule).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// GetRulesByUserID retrieves all rules of a user
+func (r *AlertRepository) GetRulesByUserID(ctx context.Context, userID uuid.UUID) ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&rules).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// GetRulesForShipment retrieves the enabled rules of all users tracking the shipment
+// that apply to it, either because they are scoped to it or to all shipments
+func (r *AlertRepository) GetRulesForShipment(ctx context.Context, shipmentID uuid.UUID) ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	err := r.db.DB.WithContext(ctx).
+		Where("enabled = ?", true).
+		Where("shipment_id IS NULL OR shipment_id = ?", shipmentID).
+		Where("user_id IN (?)", r.db.DB.Model(&shipmentModels.UserShipment{}).
+			Select("user_id").
+			Where("shipment_id = ?", shipmentID)).
+		Find(&rules).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert rules for shipment: %w", err)
+	}
+
+	return rules, nil
+}
+
+// UpdateRule updates an existing rule
+func (r *AlertRepository) UpdateRule(ctx context.Context, rule *models.AlertRule) error {
+	return r.db.DB.WithContext(ctx).
+		Model(rule).
+		Where("id = ? AND user_id = ?", rule.ID, rule.UserID).
+		Updates(map[string]interface{}{
+			"name":        rule.Name,
+			"type":        rule.Type,
+			"shipment_id": rule.ShipmentID,
+			"days":        rule.Days,
+			"event_code":  rule.EventCode,
+			"status":      rule.Status,
+			"enabled":     rule.Enabled,
+			"updated_at":  gorm.Expr("NOW()"),
+		}).Error
+}
+
+// DeleteRule deletes a rule by its ID and user ID, together with its ETA baselines
+func (r *AlertRepository) DeleteRule(ctx context.Context, ruleID, userID uuid.UUID) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Where("id = ? AND user_id = ?", ruleID, userID).
+			Delete(&models.AlertRule{})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Where("rule_id = ?", ruleID).Delete(&models.AlertETABaseline{}).Error
+	})
+}
+
+// GetETABaselines retrieves the ETA baselines of a shipment by rule ID
+func (r *AlertRepository) GetETABaselines(ctx context.Context, shipmentID uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	var baselines []models.AlertETABaseline
+	err := r.db.DB.WithContext(ctx).
+		Where("shipment_id = ?", shipmentID).
+		Find(&baselines).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ETA baselines: %w", err)
+	}
+
+	byRule := make(map[uuid.UUID]time.Time, len(baselines))
+	for _, baseline := range baselines {
+		byRule[baseline.RuleID] = baseline.PodETA
+	}
+	return byRule, nil
+}
+
+// SaveETABaseline stores the ETA baseline of a rule for a shipment, replacing an existing one
+func (r *AlertRepository) SaveETABaseline(ctx context.Context, ruleID, shipmentID uuid.UUID, podETA time.Time) error {
+	return r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "rule_id"}, {Name: "shipment_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"pod_eta", "updated_at"}),
+		}).
+		Create(&models.AlertETABaseline{
+			RuleID:     ruleID,
+			ShipmentID: shipmentID,
+			PodETA:     podETA,
+			UpdatedAt:  time.Now(),
+		}).Error
+}
+
+// UpsertAlert inserts the alert unless the user already has one with the same dedup key,
+// in that case the existing alert's occurrence count and trigger time are updated instead.
+// It reports whether a new alert was created.
+func (r *AlertRepository) UpsertAlert(ctx context.Context, alert *models.Alert) (bool, error) {
+	result := r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "dedup_key"}},
+			DoNothing: true,
+		}).
+		Create(alert)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to create alert: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.Alert{}).
+		Where("user_id = ? AND dedup_key = ?", alert.UserID, alert.DedupKey).
+		Updates(map[string]interface{}{
+			"occurrence_count":  gorm.Expr("occurrence_count + 1"),
+			"last_triggered_at": alert.LastTriggeredAt,
+			"updated_at":        gorm.Expr("NOW()"),
+		}).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to update duplicate alert: %w", err)
+	}
+
+	return false, nil
+}
+
+// GetAlertByID retrieves an alert by its ID and user ID
+func (r *AlertRepository) GetAlertByID(ctx context.Context, alertID, userID uuid.UUID) (*models.Alert, error) {
+	var alert models.Alert
+	err := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", alertID, userID).
+		First(&alert).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &alert, nil
+}
+
+// GetAlertsByUserID retrieves the alerts of a user, newest first
+func (r *AlertRepository) GetAlertsByUserID(ctx context.Context, userID uuid.UUID, filter AlertListFilter, now time.Time) ([]models.Alert, error) {
+	query := r.db.DB.WithContext(ctx).Where("user_id = ?", userID)
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ShipmentID != nil {
+		query = query.Where("shipment_id = ?", *filter.ShipmentID)
+	}
+	if !filter.IncludeSnoozed {
+		query = query.Where("snoozed_until IS NULL OR snoozed_until <= ?", now)
+	}
+
+	var alerts []models.Alert
+	if err := query.Order("last_triggered_at DESC").Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+// UpdateAlertFields updates the given columns of a user's alert
+func (r *AlertRepository) UpdateAlertFields(ctx context.Context, alertID, userID uuid.UUID, updates map[string]interface{}) error {
+	updates["updated_at"] = gorm.Expr("NOW()")
+
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.Alert{}).
+		Where("id = ? AND user_id = ?", alertID, userID).
+		Updates(updates)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// AcknowledgeShipmentAlerts acknowledges all open alerts of a shipment for a user
+func (r *AlertRepository) AcknowledgeShipmentAlerts(ctx context.Context, userID, shipmentID uuid.UUID, acknowledgedAt time.Time) (int64, error) {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.Alert{}).
+		Where("user_id = ? AND shipment_id = ? AND status = ?", userID, shipmentID, models.AlertStatusOpen).
+		Updates(map[string]interface{}{
+			"status":          models.AlertStatusAcknowledged,
+			"acknowledged_at": acknowledgedAt,
+			"updated_at":      gorm.Expr("NOW()"),
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+// UpsertMute mutes a shipment for a user, replacing an existing mute
+func (r *AlertRepository) UpsertMute(ctx context.Context, mute *models.AlertMute) error {
+	return r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "shipment_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"muted_until"}),
+		}).
+		Create(mute).Error
+}
+
+// DeleteMute unmutes a shipment for a user
+func (r *AlertRepository) DeleteMute(ctx context.Context, userID, shipmentID uuid.UUID) error {
+	result := r.db.DB.WithContext(ctx).
+		Where("user_id = ? AND shipment_id = ?", userID, shipmentID).
+		Delete(&models.AlertMute{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// GetMutesByUserID retrieves the mutes of a user that haven't expired
+func (r *AlertRepository) GetMutesByUserID(ctx context.Context, userID uuid.UUID, now time.Time) ([]models.AlertMute, error) {
+	var mutes []models.AlertMute
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("muted_until IS NULL OR muted_until > ?", now).
+		Order("created_at DESC").
+		Find(&mutes).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mutes, nil
+}
+
+// IsShipmentMuted reports whether the user muted the shipment at the given time
+func (r *AlertRepository) IsShipmentMuted(ctx context.Context, userID, shipmentID uuid.UUID, now time.Time) (bool, error) {
+	var count int64
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.AlertMute{}).
+		Where("user_id = ? AND shipment_id = ?", userID, shipmentID).
+		Where("muted_until IS NULL OR muted_until > ?", now).
+		Count(&count).Error
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// UserTracksShipment checks if the user tracks the shipment
+func (r *AlertRepository) UserTracksShipment(ctx context.Context, userID, shipmentID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.DB.WithContext(ctx).
+		Model(&shipmentModels.UserShipment{}).
+		Where("user_id = ? AND shipment_id = ?", userID, shipmentID).
+		Count(&count).Error
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
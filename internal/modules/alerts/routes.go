@@ -0,0 +1,47 @@
+package alerts
+
+import (
+	"go-starter/internal/modules/alerts/handlers"
+	"go-starter/internal/modules/alerts/repositories"
+	alertServices "go-starter/internal/modules/alerts/services"
+	"go-starter/internal/modules/auth/middlewares"
+	"go-starter/internal/modules/auth/services"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+	"go-starter/pkg/config"
+	"go-starter/pkg/db"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRoutes(api *echo.Group, database *db.Database, cfg *config.Config) {
+	// Initialize dependencies
+	alertRepo := repositories.NewAlertRepository(database)
+	alertService := alertServices.NewAlertService(alertRepo)
+	alertAPIHandler := handlers.NewAlertAPIHandler(alertService)
+
+	// Evaluate alert rules after every shipment sync, including background refreshes
+	shipmentServices.RegisterSyncListener(alertService.HandleShipmentSync)
+
+	// Create JWT service for middleware
+	jwtService := services.NewJWTService()
+
+	// Create alerts group with JWT middleware
+	alertsGroup := api.Group("/alerts", middlewares.JWTMiddleware(jwtService))
+
+	// Alert routes
+	alertsGroup.GET("", alertAPIHandler.GetAlerts)                         // GET /api/alerts
+	alertsGroup.POST("/:id/acknowledge", alertAPIHandler.AcknowledgeAlert) // POST /api/alerts/:id/acknowledge
+	alertsGroup.POST("/:id/snooze", alertAPIHandler.SnoozeAlert)           // POST /api/alerts/:id/snooze
+
+	// Per shipment routes
+	alertsGroup.GET("/mutes", alertAPIHandler.GetMutes)                                               // GET /api/alerts/mutes
+	alertsGroup.POST("/shipments/:shipmentId/acknowledge", alertAPIHandler.AcknowledgeShipmentAlerts) // POST /api/alerts/shipments/:shipmentId/acknowledge
+	alertsGroup.POST("/shipments/:shipmentId/mute", alertAPIHandler.MuteShipment)                     // POST /api/alerts/shipments/:shipmentId/mute
+	alertsGroup.DELETE("/shipments/:shipmentId/mute", alertAPIHandler.UnmuteShipment)                 // DELETE /api/alerts/shipments/:shipmentId/mute
+
+	// Rule routes
+	alertsGroup.GET("/rules", alertAPIHandler.GetRules)          // GET /api/alerts/rules
+	alertsGroup.POST("/rules", alertAPIHandler.CreateRule)       // POST /api/alerts/rules
+	alertsGroup.PUT("/rules/:id", alertAPIHandler.UpdateRule)    // PUT /api/alerts/rules/:id
+	alertsGroup.DELETE("/rules/:id", alertAPIHandler.DeleteRule) // DELETE /api/alerts/rules/:id
+}
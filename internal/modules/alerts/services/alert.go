@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-starter/internal/modules/alerts/dto"
+	"go-starter/internal/modules/alerts/models"
+	"go-starter/internal/modules/alerts/repositories"
+	shipmentModels "go-starter/internal/modules/shipments/models"
+	"go-starter/internal/modules/shipments/types"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AlertService struct {
+	alertRepo *repositories.AlertRepository
+}
+
+func NewAlertService(alertRepo *repositories.AlertRepository) *AlertService {
+	return &AlertService{
+		alertRepo: alertRepo,
+	}
+}
+
+// HandleShipmentSync evaluates the rules of all users tracking the synced shipment and
+// raises the resulting alerts. It is registered as a shipment sync listener.
+func (s *AlertService) HandleShipmentSync(ctx context.Context, event types.ShipmentSyncEvent) {
+	rules, err := s.alertRepo.GetRulesForShipment(ctx, event.ShipmentID)
+	if err != nil {
+		log.Printf("Warning: Failed to load alert rules for shipment %s: %v", event.ShipmentID, err)
+		return
+	}
+
+	baselines, err := s.alertRepo.GetETABaselines(ctx, event.ShipmentID)
+	if err != nil {
+		log.Printf("Warning: Failed to load ETA baselines for shipment %s: %v", event.ShipmentID, err)
+		return
+	}
+
+	now := time.Now()
+	for _, rule := range rules {
+		var baselineETA *time.Time
+		if baseline, ok := baselines[rule.ID]; ok {
+			baselineETA = &baseline
+		}
+
+		candidates := EvaluateRule(rule, event, baselineETA, now)
+		for _, candidate := range candidates {
+			ruleID := rule.ID
+			if _, err := s.CreateAlert(ctx, rule.UserID, event.ShipmentID, &ruleID, candidate); err != nil {
+				log.Printf("Warning: Failed to raise %s alert for shipment %s: %v", rule.Type, event.ShipmentID, err)
+			}
+		}
+
+		if rule.Type != models.RuleTypeETASlip {
+			continue
+		}
+		if next := NextETABaseline(event, baselineETA, len(candidates) > 0); next != nil {
+			if err := s.alertRepo.SaveETABaseline(ctx, rule.ID, event.ShipmentID, *next); err != nil {
+				log.Printf("Warning: Failed to save ETA baseline for shipment %s: %v", event.ShipmentID, err)
+			}
+		}
+	}
+}
+
+// CreateAlert raises an alert for a user unless the shipment is muted. An alert with the
+// same dedup key is not raised again, it reports whether a new alert was created.
+func (s *AlertService) CreateAlert(ctx context.Context, userID, shipmentID uuid.UUID, ruleID *uuid.UUID, candidate AlertCandidate) (bool, error) {
+	now := time.Now()
+
+	muted, err := s.alertRepo.IsShipmentMuted(ctx, userID, shipmentID, now)
+	if err != nil {
+		return false, fmt.Errorf("error checking shipment mute: %w", err)
+	}
+	if muted {
+		return false, nil
+	}
+
+	alert := &models.Alert{
+		UserID:          userID,
+		ShipmentID:      shipmentID,
+		RuleID:          ruleID,
+		Type:            candidate.Type,
+		Title:           candidate.Title,
+		Message:         candidate.Message,
+		DedupKey:        candidate.DedupKey,
+		LastTriggeredAt: now,
+	}
+
+	created, err := s.alertRepo.UpsertAlert(ctx, alert)
+	if err != nil {
+		return false, err
+	}
+	if created {
+		log.Printf("Raised %s alert for user %s on shipment %s: %s", alert.Type, userID, shipmentID, alert.Title)
+		alertListeners.notify(ctx, *alert)
+	}
+
+	return created, nil
+}
+
+// CreateRule validates and creates a new alert rule
+func (s *AlertService) CreateRule(ctx context.Context, userID uuid.UUID, req *dto.SaveRuleRequest) (*models.AlertRule, error) {
+	rule := &models.AlertRule{UserID: userID}
+	if err := s.applyRuleRequest(ctx, userID, rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.alertRepo.CreateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("error creating rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// GetRules retrieves all alert rules of a user
+func (s *AlertService) GetRules(ctx context.Context, userID uuid.UUID) (*dto.RulesListResponse, error) {
+	rules, err := s.alertRepo.GetRulesByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving rules: %w", err)
+	}
+
+	return &dto.RulesListResponse{
+		Rules: rules,
+		Total: len(rules),
+	}, nil
+}
+
+// UpdateRule validates and updates an existing alert rule
+func (s *AlertService) UpdateRule(ctx context.Context, userID, ruleID uuid.UUID, req *dto.SaveRuleRequest) (*models.AlertRule, error) {
+	rule, err := s.alertRepo.GetRuleByID(ctx, ruleID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("rule not found")
+		}
+		return nil, fmt.Errorf("error retrieving rule: %w", err)
+	}
+
+	if err := s.applyRuleRequest(ctx, userID, rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.alertRepo.UpdateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("error updating rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// DeleteRule deletes an alert rule, alerts it raised are kept
+func (s *AlertService) DeleteRule(ctx context.Context, userID, ruleID uuid.UUID) error {
+	if err := s.alertRepo.DeleteRule(ctx, ruleID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("rule not found")
+		}
+		return fmt.Errorf("error deleting rule: %w", err)
+	}
+
+	return nil
+}
+
+// applyRuleRequest validates the request for its rule type and copies it onto the rule
+func (s *AlertService) applyRuleRequest(ctx context.Context, userID uuid.UUID, rule *models.AlertRule, req *dto.SaveRuleRequest) error {
+	if !req.Type.IsValid() {
+		return fmt.Errorf("invalid rule type '%s'", req.Type)
+	}
+
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.Type = req.Type
+	rule.ShipmentID = req.ShipmentID
+	rule.Days = 0
+	rule.EventCode = ""
+	rule.Status = ""
+
+	switch req.Type {
+	case models.RuleTypeETASlip:
+		rule.Days = req.Days
+	case models.RuleTypeNoEvents:
+		if req.Days < 1 {
+			return fmt.Errorf("invalid rule: days must be at least 1")
+		}
+		rule.Days = req.Days
+	case models.RuleTypeContainerEvent:
+		rule.EventCode = strings.ToUpper(strings.TrimSpace(req.EventCode))
+		if rule.EventCode == "" {
+			return fmt.Errorf("invalid rule: event_code is required")
+		}
+	case models.RuleTypeStatusChange:
+		if req.Status != "" {
+			status := shipmentModels.NormalizeShipmentStatus(req.Status)
+			if status == shipmentModels.ShipmentStatusUnknown {
+				return fmt.Errorf("invalid rule: unknown status '%s'", req.Status)
+			}
+			rule.Status = string(status)
+		}
+	}
+
+	rule.Enabled = true
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+
+	if rule.ShipmentID != nil {
+		tracks, err := s.alertRepo.UserTracksShipment(ctx, userID, *rule.ShipmentID)
+		if err != nil {
+			return fmt.Errorf("error checking shipment: %w", err)
+		}
+		if !tracks {
+			return fmt.Errorf("shipment not found")
+		}
+	}
+
+	return nil
+}
+
+// GetAlerts retrieves the alerts of a user. Snoozed alerts are left out unless requested.
+func (s *AlertService) GetAlerts(ctx context.Context, userID uuid.UUID, filter repositories.AlertListFilter) (*dto.AlertsListResponse, error) {
+	alerts, err := s.alertRepo.GetAlertsByUserID(ctx, userID, filter, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving alerts: %w", err)
+	}
+
+	return &dto.AlertsListResponse{
+		Alerts: alerts,
+		Total:  len(alerts),
+	}, nil
+}
+
+// AcknowledgeAlert marks an alert as acknowledged
+func (s *AlertService) AcknowledgeAlert(ctx context.Context, userID, alertID uuid.UUID) (*models.Alert, error) {
+	err := s.alertRepo.UpdateAlertFields(ctx, alertID, userID, map[string]interface{}{
+		"status":          models.AlertStatusAcknowledged,
+		"acknowledged_at": time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("alert not found")
+		}
+		return nil, fmt.Errorf("error acknowledging alert: %w", err)
+	}
+
+	return s.getAlert(ctx, userID, alertID)
+}
+
+// AcknowledgeShipmentAlerts acknowledges all open alerts of a shipment
+func (s *AlertService) AcknowledgeShipmentAlerts(ctx context.Context, userID, shipmentID uuid.UUID) (int64, error) {
+	count, err := s.alertRepo.AcknowledgeShipmentAlerts(ctx, userID, shipmentID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("error acknowledging alerts: %w", err)
+	}
+
+	return count, nil
+}
+
+// SnoozeAlert hides an alert until the given time
+func (s *AlertService) SnoozeAlert(ctx context.Context, userID, alertID uuid.UUID, until time.Time) (*models.Alert, error) {
+	if !until.After(time.Now()) {
+		return nil, fmt.Errorf("invalid snooze: time must be in the future")
+	}
+
+	err := s.alertRepo.UpdateAlertFields(ctx, alertID, userID, map[string]interface{}{
+		"snoozed_until": until,
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("alert not found")
+		}
+		return nil, fmt.Errorf("error snoozing alert: %w", err)
+	}
+
+	return s.getAlert(ctx, userID, alertID)
+}
+
+func (s *AlertService) getAlert(ctx context.Context, userID, alertID uuid.UUID) (*models.Alert, error) {
+	alert, err := s.alertRepo.GetAlertByID(ctx, alertID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("alert not found")
+		}
+		return nil, fmt.Errorf("error retrieving alert: %w", err)
+	}
+
+	return alert, nil
+}
+
+// MuteShipment stops alerts from being raised for a shipment, forever or until the given time
+func (s *AlertService) MuteShipment(ctx context.Context, userID, shipmentID uuid.UUID, until *time.Time) (*models.AlertMute, error) {
+	if until != nil && !until.After(time.Now()) {
+		return nil, fmt.Errorf("invalid mute: time must be in the future")
+	}
+
+	tracks, err := s.alertRepo.UserTracksShipment(ctx, userID, shipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking shipment: %w", err)
+	}
+	if !tracks {
+		return nil, fmt.Errorf("shipment not found")
+	}
+
+	mute := &models.AlertMute{
+		UserID:     userID,
+		ShipmentID: shipmentID,
+		MutedUntil: until,
+	}
+	if err := s.alertRepo.UpsertMute(ctx, mute); err != nil {
+		return nil, fmt.Errorf("error muting shipment: %w", err)
+	}
+
+	return mute, nil
+}
+
+// UnmuteShipment lets alerts be raised for a shipment again
+func (s *AlertService) UnmuteShipment(ctx context.Context, userID, shipmentID uuid.UUID) error {
+	if err := s.alertRepo.DeleteMute(ctx, userID, shipmentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("mute not found")
+		}
+		return fmt.Errorf("error unmuting shipment: %w", err)
+	}
+
+	return nil
+}
+
+// GetMutes retrieves the shipments a user has currently muted
+func (s *AlertService) GetMutes(ctx context.Context, userID uuid.UUID) (*dto.MutesListResponse, error) {
+	mutes, err := s.alertRepo.GetMutesByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving mutes: %w", err)
+	}
+
+	return &dto.MutesListResponse{
+		Mutes: mutes,
+		Total: len(mutes),
+	}, nil
+}
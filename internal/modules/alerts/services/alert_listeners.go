@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"go-starter/internal/modules/alerts/models"
+)
+
+// AlertListener is notified when a new alert was raised. Duplicates of an existing alert
+// and alerts of muted shipments are not passed on.
+type AlertListener func(ctx context.Context, alert models.Alert)
+
+// alertListeners are shared by every alert service instance
+var alertListeners = &alertListenerRegistry{}
+
+type alertListenerRegistry struct {
+	mu        sync.RWMutex
+	listeners []AlertListener
+}
+
+// RegisterAlertListener adds a listener that is called for every new alert
+func RegisterAlertListener(listener AlertListener) {
+	alertListeners.mu.Lock()
+	defer alertListeners.mu.Unlock()
+	alertListeners.listeners = append(alertListeners.listeners, listener)
+}
+
+// notify calls all listeners in registration order, a panicking listener doesn't keep the others from running
+func (r *alertListenerRegistry) notify(ctx context.Context, alert models.Alert) {
+	r.mu.RLock()
+	listeners := append([]AlertListener(nil), r.listeners...)
+	r.mu.RUnlock()
+
+	for _, listener := range listeners {
+		func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					log.Printf("Warning: Alert listener panicked for alert %s: %v", alert.ID, rec)
+				}
+			}()
+			listener(ctx, alert)
+		}()
+	}
+}
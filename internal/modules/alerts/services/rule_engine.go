@@ -0,0 +1,152 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-starter/internal/modules/alerts/models"
+	shipmentModels "go-starter/internal/modules/shipments/models"
+	"go-starter/internal/modules/shipments/types"
+)
+
+// AlertCandidate is an alert a rule produced for a sync, before de-duplication and muting
+type AlertCandidate struct {
+	Type     models.RuleType
+	DedupKey string
+	Title    string
+	Message  string
+}
+
+// EvaluateRule checks a rule against a shipment sync. Rules about changes need the state
+// before the sync, so for newly created shipments only no_events rules can match.
+// eta_slip rules measure the slip against baselineETA, or the ETA before the sync if the
+// rule has no baseline for the shipment yet.
+// Dedup keys identify the condition that fired, e.g. the new ETA or the container event,
+// so repeated syncs of an unchanged shipment don't raise the same alert twice.
+func EvaluateRule(rule models.AlertRule, event types.ShipmentSyncEvent, baselineETA *time.Time, now time.Time) []AlertCandidate {
+	after := event.After
+	if after == nil {
+		return nil
+	}
+	before := event.Before
+	prefix := fmt.Sprintf("%s:%s:%s", rule.Type, rule.ID, event.ShipmentID)
+
+	switch rule.Type {
+	case models.RuleTypeETASlip:
+		if baselineETA == nil && before != nil {
+			baselineETA = before.PodETA
+		}
+		if baselineETA == nil || after.PodETA == nil || after.PodActual {
+			return nil
+		}
+		slip := after.PodETA.Sub(*baselineETA)
+		if slip <= time.Duration(rule.Days)*24*time.Hour {
+			return nil
+		}
+		return []AlertCandidate{{
+			Type:     rule.Type,
+			DedupKey: fmt.Sprintf("%s:%d", prefix, after.PodETA.Unix()),
+			Title:    fmt.Sprintf("ETA of %s slipped by %s", after.ShipmentNumber, formatDays(slip)),
+			Message: fmt.Sprintf("The ETA at the port of discharge moved from %s to %s.",
+				baselineETA.Format("2006-01-02"), after.PodETA.Format("2006-01-02")),
+		}}
+
+	case models.RuleTypeStatusChange:
+		if before == nil || before.ShippingStatus == after.ShippingStatus {
+			return nil
+		}
+		if rule.Status != "" && shipmentModels.NormalizeShipmentStatus(rule.Status) != shipmentModels.ShipmentStatus(after.ShippingStatus) {
+			return nil
+		}
+		return []AlertCandidate{{
+			Type:     rule.Type,
+			DedupKey: fmt.Sprintf("%s:%s:%s", prefix, before.ShippingStatus, after.ShippingStatus),
+			Title:    fmt.Sprintf("%s is now %s", after.ShipmentNumber, after.ShippingStatus),
+			Message:  fmt.Sprintf("The shipping status changed from %s to %s.", before.ShippingStatus, after.ShippingStatus),
+		}}
+
+	case models.RuleTypeContainerEvent:
+		if before == nil || rule.EventCode == "" {
+			return nil
+		}
+		var candidates []AlertCandidate
+		for _, containerEvent := range after.ContainerEvents {
+			if !containerEvent.IsActual || !strings.EqualFold(containerEvent.EventCode, rule.EventCode) {
+				continue
+			}
+			if before.HasEvent(containerEvent.ContainerNumber, containerEvent.EventCode, containerEvent.Date) {
+				continue
+			}
+			candidates = append(candidates, AlertCandidate{
+				Type:     rule.Type,
+				DedupKey: fmt.Sprintf("%s:%s:%s:%d", prefix, containerEvent.ContainerNumber, containerEvent.EventCode, containerEvent.Date.Unix()),
+				Title:    fmt.Sprintf("%s: %s", containerEvent.ContainerNumber, containerEvent.Description),
+				Message: fmt.Sprintf("Container %s of %s reached %s (%s) at %s on %s.",
+					containerEvent.ContainerNumber, after.ShipmentNumber, containerEvent.EventCode,
+					containerEvent.Description, containerEvent.LocationName, containerEvent.Date.Format("2006-01-02")),
+			})
+		}
+		return candidates
+
+	case models.RuleTypeVesselArrival:
+		if before == nil || before.PodActual || !after.PodActual {
+			return nil
+		}
+		arrivedAt := "recently"
+		if after.PodETA != nil {
+			arrivedAt = "on " + after.PodETA.Format("2006-01-02")
+		}
+		return []AlertCandidate{{
+			Type:     rule.Type,
+			DedupKey: prefix,
+			Title:    fmt.Sprintf("%s arrived at the port of discharge", after.ShipmentNumber),
+			Message:  fmt.Sprintf("The vessel arrived at %s %s.", after.PodLocode, arrivedAt),
+		}}
+
+	case models.RuleTypeNoEvents:
+		if after.LastEventAt == nil || shipmentModels.ShipmentStatus(after.ShippingStatus).IsTerminal() {
+			return nil
+		}
+		silence := now.Sub(*after.LastEventAt)
+		if silence <= time.Duration(rule.Days)*24*time.Hour {
+			return nil
+		}
+		return []AlertCandidate{{
+			Type:     rule.Type,
+			DedupKey: fmt.Sprintf("%s:%d", prefix, after.LastEventAt.Unix()),
+			Title:    fmt.Sprintf("No events for %s in %s", after.ShipmentNumber, formatDays(silence)),
+			Message:  fmt.Sprintf("The last container event was on %s.", after.LastEventAt.Format("2006-01-02")),
+		}}
+	}
+
+	return nil
+}
+
+// NextETABaseline returns the ETA baseline an eta_slip rule keeps after a sync, nil if it
+// stays as it is. The first ETA seen becomes the baseline, a rule that fired moves it to
+// the new ETA so the next alert needs another slip of the threshold.
+func NextETABaseline(event types.ShipmentSyncEvent, baselineETA *time.Time, fired bool) *time.Time {
+	if event.After == nil {
+		return nil
+	}
+	if fired {
+		return event.After.PodETA
+	}
+	if baselineETA != nil {
+		return nil
+	}
+	if event.Before != nil && event.Before.PodETA != nil {
+		return event.Before.PodETA
+	}
+	return event.After.PodETA
+}
+
+// formatDays formats a duration as whole days
+func formatDays(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"go-starter/internal/modules/alerts/models"
+	"go-starter/internal/modules/shipments/types"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEvaluateRule(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	shipmentID := uuid.New()
+
+	at := func(d time.Duration) *time.Time {
+		v := now.Add(d)
+		return &v
+	}
+	day := 24 * time.Hour
+	discharge := types.ContainerEventSnapshot{
+		ContainerNumber: "MSCU1234567",
+		EventCode:       "DISC",
+		Description:     "Discharge",
+		Date:            now.Add(-day),
+		IsActual:        true,
+	}
+
+	tests := []struct {
+		name   string
+		rule   models.AlertRule
+		before *types.ShipmentSnapshot
+		after  types.ShipmentSnapshot
+		want   int
+	}{
+		{
+			name:   "eta slipped more than threshold",
+			rule:   models.AlertRule{Type: models.RuleTypeETASlip, Days: 2},
+			before: &types.ShipmentSnapshot{PodETA: at(5 * day)},
+			after:  types.ShipmentSnapshot{PodETA: at(8 * day)},
+			want:   1,
+		},
+		{
+			name:   "eta slipped within threshold",
+			rule:   models.AlertRule{Type: models.RuleTypeETASlip, Days: 2},
+			before: &types.ShipmentSnapshot{PodETA: at(5 * day)},
+			after:  types.ShipmentSnapshot{PodETA: at(6 * day)},
+			want:   0,
+		},
+		{
+			name:   "eta moved earlier",
+			rule:   models.AlertRule{Type: models.RuleTypeETASlip},
+			before: &types.ShipmentSnapshot{PodETA: at(5 * day)},
+			after:  types.ShipmentSnapshot{PodETA: at(3 * day)},
+			want:   0,
+		},
+		{
+			name:  "eta slip on new shipment",
+			rule:  models.AlertRule{Type: models.RuleTypeETASlip},
+			after: types.ShipmentSnapshot{PodETA: at(8 * day)},
+			want:  0,
+		},
+		{
+			name:   "status changed",
+			rule:   models.AlertRule{Type: models.RuleTypeStatusChange},
+			before: &types.ShipmentSnapshot{ShippingStatus: "IN_TRANSIT"},
+			after:  types.ShipmentSnapshot{ShippingStatus: "DELIVERED"},
+			want:   1,
+		},
+		{
+			name:   "status changed to other status than watched",
+			rule:   models.AlertRule{Type: models.RuleTypeStatusChange, Status: "CANCELLED"},
+			before: &types.ShipmentSnapshot{ShippingStatus: "IN_TRANSIT"},
+			after:  types.ShipmentSnapshot{ShippingStatus: "DELIVERED"},
+			want:   0,
+		},
+		{
+			name:   "status unchanged",
+			rule:   models.AlertRule{Type: models.RuleTypeStatusChange},
+			before: &types.ShipmentSnapshot{ShippingStatus: "IN_TRANSIT"},
+			after:  types.ShipmentSnapshot{ShippingStatus: "IN_TRANSIT"},
+			want:   0,
+		},
+		{
+			name:   "container reached event",
+			rule:   models.AlertRule{Type: models.RuleTypeContainerEvent, EventCode: "disc"},
+			before: &types.ShipmentSnapshot{},
+			after:  types.ShipmentSnapshot{ContainerEvents: []types.ContainerEventSnapshot{discharge}},
+			want:   1,
+		},
+		{
+			name:   "container event already seen",
+			rule:   models.AlertRule{Type: models.RuleTypeContainerEvent, EventCode: "DISC"},
+			before: &types.ShipmentSnapshot{ContainerEvents: []types.ContainerEventSnapshot{discharge}},
+			after:  types.ShipmentSnapshot{ContainerEvents: []types.ContainerEventSnapshot{discharge}},
+			want:   0,
+		},
+		{
+			name:   "vessel arrived",
+			rule:   models.AlertRule{Type: models.RuleTypeVesselArrival},
+			before: &types.ShipmentSnapshot{PodActual: false},
+			after:  types.ShipmentSnapshot{PodActual: true, PodETA: at(-day)},
+			want:   1,
+		},
+		{
+			name:   "vessel arrived before",
+			rule:   models.AlertRule{Type: models.RuleTypeVesselArrival},
+			before: &types.ShipmentSnapshot{PodActual: true},
+			after:  types.ShipmentSnapshot{PodActual: true},
+			want:   0,
+		},
+		{
+			name:  "no events for longer than threshold",
+			rule:  models.AlertRule{Type: models.RuleTypeNoEvents, Days: 7},
+			after: types.ShipmentSnapshot{ShippingStatus: "IN_TRANSIT", LastEventAt: at(-10 * day)},
+			want:  1,
+		},
+		{
+			name:  "no events but shipment delivered",
+			rule:  models.AlertRule{Type: models.RuleTypeNoEvents, Days: 7},
+			after: types.ShipmentSnapshot{ShippingStatus: "DELIVERED", LastEventAt: at(-10 * day)},
+			want:  0,
+		},
+		{
+			name:  "recent events",
+			rule:  models.AlertRule{Type: models.RuleTypeNoEvents, Days: 7},
+			after: types.ShipmentSnapshot{ShippingStatus: "IN_TRANSIT", LastEventAt: at(-3 * day)},
+			want:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.ID = uuid.New()
+			after := tt.after
+			event := types.ShipmentSyncEvent{ShipmentID: shipmentID, Before: tt.before, After: &after}
+
+			got := EvaluateRule(tt.rule, event, nil, now)
+			if len(got) != tt.want {
+				t.Fatalf("Expected %d alerts, got %d: %+v", tt.want, len(got), got)
+			}
+			for _, candidate := range got {
+				if candidate.DedupKey == "" || candidate.Title == "" {
+					t.Errorf("Expected dedup key and title to be set, got %+v", candidate)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateRule_DedupKeyIsStable(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	lastEvent := now.Add(-10 * 24 * time.Hour)
+	rule := models.AlertRule{ID: uuid.New(), Type: models.RuleTypeNoEvents, Days: 7}
+	event := types.ShipmentSyncEvent{
+		ShipmentID: uuid.New(),
+		After:      &types.ShipmentSnapshot{ShippingStatus: "IN_TRANSIT", LastEventAt: &lastEvent},
+	}
+
+	first := EvaluateRule(rule, event, nil, now)
+	second := EvaluateRule(rule, event, nil, now.Add(6*time.Hour))
+	if len(first) != 1 || len(second) != 1 {
+		t.Fatalf("Expected one alert per evaluation, got %d and %d", len(first), len(second))
+	}
+	if first[0].DedupKey != second[0].DedupKey {
+		t.Errorf("Expected the same dedup key for an unchanged condition, got %q and %q", first[0].DedupKey, second[0].DedupKey)
+	}
+}
+
+func TestEvaluateRule_ETASlipAddsUpOverSyncs(t *testing.T) {
+	day := 24 * time.Hour
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	rule := models.AlertRule{ID: uuid.New(), Type: models.RuleTypeETASlip, Days: 3}
+	shipmentID := uuid.New()
+
+	// The ETA slips a day with every daily sync, no single sync moves it by more than 3 days
+	var baseline *time.Time
+	var fired []int
+	eta := now.Add(20 * day)
+	for sync := 1; sync <= 8; sync++ {
+		before := eta
+		eta = eta.Add(day)
+		after := eta
+		event := types.ShipmentSyncEvent{
+			ShipmentID: shipmentID,
+			Before:     &types.ShipmentSnapshot{PodETA: &before},
+			After:      &types.ShipmentSnapshot{PodETA: &after},
+		}
+
+		candidates := EvaluateRule(rule, event, baseline, now.Add(time.Duration(sync)*day))
+		if len(candidates) > 0 {
+			fired = append(fired, sync)
+		}
+		if next := NextETABaseline(event, baseline, len(candidates) > 0); next != nil {
+			baseline = next
+		}
+	}
+
+	// Syncs 4 and 8 are the first to be more than 3 days past the baseline
+	if len(fired) != 2 || fired[0] != 4 || fired[1] != 8 {
+		t.Errorf("Expected alerts on syncs 4 and 8, got %v", fired)
+	}
+}
@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"go-starter/internal/modules/auth/services"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminMiddleware only lets users listed in adminEmails through.
+// It has to run after JWTMiddleware, which puts the user claims in the context.
+func AdminMiddleware(adminEmails []string) echo.MiddlewareFunc {
+	admins := make(map[string]struct{}, len(adminEmails))
+	for _, email := range adminEmails {
+		admins[strings.ToLower(email)] = struct{}{}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			email, err := services.GetUserEmailFromContext(c)
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "Invalid or expired token",
+				})
+			}
+
+			if _, ok := admins[strings.ToLower(email)]; !ok {
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error": "admin access required",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -107,3 +107,17 @@ func GetUserIDFromContext(c echo.Context) (uuid.UUID, error) {
 
 	return claims.UserID, nil
 }
+
+func GetUserEmailFromContext(c echo.Context) (string, error) {
+	user := c.Get("user")
+	if user == nil {
+		return "", fmt.Errorf("user not found in context")
+	}
+
+	claims, ok := user.(*Claims)
+	if !ok {
+		return "", fmt.Errorf("invalid user claims in context")
+	}
+
+	return claims.Email, nil
+}
@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"time"
+
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+
+	"github.com/google/uuid"
+)
+
+// TrackContainerRequest represents the request to track a container on its own
+type TrackContainerRequest struct {
+	ContainerNumber string `json:"containerNumber" validate:"required"`
+	SealineCode     string `json:"sealineCode" validate:"omitempty,len=4,alpha"`
+}
+
+// ContainerEventResponse is the last event of a container
+type ContainerEventResponse struct {
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	Location    string    `json:"location"`
+	Locode      string    `json:"locode"`
+	Date        time.Time `json:"date"`
+	IsActual    bool      `json:"isActual"`
+}
+
+// ContainerSummaryResponse is a container on shipments of the user
+type ContainerSummaryResponse struct {
+	Number    string                  `json:"number"`
+	IsoCode   string                  `json:"isoCode"`
+	SizeType  string                  `json:"sizeType"`
+	Status    string                  `json:"status"`
+	LastEvent *ContainerEventResponse `json:"lastEvent"`
+	Shipments int                     `json:"shipments"`
+}
+
+// ContainersListResponse represents the response when searching containers
+type ContainersListResponse struct {
+	Containers []ContainerSummaryResponse `json:"containers"`
+	Total      int                        `json:"total"`
+}
+
+// ContainerShipmentResponse is a shipment of the user a container appears in
+type ContainerShipmentResponse struct {
+	ShipmentID     uuid.UUID  `json:"shipmentId"`
+	ShipmentNumber string     `json:"shipmentNumber"`
+	ShipmentType   string     `json:"shipmentType"`
+	SealineName    string     `json:"sealineName"`
+	ShippingStatus string     `json:"shippingStatus"`
+	Pol            string     `json:"pol"`
+	PolDate        *time.Time `json:"polDate"`
+	Pod            string     `json:"pod"`
+	PodDate        *time.Time `json:"podDate"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// ContainerTimelineResponse is the event timeline of a container, oldest event first
+type ContainerTimelineResponse struct {
+	Number string                                       `json:"number"`
+	Events []shipmentDto.ShipmentContainerEventResponse `json:"events"`
+}
+
+// ContainerShipmentsResponse lists the shipments of the user a container appears in, newest first
+type ContainerShipmentsResponse struct {
+	Number    string                      `json:"number"`
+	Shipments []ContainerShipmentResponse `json:"shipments"`
+}
+
+// ContainerDetailsResponse is a container with its timeline and the shipments it appears in
+type ContainerDetailsResponse struct {
+	Container ContainerSummaryResponse                     `json:"container"`
+	Events    []shipmentDto.ShipmentContainerEventResponse `json:"events"`
+	Shipments []ContainerShipmentResponse                  `json:"shipments"`
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"go-starter/internal/modules/auth/services"
+	"go-starter/internal/modules/containers/dto"
+	containerServices "go-starter/internal/modules/containers/services"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// minSearchLength is how many characters of a container number a search needs
+const minSearchLength = 3
+
+type ContainerAPIHandler struct {
+	containerService *containerServices.ContainerService
+	validator        *validator.Validate
+}
+
+func NewContainerAPIHandler(containerService *containerServices.ContainerService) *ContainerAPIHandler {
+	return &ContainerAPIHandler{
+		containerService: containerService,
+		validator:        validator.New(),
+	}
+}
+
+// SearchContainers handles GET /api/containers
+func (h *ContainerAPIHandler) SearchContainers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	query := containerServices.NormalizeContainerNumber(c.QueryParam("q"))
+	if len(query) < minSearchLength {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Search needs at least 3 characters of the container number",
+		})
+	}
+
+	response, err := h.containerService.SearchContainers(ctx, userID, query)
+	if err != nil {
+		return h.serviceError(c, "Failed to search containers", err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetContainer handles GET /api/containers/:number
+func (h *ContainerAPIHandler) GetContainer(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	container, err := h.containerService.GetContainer(ctx, userID, c.Param("number"))
+	if err != nil {
+		return h.serviceError(c, "Failed to retrieve container", err)
+	}
+
+	return c.JSON(http.StatusOK, container)
+}
+
+// GetContainerEvents handles GET /api/containers/:number/events
+func (h *ContainerAPIHandler) GetContainerEvents(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	timeline, err := h.containerService.GetContainerEvents(ctx, userID, c.Param("number"))
+	if err != nil {
+		return h.serviceError(c, "Failed to retrieve container events", err)
+	}
+
+	return c.JSON(http.StatusOK, timeline)
+}
+
+// GetContainerShipments handles GET /api/containers/:number/shipments
+func (h *ContainerAPIHandler) GetContainerShipments(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	shipments, err := h.containerService.GetContainerShipments(ctx, userID, c.Param("number"))
+	if err != nil {
+		return h.serviceError(c, "Failed to retrieve container shipments", err)
+	}
+
+	return c.JSON(http.StatusOK, shipments)
+}
+
+// TrackContainer handles POST /api/containers/track
+func (h *ContainerAPIHandler) TrackContainer(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.TrackContainerRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+	req.SealineCode = strings.ToUpper(strings.TrimSpace(req.SealineCode))
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Validation failed: " + err.Error(),
+		})
+	}
+
+	container, err := h.containerService.TrackContainer(ctx, userID, &req)
+	if err != nil {
+		return h.serviceError(c, "Failed to track container", err)
+	}
+
+	return c.JSON(http.StatusCreated, container)
+}
+
+// serviceError maps service errors to status codes, validation errors are returned as is
+func (h *ContainerAPIHandler) serviceError(c echo.Context, message string, err error) error {
+	switch {
+	case strings.Contains(err.Error(), "already tracking"):
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "You are already tracking this container",
+		})
+	case strings.Contains(err.Error(), "rate limit"):
+		return c.JSON(http.StatusTooManyRequests, map[string]string{
+			"error": "API rate limit exceeded. Please try again later",
+		})
+	case strings.Contains(err.Error(), "not found"):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	case strings.HasPrefix(err.Error(), "invalid"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message + ": " + err.Error(),
+	})
+}
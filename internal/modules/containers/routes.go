@@ -0,0 +1,29 @@
+package containers
+
+import (
+	"go-starter/internal/modules/auth/middlewares"
+	"go-starter/internal/modules/auth/services"
+	"go-starter/internal/modules/containers/handlers"
+	containerServices "go-starter/internal/modules/containers/services"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRoutes(api *echo.Group, shipmentService shipmentServices.ShipmentService) {
+	// Initialize dependencies
+	containerService := containerServices.NewContainerService(shipmentService)
+	containerAPIHandler := handlers.NewContainerAPIHandler(containerService)
+
+	// Create JWT service for middleware
+	jwtService := services.NewJWTService()
+
+	// Create containers group with JWT middleware
+	containersGroup := api.Group("/containers", middlewares.JWTMiddleware(jwtService))
+
+	containersGroup.GET("", containerAPIHandler.SearchContainers)                        // GET /api/containers?q=
+	containersGroup.POST("/track", containerAPIHandler.TrackContainer)                   // POST /api/containers/track
+	containersGroup.GET("/:number", containerAPIHandler.GetContainer)                    // GET /api/containers/:number
+	containersGroup.GET("/:number/events", containerAPIHandler.GetContainerEvents)       // GET /api/containers/:number/events
+	containersGroup.GET("/:number/shipments", containerAPIHandler.GetContainerShipments) // GET /api/containers/:number/shipments
+}
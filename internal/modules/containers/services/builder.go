@@ -0,0 +1,172 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"go-starter/internal/modules/containers/dto"
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+)
+
+// containerView collects what the user's shipments tell about one container
+type containerView struct {
+	container shipmentDto.ShipmentContainerResponse
+	events    []shipmentDto.ShipmentContainerEventResponse
+	seen      map[string]bool
+	shipments []dto.ContainerShipmentResponse
+	updatedAt time.Time
+}
+
+// BuildContainers groups the user's shipments by container number. Events are stored per
+// container, so a container on several bookings carries its events on each of them, they
+// are merged into one timeline. Master data comes from the most recently updated shipment.
+func BuildContainers(rows []shipmentDto.ShipmentDetailsResponse) map[string]*dto.ContainerDetailsResponse {
+	views := map[string]*containerView{}
+	for _, row := range rows {
+		for _, container := range row.Containers {
+			number := NormalizeContainerNumber(container.Number)
+			if number == "" {
+				continue
+			}
+			v, ok := views[number]
+			if !ok {
+				v = &containerView{seen: map[string]bool{}}
+				views[number] = v
+			}
+			if !ok || row.UpdatedAt.After(v.updatedAt) {
+				v.container, v.updatedAt = container, row.UpdatedAt
+				v.container.Number = number
+			}
+			for _, event := range container.Events {
+				key := eventKey(event)
+				if !v.seen[key] {
+					v.seen[key] = true
+					v.events = append(v.events, event)
+				}
+			}
+			v.shipments = append(v.shipments, containerShipment(row))
+		}
+	}
+
+	containers := make(map[string]*dto.ContainerDetailsResponse, len(views))
+	for number, v := range views {
+		containers[number] = v.details()
+	}
+	return containers
+}
+
+// SearchSummaries lists the containers whose number contains the query, the best matches
+// first: exact, then prefix, then the rest in number order
+func SearchSummaries(containers map[string]*dto.ContainerDetailsResponse, query string) []dto.ContainerSummaryResponse {
+	query = NormalizeContainerNumber(query)
+	summaries := []dto.ContainerSummaryResponse{}
+	for number, container := range containers {
+		if strings.Contains(number, query) {
+			summaries = append(summaries, container.Container)
+		}
+	}
+
+	rank := func(number string) int {
+		switch {
+		case number == query:
+			return 0
+		case strings.HasPrefix(number, query):
+			return 1
+		}
+		return 2
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := rank(summaries[i].Number), rank(summaries[j].Number)
+		if a != b {
+			return a < b
+		}
+		return summaries[i].Number < summaries[j].Number
+	})
+	return summaries
+}
+
+// details orders the timeline oldest event first and the shipments newest first
+func (v *containerView) details() *dto.ContainerDetailsResponse {
+	sort.SliceStable(v.events, func(i, j int) bool {
+		return v.events[i].Date.Before(v.events[j].Date)
+	})
+	sort.SliceStable(v.shipments, func(i, j int) bool {
+		return v.shipments[i].CreatedAt.After(v.shipments[j].CreatedAt)
+	})
+
+	details := &dto.ContainerDetailsResponse{
+		Container: dto.ContainerSummaryResponse{
+			Number:    v.container.Number,
+			IsoCode:   v.container.IsoCode,
+			SizeType:  v.container.SizeType,
+			Status:    v.container.Status,
+			LastEvent: LastEvent(v.events),
+			Shipments: len(v.shipments),
+		},
+		Events:    v.events,
+		Shipments: v.shipments,
+	}
+	if details.Events == nil {
+		details.Events = []shipmentDto.ShipmentContainerEventResponse{}
+	}
+	return details
+}
+
+// LastEvent returns the latest actual event, or the first estimated one when nothing
+// happened yet
+func LastEvent(events []shipmentDto.ShipmentContainerEventResponse) *dto.ContainerEventResponse {
+	var last *shipmentDto.ShipmentContainerEventResponse
+	for i := range events {
+		event := &events[i]
+		switch {
+		case last == nil:
+			last = event
+		case event.IsActual && (!last.IsActual || event.Date.After(last.Date)):
+			last = event
+		case !event.IsActual && !last.IsActual && event.Date.Before(last.Date):
+			last = event
+		}
+	}
+	if last == nil {
+		return nil
+	}
+
+	return &dto.ContainerEventResponse{
+		Description: last.Description,
+		Status:      last.Status,
+		Location:    last.Location.Name,
+		Locode:      last.Location.Locode,
+		Date:        last.Date,
+		IsActual:    last.IsActual,
+	}
+}
+
+func containerShipment(row shipmentDto.ShipmentDetailsResponse) dto.ContainerShipmentResponse {
+	shipment := dto.ContainerShipmentResponse{
+		ShipmentID:     row.ID,
+		ShipmentNumber: row.ShipmentNumber,
+		ShipmentType:   row.ShipmentType,
+		SealineName:    row.SealineName,
+		ShippingStatus: row.ShippingStatus,
+		CreatedAt:      row.CreatedAt,
+	}
+	if pol := row.Route.Pol; pol != nil {
+		shipment.Pol, shipment.PolDate = pol.Location.Name, pol.Date
+	}
+	if pod := row.Route.Pod; pod != nil {
+		shipment.Pod, shipment.PodDate = pod.Location.Name, pod.Date
+	}
+	return shipment
+}
+
+// eventKey identifies an event across the copies of a container on several shipments
+func eventKey(event shipmentDto.ShipmentContainerEventResponse) string {
+	return strings.Join([]string{
+		event.Date.UTC().Format(time.RFC3339),
+		event.Status,
+		event.Location.Locode,
+		event.Location.Name,
+		event.Description,
+	}, "|")
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+
+	"github.com/google/uuid"
+)
+
+func containerAt(day int) time.Time {
+	return time.Date(2026, 4, day, 0, 0, 0, 0, time.UTC)
+}
+
+func containerEvent(day int, status string, actual bool) shipmentDto.ShipmentContainerEventResponse {
+	return shipmentDto.ShipmentContainerEventResponse{
+		Location:    shipmentDto.ShipmentLocationResponse{Name: "Rotterdam", Locode: "NLRTM"},
+		Description: status,
+		Status:      status,
+		Date:        containerAt(day),
+		IsActual:    actual,
+	}
+}
+
+func bookingRow(number string, created int, status string, events ...shipmentDto.ShipmentContainerEventResponse) shipmentDto.ShipmentDetailsResponse {
+	return shipmentDto.ShipmentDetailsResponse{
+		ID:             uuid.New(),
+		ShipmentNumber: number,
+		ShipmentType:   "BL",
+		CreatedAt:      containerAt(created),
+		UpdatedAt:      containerAt(created),
+		Containers: []shipmentDto.ShipmentContainerResponse{
+			{Number: "cscu 600513-2", SizeType: "40HC", Status: status, Events: events},
+		},
+	}
+}
+
+func TestBuildContainers(t *testing.T) {
+	rows := []shipmentDto.ShipmentDetailsResponse{
+		bookingRow("BL-NEW", 10, "DISCHARGED", containerEvent(12, "LOAD", true), containerEvent(20, "DISC", true), containerEvent(25, "GTOT", false)),
+		bookingRow("BL-OLD", 1, "RETURNED", containerEvent(2, "GTOT", true), containerEvent(12, "LOAD", true)),
+	}
+
+	containers := BuildContainers(rows)
+	container, ok := containers["CSCU6005132"]
+	if !ok || len(containers) != 1 {
+		t.Fatalf("containers = %v, want CSCU6005132 only", containers)
+	}
+
+	if container.Container.Status != "DISCHARGED" || container.Container.Shipments != 2 {
+		t.Errorf("summary = %+v, want the status of the newest shipment on 2 shipments", container.Container)
+	}
+	if container.Shipments[0].ShipmentNumber != "BL-NEW" || container.Shipments[1].ShipmentNumber != "BL-OLD" {
+		t.Errorf("shipments = %+v, want newest first", container.Shipments)
+	}
+
+	var statuses []string
+	for _, event := range container.Events {
+		statuses = append(statuses, event.Status)
+	}
+	if len(statuses) != 4 || statuses[0] != "GTOT" || statuses[1] != "LOAD" || statuses[3] != "GTOT" {
+		t.Errorf("timeline = %v, want GTOT LOAD DISC GTOT without the duplicate load", statuses)
+	}
+
+	last := container.Container.LastEvent
+	if last == nil || last.Status != "DISC" || !last.IsActual || last.Locode != "NLRTM" {
+		t.Errorf("last event = %+v, want the actual discharge", last)
+	}
+}
+
+func TestLastEventWithoutActualEvents(t *testing.T) {
+	last := LastEvent([]shipmentDto.ShipmentContainerEventResponse{
+		containerEvent(20, "DISC", false),
+		containerEvent(12, "LOAD", false),
+	})
+	if last == nil || last.Status != "LOAD" {
+		t.Errorf("last event = %+v, want the next planned event", last)
+	}
+	if LastEvent(nil) != nil {
+		t.Error("last event of no events isn't nil")
+	}
+}
+
+func TestSearchSummaries(t *testing.T) {
+	rows := []shipmentDto.ShipmentDetailsResponse{
+		{Containers: []shipmentDto.ShipmentContainerResponse{{Number: "XMSU1234565"}, {Number: "MSCU1234566"}, {Number: "TGHU9999999"}}},
+		{Containers: []shipmentDto.ShipmentContainerResponse{{Number: "MSCU123"}}},
+	}
+
+	summaries := SearchSummaries(BuildContainers(rows), "mscu 123")
+	if len(summaries) != 2 || summaries[0].Number != "MSCU123" || summaries[1].Number != "MSCU1234566" {
+		t.Errorf("summaries = %+v, want the exact match, then the prefix match", summaries)
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go-starter/internal/modules/containers/dto"
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+
+	"github.com/google/uuid"
+)
+
+// containerShipmentType is the SafeCube shipment type that tracks a container number
+const containerShipmentType = "CT"
+
+type ContainerService struct {
+	shipmentService shipmentServices.ShipmentService
+}
+
+func NewContainerService(shipmentService shipmentServices.ShipmentService) *ContainerService {
+	return &ContainerService{
+		shipmentService: shipmentService,
+	}
+}
+
+// SearchContainers finds the containers on the user's shipments whose number contains the query
+func (s *ContainerService) SearchContainers(ctx context.Context, userID uuid.UUID, query string) (*dto.ContainersListResponse, error) {
+	containers, err := s.buildContainers(ctx, userID, NormalizeContainerNumber(query), true)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := SearchSummaries(containers, query)
+	return &dto.ContainersListResponse{
+		Containers: summaries,
+		Total:      len(summaries),
+	}, nil
+}
+
+// GetContainer retrieves a container on the user's shipments with its timeline and shipments
+func (s *ContainerService) GetContainer(ctx context.Context, userID uuid.UUID, number string) (*dto.ContainerDetailsResponse, error) {
+	number = NormalizeContainerNumber(number)
+	containers, err := s.buildContainers(ctx, userID, number, false)
+	if err != nil {
+		return nil, err
+	}
+
+	container, ok := containers[number]
+	if !ok {
+		return nil, fmt.Errorf("container not found")
+	}
+	return container, nil
+}
+
+// GetContainerEvents retrieves the event timeline of a container on the user's shipments
+func (s *ContainerService) GetContainerEvents(ctx context.Context, userID uuid.UUID, number string) (*dto.ContainerTimelineResponse, error) {
+	container, err := s.GetContainer(ctx, userID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ContainerTimelineResponse{
+		Number: container.Container.Number,
+		Events: container.Events,
+	}, nil
+}
+
+// GetContainerShipments retrieves the user's shipments a container appears in
+func (s *ContainerService) GetContainerShipments(ctx context.Context, userID uuid.UUID, number string) (*dto.ContainerShipmentsResponse, error) {
+	container, err := s.GetContainer(ctx, userID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ContainerShipmentsResponse{
+		Number:    container.Container.Number,
+		Shipments: container.Shipments,
+	}, nil
+}
+
+// TrackContainer starts tracking a container on its own, as a shipment of type CT
+func (s *ContainerService) TrackContainer(ctx context.Context, userID uuid.UUID, req *dto.TrackContainerRequest) (*dto.ContainerDetailsResponse, error) {
+	number := NormalizeContainerNumber(req.ContainerNumber)
+	if err := ValidateContainerNumber(number); err != nil {
+		return nil, err
+	}
+
+	_, err := s.shipmentService.AddShipment(ctx, userID, &shipmentDto.AddShipmentRequest{
+		ShipmentNumber: number,
+		ShipmentType:   containerShipmentType,
+		SealineCode:    req.SealineCode,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetContainer(ctx, userID, number)
+}
+
+// buildContainers builds the containers with the normalized number, or with a number
+// containing it when partial is set, from the user's shipments holding them
+func (s *ContainerService) buildContainers(ctx context.Context, userID uuid.UUID, number string, partial bool) (map[string]*dto.ContainerDetailsResponse, error) {
+	rows, err := s.shipmentService.GetShipmentsByContainer(ctx, userID, number, partial)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipments: %w", err)
+	}
+	return BuildContainers(rows), nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalizeContainerNumber uppercases a container number and drops the spaces and dashes
+// it is often written with, as in "MSCU 123456-5"
+func NormalizeContainerNumber(number string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.ToUpper(strings.TrimSpace(number)))
+}
+
+// ValidateContainerNumber checks a normalized container number against ISO 6346: an owner
+// code of three letters, the category letter U, J or Z, six digits and the check digit
+func ValidateContainerNumber(number string) error {
+	if len(number) != 11 {
+		return fmt.Errorf("invalid container number %s, expected 4 letters and 7 digits", number)
+	}
+	for i := 0; i < 4; i++ {
+		if number[i] < 'A' || number[i] > 'Z' {
+			return fmt.Errorf("invalid container number %s, expected 4 letters and 7 digits", number)
+		}
+	}
+	if !strings.ContainsRune("UJZ", rune(number[3])) {
+		return fmt.Errorf("invalid container number %s, the 4th letter must be U, J or Z", number)
+	}
+	for i := 4; i < 11; i++ {
+		if number[i] < '0' || number[i] > '9' {
+			return fmt.Errorf("invalid container number %s, expected 4 letters and 7 digits", number)
+		}
+	}
+
+	if want := checkDigit(number[:10]); int(number[10]-'0') != want {
+		return fmt.Errorf("invalid container number %s, the check digit should be %d", number, want)
+	}
+	return nil
+}
+
+// checkDigit computes the ISO 6346 check digit. Letters count from 10 on, skipping the
+// multiples of 11, and the values are weighted by powers of two.
+func checkDigit(code string) int {
+	sum := 0
+	for i, c := range code {
+		value := int(c - '0')
+		if c >= 'A' && c <= 'Z' {
+			value = int(c-'A') + 10
+			value += (value - 1) / 10
+		}
+		sum += value << i
+	}
+	return sum % 11 % 10
+}
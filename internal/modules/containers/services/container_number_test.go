@@ -0,0 +1,31 @@
+package services
+
+import "testing"
+
+func TestNormalizeContainerNumber(t *testing.T) {
+	if got := NormalizeContainerNumber(" cscu 600513-2 "); got != "CSCU6005132" {
+		t.Errorf("NormalizeContainerNumber = %q, want CSCU6005132", got)
+	}
+}
+
+func TestValidateContainerNumber(t *testing.T) {
+	tests := []struct {
+		number string
+		valid  bool
+	}{
+		{"CSQU3054383", true},
+		{"MSCU6639870", true},
+		{"TGHU1234560", false},
+		{"CSQU3054384", false},
+		{"CSQX3054383", false},
+		{"CSQU305438", false},
+		{"CSQU30543A3", false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateContainerNumber(tt.number)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateContainerNumber(%q) = %v, want valid %v", tt.number, err, tt.valid)
+		}
+	}
+}
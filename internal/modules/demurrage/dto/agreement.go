@@ -0,0 +1,24 @@
+package dto
+
+import "go-starter/internal/modules/demurrage/models"
+
+// SaveAgreementRequest represents the request to create or update a free-time agreement
+type SaveAgreementRequest struct {
+	Name          string            `json:"name" validate:"required,max=100"`
+	ChargeType    models.ChargeType `json:"charge_type" validate:"required,oneof=demurrage detention"`
+	CarrierCode   string            `json:"carrier_code" validate:"max=20"`
+	PortLocode    string            `json:"port_locode" validate:"omitempty,len=5"`
+	ContainerType string            `json:"container_type" validate:"max=50"`
+	FreeDays      int               `json:"free_days" validate:"min=0,max=365"`
+	DailyRate     float64           `json:"daily_rate" validate:"min=0"`
+	// Currency defaults to USD
+	Currency string `json:"currency" validate:"omitempty,len=3"`
+	// AlertDaysBefore defaults to 2, 0 only alerts once free time ran out
+	AlertDaysBefore *int `json:"alert_days_before" validate:"omitempty,min=0,max=30"`
+}
+
+// AgreementsListResponse represents the response when listing free-time agreements
+type AgreementsListResponse struct {
+	Agreements []models.FreeTimeAgreement `json:"agreements"`
+	Total      int                        `json:"total"`
+}
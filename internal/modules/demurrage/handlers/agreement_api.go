@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"go-starter/internal/modules/auth/services"
+	"go-starter/internal/modules/demurrage/dto"
+	demurrageServices "go-starter/internal/modules/demurrage/services"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type AgreementAPIHandler struct {
+	freeTimeService *demurrageServices.FreeTimeService
+	validator       *validator.Validate
+}
+
+func NewAgreementAPIHandler(freeTimeService *demurrageServices.FreeTimeService) *AgreementAPIHandler {
+	return &AgreementAPIHandler{
+		freeTimeService: freeTimeService,
+		validator:       validator.New(),
+	}
+}
+
+// GetAgreements handles GET /api/demurrage/agreements
+func (h *AgreementAPIHandler) GetAgreements(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	response, err := h.freeTimeService.GetAgreements(ctx, userID)
+	if err != nil {
+		return h.serviceError(c, "Failed to retrieve free-time agreements", err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// CreateAgreement handles POST /api/demurrage/agreements
+func (h *AgreementAPIHandler) CreateAgreement(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.SaveAgreementRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Validation failed: " + err.Error(),
+		})
+	}
+
+	agreement, err := h.freeTimeService.CreateAgreement(ctx, userID, &req)
+	if err != nil {
+		return h.serviceError(c, "Failed to create free-time agreement", err)
+	}
+
+	return c.JSON(http.StatusCreated, agreement)
+}
+
+// UpdateAgreement handles PUT /api/demurrage/agreements/:id
+func (h *AgreementAPIHandler) UpdateAgreement(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	agreementID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid agreement ID",
+		})
+	}
+
+	var req dto.SaveAgreementRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Validation failed: " + err.Error(),
+		})
+	}
+
+	agreement, err := h.freeTimeService.UpdateAgreement(ctx, userID, agreementID, &req)
+	if err != nil {
+		return h.serviceError(c, "Failed to update free-time agreement", err)
+	}
+
+	return c.JSON(http.StatusOK, agreement)
+}
+
+// DeleteAgreement handles DELETE /api/demurrage/agreements/:id
+func (h *AgreementAPIHandler) DeleteAgreement(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	agreementID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid agreement ID",
+		})
+	}
+
+	if err := h.freeTimeService.DeleteAgreement(ctx, userID, agreementID); err != nil {
+		return h.serviceError(c, "Failed to delete free-time agreement", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Free-time agreement deleted successfully",
+	})
+}
+
+// serviceError maps service errors to status codes, validation errors are returned as is
+func (h *AgreementAPIHandler) serviceError(c echo.Context, message string, err error) error {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	case strings.HasPrefix(err.Error(), "invalid"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message + ": " + err.Error(),
+	})
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ChargeType is what a free-time agreement charges for
+type ChargeType string
+
+const (
+	// ChargeTypeDemurrage runs while the full container waits at the terminal, from discharge to gate out
+	ChargeTypeDemurrage ChargeType = "demurrage"
+	// ChargeTypeDetention runs while the container is outside the terminal, from gate out to empty return
+	ChargeTypeDetention ChargeType = "detention"
+)
+
+// IsValid reports whether the charge type is known
+func (t ChargeType) IsValid() bool {
+	return t == ChargeTypeDemurrage || t == ChargeTypeDetention
+}
+
+// FreeTimeAgreement is the free time a carrier grants before it charges demurrage or
+// detention. Empty CarrierCode, PortLocode and ContainerType match any value, the most
+// specific matching agreement applies to a container.
+type FreeTimeAgreement struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name       string     `json:"name" gorm:"type:varchar(100);not null"`
+	ChargeType ChargeType `json:"charge_type" gorm:"type:varchar(20);not null"`
+	// CarrierCode is the sealine code of the shipment, e.g. MSCU
+	CarrierCode string `json:"carrier_code" gorm:"type:varchar(20)"`
+	// PortLocode is the port of discharge
+	PortLocode string `json:"port_locode" gorm:"type:varchar(10)"`
+	// ContainerType is matched against the ISO code and the size type, e.g. 45G1 or 40HC
+	ContainerType string `json:"container_type" gorm:"type:varchar(50)"`
+	// FreeDays are calendar days, the day the clock starts is the first one
+	FreeDays  int     `json:"free_days" gorm:"not null"`
+	DailyRate float64 `json:"daily_rate" gorm:"type:numeric(12,2);not null;default:0"`
+	Currency  string  `json:"currency" gorm:"type:varchar(3);not null;default:'USD'"`
+	// AlertDaysBefore is how many days before free time runs out an alert is raised
+	AlertDaysBefore int       `json:"alert_days_before" gorm:"not null;default:2"`
+	CreatedAt       time.Time `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for FreeTimeAgreement
+func (FreeTimeAgreement) TableName() string {
+	return "free_time_agreements"
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (a *FreeTimeAgreement) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// Matches reports whether the agreement applies to a container of a shipment
+func (a *FreeTimeAgreement) Matches(carrierCode, portLocode, isoCode, sizeType string) bool {
+	if a.CarrierCode != "" && !strings.EqualFold(a.CarrierCode, carrierCode) {
+		return false
+	}
+	if a.PortLocode != "" && !strings.EqualFold(a.PortLocode, portLocode) {
+		return false
+	}
+	if a.ContainerType != "" && !strings.EqualFold(a.ContainerType, isoCode) && !strings.EqualFold(a.ContainerType, sizeType) {
+		return false
+	}
+	return true
+}
+
+// Specificity counts the fields the agreement restricts, more specific agreements win
+func (a *FreeTimeAgreement) Specificity() int {
+	specificity := 0
+	for _, field := range []string{a.CarrierCode, a.PortLocode, a.ContainerType} {
+		if field != "" {
+			specificity++
+		}
+	}
+	return specificity
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go-starter/internal/modules/demurrage/models"
+	"go-starter/pkg/db"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AgreementRepository struct {
+	db *db.Database
+}
+
+func NewAgreementRepository(database *db.Database) *AgreementRepository {
+	return &AgreementRepository{
+		db: database,
+	}
+}
+
+// CreateAgreement creates a new free-time agreement
+func (r *AgreementRepository) CreateAgreement(ctx context.Context, agreement *models.FreeTimeAgreement) error {
+	return r.db.DB.WithContext(ctx).Create(agreement).Error
+}
+
+// GetAgreementByID retrieves a free-time agreement by its ID and user ID
+func (r *AgreementRepository) GetAgreementByID(ctx context.Context, agreementID, userID uuid.UUID) (*models.FreeTimeAgreement, error) {
+	var agreement models.FreeTimeAgreement
+	err := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", agreementID, userID).
+		First(&agreement).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &agreement, nil
+}
+
+// GetAgreementsByUserID retrieves all free-time agreements of a user, oldest first
+func (r *AgreementRepository) GetAgreementsByUserID(ctx context.Context, userID uuid.UUID) ([]models.FreeTimeAgreement, error) {
+	var agreements []models.FreeTimeAgreement
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&agreements).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return agreements, nil
+}
+
+// GetUserIDsWithAgreements retrieves the users that have at least one free-time agreement
+func (r *AgreementRepository) GetUserIDsWithAgreements(ctx context.Context) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.FreeTimeAgreement{}).
+		Distinct("user_id").
+		Pluck("user_id", &userIDs).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users with free-time agreements: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+// UpdateAgreement updates an existing free-time agreement
+func (r *AgreementRepository) UpdateAgreement(ctx context.Context, agreement *models.FreeTimeAgreement) error {
+	return r.db.DB.WithContext(ctx).
+		Model(agreement).
+		Where("id = ? AND user_id = ?", agreement.ID, agreement.UserID).
+		Updates(map[string]interface{}{
+			"name":              agreement.Name,
+			"charge_type":       agreement.ChargeType,
+			"carrier_code":      agreement.CarrierCode,
+			"port_locode":       agreement.PortLocode,
+			"container_type":    agreement.ContainerType,
+			"free_days":         agreement.FreeDays,
+			"daily_rate":        agreement.DailyRate,
+			"currency":          agreement.Currency,
+			"alert_days_before": agreement.AlertDaysBefore,
+			"updated_at":        gorm.Expr("NOW()"),
+		}).Error
+}
+
+// DeleteAgreement deletes a free-time agreement by its ID and user ID
+func (r *AgreementRepository) DeleteAgreement(ctx context.Context, agreementID, userID uuid.UUID) error {
+	result := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", agreementID, userID).
+		Delete(&models.FreeTimeAgreement{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package demurrage
+
+import (
+	alertRepositories "go-starter/internal/modules/alerts/repositories"
+	alertServices "go-starter/internal/modules/alerts/services"
+	"go-starter/internal/modules/auth/middlewares"
+	"go-starter/internal/modules/auth/services"
+	"go-starter/internal/modules/demurrage/handlers"
+	"go-starter/internal/modules/demurrage/repositories"
+	demurrageServices "go-starter/internal/modules/demurrage/services"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+	"go-starter/pkg/db"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRoutes(api *echo.Group, database *db.Database, shipmentService shipmentServices.ShipmentService) {
+	// Initialize dependencies, free time alerts are raised by the free time alert job
+	agreementRepo := repositories.NewAgreementRepository(database)
+	alertService := alertServices.NewAlertService(alertRepositories.NewAlertRepository(database))
+	freeTimeService := demurrageServices.NewFreeTimeService(agreementRepo, shipmentService, alertService)
+	agreementAPIHandler := handlers.NewAgreementAPIHandler(freeTimeService)
+
+	// Add the demurrage and detention clocks to shipment details and grid rows
+	shipmentServices.RegisterDetailsDecorator(freeTimeService.DecorateDetails)
+
+	// Create JWT service for middleware
+	jwtService := services.NewJWTService()
+
+	// Create demurrage group with JWT middleware
+	demurrageGroup := api.Group("/demurrage", middlewares.JWTMiddleware(jwtService))
+
+	demurrageGroup.GET("/agreements", agreementAPIHandler.GetAgreements)          // GET /api/demurrage/agreements
+	demurrageGroup.POST("/agreements", agreementAPIHandler.CreateAgreement)       // POST /api/demurrage/agreements
+	demurrageGroup.PUT("/agreements/:id", agreementAPIHandler.UpdateAgreement)    // PUT /api/demurrage/agreements/:id
+	demurrageGroup.DELETE("/agreements/:id", agreementAPIHandler.DeleteAgreement) // DELETE /api/demurrage/agreements/:id
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"go-starter/internal/modules/demurrage/models"
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+	shipmentModels "go-starter/internal/modules/shipments/models"
+)
+
+// ContainerFreeTime computes the demurrage and detention clocks of every container of a
+// shipment under the most specific matching agreements. Containers whose clock has not
+// started yet, or that no agreement covers, are left out.
+func ContainerFreeTime(agreements []models.FreeTimeAgreement, row shipmentDto.ShipmentDetailsResponse, now time.Time) []shipmentDto.ContainerFreeTimeResponse {
+	var freeTime []shipmentDto.ContainerFreeTimeResponse
+
+	for _, container := range row.Containers {
+		portLocode := dischargePort(row, container)
+
+		for _, chargeType := range []models.ChargeType{models.ChargeTypeDemurrage, models.ChargeTypeDetention} {
+			started, stopped := chargeClock(chargeType, container.Milestones)
+			if started == nil {
+				continue
+			}
+
+			agreement := matchAgreement(agreements, chargeType, row.SealineCode, portLocode, container.IsoCode, container.SizeType)
+			if agreement == nil {
+				continue
+			}
+
+			freeTime = append(freeTime, CalculateFreeTime(*agreement, container.Number, *started, stopped, now))
+		}
+	}
+
+	return freeTime
+}
+
+// CalculateFreeTime computes a container's clock under an agreement. Free time ends after
+// FreeDays calendar days counting the day the clock started, every day or part of a day
+// after that is charged.
+func CalculateFreeTime(agreement models.FreeTimeAgreement, containerNumber string, started time.Time, stopped *time.Time, now time.Time) shipmentDto.ContainerFreeTimeResponse {
+	startDay := started.UTC().Truncate(24 * time.Hour)
+	freeTimeEnds := startDay.AddDate(0, 0, agreement.FreeDays)
+
+	reference := now
+	if stopped != nil {
+		reference = *stopped
+	}
+
+	freeTime := shipmentDto.ContainerFreeTimeResponse{
+		ContainerNumber: containerNumber,
+		ChargeType:      string(agreement.ChargeType),
+		AgreementID:     agreement.ID,
+		AgreementName:   agreement.Name,
+		StartedAt:       started,
+		StoppedAt:       stopped,
+		FreeDays:        agreement.FreeDays,
+		FreeTimeEndsAt:  freeTimeEnds,
+		Currency:        agreement.Currency,
+	}
+
+	if reference.After(freeTimeEnds) {
+		freeTime.ChargeableDays = ceilDays(reference.Sub(freeTimeEnds))
+		freeTime.AccruedCharges = math.Round(float64(freeTime.ChargeableDays)*agreement.DailyRate*100) / 100
+	} else if stopped == nil {
+		freeTime.DaysRemaining = ceilDays(freeTimeEnds.Sub(reference))
+	}
+
+	switch {
+	case stopped != nil:
+		freeTime.Status = shipmentDto.FreeTimeStatusClosed
+	case freeTime.ChargeableDays > 0:
+		freeTime.Status = shipmentDto.FreeTimeStatusOverdue
+	case freeTime.DaysRemaining <= agreement.AlertDaysBefore:
+		freeTime.Status = shipmentDto.FreeTimeStatusExpiring
+	default:
+		freeTime.Status = shipmentDto.FreeTimeStatusRunning
+	}
+
+	return freeTime
+}
+
+// chargeClock returns when a charge started and stopped for a container. Only actual
+// milestones count, the clock of a container that is merely expected to arrive isn't running.
+func chargeClock(chargeType models.ChargeType, milestones []shipmentDto.ShipmentMilestoneResponse) (*time.Time, *time.Time) {
+	startMilestone, stopMilestone := shipmentModels.MilestoneDischarged, shipmentModels.MilestoneGateOut
+	if chargeType == models.ChargeTypeDetention {
+		startMilestone, stopMilestone = shipmentModels.MilestoneGateOut, shipmentModels.MilestoneEmptyReturn
+	}
+
+	var started, stopped *time.Time
+	for _, milestone := range milestones {
+		switch shipmentModels.Milestone(milestone.Milestone) {
+		case startMilestone:
+			started = milestone.ActualAt
+		case stopMilestone:
+			stopped = milestone.ActualAt
+		}
+	}
+	if started == nil {
+		return nil, nil
+	}
+
+	return started, stopped
+}
+
+// matchAgreement returns the most specific agreement for a container, the oldest one on a tie
+func matchAgreement(agreements []models.FreeTimeAgreement, chargeType models.ChargeType, carrierCode, portLocode, isoCode, sizeType string) *models.FreeTimeAgreement {
+	var best *models.FreeTimeAgreement
+	for i := range agreements {
+		agreement := &agreements[i]
+		if agreement.ChargeType != chargeType || !agreement.Matches(carrierCode, portLocode, isoCode, sizeType) {
+			continue
+		}
+		if best == nil || agreement.Specificity() > best.Specificity() {
+			best = agreement
+		}
+	}
+	return best
+}
+
+// dischargePort is where the container was discharged, or the shipment's port of discharge
+func dischargePort(row shipmentDto.ShipmentDetailsResponse, container shipmentDto.ShipmentContainerResponse) string {
+	for _, milestone := range container.Milestones {
+		if milestone.Milestone == string(shipmentModels.MilestoneDischarged) && milestone.Locode != "" {
+			return milestone.Locode
+		}
+	}
+	if row.Route.Pod != nil {
+		return row.Route.Pod.Location.Locode
+	}
+	return ""
+}
+
+// ceilDays counts every started day of a duration
+func ceilDays(d time.Duration) int {
+	return int(math.Ceil(d.Hours() / 24))
+}
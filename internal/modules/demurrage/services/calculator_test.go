@@ -0,0 +1,156 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"go-starter/internal/modules/demurrage/models"
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+
+	"github.com/google/uuid"
+)
+
+func TestCalculateFreeTime(t *testing.T) {
+	agreement := models.FreeTimeAgreement{
+		ID:              uuid.New(),
+		Name:            "MSC Rotterdam",
+		ChargeType:      models.ChargeTypeDemurrage,
+		FreeDays:        5,
+		DailyRate:       75.5,
+		Currency:        "EUR",
+		AlertDaysBefore: 2,
+	}
+	discharged := time.Date(2025, 6, 1, 18, 30, 0, 0, time.UTC)
+	freeTimeEnds := time.Date(2025, 6, 6, 0, 0, 0, 0, time.UTC)
+	stoppedEarly := time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC)
+	stoppedLate := time.Date(2025, 6, 8, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		stopped        *time.Time
+		now            time.Time
+		status         string
+		daysRemaining  int
+		chargeableDays int
+		accrued        float64
+	}{
+		{"running", nil, time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC), shipmentDto.FreeTimeStatusRunning, 4, 0, 0},
+		{"expiring counts started days", nil, time.Date(2025, 6, 4, 12, 0, 0, 0, time.UTC), shipmentDto.FreeTimeStatusExpiring, 2, 0, 0},
+		{"overdue", nil, time.Date(2025, 6, 7, 12, 0, 0, 0, time.UTC), shipmentDto.FreeTimeStatusOverdue, 0, 2, 151},
+		{"closed within free time", &stoppedEarly, time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC), shipmentDto.FreeTimeStatusClosed, 0, 0, 0},
+		{"closed with charges", &stoppedLate, time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC), shipmentDto.FreeTimeStatusClosed, 0, 3, 226.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateFreeTime(agreement, "MSCU1234565", discharged, tt.stopped, tt.now)
+
+			if !got.FreeTimeEndsAt.Equal(freeTimeEnds) {
+				t.Errorf("Expected free time to end at %v, got %v", freeTimeEnds, got.FreeTimeEndsAt)
+			}
+			if got.Status != tt.status {
+				t.Errorf("Expected status %s, got %s", tt.status, got.Status)
+			}
+			if got.DaysRemaining != tt.daysRemaining {
+				t.Errorf("Expected %d days remaining, got %d", tt.daysRemaining, got.DaysRemaining)
+			}
+			if got.ChargeableDays != tt.chargeableDays {
+				t.Errorf("Expected %d chargeable days, got %d", tt.chargeableDays, got.ChargeableDays)
+			}
+			if got.AccruedCharges != tt.accrued {
+				t.Errorf("Expected %.2f accrued, got %.2f", tt.accrued, got.AccruedCharges)
+			}
+		})
+	}
+}
+
+func TestContainerFreeTime(t *testing.T) {
+	discharged := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	gatedOut := time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)
+
+	general := models.FreeTimeAgreement{ID: uuid.New(), ChargeType: models.ChargeTypeDemurrage, FreeDays: 3}
+	port := models.FreeTimeAgreement{ID: uuid.New(), ChargeType: models.ChargeTypeDemurrage, PortLocode: "NLRTM", FreeDays: 5}
+	reefer := models.FreeTimeAgreement{ID: uuid.New(), ChargeType: models.ChargeTypeDemurrage, PortLocode: "NLRTM", ContainerType: "45R1", FreeDays: 2}
+	detention := models.FreeTimeAgreement{ID: uuid.New(), ChargeType: models.ChargeTypeDetention, CarrierCode: "MSCU", FreeDays: 10}
+	otherCarrier := models.FreeTimeAgreement{ID: uuid.New(), ChargeType: models.ChargeTypeDetention, CarrierCode: "MAEU", FreeDays: 1}
+
+	row := shipmentDto.ShipmentDetailsResponse{
+		SealineCode: "MSCU",
+		Containers: []shipmentDto.ShipmentContainerResponse{
+			{
+				Number:  "MSCU1234565",
+				IsoCode: "45G1",
+				Milestones: []shipmentDto.ShipmentMilestoneResponse{
+					{Milestone: "discharged", ActualAt: &discharged, Locode: "NLRTM"},
+					{Milestone: "gate_out", ActualAt: &gatedOut},
+				},
+			},
+			{
+				Number:  "MSCU7654321",
+				IsoCode: "45R1",
+				Milestones: []shipmentDto.ShipmentMilestoneResponse{
+					{Milestone: "discharged", ActualAt: &discharged, Locode: "NLRTM"},
+				},
+			},
+			{
+				Number:  "MSCU0000000",
+				IsoCode: "45G1",
+				Milestones: []shipmentDto.ShipmentMilestoneResponse{
+					{Milestone: "discharged", EstimatedAt: &now},
+				},
+			},
+		},
+	}
+
+	got := ContainerFreeTime([]models.FreeTimeAgreement{general, port, reefer, otherCarrier, detention}, row, now)
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 clocks, got %d: %+v", len(got), got)
+	}
+
+	if got[0].ContainerNumber != "MSCU1234565" || got[0].AgreementID != port.ID || got[0].Status != shipmentDto.FreeTimeStatusClosed {
+		t.Errorf("Expected the port agreement to close demurrage of the first container, got %+v", got[0])
+	}
+	if got[1].ContainerNumber != "MSCU1234565" || got[1].AgreementID != detention.ID || got[1].Status != shipmentDto.FreeTimeStatusRunning {
+		t.Errorf("Expected the carrier's detention to run for the first container, got %+v", got[1])
+	}
+	if got[2].ContainerNumber != "MSCU7654321" || got[2].AgreementID != reefer.ID || got[2].Status != shipmentDto.FreeTimeStatusOverdue {
+		t.Errorf("Expected the reefer agreement to be overdue for the second container, got %+v", got[2])
+	}
+}
+
+func TestFreeTimeAlert(t *testing.T) {
+	row := shipmentDto.ShipmentDetailsResponse{ID: uuid.New(), ShipmentNumber: "MEDU1234567"}
+	freeTime := shipmentDto.ContainerFreeTimeResponse{
+		ContainerNumber: "MSCU1234565",
+		ChargeType:      string(models.ChargeTypeDemurrage),
+		AgreementName:   "MSC Rotterdam",
+		FreeTimeEndsAt:  time.Date(2025, 6, 6, 0, 0, 0, 0, time.UTC),
+		DaysRemaining:   1,
+	}
+
+	for _, status := range []string{shipmentDto.FreeTimeStatusRunning, shipmentDto.FreeTimeStatusClosed} {
+		freeTime.Status = status
+		if _, ok := freeTimeAlert(row, freeTime); ok {
+			t.Errorf("Expected no alert while free time is %s", status)
+		}
+	}
+
+	freeTime.Status = shipmentDto.FreeTimeStatusExpiring
+	expiring, ok := freeTimeAlert(row, freeTime)
+	if !ok || expiring.Title != "Demurrage free time of MSCU1234565 ends in 1 day" {
+		t.Errorf("Expected an expiring alert, got %+v", expiring)
+	}
+
+	freeTime.Status = shipmentDto.FreeTimeStatusOverdue
+	overdue, ok := freeTimeAlert(row, freeTime)
+	if !ok || overdue.DedupKey == expiring.DedupKey {
+		t.Errorf("Expected an overdue alert with its own dedup key, got %+v", overdue)
+	}
+
+	// A corrected discharge date moves the end of the free time and alerts again
+	freeTime.FreeTimeEndsAt = freeTime.FreeTimeEndsAt.Add(24 * time.Hour)
+	if corrected, _ := freeTimeAlert(row, freeTime); corrected.DedupKey == overdue.DedupKey {
+		t.Errorf("Expected a new dedup key for a new free time end, got %q", corrected.DedupKey)
+	}
+}
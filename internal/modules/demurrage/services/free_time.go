@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	alertModels "go-starter/internal/modules/alerts/models"
+	alertServices "go-starter/internal/modules/alerts/services"
+	"go-starter/internal/modules/demurrage/dto"
+	"go-starter/internal/modules/demurrage/models"
+	"go-starter/internal/modules/demurrage/repositories"
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// defaultAlertDaysBefore is how early agreements alert unless they say otherwise
+const defaultAlertDaysBefore = 2
+
+// AlertRaiser raises alerts for a user, implemented by the alert service
+type AlertRaiser interface {
+	CreateAlert(ctx context.Context, userID, shipmentID uuid.UUID, ruleID *uuid.UUID, candidate alertServices.AlertCandidate) (bool, error)
+}
+
+type FreeTimeService struct {
+	agreementRepo   *repositories.AgreementRepository
+	shipmentService shipmentServices.ShipmentService
+	alerts          AlertRaiser
+}
+
+func NewFreeTimeService(agreementRepo *repositories.AgreementRepository, shipmentService shipmentServices.ShipmentService, alerts AlertRaiser) *FreeTimeService {
+	return &FreeTimeService{
+		agreementRepo:   agreementRepo,
+		shipmentService: shipmentService,
+		alerts:          alerts,
+	}
+}
+
+// DecorateDetails adds the demurrage and detention clocks of the user's agreements to
+// shipment details. It is registered as a shipment details decorator.
+func (s *FreeTimeService) DecorateDetails(ctx context.Context, userID uuid.UUID, rows []shipmentDto.ShipmentDetailsResponse) {
+	agreements, err := s.agreementRepo.GetAgreementsByUserID(ctx, userID)
+	if err != nil {
+		log.Printf("Warning: Failed to load free-time agreements of user %s: %v", userID, err)
+		return
+	}
+	if len(agreements) == 0 {
+		return
+	}
+
+	now := time.Now()
+	for i := range rows {
+		rows[i].FreeTime = ContainerFreeTime(agreements, rows[i], now)
+	}
+}
+
+// CheckFreeTime raises an alert for every running clock that is about to run out or ran
+// out, once per container and free time end. It returns how many new alerts were raised.
+func (s *FreeTimeService) CheckFreeTime(ctx context.Context, now time.Time) (int, error) {
+	userIDs, err := s.agreementRepo.GetUserIDsWithAgreements(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	raised := 0
+	for _, userID := range userIDs {
+		count, err := s.checkUser(ctx, userID, now)
+		if err != nil {
+			log.Printf("Warning: Failed to check free time of user %s: %v", userID, err)
+			continue
+		}
+		raised += count
+	}
+
+	return raised, nil
+}
+
+func (s *FreeTimeService) checkUser(ctx context.Context, userID uuid.UUID, now time.Time) (int, error) {
+	agreements, err := s.agreementRepo.GetAgreementsByUserID(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load free-time agreements: %w", err)
+	}
+
+	rows, err := s.shipmentService.GetShipmentsWithRunningClocks(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	raised := 0
+	for _, row := range rows {
+		for _, freeTime := range ContainerFreeTime(agreements, row, now) {
+			candidate, ok := freeTimeAlert(row, freeTime)
+			if !ok {
+				continue
+			}
+			created, err := s.alerts.CreateAlert(ctx, userID, row.ID, nil, candidate)
+			if err != nil {
+				log.Printf("Warning: Failed to raise free time alert for shipment %s: %v", row.ID, err)
+				continue
+			}
+			if created {
+				raised++
+			}
+		}
+	}
+
+	return raised, nil
+}
+
+// freeTimeAlert describes a clock that is about to run out or ran out. The dedup key
+// includes the free time end, so a corrected discharge date alerts again.
+func freeTimeAlert(row shipmentDto.ShipmentDetailsResponse, freeTime shipmentDto.ContainerFreeTimeResponse) (alertServices.AlertCandidate, bool) {
+	dedupKey := fmt.Sprintf("%s:%s:%s:%s:%s:%d", alertModels.AlertTypeFreeTime, freeTime.Status, freeTime.ChargeType,
+		row.ID, freeTime.ContainerNumber, freeTime.FreeTimeEndsAt.Unix())
+
+	switch freeTime.Status {
+	case shipmentDto.FreeTimeStatusExpiring:
+		return alertServices.AlertCandidate{
+			Type:     alertModels.AlertTypeFreeTime,
+			DedupKey: dedupKey,
+			Title:    fmt.Sprintf("%s free time of %s ends in %s", chargeTypeLabel(freeTime.ChargeType), freeTime.ContainerNumber, formatDays(freeTime.DaysRemaining)),
+			Message: fmt.Sprintf("Container %s of %s has %s free time until %s under %s.",
+				freeTime.ContainerNumber, row.ShipmentNumber, freeTime.ChargeType,
+				freeTime.FreeTimeEndsAt.Format("2006-01-02"), freeTime.AgreementName),
+		}, true
+	case shipmentDto.FreeTimeStatusOverdue:
+		return alertServices.AlertCandidate{
+			Type:     alertModels.AlertTypeFreeTime,
+			DedupKey: dedupKey,
+			Title:    fmt.Sprintf("%s free time of %s ran out", chargeTypeLabel(freeTime.ChargeType), freeTime.ContainerNumber),
+			Message: fmt.Sprintf("Container %s of %s is accruing %s since %s under %s, %.2f %s so far.",
+				freeTime.ContainerNumber, row.ShipmentNumber, freeTime.ChargeType,
+				freeTime.FreeTimeEndsAt.Format("2006-01-02"), freeTime.AgreementName,
+				freeTime.AccruedCharges, freeTime.Currency),
+		}, true
+	}
+
+	return alertServices.AlertCandidate{}, false
+}
+
+// CreateAgreement validates and creates a new free-time agreement
+func (s *FreeTimeService) CreateAgreement(ctx context.Context, userID uuid.UUID, req *dto.SaveAgreementRequest) (*models.FreeTimeAgreement, error) {
+	agreement := &models.FreeTimeAgreement{UserID: userID}
+	if err := applyAgreementRequest(agreement, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.agreementRepo.CreateAgreement(ctx, agreement); err != nil {
+		return nil, fmt.Errorf("error creating agreement: %w", err)
+	}
+
+	return agreement, nil
+}
+
+// GetAgreements retrieves all free-time agreements of a user
+func (s *FreeTimeService) GetAgreements(ctx context.Context, userID uuid.UUID) (*dto.AgreementsListResponse, error) {
+	agreements, err := s.agreementRepo.GetAgreementsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving agreements: %w", err)
+	}
+
+	return &dto.AgreementsListResponse{
+		Agreements: agreements,
+		Total:      len(agreements),
+	}, nil
+}
+
+// UpdateAgreement validates and updates an existing free-time agreement
+func (s *FreeTimeService) UpdateAgreement(ctx context.Context, userID, agreementID uuid.UUID, req *dto.SaveAgreementRequest) (*models.FreeTimeAgreement, error) {
+	agreement, err := s.agreementRepo.GetAgreementByID(ctx, agreementID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("agreement not found")
+		}
+		return nil, fmt.Errorf("error retrieving agreement: %w", err)
+	}
+
+	if err := applyAgreementRequest(agreement, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.agreementRepo.UpdateAgreement(ctx, agreement); err != nil {
+		return nil, fmt.Errorf("error updating agreement: %w", err)
+	}
+
+	return agreement, nil
+}
+
+// DeleteAgreement deletes a free-time agreement
+func (s *FreeTimeService) DeleteAgreement(ctx context.Context, userID, agreementID uuid.UUID) error {
+	if err := s.agreementRepo.DeleteAgreement(ctx, agreementID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("agreement not found")
+		}
+		return fmt.Errorf("error deleting agreement: %w", err)
+	}
+
+	return nil
+}
+
+// applyAgreementRequest validates the request and copies it onto the agreement, codes are
+// stored upper case
+func applyAgreementRequest(agreement *models.FreeTimeAgreement, req *dto.SaveAgreementRequest) error {
+	if !req.ChargeType.IsValid() {
+		return fmt.Errorf("invalid charge type '%s'", req.ChargeType)
+	}
+
+	agreement.Name = strings.TrimSpace(req.Name)
+	agreement.ChargeType = req.ChargeType
+	agreement.CarrierCode = strings.ToUpper(strings.TrimSpace(req.CarrierCode))
+	agreement.PortLocode = strings.ToUpper(strings.TrimSpace(req.PortLocode))
+	agreement.ContainerType = strings.ToUpper(strings.TrimSpace(req.ContainerType))
+	agreement.FreeDays = req.FreeDays
+	agreement.DailyRate = req.DailyRate
+
+	agreement.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+	if agreement.Currency == "" {
+		agreement.Currency = "USD"
+	}
+
+	agreement.AlertDaysBefore = defaultAlertDaysBefore
+	if req.AlertDaysBefore != nil {
+		agreement.AlertDaysBefore = *req.AlertDaysBefore
+	}
+
+	return nil
+}
+
+// chargeTypeLabel capitalizes a charge type for alert titles
+func chargeTypeLabel(chargeType string) string {
+	if chargeType == "" {
+		return ""
+	}
+	return strings.ToUpper(chargeType[:1]) + chargeType[1:]
+}
+
+// formatDays formats a number of days
+func formatDays(days int) string {
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"time"
+
+	"go-starter/internal/modules/digests/models"
+
+	"github.com/google/uuid"
+)
+
+// SaveScheduleRequest represents the request to create or update a digest schedule
+type SaveScheduleRequest struct {
+	Name       string     `json:"name" validate:"required,max=100"`
+	FilterID   *uuid.UUID `json:"filter_id"`
+	Frequency  string     `json:"frequency" validate:"required,oneof=daily weekly"`
+	Hour       int        `json:"hour" validate:"min=0,max=23"`
+	Weekday    int        `json:"weekday" validate:"min=0,max=6"`
+	Recipients []string   `json:"recipients" validate:"max=20,dive,email"`
+	// StaleDays defaults to 7, 0 leaves stale shipments out of the digest
+	StaleDays *int  `json:"stale_days" validate:"omitempty,min=0,max=90"`
+	Enabled   *bool `json:"enabled"`
+}
+
+// SchedulesListResponse represents the response when listing digest schedules
+type SchedulesListResponse struct {
+	Schedules []models.DigestSchedule `json:"schedules"`
+	Total     int                     `json:"total"`
+}
+
+// Digest sections
+const (
+	SectionArriving  = "arriving"
+	SectionETASlips  = "eta_slips"
+	SectionDelivered = "delivered"
+	SectionStale     = "stale"
+	SectionUnpaid    = "unpaid"
+)
+
+// Digest is the content of one digest email
+type Digest struct {
+	ScheduleName string          `json:"schedule_name"`
+	Since        time.Time       `json:"since"`
+	GeneratedAt  time.Time       `json:"generated_at"`
+	Shipments    int             `json:"shipments"`
+	Sections     []DigestSection `json:"sections"`
+}
+
+// DigestSection is one list of shipments in a digest, e.g. the ones arriving soon
+type DigestSection struct {
+	Key     string        `json:"key"`
+	Title   string        `json:"title"`
+	Entries []DigestEntry `json:"entries"`
+}
+
+// DigestEntry is a shipment listed in a digest section
+type DigestEntry struct {
+	ShipmentID     uuid.UUID  `json:"shipment_id"`
+	ShipmentNumber string     `json:"shipment_number"`
+	Status         string     `json:"status"`
+	Destination    string     `json:"destination"`
+	ETA            *time.Time `json:"eta"`
+	PreviousETA    *time.Time `json:"previous_eta,omitempty"`
+	Detail         string     `json:"detail"`
+	Consignee      string     `json:"consignee"`
+	AssignedTo     string     `json:"assigned_to"`
+	InvoiceAmount  string     `json:"invoice_amount"`
+	URL            string     `json:"url"`
+}
+
+// IsEmpty reports whether no section lists any shipment
+func (d *Digest) IsEmpty() bool {
+	for _, section := range d.Sections {
+		if len(section.Entries) > 0 {
+			return false
+		}
+	}
+	return true
+}
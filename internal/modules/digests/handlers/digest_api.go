@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"go-starter/internal/modules/auth/services"
+	"go-starter/internal/modules/digests/dto"
+	digestServices "go-starter/internal/modules/digests/services"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type DigestAPIHandler struct {
+	digestService *digestServices.DigestService
+	validator     *validator.Validate
+}
+
+func NewDigestAPIHandler(digestService *digestServices.DigestService) *DigestAPIHandler {
+	return &DigestAPIHandler{
+		digestService: digestService,
+		validator:     validator.New(),
+	}
+}
+
+// GetSchedules handles GET /api/digests
+func (h *DigestAPIHandler) GetSchedules(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	response, err := h.digestService.GetSchedules(ctx, userID)
+	if err != nil {
+		return h.serviceError(c, "Failed to retrieve digest schedules", err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// CreateSchedule handles POST /api/digests
+func (h *DigestAPIHandler) CreateSchedule(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.SaveScheduleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Validation failed: " + err.Error(),
+		})
+	}
+
+	schedule, err := h.digestService.CreateSchedule(ctx, userID, &req)
+	if err != nil {
+		return h.serviceError(c, "Failed to create digest schedule", err)
+	}
+
+	return c.JSON(http.StatusCreated, schedule)
+}
+
+// UpdateSchedule handles PUT /api/digests/:id
+func (h *DigestAPIHandler) UpdateSchedule(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	scheduleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid digest schedule ID",
+		})
+	}
+
+	var req dto.SaveScheduleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Validation failed: " + err.Error(),
+		})
+	}
+
+	schedule, err := h.digestService.UpdateSchedule(ctx, userID, scheduleID, &req)
+	if err != nil {
+		return h.serviceError(c, "Failed to update digest schedule", err)
+	}
+
+	return c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteSchedule handles DELETE /api/digests/:id
+func (h *DigestAPIHandler) DeleteSchedule(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	scheduleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid digest schedule ID",
+		})
+	}
+
+	if err := h.digestService.DeleteSchedule(ctx, userID, scheduleID); err != nil {
+		return h.serviceError(c, "Failed to delete digest schedule", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Digest schedule deleted successfully",
+	})
+}
+
+// PreviewDigest handles GET /api/digests/:id/preview
+func (h *DigestAPIHandler) PreviewDigest(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	scheduleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid digest schedule ID",
+		})
+	}
+
+	digest, err := h.digestService.PreviewDigest(ctx, userID, scheduleID)
+	if err != nil {
+		return h.serviceError(c, "Failed to build digest", err)
+	}
+
+	return c.JSON(http.StatusOK, digest)
+}
+
+// SendDigest handles POST /api/digests/:id/send
+func (h *DigestAPIHandler) SendDigest(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	scheduleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid digest schedule ID",
+		})
+	}
+
+	digest, err := h.digestService.SendDigestNow(ctx, userID, scheduleID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "failed to send") {
+			return c.JSON(http.StatusBadGateway, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return h.serviceError(c, "Failed to send digest", err)
+	}
+
+	return c.JSON(http.StatusOK, digest)
+}
+
+// serviceError maps service errors to status codes, validation errors are returned as is
+func (h *DigestAPIHandler) serviceError(c echo.Context, message string, err error) error {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	case strings.HasPrefix(err.Error(), "invalid"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message + ": " + err.Error(),
+	})
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Digest frequencies
+const (
+	FrequencyDaily  = "daily"
+	FrequencyWeekly = "weekly"
+)
+
+// ETASnapshot maps shipment IDs to the POD ETA they had when the last digest was sent,
+// the next digest reports the shipments whose ETA moved later since then
+type ETASnapshot map[string]time.Time
+
+// Scan implements the sql.Scanner interface for ETASnapshot
+func (s *ETASnapshot) Scan(value interface{}) error {
+	if value == nil {
+		*s = ETASnapshot{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into ETASnapshot", value)
+	}
+
+	return json.Unmarshal(bytes, s)
+}
+
+// Value implements the driver.Valuer interface for ETASnapshot
+func (s ETASnapshot) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+// DigestSchedule sends a user a daily or weekly summary of the shipments matching a saved
+// filter by email. Digests are due at Hour (UTC), weekly ones on Weekday.
+type DigestSchedule struct {
+	ID     uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name   string    `json:"name" gorm:"type:varchar(100);not null"`
+	// FilterID is the saved grid filter that scopes the digest, nil covers all tracked shipments
+	FilterID  *uuid.UUID `json:"filter_id" gorm:"type:uuid;index"`
+	Frequency string     `json:"frequency" gorm:"type:varchar(10);not null"`
+	Hour      int        `json:"hour" gorm:"not null;default:7"`
+	// Weekday of weekly digests, 0 is Sunday
+	Weekday int `json:"weekday" gorm:"not null;default:1"`
+	// Recipients are the addresses the digest is sent to, empty sends it to the user
+	Recipients pq.StringArray `json:"recipients" gorm:"type:text[]"`
+	// StaleDays is how long a shipment in transit may go without container events before it is stale
+	StaleDays int  `json:"stale_days" gorm:"not null;default:7"`
+	Enabled   bool `json:"enabled" gorm:"not null;default:true"`
+	// LastSentAt is when the last digest was sent, it is set when a digest is claimed
+	LastSentAt     *time.Time  `json:"last_sent_at" gorm:"type:timestamptz"`
+	FailedAttempts int         `json:"failed_attempts" gorm:"not null;default:0"`
+	LastError      string      `json:"last_error" gorm:"type:text"`
+	ETASnapshot    ETASnapshot `json:"-" gorm:"type:jsonb"`
+	CreatedAt      time.Time   `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time   `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for DigestSchedule
+func (DigestSchedule) TableName() string {
+	return "digest_schedules"
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (d *DigestSchedule) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+// Period is the time a digest covers
+func (d *DigestSchedule) Period() time.Duration {
+	if d.Frequency == FrequencyWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// DueAt returns the most recent time at or before now the digest was scheduled for
+func (d *DigestSchedule) DueAt(now time.Time) time.Time {
+	now = now.UTC()
+	dueAt := time.Date(now.Year(), now.Month(), now.Day(), d.Hour, 0, 0, 0, time.UTC)
+
+	if d.Frequency == FrequencyWeekly {
+		dueAt = dueAt.AddDate(0, 0, -((int(now.Weekday()) - d.Weekday + 7) % 7))
+	}
+	if dueAt.After(now) {
+		dueAt = dueAt.Add(-d.Period())
+	}
+	return dueAt
+}
+
+// IsDue reports whether a digest has to be sent at now. Schedules only start with the
+// first slot after they were created.
+func (d *DigestSchedule) IsDue(now time.Time) bool {
+	if !d.Enabled {
+		return false
+	}
+	last := d.CreatedAt
+	if d.LastSentAt != nil {
+		last = *d.LastSentAt
+	}
+	return last.Before(d.DueAt(now))
+}
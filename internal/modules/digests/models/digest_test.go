@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDigestSchedule_DueAt(t *testing.T) {
+	// 2025-06-04 is a Wednesday
+	now := time.Date(2025, 6, 4, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule DigestSchedule
+		want     time.Time
+	}{
+		{"daily after the hour", DigestSchedule{Frequency: FrequencyDaily, Hour: 7}, time.Date(2025, 6, 4, 7, 0, 0, 0, time.UTC)},
+		{"daily before the hour", DigestSchedule{Frequency: FrequencyDaily, Hour: 11}, time.Date(2025, 6, 3, 11, 0, 0, 0, time.UTC)},
+		{"weekly earlier this week", DigestSchedule{Frequency: FrequencyWeekly, Hour: 7, Weekday: 1}, time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)},
+		{"weekly today after the hour", DigestSchedule{Frequency: FrequencyWeekly, Hour: 7, Weekday: 3}, time.Date(2025, 6, 4, 7, 0, 0, 0, time.UTC)},
+		{"weekly today before the hour", DigestSchedule{Frequency: FrequencyWeekly, Hour: 11, Weekday: 3}, time.Date(2025, 5, 28, 11, 0, 0, 0, time.UTC)},
+		{"weekly later this week", DigestSchedule{Frequency: FrequencyWeekly, Hour: 7, Weekday: 5}, time.Date(2025, 5, 30, 7, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.DueAt(now); !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDigestSchedule_IsDue(t *testing.T) {
+	now := time.Date(2025, 6, 4, 10, 30, 0, 0, time.UTC)
+	sentEarlier := time.Date(2025, 6, 3, 7, 0, 0, 0, time.UTC)
+	sentToday := time.Date(2025, 6, 4, 7, 5, 0, 0, time.UTC)
+
+	schedule := DigestSchedule{Frequency: FrequencyDaily, Hour: 7, Enabled: true, CreatedAt: sentEarlier.Add(-time.Hour)}
+	if !schedule.IsDue(now) {
+		t.Error("Expected a schedule that never sent to be due")
+	}
+
+	schedule.CreatedAt = time.Date(2025, 6, 4, 9, 0, 0, 0, time.UTC)
+	if schedule.IsDue(now) {
+		t.Error("Expected a new schedule to wait for its first slot")
+	}
+
+	schedule.LastSentAt = &sentEarlier
+	if !schedule.IsDue(now) {
+		t.Error("Expected the schedule to be due after its hour passed")
+	}
+
+	schedule.LastSentAt = &sentToday
+	if schedule.IsDue(now) {
+		t.Error("Expected the schedule not to be due again the same day")
+	}
+
+	schedule.LastSentAt = &sentEarlier
+	schedule.Enabled = false
+	if schedule.IsDue(now) {
+		t.Error("Expected a disabled schedule not to be due")
+	}
+}
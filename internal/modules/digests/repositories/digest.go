@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	authModels "go-starter/internal/modules/auth/models"
+	"go-starter/internal/modules/digests/models"
+	filterModels "go-starter/internal/modules/filters/models"
+	"go-starter/pkg/db"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DigestRepository struct {
+	db *db.Database
+}
+
+func NewDigestRepository(database *db.Database) *DigestRepository {
+	return &DigestRepository{
+		db: database,
+	}
+}
+
+// CreateSchedule creates a new digest schedule
+func (r *DigestRepository) CreateSchedule(ctx context.Context, schedule *models.DigestSchedule) error {
+	return r.db.DB.WithContext(ctx).Create(schedule).Error
+}
+
+// GetScheduleByID retrieves a digest schedule by its ID and user ID
+func (r *DigestRepository) GetScheduleByID(ctx context.Context, scheduleID, userID uuid.UUID) (*models.DigestSchedule, error) {
+	var schedule models.DigestSchedule
+	err := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", scheduleID, userID).
+		First(&schedule).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// GetSchedulesByUserID retrieves all digest schedules of a user
+func (r *DigestRepository) GetSchedulesByUserID(ctx context.Context, userID uuid.UUID) ([]models.DigestSchedule, error) {
+	var schedules []models.DigestSchedule
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&schedules).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// GetEnabledSchedules retrieves the enabled digest schedules of all users
+func (r *DigestRepository) GetEnabledSchedules(ctx context.Context) ([]models.DigestSchedule, error) {
+	var schedules []models.DigestSchedule
+	err := r.db.DB.WithContext(ctx).
+		Where("enabled = ?", true).
+		Find(&schedules).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enabled digest schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// UpdateSchedule updates the settings of an existing digest schedule
+func (r *DigestRepository) UpdateSchedule(ctx context.Context, schedule *models.DigestSchedule) error {
+	return r.db.DB.WithContext(ctx).
+		Model(schedule).
+		Where("id = ? AND user_id = ?", schedule.ID, schedule.UserID).
+		Updates(map[string]interface{}{
+			"name":       schedule.Name,
+			"filter_id":  schedule.FilterID,
+			"frequency":  schedule.Frequency,
+			"hour":       schedule.Hour,
+			"weekday":    schedule.Weekday,
+			"recipients": schedule.Recipients,
+			"stale_days": schedule.StaleDays,
+			"enabled":    schedule.Enabled,
+			"updated_at": gorm.Expr("NOW()"),
+		}).Error
+}
+
+// DeleteSchedule deletes a digest schedule by its ID and user ID
+func (r *DigestRepository) DeleteSchedule(ctx context.Context, scheduleID, userID uuid.UUID) error {
+	result := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", scheduleID, userID).
+		Delete(&models.DigestSchedule{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ClaimSchedule records that a digest is sent at now, unless another instance changed
+// last_sent_at since it was read as previous. It reports whether the claim succeeded.
+func (r *DigestRepository) ClaimSchedule(ctx context.Context, scheduleID uuid.UUID, previous *time.Time, now time.Time) (bool, error) {
+	query := r.db.DB.WithContext(ctx).
+		Model(&models.DigestSchedule{}).
+		Where("id = ?", scheduleID)
+	if previous == nil {
+		query = query.Where("last_sent_at IS NULL")
+	} else {
+		query = query.Where("last_sent_at = ?", *previous)
+	}
+
+	result := query.UpdateColumn("last_sent_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateScheduleFields updates the delivery state of a digest schedule
+func (r *DigestRepository) UpdateScheduleFields(ctx context.Context, scheduleID uuid.UUID, updates map[string]interface{}) error {
+	return r.db.DB.WithContext(ctx).
+		Model(&models.DigestSchedule{}).
+		Where("id = ?", scheduleID).
+		UpdateColumns(updates).Error
+}
+
+// GetFilter retrieves a saved grid filter of a user
+func (r *DigestRepository) GetFilter(ctx context.Context, filterID, userID uuid.UUID) (*filterModels.UserFilter, error) {
+	var filter filterModels.UserFilter
+	err := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", filterID, userID).
+		First(&filter).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &filter, nil
+}
+
+// GetUser retrieves the user a digest belongs to
+func (r *DigestRepository) GetUser(ctx context.Context, userID uuid.UUID) (*authModels.User, error) {
+	var user authModels.User
+	if err := r.db.DB.WithContext(ctx).First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,35 @@
+package digests
+
+import (
+	"go-starter/internal/modules/auth/middlewares"
+	"go-starter/internal/modules/auth/services"
+	"go-starter/internal/modules/digests/handlers"
+	"go-starter/internal/modules/digests/repositories"
+	digestServices "go-starter/internal/modules/digests/services"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+	"go-starter/pkg/config"
+	"go-starter/pkg/db"
+	"go-starter/pkg/mailer"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRoutes(api *echo.Group, database *db.Database, cfg *config.Config, shipmentService shipmentServices.ShipmentService, mailer mailer.Mailer) {
+	// Initialize dependencies, scheduled digests are sent by the digest delivery job
+	digestRepo := repositories.NewDigestRepository(database)
+	digestService := digestServices.NewDigestService(digestRepo, shipmentService, mailer, digestServices.NewDigestSettingsFromConfig(cfg))
+	digestAPIHandler := handlers.NewDigestAPIHandler(digestService)
+
+	// Create JWT service for middleware
+	jwtService := services.NewJWTService()
+
+	// Create digests group with JWT middleware
+	digestsGroup := api.Group("/digests", middlewares.JWTMiddleware(jwtService))
+
+	digestsGroup.GET("", digestAPIHandler.GetSchedules)              // GET /api/digests
+	digestsGroup.POST("", digestAPIHandler.CreateSchedule)           // POST /api/digests
+	digestsGroup.PUT("/:id", digestAPIHandler.UpdateSchedule)        // PUT /api/digests/:id
+	digestsGroup.DELETE("/:id", digestAPIHandler.DeleteSchedule)     // DELETE /api/digests/:id
+	digestsGroup.GET("/:id/preview", digestAPIHandler.PreviewDigest) // GET /api/digests/:id/preview
+	digestsGroup.POST("/:id/send", digestAPIHandler.SendDigest)      // POST /api/digests/:id/send
+}
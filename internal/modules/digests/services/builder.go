@@ -0,0 +1,170 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go-starter/internal/modules/digests/dto"
+	"go-starter/internal/modules/digests/models"
+	filterServices "go-starter/internal/modules/filters/services"
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+	shipmentModels "go-starter/internal/modules/shipments/models"
+
+	"github.com/google/uuid"
+)
+
+// arrivingWindow is how far ahead the digest lists arriving shipments
+const arrivingWindow = 7 * 24 * time.Hour
+
+// minETASlip ignores ETA moves of a few hours, carriers adjust those all the time
+const minETASlip = 24 * time.Hour
+
+// BuildDigest sorts the shipments in scope of a digest into its sections. since is when the
+// previous digest was sent, previous the ETAs it saw. It also returns the ETAs to compare
+// the next digest against.
+func BuildDigest(schedule models.DigestSchedule, rows []shipmentDto.ShipmentDetailsResponse, previous models.ETASnapshot, since, now time.Time, baseURL string) (*dto.Digest, models.ETASnapshot) {
+	arriving := dto.DigestSection{Key: dto.SectionArriving, Title: "Arriving in the next 7 days"}
+	slips := dto.DigestSection{Key: dto.SectionETASlips, Title: "ETA slips"}
+	delivered := dto.DigestSection{Key: dto.SectionDelivered, Title: "Newly delivered"}
+	stale := dto.DigestSection{Key: dto.SectionStale, Title: "Stale shipments"}
+	unpaid := dto.DigestSection{Key: dto.SectionUnpaid, Title: "Unpaid invoices"}
+
+	snapshot := models.ETASnapshot{}
+
+	for _, row := range rows {
+		status := shipmentModels.ShipmentStatus(row.ShippingStatus)
+		eta, arrived := podETA(row)
+		entry := newEntry(row, eta, baseURL)
+
+		if eta != nil && !arrived && !status.IsTerminal() {
+			snapshot[row.ID.String()] = *eta
+
+			if !eta.Before(now) && eta.Sub(now) <= arrivingWindow {
+				entry := entry
+				entry.Detail = fmt.Sprintf("Arrives on %s", eta.Format("2006-01-02"))
+				arriving.Entries = append(arriving.Entries, entry)
+			}
+
+			if previousETA, ok := previous[row.ID.String()]; ok && eta.Sub(previousETA) >= minETASlip {
+				entry := entry
+				entry.PreviousETA = &previousETA
+				entry.Detail = fmt.Sprintf("ETA moved later by %s", formatDays(eta.Sub(previousETA)))
+				slips.Entries = append(slips.Entries, entry)
+			}
+		}
+
+		if (status == shipmentModels.ShipmentStatusDelivered || status == shipmentModels.ShipmentStatusEmptyReturned) &&
+			row.TerminalSince != nil && !row.TerminalSince.Before(since) {
+			entry := entry
+			entry.Detail = fmt.Sprintf("Delivered on %s", row.TerminalSince.Format("2006-01-02"))
+			delivered.Entries = append(delivered.Entries, entry)
+		}
+
+		if !status.IsTerminal() && row.TrackingState != shipmentModels.TrackingStateCompleted {
+			if detail, ok := staleDetail(row, schedule.StaleDays, now); ok {
+				entry := entry
+				entry.Detail = detail
+				stale.Entries = append(stale.Entries, entry)
+			}
+		}
+
+		if row.Invoiced && !row.PaymentReceived {
+			entry := entry
+			entry.Detail = "Invoiced, payment outstanding"
+			if row.InvoiceAmount != "" {
+				entry.Detail = fmt.Sprintf("Invoiced %s, payment outstanding", row.InvoiceAmount)
+			}
+			unpaid.Entries = append(unpaid.Entries, entry)
+		}
+	}
+
+	sortByETA(arriving.Entries)
+	sortByETA(slips.Entries)
+
+	return &dto.Digest{
+		ScheduleName: schedule.Name,
+		Since:        since,
+		GeneratedAt:  now,
+		Shipments:    len(rows),
+		Sections:     []dto.DigestSection{arriving, slips, delivered, stale, unpaid},
+	}, snapshot
+}
+
+// podETA returns the ETA at the port of discharge, the predictive one when the provider has
+// one, and whether the vessel already arrived there
+func podETA(row shipmentDto.ShipmentDetailsResponse) (*time.Time, bool) {
+	pod := row.Route.Pod
+	if pod == nil {
+		return nil, false
+	}
+	arrived := pod.Actual != nil && *pod.Actual
+	if pod.PredictiveETA != nil && !arrived {
+		return pod.PredictiveETA, arrived
+	}
+	return pod.Date, arrived
+}
+
+// staleDetail reports whether the shipment had no actual container events for more than
+// staleDays, counting from when tracking started if it never had one
+func staleDetail(row shipmentDto.ShipmentDetailsResponse, staleDays int, now time.Time) (string, bool) {
+	if staleDays <= 0 {
+		return "", false
+	}
+
+	var lastEventAt *time.Time
+	for _, container := range row.Containers {
+		for _, event := range container.Events {
+			if event.IsActual && (lastEventAt == nil || event.Date.After(*lastEventAt)) {
+				date := event.Date
+				lastEventAt = &date
+			}
+		}
+	}
+
+	threshold := time.Duration(staleDays) * 24 * time.Hour
+	if lastEventAt == nil {
+		if now.Sub(row.CreatedAt) <= threshold {
+			return "", false
+		}
+		return fmt.Sprintf("No container events since tracking started %s ago", formatDays(now.Sub(row.CreatedAt))), true
+	}
+	if now.Sub(*lastEventAt) <= threshold {
+		return "", false
+	}
+	return fmt.Sprintf("No container events for %s", formatDays(now.Sub(*lastEventAt))), true
+}
+
+func newEntry(row shipmentDto.ShipmentDetailsResponse, eta *time.Time, baseURL string) dto.DigestEntry {
+	return dto.DigestEntry{
+		ShipmentID:     row.ID,
+		ShipmentNumber: row.ShipmentNumber,
+		Status:         row.ShippingStatus,
+		Destination:    filterServices.RoutePointLabel(row.Route.Pod),
+		ETA:            eta,
+		Consignee:      row.Consignee,
+		AssignedTo:     row.AssignedTo,
+		InvoiceAmount:  row.InvoiceAmount,
+		URL:            shipmentURL(baseURL, row.ID),
+	}
+}
+
+// shipmentURL links to the shipments page with the shipment's details opened
+func shipmentURL(baseURL string, shipmentID uuid.UUID) string {
+	return baseURL + "/shipments?shipment=" + shipmentID.String()
+}
+
+func sortByETA(entries []dto.DigestEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].ETA.Before(*entries[j].ETA)
+	})
+}
+
+// formatDays formats a duration as whole days
+func formatDays(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
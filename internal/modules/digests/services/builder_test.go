@@ -0,0 +1,136 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"go-starter/internal/modules/digests/dto"
+	"go-starter/internal/modules/digests/models"
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+
+	"github.com/google/uuid"
+)
+
+func digestRow(number, status string, eta *time.Time) shipmentDto.ShipmentDetailsResponse {
+	row := shipmentDto.ShipmentDetailsResponse{
+		ID:             uuid.New(),
+		ShipmentNumber: number,
+		ShippingStatus: status,
+		TrackingState:  "active",
+	}
+	if eta != nil {
+		row.Route.Pod = &shipmentDto.ShipmentRoutePoint{Date: eta}
+	}
+	return row
+}
+
+func sectionNumbers(digest *dto.Digest, key string) []string {
+	var numbers []string
+	for _, section := range digest.Sections {
+		if section.Key != key {
+			continue
+		}
+		for _, entry := range section.Entries {
+			numbers = append(numbers, entry.ShipmentNumber)
+		}
+	}
+	return numbers
+}
+
+func TestBuildDigest(t *testing.T) {
+	now := time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)
+	since := now.Add(-24 * time.Hour)
+	at := func(days int) *time.Time {
+		t := now.Add(time.Duration(days) * 24 * time.Hour)
+		return &t
+	}
+
+	arriving := digestRow("ARRIVING", "IN_TRANSIT", at(3))
+	arriving.CreatedAt = now
+	later := digestRow("LATER", "IN_TRANSIT", at(20))
+	later.CreatedAt = now
+	slipped := digestRow("SLIPPED", "IN_TRANSIT", at(5))
+	slipped.CreatedAt = now
+	delivered := digestRow("DELIVERED", "DELIVERED", nil)
+	delivered.TerminalSince = at(0)
+	oldDelivery := digestRow("OLD_DELIVERY", "DELIVERED", nil)
+	oldDelivery.TerminalSince = at(-10)
+	stale := digestRow("STALE", "IN_TRANSIT", at(30))
+	stale.Containers = []shipmentDto.ShipmentContainerResponse{{
+		Events: []shipmentDto.ShipmentContainerEventResponse{{Date: *at(-9), IsActual: true}},
+	}}
+	unpaid := digestRow("UNPAID", "DELIVERED", nil)
+	unpaid.Invoiced = true
+	unpaid.InvoiceAmount = "1200 EUR"
+	paid := digestRow("PAID", "DELIVERED", nil)
+	paid.Invoiced = true
+	paid.PaymentReceived = true
+
+	previous := models.ETASnapshot{
+		slipped.ID.String(): *at(2),
+		later.ID.String():   now.Add(20*24*time.Hour - time.Hour),
+	}
+	schedule := models.DigestSchedule{Name: "Morning digest", Frequency: models.FrequencyDaily, StaleDays: 7}
+	rows := []shipmentDto.ShipmentDetailsResponse{later, arriving, slipped, delivered, oldDelivery, stale, unpaid, paid}
+
+	digest, snapshot := BuildDigest(schedule, rows, previous, since, now, "https://tracker.example.com")
+
+	expect := map[string][]string{
+		dto.SectionArriving:  {"ARRIVING", "SLIPPED"},
+		dto.SectionETASlips:  {"SLIPPED"},
+		dto.SectionDelivered: {"DELIVERED"},
+		dto.SectionStale:     {"STALE"},
+		dto.SectionUnpaid:    {"UNPAID"},
+	}
+	for key, want := range expect {
+		got := sectionNumbers(digest, key)
+		if len(got) != len(want) {
+			t.Errorf("Section %s: expected %v, got %v", key, want, got)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("Section %s: expected %v, got %v", key, want, got)
+				break
+			}
+		}
+	}
+
+	if len(snapshot) != 4 {
+		t.Errorf("Expected the ETAs of the 4 shipments in transit, got %d", len(snapshot))
+	}
+	if !snapshot[slipped.ID.String()].Equal(*at(5)) {
+		t.Errorf("Expected the snapshot to hold the new ETA, got %v", snapshot[slipped.ID.String()])
+	}
+	if digest.Shipments != len(rows) || digest.IsEmpty() {
+		t.Errorf("Unexpected digest %+v", digest)
+	}
+	if url := digest.Sections[0].Entries[0].URL; url != "https://tracker.example.com/shipments?shipment="+arriving.ID.String() {
+		t.Errorf("Unexpected shipment link %s", url)
+	}
+}
+
+func TestBuildDigest_StaleWithoutEvents(t *testing.T) {
+	now := time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)
+
+	recent := digestRow("RECENT", "PLANNED", nil)
+	recent.CreatedAt = now.Add(-2 * 24 * time.Hour)
+	old := digestRow("OLD", "PLANNED", nil)
+	old.CreatedAt = now.Add(-10 * 24 * time.Hour)
+	completed := digestRow("COMPLETED", "PLANNED", nil)
+	completed.CreatedAt = old.CreatedAt
+	completed.TrackingState = "completed"
+
+	schedule := models.DigestSchedule{StaleDays: 7}
+	digest, _ := BuildDigest(schedule, []shipmentDto.ShipmentDetailsResponse{recent, old, completed}, nil, now, now, "")
+
+	if got := sectionNumbers(digest, dto.SectionStale); len(got) != 1 || got[0] != "OLD" {
+		t.Errorf("Expected only OLD to be stale, got %v", got)
+	}
+
+	schedule.StaleDays = 0
+	digest, _ = BuildDigest(schedule, []shipmentDto.ShipmentDetailsResponse{old}, nil, now, now, "")
+	if !digest.IsEmpty() {
+		t.Errorf("Expected no stale shipments when stale days is 0")
+	}
+}
@@ -0,0 +1,379 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-starter/internal/modules/digests/dto"
+	"go-starter/internal/modules/digests/models"
+	"go-starter/internal/modules/digests/repositories"
+	"go-starter/internal/modules/digests/views"
+	filterServices "go-starter/internal/modules/filters/services"
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+	"go-starter/pkg/config"
+	"go-starter/pkg/mailer"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// defaultStaleDays is how long a shipment may go without events before a digest lists it as stale
+const defaultStaleDays = 7
+
+// maxDigestAttempts is how often a scheduled digest is tried before it is skipped until the next one
+const maxDigestAttempts = 3
+
+// DigestSettings holds what digests need from the application config
+type DigestSettings struct {
+	// BaseURL is the public URL of the app used for links in digests
+	BaseURL string
+}
+
+// NewDigestSettingsFromConfig creates digest settings from the application config
+func NewDigestSettingsFromConfig(cfg *config.Config) DigestSettings {
+	return DigestSettings{
+		BaseURL: cfg.AppBaseURL,
+	}
+}
+
+type DigestService struct {
+	digestRepo      *repositories.DigestRepository
+	shipmentService shipmentServices.ShipmentService
+	mailer          mailer.Mailer
+	settings        DigestSettings
+}
+
+func NewDigestService(
+	digestRepo *repositories.DigestRepository,
+	shipmentService shipmentServices.ShipmentService,
+	mailer mailer.Mailer,
+	settings DigestSettings,
+) *DigestService {
+	return &DigestService{
+		digestRepo:      digestRepo,
+		shipmentService: shipmentService,
+		mailer:          mailer,
+		settings:        settings,
+	}
+}
+
+// SendDueDigests sends every digest whose scheduled time has passed. Schedules are claimed
+// one by one so every instance running the digest job can call it. A digest that fails is
+// tried again on the next run, up to maxDigestAttempts times. It returns how many were sent.
+func (s *DigestService) SendDueDigests(ctx context.Context, now time.Time) (int, error) {
+	schedules, err := s.digestRepo.GetEnabledSchedules(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, schedule := range schedules {
+		if ctx.Err() != nil {
+			break
+		}
+		if !schedule.IsDue(now) {
+			continue
+		}
+
+		claimed, err := s.digestRepo.ClaimSchedule(ctx, schedule.ID, schedule.LastSentAt, now)
+		if err != nil {
+			return sent, fmt.Errorf("error claiming digest %s: %w", schedule.ID, err)
+		}
+		if !claimed {
+			continue
+		}
+
+		since := schedule.DueAt(now).Add(-schedule.Period())
+		if schedule.LastSentAt != nil {
+			since = *schedule.LastSentAt
+		}
+
+		snapshot, delivered, err := s.send(ctx, schedule, since, now)
+		s.recordOutcome(ctx, schedule, snapshot, err)
+		if delivered {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// recordOutcome stores the ETAs the next digest compares against, or the error. Failed
+// digests are released so the next run tries them again.
+func (s *DigestService) recordOutcome(ctx context.Context, schedule models.DigestSchedule, snapshot models.ETASnapshot, err error) {
+	updates := map[string]interface{}{
+		"failed_attempts": 0,
+		"last_error":      "",
+		"eta_snapshot":    snapshot,
+	}
+	if err != nil {
+		attempts := schedule.FailedAttempts + 1
+		updates = map[string]interface{}{
+			"failed_attempts": attempts,
+			"last_error":      err.Error(),
+		}
+		if attempts < maxDigestAttempts {
+			updates["last_sent_at"] = schedule.LastSentAt
+			log.Printf("Digest %s failed (attempt %d), retrying: %v", schedule.ID, attempts, err)
+		} else {
+			updates["failed_attempts"] = 0
+			log.Printf("Giving up on digest %s after %d attempts: %v", schedule.ID, attempts, err)
+		}
+	}
+
+	// Record the outcome even when the job is being stopped
+	if updateErr := s.digestRepo.UpdateScheduleFields(context.WithoutCancel(ctx), schedule.ID, updates); updateErr != nil {
+		log.Printf("Warning: Failed to record outcome of digest %s: %v", schedule.ID, updateErr)
+	}
+}
+
+// send builds the digest and emails it. Digests without anything to report aren't sent.
+// It returns the ETAs the digest saw and whether an email was sent.
+func (s *DigestService) send(ctx context.Context, schedule models.DigestSchedule, since, now time.Time) (models.ETASnapshot, bool, error) {
+	digest, snapshot, err := s.build(ctx, schedule, since, now)
+	if err != nil {
+		return nil, false, err
+	}
+	if digest.IsEmpty() {
+		return snapshot, false, nil
+	}
+
+	if err := s.deliver(ctx, schedule, digest); err != nil {
+		return nil, false, err
+	}
+
+	return snapshot, true, nil
+}
+
+// build collects the shipments in scope of the schedule and sorts them into the digest sections
+func (s *DigestService) build(ctx context.Context, schedule models.DigestSchedule, since, now time.Time) (*dto.Digest, models.ETASnapshot, error) {
+	rows, err := s.scopedRows(ctx, schedule, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	digest, snapshot := BuildDigest(schedule, rows, schedule.ETASnapshot, since, now, s.settings.BaseURL)
+	return digest, snapshot, nil
+}
+
+// scopedRows returns the user's grid rows that pass the schedule's saved filter
+func (s *DigestService) scopedRows(ctx context.Context, schedule models.DigestSchedule, now time.Time) ([]shipmentDto.ShipmentDetailsResponse, error) {
+	var filterModel map[string]interface{}
+	if schedule.FilterID != nil {
+		filter, err := s.digestRepo.GetFilter(ctx, *schedule.FilterID, schedule.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("saved filter not found")
+			}
+			return nil, fmt.Errorf("error retrieving saved filter: %w", err)
+		}
+		filterModel, _ = filter.FilterData.Filters.(map[string]interface{})
+	}
+
+	grid, err := s.shipmentService.GetShipmentsForGrid(ctx, schedule.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]shipmentDto.ShipmentDetailsResponse, 0, len(grid.Rows))
+	for _, row := range grid.Rows {
+		if filterServices.MatchesFilterModel(filterModel, row, now) {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// deliver renders the digest and emails it with the CSV attached
+func (s *DigestService) deliver(ctx context.Context, schedule models.DigestSchedule, digest *dto.Digest) error {
+	user, err := s.digestRepo.GetUser(ctx, schedule.UserID)
+	if err != nil {
+		return fmt.Errorf("error retrieving user: %w", err)
+	}
+
+	email, err := views.RenderDigestEmail(ctx, views.DigestEmailData{
+		RecipientName: user.FirstName,
+		Digest:        digest,
+		ShipmentsURL:  s.settings.BaseURL + "/shipments",
+	})
+	if err != nil {
+		return err
+	}
+
+	recipients := []string(schedule.Recipients)
+	if len(recipients) == 0 {
+		recipients = []string{user.Email}
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:       recipients,
+		Subject:  email.Subject,
+		TextBody: email.TextBody,
+		HTMLBody: email.HTMLBody,
+		Attachments: []mailer.Attachment{{
+			Filename:    email.CSVFilename,
+			ContentType: "text/csv; charset=utf-8",
+			Data:        email.CSV,
+		}},
+	})
+}
+
+// PreviewDigest builds the digest the schedule would send now, without sending it
+func (s *DigestService) PreviewDigest(ctx context.Context, userID, scheduleID uuid.UUID) (*dto.Digest, error) {
+	schedule, err := s.getSchedule(ctx, userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	digest, _, err := s.build(ctx, *schedule, s.previewSince(schedule, now), now)
+	if err != nil {
+		return nil, err
+	}
+
+	return digest, nil
+}
+
+// SendDigestNow emails the digest the schedule would send now. It doesn't change the
+// schedule, the next scheduled digest still covers everything since the previous one.
+func (s *DigestService) SendDigestNow(ctx context.Context, userID, scheduleID uuid.UUID) (*dto.Digest, error) {
+	schedule, err := s.getSchedule(ctx, userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	digest, _, err := s.build(ctx, *schedule, s.previewSince(schedule, now), now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deliver(ctx, *schedule, digest); err != nil {
+		return nil, fmt.Errorf("failed to send digest: %w", err)
+	}
+
+	return digest, nil
+}
+
+func (s *DigestService) previewSince(schedule *models.DigestSchedule, now time.Time) time.Time {
+	if schedule.LastSentAt != nil {
+		return *schedule.LastSentAt
+	}
+	return now.Add(-schedule.Period())
+}
+
+// CreateSchedule validates and creates a new digest schedule
+func (s *DigestService) CreateSchedule(ctx context.Context, userID uuid.UUID, req *dto.SaveScheduleRequest) (*models.DigestSchedule, error) {
+	schedule := &models.DigestSchedule{UserID: userID}
+	if err := s.applyScheduleRequest(ctx, schedule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.digestRepo.CreateSchedule(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("error creating digest schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// GetSchedules retrieves all digest schedules of a user
+func (s *DigestService) GetSchedules(ctx context.Context, userID uuid.UUID) (*dto.SchedulesListResponse, error) {
+	schedules, err := s.digestRepo.GetSchedulesByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving digest schedules: %w", err)
+	}
+
+	return &dto.SchedulesListResponse{
+		Schedules: schedules,
+		Total:     len(schedules),
+	}, nil
+}
+
+// UpdateSchedule validates and updates an existing digest schedule
+func (s *DigestService) UpdateSchedule(ctx context.Context, userID, scheduleID uuid.UUID, req *dto.SaveScheduleRequest) (*models.DigestSchedule, error) {
+	schedule, err := s.getSchedule(ctx, userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyScheduleRequest(ctx, schedule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.digestRepo.UpdateSchedule(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("error updating digest schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// DeleteSchedule deletes a digest schedule
+func (s *DigestService) DeleteSchedule(ctx context.Context, userID, scheduleID uuid.UUID) error {
+	if err := s.digestRepo.DeleteSchedule(ctx, scheduleID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("digest schedule not found")
+		}
+		return fmt.Errorf("error deleting digest schedule: %w", err)
+	}
+
+	return nil
+}
+
+func (s *DigestService) getSchedule(ctx context.Context, userID, scheduleID uuid.UUID) (*models.DigestSchedule, error) {
+	schedule, err := s.digestRepo.GetScheduleByID(ctx, scheduleID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("digest schedule not found")
+		}
+		return nil, fmt.Errorf("error retrieving digest schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// applyScheduleRequest validates the request and copies it onto the schedule. The saved
+// filter must belong to the user.
+func (s *DigestService) applyScheduleRequest(ctx context.Context, schedule *models.DigestSchedule, req *dto.SaveScheduleRequest) error {
+	if req.Frequency != models.FrequencyDaily && req.Frequency != models.FrequencyWeekly {
+		return fmt.Errorf("invalid frequency '%s'", req.Frequency)
+	}
+
+	if req.FilterID != nil {
+		if _, err := s.digestRepo.GetFilter(ctx, *req.FilterID, schedule.UserID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("invalid filter_id, saved filter not found")
+			}
+			return fmt.Errorf("error retrieving saved filter: %w", err)
+		}
+	}
+
+	recipients := pq.StringArray{}
+	for _, recipient := range req.Recipients {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			recipients = append(recipients, recipient)
+		}
+	}
+
+	schedule.Name = strings.TrimSpace(req.Name)
+	schedule.FilterID = req.FilterID
+	schedule.Frequency = req.Frequency
+	schedule.Hour = req.Hour
+	schedule.Weekday = req.Weekday
+	schedule.Recipients = recipients
+	schedule.StaleDays = defaultStaleDays
+	if req.StaleDays != nil {
+		schedule.StaleDays = *req.StaleDays
+	}
+	schedule.Enabled = true
+	if req.Enabled != nil {
+		schedule.Enabled = *req.Enabled
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package views
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"go-starter/internal/modules/digests/dto"
+)
+
+// maxEntriesPerSection keeps long sections from bloating the email, the CSV lists them all
+const maxEntriesPerSection = 25
+
+// DigestEmailData is rendered into a digest email
+type DigestEmailData struct {
+	RecipientName string
+	Digest        *dto.Digest
+	ShipmentsURL  string
+}
+
+// RenderedDigest is a digest email ready to be sent
+type RenderedDigest struct {
+	Subject     string
+	TextBody    string
+	HTMLBody    string
+	CSVFilename string
+	CSV         []byte
+}
+
+var digestTextTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"visible": visibleEntries,
+	"hidden":  hiddenEntries,
+}).Parse(`Hello {{.RecipientName}},
+
+Here is your {{.Digest.ScheduleName}} digest of {{.Digest.Shipments}} shipments since {{.Digest.Since.Format "2006-01-02 15:04 MST"}}.
+{{range .Digest.Sections}}
+{{.Title}} ({{len .Entries}})
+{{range visible .}}- {{.ShipmentNumber}}: {{.Detail}}
+{{else}}Nothing to report.
+{{end}}{{with hidden .}}and {{.}} more, see the attached CSV.
+{{end}}{{end}}
+Open your shipments: {{.ShipmentsURL}}
+
+You receive this email because of your shipment digest schedule.
+`))
+
+// RenderDigestEmail renders the HTML and plain-text digest email and its CSV attachment
+func RenderDigestEmail(ctx context.Context, data DigestEmailData) (*RenderedDigest, error) {
+	var text bytes.Buffer
+	if err := digestTextTemplate.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to render digest email text: %w", err)
+	}
+
+	var html bytes.Buffer
+	if err := DigestEmail(data).Render(ctx, &html); err != nil {
+		return nil, fmt.Errorf("failed to render digest email html: %w", err)
+	}
+
+	csvData, err := RenderDigestCSV(data.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RenderedDigest{
+		Subject:     digestSubject(data.Digest),
+		TextBody:    text.String(),
+		HTMLBody:    html.String(),
+		CSVFilename: fmt.Sprintf("digest-%s.csv", data.Digest.GeneratedAt.UTC().Format("2006-01-02")),
+		CSV:         csvData,
+	}, nil
+}
+
+// RenderDigestCSV lists every entry of the digest, one row per section a shipment appears in
+func RenderDigestCSV(digest *dto.Digest) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	rows := [][]string{{
+		"Section", "Shipment Number", "Status", "Destination", "ETA", "Previous ETA",
+		"Details", "Consignee", "Assigned To", "Invoice Amount", "Link",
+	}}
+	for _, section := range digest.Sections {
+		for _, entry := range section.Entries {
+			rows = append(rows, []string{
+				section.Title,
+				entry.ShipmentNumber,
+				entry.Status,
+				entry.Destination,
+				formatDate(entry.ETA),
+				formatDate(entry.PreviousETA),
+				entry.Detail,
+				entry.Consignee,
+				entry.AssignedTo,
+				entry.InvoiceAmount,
+				entry.URL,
+			})
+		}
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to render digest csv: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// digestSubject names the schedule and its most important numbers
+func digestSubject(digest *dto.Digest) string {
+	var counts []string
+	for _, section := range digest.Sections {
+		if len(section.Entries) == 0 {
+			continue
+		}
+		switch section.Key {
+		case dto.SectionArriving:
+			counts = append(counts, fmt.Sprintf("%d arriving", len(section.Entries)))
+		case dto.SectionETASlips:
+			counts = append(counts, fmt.Sprintf("%d ETA slips", len(section.Entries)))
+		case dto.SectionDelivered:
+			counts = append(counts, fmt.Sprintf("%d delivered", len(section.Entries)))
+		}
+	}
+
+	if len(counts) == 0 {
+		return digest.ScheduleName
+	}
+	return fmt.Sprintf("%s: %s", digest.ScheduleName, strings.Join(counts, ", "))
+}
+
+func visibleEntries(section dto.DigestSection) []dto.DigestEntry {
+	return section.Entries[:min(len(section.Entries), maxEntriesPerSection)]
+}
+
+func hiddenEntries(section dto.DigestSection) int {
+	return max(len(section.Entries)-maxEntriesPerSection, 0)
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02")
+}
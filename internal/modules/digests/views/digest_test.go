@@ -0,0 +1,101 @@
+package views
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"go-starter/internal/modules/digests/dto"
+)
+
+func TestRenderDigestEmail(t *testing.T) {
+	eta := time.Date(2025, 6, 4, 8, 0, 0, 0, time.UTC)
+	previous := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	digest := &dto.Digest{
+		ScheduleName: "Morning digest",
+		Since:        time.Date(2025, 6, 1, 7, 0, 0, 0, time.UTC),
+		GeneratedAt:  time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC),
+		Shipments:    12,
+		Sections: []dto.DigestSection{
+			{Key: dto.SectionArriving, Title: "Arriving in the next 7 days", Entries: []dto.DigestEntry{
+				{ShipmentNumber: "MSCU1234567", Destination: "Rotterdam (NLRTM)", ETA: &eta, Detail: "Arrives on 2025-06-04", Consignee: "Acme, Inc.", URL: "https://tracker.example.com/shipments?shipment=1"},
+			}},
+			{Key: dto.SectionETASlips, Title: "ETA slips", Entries: []dto.DigestEntry{
+				{ShipmentNumber: "MSCU1234567", ETA: &eta, PreviousETA: &previous, Detail: "ETA moved later by 3 days <via transshipment>"},
+			}},
+			{Key: dto.SectionStale, Title: "Stale shipments"},
+		},
+	}
+
+	email, err := RenderDigestEmail(context.Background(), DigestEmailData{
+		RecipientName: "Ada",
+		Digest:        digest,
+		ShipmentsURL:  "https://tracker.example.com/shipments",
+	})
+	if err != nil {
+		t.Fatalf("RenderDigestEmail failed: %v", err)
+	}
+
+	if email.Subject != "Morning digest: 1 arriving, 1 ETA slips" {
+		t.Errorf("Unexpected subject %q", email.Subject)
+	}
+	if email.CSVFilename != "digest-2025-06-02.csv" {
+		t.Errorf("Unexpected CSV filename %q", email.CSVFilename)
+	}
+	if !strings.Contains(email.TextBody, "- MSCU1234567: ETA moved later by 3 days <via transshipment>") {
+		t.Errorf("Expected plain text to list the slip unescaped, got:\n%s", email.TextBody)
+	}
+	if !strings.Contains(email.HTMLBody, "&lt;via transshipment&gt;") {
+		t.Errorf("Expected HTML to be escaped, got:\n%s", email.HTMLBody)
+	}
+	if !strings.Contains(email.HTMLBody, `href="https://tracker.example.com/shipments?shipment=1"`) {
+		t.Errorf("Expected a link to the shipment, got:\n%s", email.HTMLBody)
+	}
+	if !strings.Contains(email.TextBody, "Stale shipments (0)\nNothing to report.") {
+		t.Errorf("Expected empty sections to say so, got:\n%s", email.TextBody)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(email.CSV)).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected a header and 2 rows, got %d", len(records))
+	}
+	if records[1][0] != "Arriving in the next 7 days" || records[1][7] != "Acme, Inc." {
+		t.Errorf("Unexpected CSV row %v", records[1])
+	}
+	if records[2][4] != "2025-06-04" || records[2][5] != "2025-06-01" {
+		t.Errorf("Expected the ETA and previous ETA, got %v", records[2])
+	}
+}
+
+func TestRenderDigestEmail_TruncatesLongSections(t *testing.T) {
+	entries := make([]dto.DigestEntry, maxEntriesPerSection+5)
+	for i := range entries {
+		entries[i] = dto.DigestEntry{ShipmentNumber: "MSCU1234567", Detail: "Invoiced, payment outstanding"}
+	}
+	digest := &dto.Digest{
+		ScheduleName: "Weekly digest",
+		GeneratedAt:  time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC),
+		Sections:     []dto.DigestSection{{Key: dto.SectionUnpaid, Title: "Unpaid invoices", Entries: entries}},
+	}
+
+	email, err := RenderDigestEmail(context.Background(), DigestEmailData{RecipientName: "Ada", Digest: digest})
+	if err != nil {
+		t.Fatalf("RenderDigestEmail failed: %v", err)
+	}
+
+	if !strings.Contains(email.TextBody, "and 5 more, see the attached CSV.") || !strings.Contains(email.HTMLBody, "and 5 more") {
+		t.Errorf("Expected the hidden entries to be mentioned")
+	}
+	if strings.Count(email.TextBody, "- MSCU1234567") != maxEntriesPerSection {
+		t.Errorf("Expected %d listed entries", maxEntriesPerSection)
+	}
+	if lines := strings.Count(string(email.CSV), "\n"); lines != len(entries)+1 {
+		t.Errorf("Expected the CSV to list every entry, got %d lines", lines)
+	}
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"time"
+
+	"go-starter/internal/modules/emissions/models"
+)
+
+// SaveFactorRequest represents the request to create or update an emission factor
+type SaveFactorRequest struct {
+	Name          string      `json:"name" validate:"required,max=100"`
+	Mode          models.Mode `json:"mode" validate:"required,oneof=sea land"`
+	ContainerType string      `json:"container_type" validate:"max=50"`
+	GramsPerTEUKm float64     `json:"grams_per_teu_km" validate:"gt=0,max=10000"`
+}
+
+// FactorsListResponse represents the response when listing emission factors, Defaults are
+// the grams of CO2e per TEU-km of each mode that apply where no factor of the user matches
+type FactorsListResponse struct {
+	Factors  []models.EmissionFactor `json:"factors"`
+	Defaults map[models.Mode]float64 `json:"defaults"`
+	Total    int                     `json:"total"`
+}
+
+// Report groupings
+const (
+	GroupByCustomer = "customer"
+	GroupByPeriod   = "period"
+)
+
+// Report periods
+const (
+	PeriodMonth   = "month"
+	PeriodQuarter = "quarter"
+	PeriodYear    = "year"
+)
+
+// ReportGroup is the CO2e of the shipments of a customer or a period
+type ReportGroup struct {
+	// Key is the consignee for customer groups, e.g. 2025-06, 2025-Q2 or 2025 for periods
+	Key        string  `json:"key"`
+	Shipments  int     `json:"shipments"`
+	Containers int     `json:"containers"`
+	TEU        float64 `json:"teu"`
+	TotalKg    float64 `json:"totalKg"`
+}
+
+// ReportResponse aggregates the CO2e of the user's shipments that departed between From and To
+type ReportResponse struct {
+	GroupBy   string        `json:"groupBy"`
+	Period    string        `json:"period"`
+	From      *time.Time    `json:"from"`
+	To        *time.Time    `json:"to"`
+	Groups    []ReportGroup `json:"groups"`
+	Shipments int           `json:"shipments"`
+	TotalKg   float64       `json:"totalKg"`
+}
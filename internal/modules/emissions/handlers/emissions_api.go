@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"go-starter/internal/modules/auth/services"
+	"go-starter/internal/modules/emissions/dto"
+	emissionServices "go-starter/internal/modules/emissions/services"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type EmissionsAPIHandler struct {
+	emissionService *emissionServices.EmissionService
+	validator       *validator.Validate
+}
+
+func NewEmissionsAPIHandler(emissionService *emissionServices.EmissionService) *EmissionsAPIHandler {
+	return &EmissionsAPIHandler{
+		emissionService: emissionService,
+		validator:       validator.New(),
+	}
+}
+
+// GetShipmentEmissions handles GET /api/emissions/shipments/:id
+func (h *EmissionsAPIHandler) GetShipmentEmissions(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid shipment ID",
+		})
+	}
+
+	emissions, err := h.emissionService.GetShipmentEmissions(ctx, userID, shipmentID)
+	if err != nil {
+		return h.serviceError(c, "Failed to estimate shipment emissions", err)
+	}
+
+	return c.JSON(http.StatusOK, emissions)
+}
+
+// GetReport handles GET /api/emissions/report?group_by=&period=&from=&to=, from and to are
+// dates and both days are included
+func (h *EmissionsAPIHandler) GetReport(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	options := emissionServices.ReportOptions{
+		GroupBy: c.QueryParam("group_by"),
+		Period:  c.QueryParam("period"),
+	}
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		from, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid from date, expected YYYY-MM-DD",
+			})
+		}
+		options.From = &from
+	}
+	if toStr := c.QueryParam("to"); toStr != "" {
+		to, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid to date, expected YYYY-MM-DD",
+			})
+		}
+		to = to.AddDate(0, 0, 1)
+		options.To = &to
+	}
+
+	report, err := h.emissionService.GetReport(ctx, userID, options)
+	if err != nil {
+		return h.serviceError(c, "Failed to build emissions report", err)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// GetFactors handles GET /api/emissions/factors
+func (h *EmissionsAPIHandler) GetFactors(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	response, err := h.emissionService.GetFactors(ctx, userID)
+	if err != nil {
+		return h.serviceError(c, "Failed to retrieve emission factors", err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// CreateFactor handles POST /api/emissions/factors
+func (h *EmissionsAPIHandler) CreateFactor(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.SaveFactorRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Validation failed: " + err.Error(),
+		})
+	}
+
+	factor, err := h.emissionService.CreateFactor(ctx, userID, &req)
+	if err != nil {
+		return h.serviceError(c, "Failed to create emission factor", err)
+	}
+
+	return c.JSON(http.StatusCreated, factor)
+}
+
+// UpdateFactor handles PUT /api/emissions/factors/:id
+func (h *EmissionsAPIHandler) UpdateFactor(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	factorID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid emission factor ID",
+		})
+	}
+
+	var req dto.SaveFactorRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Validation failed: " + err.Error(),
+		})
+	}
+
+	factor, err := h.emissionService.UpdateFactor(ctx, userID, factorID, &req)
+	if err != nil {
+		return h.serviceError(c, "Failed to update emission factor", err)
+	}
+
+	return c.JSON(http.StatusOK, factor)
+}
+
+// DeleteFactor handles DELETE /api/emissions/factors/:id
+func (h *EmissionsAPIHandler) DeleteFactor(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	factorID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid emission factor ID",
+		})
+	}
+
+	if err := h.emissionService.DeleteFactor(ctx, userID, factorID); err != nil {
+		return h.serviceError(c, "Failed to delete emission factor", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Emission factor deleted successfully",
+	})
+}
+
+// serviceError maps service errors to status codes, validation errors are returned as is
+func (h *EmissionsAPIHandler) serviceError(c echo.Context, message string, err error) error {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	case strings.HasPrefix(err.Error(), "invalid"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message + ": " + err.Error(),
+	})
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Mode is the transport mode of a route leg
+type Mode string
+
+const (
+	// ModeSea are legs on a vessel
+	ModeSea Mode = "sea"
+	// ModeLand are legs by truck or rail
+	ModeLand Mode = "land"
+)
+
+// IsValid reports whether the mode is known
+func (m Mode) IsValid() bool {
+	return m == ModeSea || m == ModeLand
+}
+
+// ModeForRouteType maps the route type of a route segment, e.g. SEA or LAND, to a mode
+func ModeForRouteType(routeType string) (Mode, bool) {
+	mode := Mode(strings.ToLower(strings.TrimSpace(routeType)))
+	return mode, mode.IsValid()
+}
+
+// EmissionFactor is the well-to-wheel CO2e a user assumes for a TEU moved one kilometer in
+// a mode. An empty ContainerType matches any container, the most specific matching factor
+// applies to a container and the built-in defaults to containers no factor covers.
+type EmissionFactor struct {
+	ID     uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name   string    `json:"name" gorm:"type:varchar(100);not null"`
+	Mode   Mode      `json:"mode" gorm:"type:varchar(10);not null"`
+	// ContainerType is matched against the ISO code and the size type, e.g. 45R1 or 40RF
+	ContainerType string    `json:"container_type" gorm:"type:varchar(50)"`
+	GramsPerTEUKm float64   `json:"grams_per_teu_km" gorm:"type:numeric(10,2);not null"`
+	CreatedAt     time.Time `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for EmissionFactor
+func (EmissionFactor) TableName() string {
+	return "emission_factors"
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (f *EmissionFactor) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return nil
+}
+
+// Matches reports whether the factor applies to a container on a leg of the mode
+func (f *EmissionFactor) Matches(mode Mode, isoCode, sizeType string) bool {
+	if f.Mode != mode {
+		return false
+	}
+	if f.ContainerType != "" && !strings.EqualFold(f.ContainerType, isoCode) && !strings.EqualFold(f.ContainerType, sizeType) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+
+	"go-starter/internal/modules/emissions/models"
+	"go-starter/pkg/db"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type EmissionFactorRepository struct {
+	db *db.Database
+}
+
+func NewEmissionFactorRepository(database *db.Database) *EmissionFactorRepository {
+	return &EmissionFactorRepository{
+		db: database,
+	}
+}
+
+// CreateFactor creates a new emission factor
+func (r *EmissionFactorRepository) CreateFactor(ctx context.Context, factor *models.EmissionFactor) error {
+	return r.db.DB.WithContext(ctx).Create(factor).Error
+}
+
+// GetFactorByID retrieves an emission factor by its ID and user ID
+func (r *EmissionFactorRepository) GetFactorByID(ctx context.Context, factorID, userID uuid.UUID) (*models.EmissionFactor, error) {
+	var factor models.EmissionFactor
+	err := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", factorID, userID).
+		First(&factor).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &factor, nil
+}
+
+// GetFactorsByUserID retrieves all emission factors of a user, oldest first
+func (r *EmissionFactorRepository) GetFactorsByUserID(ctx context.Context, userID uuid.UUID) ([]models.EmissionFactor, error) {
+	var factors []models.EmissionFactor
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&factors).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return factors, nil
+}
+
+// UpdateFactor updates an existing emission factor
+func (r *EmissionFactorRepository) UpdateFactor(ctx context.Context, factor *models.EmissionFactor) error {
+	return r.db.DB.WithContext(ctx).
+		Model(factor).
+		Where("id = ? AND user_id = ?", factor.ID, factor.UserID).
+		Updates(map[string]interface{}{
+			"name":             factor.Name,
+			"mode":             factor.Mode,
+			"container_type":   factor.ContainerType,
+			"grams_per_teu_km": factor.GramsPerTEUKm,
+			"updated_at":       gorm.Expr("NOW()"),
+		}).Error
+}
+
+// DeleteFactor deletes an emission factor by its ID and user ID
+func (r *EmissionFactorRepository) DeleteFactor(ctx context.Context, factorID, userID uuid.UUID) error {
+	result := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", factorID, userID).
+		Delete(&models.EmissionFactor{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package emissions
+
+import (
+	"go-starter/internal/modules/auth/middlewares"
+	"go-starter/internal/modules/auth/services"
+	"go-starter/internal/modules/emissions/handlers"
+	"go-starter/internal/modules/emissions/repositories"
+	emissionServices "go-starter/internal/modules/emissions/services"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+	"go-starter/pkg/db"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRoutes(api *echo.Group, database *db.Database, shipmentService shipmentServices.ShipmentService) {
+	// Initialize dependencies
+	factorRepo := repositories.NewEmissionFactorRepository(database)
+	emissionService := emissionServices.NewEmissionService(factorRepo, shipmentService)
+	emissionsAPIHandler := handlers.NewEmissionsAPIHandler(emissionService)
+
+	// Add the CO2e estimate to shipment details and grid rows, exports and reports read it from there
+	shipmentServices.RegisterDetailsDecorator(emissionService.DecorateDetails)
+
+	// Create JWT service for middleware
+	jwtService := services.NewJWTService()
+
+	// Create emissions group with JWT middleware
+	emissionsGroup := api.Group("/emissions", middlewares.JWTMiddleware(jwtService))
+
+	emissionsGroup.GET("/shipments/:id", emissionsAPIHandler.GetShipmentEmissions) // GET /api/emissions/shipments/:id
+	emissionsGroup.GET("/report", emissionsAPIHandler.GetReport)                   // GET /api/emissions/report
+	emissionsGroup.GET("/factors", emissionsAPIHandler.GetFactors)                 // GET /api/emissions/factors
+	emissionsGroup.POST("/factors", emissionsAPIHandler.CreateFactor)              // POST /api/emissions/factors
+	emissionsGroup.PUT("/factors/:id", emissionsAPIHandler.UpdateFactor)           // PUT /api/emissions/factors/:id
+	emissionsGroup.DELETE("/factors/:id", emissionsAPIHandler.DeleteFactor)        // DELETE /api/emissions/factors/:id
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"go-starter/internal/modules/emissions/models"
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+	"go-starter/pkg/geo"
+)
+
+// DefaultGramsPerTEUKm are well-to-wheel CO2e averages for a TEU with about 10 t of cargo,
+// they apply where no emission factor of the user matches
+var DefaultGramsPerTEUKm = map[models.Mode]float64{
+	models.ModeSea:  80,
+	models.ModeLand: 800,
+}
+
+// ShipmentEmissions estimates the CO2e of every container of a shipment over the sea and
+// land legs of its planned route. Shipments without containers or route geometry have no
+// estimate.
+func ShipmentEmissions(factors []models.EmissionFactor, row shipmentDto.ShipmentDetailsResponse) *shipmentDto.ShipmentEmissionsResponse {
+	legs := routeLegs(row.RouteData.RouteSegments)
+	if len(legs) == 0 || len(row.Containers) == 0 {
+		return nil
+	}
+
+	emissions := &shipmentDto.ShipmentEmissionsResponse{}
+	legKg := make([]float64, len(legs))
+	for _, container := range row.Containers {
+		teu := TEUs(container.IsoCode, container.SizeType)
+
+		containerKg := 0.0
+		for i, leg := range legs {
+			kg := leg.DistanceKm * teu * gramsPerTEUKm(factors, models.Mode(leg.Mode), container.IsoCode, container.SizeType) / 1000
+			legKg[i] += kg
+			containerKg += kg
+		}
+
+		emissions.TEU += teu
+		emissions.TotalKg += containerKg
+		emissions.Containers = append(emissions.Containers, shipmentDto.ContainerEmissionsResponse{
+			ContainerNumber: container.Number,
+			SizeType:        container.SizeType,
+			TEU:             teu,
+			Kg:              round1(containerKg),
+		})
+	}
+
+	for i := range legs {
+		legs[i].Kg = round1(legKg[i])
+		emissions.DistanceKm += legs[i].DistanceKm
+	}
+	emissions.Legs = legs
+	emissions.TotalKg = round1(emissions.TotalKg)
+	emissions.DistanceKm = round1(emissions.DistanceKm)
+
+	return emissions
+}
+
+// TEUs is the size of a container in twenty-foot equivalent units, from the length code of
+// its ISO 6346 type or the length its size type starts with. Unknown sizes count as one TEU.
+func TEUs(isoCode, sizeType string) float64 {
+	if isoCode != "" {
+		switch strings.ToUpper(isoCode)[0] {
+		case '1':
+			return 0.5
+		case '2':
+			return 1
+		case '3':
+			return 1.5
+		case '4':
+			return 2
+		case 'L':
+			return 2.25
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(sizeType, "20"):
+		return 1
+	case strings.HasPrefix(sizeType, "40"):
+		return 2
+	case strings.HasPrefix(sizeType, "45"):
+		return 2.25
+	}
+	return 1
+}
+
+// routeLegs measures the route segments of a known mode in segment order
+func routeLegs(segments []shipmentDto.ShipmentRouteSegmentResponse) []shipmentDto.EmissionLegResponse {
+	ordered := append([]shipmentDto.ShipmentRouteSegmentResponse(nil), segments...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].SegmentOrder < ordered[j].SegmentOrder })
+
+	var legs []shipmentDto.EmissionLegResponse
+	for _, segment := range ordered {
+		mode, ok := models.ModeForRouteType(segment.RouteType)
+		if !ok || len(segment.Path) < 2 {
+			continue
+		}
+
+		path := append([]shipmentDto.ShipmentRouteSegmentPointResponse(nil), segment.Path...)
+		sort.SliceStable(path, func(i, j int) bool { return path[i].PointOrder < path[j].PointOrder })
+		line := make([]geo.Point, len(path))
+		for i, point := range path {
+			line[i] = geo.Point{Lat: point.Latitude, Lng: point.Longitude}
+		}
+
+		legs = append(legs, shipmentDto.EmissionLegResponse{
+			SegmentOrder: segment.SegmentOrder,
+			Mode:         string(mode),
+			DistanceKm:   round1(geo.LineLength(line) / 1000),
+		})
+	}
+	return legs
+}
+
+// gramsPerTEUKm returns the factor of the user for a container on a leg, a factor for the
+// container type wins over one for any container and the oldest one wins a tie
+func gramsPerTEUKm(factors []models.EmissionFactor, mode models.Mode, isoCode, sizeType string) float64 {
+	var best *models.EmissionFactor
+	for i := range factors {
+		factor := &factors[i]
+		if !factor.Matches(mode, isoCode, sizeType) {
+			continue
+		}
+		if best == nil || (best.ContainerType == "" && factor.ContainerType != "") {
+			best = factor
+		}
+	}
+
+	if best == nil {
+		return DefaultGramsPerTEUKm[mode]
+	}
+	return best.GramsPerTEUKm
+}
+
+func round1(value float64) float64 {
+	return math.Round(value*10) / 10
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go-starter/internal/modules/emissions/dto"
+	"go-starter/internal/modules/emissions/models"
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+)
+
+// emissionsShipment has a land leg of about 111 km along a meridian and a sea leg of about
+// 1112 km along the equator
+func emissionsShipment(containers ...shipmentDto.ShipmentContainerResponse) shipmentDto.ShipmentDetailsResponse {
+	return shipmentDto.ShipmentDetailsResponse{
+		Containers: containers,
+		RouteData: shipmentDto.ShipmentRouteDataResponse{
+			RouteSegments: []shipmentDto.ShipmentRouteSegmentResponse{
+				{SegmentOrder: 1, RouteType: "SEA", Path: []shipmentDto.ShipmentRouteSegmentPointResponse{
+					{Latitude: 0, Longitude: 10, PointOrder: 1},
+					{Latitude: 0, Longitude: 0, PointOrder: 0},
+				}},
+				{SegmentOrder: 0, RouteType: "LAND", Path: []shipmentDto.ShipmentRouteSegmentPointResponse{
+					{Latitude: 1, Longitude: 0, PointOrder: 0},
+					{Latitude: 0, Longitude: 0, PointOrder: 1},
+				}},
+				{SegmentOrder: 2, RouteType: "UNKNOWN", Path: []shipmentDto.ShipmentRouteSegmentPointResponse{
+					{Latitude: 0, Longitude: 10, PointOrder: 0},
+					{Latitude: 5, Longitude: 10, PointOrder: 1},
+				}},
+			},
+		},
+	}
+}
+
+func TestTEUs(t *testing.T) {
+	tests := []struct {
+		isoCode, sizeType string
+		want              float64
+	}{
+		{"22G1", "20DV", 1},
+		{"45G1", "40HC", 2},
+		{"L5G1", "45HC", 2.25},
+		{"", "40RF", 2},
+		{"", "45HC", 2.25},
+		{"", "", 1},
+	}
+
+	for _, tt := range tests {
+		if got := TEUs(tt.isoCode, tt.sizeType); got != tt.want {
+			t.Errorf("TEUs(%q, %q) = %v, want %v", tt.isoCode, tt.sizeType, got, tt.want)
+		}
+	}
+}
+
+func TestShipmentEmissions(t *testing.T) {
+	row := emissionsShipment(
+		shipmentDto.ShipmentContainerResponse{Number: "MSCU0000001", IsoCode: "22G1", SizeType: "20DV"},
+		shipmentDto.ShipmentContainerResponse{Number: "MSCU0000002", IsoCode: "45R1", SizeType: "40RF"},
+	)
+	factors := []models.EmissionFactor{
+		{Mode: models.ModeSea, GramsPerTEUKm: 60},
+		{Mode: models.ModeSea, ContainerType: "40RF", GramsPerTEUKm: 90},
+	}
+
+	emissions := ShipmentEmissions(factors, row)
+	if emissions == nil {
+		t.Fatal("Expected an emissions estimate")
+	}
+
+	if len(emissions.Legs) != 2 || emissions.Legs[0].Mode != "land" || emissions.Legs[1].Mode != "sea" {
+		t.Fatalf("Expected the land and the sea leg in order, got %+v", emissions.Legs)
+	}
+	if emissions.TEU != 3 || math.Abs(emissions.DistanceKm-1223.1) > 1 {
+		t.Errorf("Unexpected totals %+v", emissions)
+	}
+
+	// The 20' container uses the user's sea factor and the default land factor, the
+	// reefer its own sea factor
+	land, sea := emissions.Legs[0].DistanceKm, emissions.Legs[1].DistanceKm
+	wantDry := (land*DefaultGramsPerTEUKm[models.ModeLand] + sea*60) / 1000
+	wantReefer := 2 * (land*DefaultGramsPerTEUKm[models.ModeLand] + sea*90) / 1000
+	if math.Abs(emissions.Containers[0].Kg-wantDry) > 0.1 || math.Abs(emissions.Containers[1].Kg-wantReefer) > 0.1 {
+		t.Errorf("Expected %.1f and %.1f kg, got %+v", wantDry, wantReefer, emissions.Containers)
+	}
+	if math.Abs(emissions.TotalKg-(wantDry+wantReefer)) > 0.2 {
+		t.Errorf("Expected %.1f kg in total, got %.1f", wantDry+wantReefer, emissions.TotalKg)
+	}
+
+	if ShipmentEmissions(nil, emissionsShipment()) != nil {
+		t.Error("Expected no estimate without containers")
+	}
+	if ShipmentEmissions(nil, shipmentDto.ShipmentDetailsResponse{Containers: row.Containers}) != nil {
+		t.Error("Expected no estimate without route legs")
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	shipment := func(consignee string, departure time.Time, kg float64) shipmentDto.ShipmentDetailsResponse {
+		return shipmentDto.ShipmentDetailsResponse{
+			Consignee: consignee,
+			Route:     shipmentDto.ShipmentRouteResponse{Pol: &shipmentDto.ShipmentRoutePoint{Date: &departure}},
+			Emissions: &shipmentDto.ShipmentEmissionsResponse{TotalKg: kg, TEU: 2, Containers: []shipmentDto.ContainerEmissionsResponse{{}}},
+		}
+	}
+	rows := []shipmentDto.ShipmentDetailsResponse{
+		shipment("Acme", time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), 100),
+		shipment("Globex", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), 300),
+		shipment("Acme", time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC), 50),
+		shipment("", time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC), 10),
+		{Consignee: "No estimate"},
+	}
+
+	report := BuildReport(rows, ReportOptions{GroupBy: dto.GroupByPeriod, Period: dto.PeriodQuarter})
+	if len(report.Groups) != 2 || report.Groups[0].Key != "2025-Q1" || report.Groups[0].TotalKg != 400 || report.Groups[1].Shipments != 2 {
+		t.Errorf("Unexpected quarterly report %+v", report.Groups)
+	}
+	if report.Shipments != 4 || report.TotalKg != 460 {
+		t.Errorf("Expected 4 shipments with 460 kg, got %d with %.1f", report.Shipments, report.TotalKg)
+	}
+
+	from := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	report = BuildReport(rows, ReportOptions{GroupBy: dto.GroupByCustomer, Period: dto.PeriodMonth, From: &from})
+	if len(report.Groups) != 3 || report.Groups[0].Key != "Globex" || report.Groups[1].Key != "Acme" || report.Groups[2].Key != unassignedCustomer {
+		t.Errorf("Expected customers by CO2e from February, got %+v", report.Groups)
+	}
+	if report.Groups[1].TotalKg != 50 || report.Groups[1].TEU != 2 {
+		t.Errorf("Expected only Acme's April shipment, got %+v", report.Groups[1])
+	}
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"go-starter/internal/modules/emissions/dto"
+	"go-starter/internal/modules/emissions/models"
+	"go-starter/internal/modules/emissions/repositories"
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// unassignedCustomer groups shipments without a consignee in customer reports
+const unassignedCustomer = "Unassigned"
+
+// ReportOptions select and group the shipments of an emissions report, From and To bound
+// the departure date and are optional
+type ReportOptions struct {
+	GroupBy string
+	Period  string
+	From    *time.Time
+	To      *time.Time
+}
+
+type EmissionService struct {
+	factorRepo      *repositories.EmissionFactorRepository
+	shipmentService shipmentServices.ShipmentService
+}
+
+func NewEmissionService(factorRepo *repositories.EmissionFactorRepository, shipmentService shipmentServices.ShipmentService) *EmissionService {
+	return &EmissionService{
+		factorRepo:      factorRepo,
+		shipmentService: shipmentService,
+	}
+}
+
+// DecorateDetails adds the CO2e estimate under the user's emission factors to shipment
+// details. It is registered as a shipment details decorator.
+func (s *EmissionService) DecorateDetails(ctx context.Context, userID uuid.UUID, rows []shipmentDto.ShipmentDetailsResponse) {
+	factors, err := s.factorRepo.GetFactorsByUserID(ctx, userID)
+	if err != nil {
+		log.Printf("Warning: Failed to load emission factors of user %s: %v", userID, err)
+		return
+	}
+
+	for i := range rows {
+		rows[i].Emissions = ShipmentEmissions(factors, rows[i])
+	}
+}
+
+// GetShipmentEmissions retrieves the CO2e estimate of a shipment of the user
+func (s *EmissionService) GetShipmentEmissions(ctx context.Context, userID, shipmentID uuid.UUID) (*shipmentDto.ShipmentEmissionsResponse, error) {
+	details, err := s.shipmentService.GetShipmentDetails(ctx, userID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if details.Emissions == nil {
+		return nil, fmt.Errorf("emissions estimate not found, the shipment has no route legs or containers")
+	}
+
+	return details.Emissions, nil
+}
+
+// GetReport aggregates the CO2e of the user's shipments per customer or per period
+func (s *EmissionService) GetReport(ctx context.Context, userID uuid.UUID, options ReportOptions) (*dto.ReportResponse, error) {
+	if options.GroupBy == "" {
+		options.GroupBy = dto.GroupByPeriod
+	}
+	if options.GroupBy != dto.GroupByPeriod && options.GroupBy != dto.GroupByCustomer {
+		return nil, fmt.Errorf("invalid group_by '%s', expected period or customer", options.GroupBy)
+	}
+	if options.Period == "" {
+		options.Period = dto.PeriodMonth
+	}
+	if options.Period != dto.PeriodMonth && options.Period != dto.PeriodQuarter && options.Period != dto.PeriodYear {
+		return nil, fmt.Errorf("invalid period '%s', expected month, quarter or year", options.Period)
+	}
+
+	factors, err := s.factorRepo.GetFactorsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving emission factors: %w", err)
+	}
+
+	rows, err := s.shipmentService.GetShipmentsDepartedBetween(ctx, userID, options.From, options.To)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Emissions = ShipmentEmissions(factors, rows[i])
+	}
+
+	return BuildReport(rows, options), nil
+}
+
+// BuildReport aggregates the estimates of the rows that departed within the report's
+// bounds. Periods are listed in order, customers by their CO2e, most first.
+func BuildReport(rows []shipmentDto.ShipmentDetailsResponse, options ReportOptions) *dto.ReportResponse {
+	report := &dto.ReportResponse{
+		GroupBy: options.GroupBy,
+		Period:  options.Period,
+		From:    options.From,
+		To:      options.To,
+		Groups:  []dto.ReportGroup{},
+	}
+
+	groups := map[string]*dto.ReportGroup{}
+	for _, row := range rows {
+		if row.Emissions == nil {
+			continue
+		}
+
+		departure := departureDate(row)
+		if (options.From != nil && departure.Before(*options.From)) || (options.To != nil && !departure.Before(*options.To)) {
+			continue
+		}
+
+		key := periodKey(departure, options.Period)
+		if options.GroupBy == dto.GroupByCustomer {
+			key = strings.TrimSpace(row.Consignee)
+			if key == "" {
+				key = unassignedCustomer
+			}
+		}
+
+		group, ok := groups[key]
+		if !ok {
+			group = &dto.ReportGroup{Key: key}
+			groups[key] = group
+		}
+		group.Shipments++
+		group.Containers += len(row.Emissions.Containers)
+		group.TEU += row.Emissions.TEU
+		group.TotalKg += row.Emissions.TotalKg
+
+		report.Shipments++
+		report.TotalKg += row.Emissions.TotalKg
+	}
+
+	for _, group := range groups {
+		group.TotalKg = round1(group.TotalKg)
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if options.GroupBy == dto.GroupByCustomer && report.Groups[i].TotalKg != report.Groups[j].TotalKg {
+			return report.Groups[i].TotalKg > report.Groups[j].TotalKg
+		}
+		return report.Groups[i].Key < report.Groups[j].Key
+	})
+	report.TotalKg = round1(report.TotalKg)
+
+	return report
+}
+
+// departureDate is when the shipment left the port of loading, or when it was added when
+// the port of loading has no date
+func departureDate(row shipmentDto.ShipmentDetailsResponse) time.Time {
+	if row.Route.Pol != nil && row.Route.Pol.Date != nil {
+		return *row.Route.Pol.Date
+	}
+	return row.CreatedAt
+}
+
+// periodKey labels the period a date falls in, e.g. 2025-06, 2025-Q2 or 2025
+func periodKey(date time.Time, period string) string {
+	date = date.UTC()
+	switch period {
+	case dto.PeriodQuarter:
+		return fmt.Sprintf("%d-Q%d", date.Year(), (int(date.Month())+2)/3)
+	case dto.PeriodYear:
+		return fmt.Sprintf("%d", date.Year())
+	}
+	return date.Format("2006-01")
+}
+
+// CreateFactor validates and creates a new emission factor
+func (s *EmissionService) CreateFactor(ctx context.Context, userID uuid.UUID, req *dto.SaveFactorRequest) (*models.EmissionFactor, error) {
+	factor := &models.EmissionFactor{UserID: userID}
+	if err := applyFactorRequest(factor, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.factorRepo.CreateFactor(ctx, factor); err != nil {
+		return nil, fmt.Errorf("error creating emission factor: %w", err)
+	}
+
+	return factor, nil
+}
+
+// GetFactors retrieves all emission factors of a user with the defaults they override
+func (s *EmissionService) GetFactors(ctx context.Context, userID uuid.UUID) (*dto.FactorsListResponse, error) {
+	factors, err := s.factorRepo.GetFactorsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving emission factors: %w", err)
+	}
+
+	return &dto.FactorsListResponse{
+		Factors:  factors,
+		Defaults: DefaultGramsPerTEUKm,
+		Total:    len(factors),
+	}, nil
+}
+
+// UpdateFactor validates and updates an existing emission factor
+func (s *EmissionService) UpdateFactor(ctx context.Context, userID, factorID uuid.UUID, req *dto.SaveFactorRequest) (*models.EmissionFactor, error) {
+	factor, err := s.factorRepo.GetFactorByID(ctx, factorID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("emission factor not found")
+		}
+		return nil, fmt.Errorf("error retrieving emission factor: %w", err)
+	}
+
+	if err := applyFactorRequest(factor, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.factorRepo.UpdateFactor(ctx, factor); err != nil {
+		return nil, fmt.Errorf("error updating emission factor: %w", err)
+	}
+
+	return factor, nil
+}
+
+// DeleteFactor deletes an emission factor
+func (s *EmissionService) DeleteFactor(ctx context.Context, userID, factorID uuid.UUID) error {
+	if err := s.factorRepo.DeleteFactor(ctx, factorID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("emission factor not found")
+		}
+		return fmt.Errorf("error deleting emission factor: %w", err)
+	}
+
+	return nil
+}
+
+// applyFactorRequest validates the request and copies it onto the factor, container types
+// are stored upper case
+func applyFactorRequest(factor *models.EmissionFactor, req *dto.SaveFactorRequest) error {
+	if !req.Mode.IsValid() {
+		return fmt.Errorf("invalid mode '%s'", req.Mode)
+	}
+
+	factor.Name = strings.TrimSpace(req.Name)
+	factor.Mode = req.Mode
+	factor.ContainerType = strings.ToUpper(strings.TrimSpace(req.ContainerType))
+	factor.GramsPerTEUKm = req.GramsPerTEUKm
+
+	return nil
+}
@@ -0,0 +1,415 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+	shipmentModels "go-starter/internal/modules/shipments/models"
+)
+
+// agGridDateLayout is how the date filter stores its dates
+const agGridDateLayout = "2006-01-02 15:04:05"
+
+// milestoneColumns maps the grid's milestone columns to the milestone they show
+var milestoneColumns = map[string]shipmentModels.Milestone{
+	"emptyPickupAt":   shipmentModels.MilestoneEmptyPickup,
+	"gateInAt":        shipmentModels.MilestoneGateIn,
+	"loadedAt":        shipmentModels.MilestoneLoaded,
+	"departedAt":      shipmentModels.MilestoneDeparted,
+	"transshipmentAt": shipmentModels.MilestoneTransshipment,
+	"arrivedAt":       shipmentModels.MilestoneArrived,
+	"dischargedAt":    shipmentModels.MilestoneDischarged,
+	"gateOutAt":       shipmentModels.MilestoneGateOut,
+	"emptyReturnAt":   shipmentModels.MilestoneEmptyReturn,
+}
+
+// MatchesFilterModel reports whether a grid row passes a saved AG Grid filter model, so
+// digests and exports cover the same shipments the saved filter shows in the grid. Columns without
+// data of their own are compared by what their cells display. Filters on unknown columns
+// or of unknown types are ignored.
+func MatchesFilterModel(model map[string]interface{}, row shipmentDto.ShipmentDetailsResponse, now time.Time) bool {
+	for field, raw := range model {
+		filter, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if !matchesFilter(filter, field, row, now) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesFilter checks one column filter, which may combine several conditions
+func matchesFilter(filter map[string]interface{}, field string, row shipmentDto.ShipmentDetailsResponse, now time.Time) bool {
+	conditions := combinedConditions(filter)
+	if conditions == nil {
+		return matchesCondition(filter, field, row, now)
+	}
+
+	if strings.EqualFold(stringParam(filter, "operator"), "OR") {
+		for _, condition := range conditions {
+			if matchesCondition(condition, field, row, now) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, condition := range conditions {
+		if !matchesCondition(condition, field, row, now) {
+			return false
+		}
+	}
+	return true
+}
+
+// combinedConditions returns the conditions of a combined filter, nil for a single condition.
+// Older grid versions saved two conditions as condition1 and condition2.
+func combinedConditions(filter map[string]interface{}) []map[string]interface{} {
+	var raw []interface{}
+	if list, ok := filter["conditions"].([]interface{}); ok {
+		raw = list
+	} else if _, ok := filter["condition1"]; ok {
+		raw = []interface{}{filter["condition1"], filter["condition2"]}
+	} else {
+		return nil
+	}
+
+	conditions := make([]map[string]interface{}, 0, len(raw))
+	for _, item := range raw {
+		if condition, ok := item.(map[string]interface{}); ok {
+			conditions = append(conditions, condition)
+		}
+	}
+	return conditions
+}
+
+func matchesCondition(condition map[string]interface{}, field string, row shipmentDto.ShipmentDetailsResponse, now time.Time) bool {
+	switch stringParam(condition, "filterType") {
+	case "set":
+		values, ok := condition["values"].([]interface{})
+		if !ok {
+			return true
+		}
+		value := GridCellValue(row, field, now)
+		for _, allowed := range values {
+			if allowed == nil && value == "" {
+				return true
+			}
+			if allowed != nil && fmt.Sprint(allowed) == value {
+				return true
+			}
+		}
+		return false
+
+	case "date":
+		return matchesDateCondition(condition, GridCellDate(row, field, now))
+
+	case "number":
+		return matchesNumberCondition(condition, GridCellNumber(row, field))
+
+	case "text", "":
+		return matchesTextCondition(condition, GridCellValue(row, field, now))
+	}
+
+	return true
+}
+
+func matchesTextCondition(condition map[string]interface{}, value string) bool {
+	value = strings.ToLower(value)
+	filter := strings.ToLower(stringParam(condition, "filter"))
+
+	switch stringParam(condition, "type") {
+	case "blank":
+		return value == ""
+	case "notBlank":
+		return value != ""
+	case "equals":
+		return value == filter
+	case "notEqual":
+		return value != filter
+	case "startsWith":
+		return strings.HasPrefix(value, filter)
+	case "endsWith":
+		return strings.HasSuffix(value, filter)
+	case "notContains":
+		return !strings.Contains(value, filter)
+	default:
+		return strings.Contains(value, filter)
+	}
+}
+
+// matchesDateCondition compares whole days like the grid's date filter does
+func matchesDateCondition(condition map[string]interface{}, value *time.Time) bool {
+	conditionType := stringParam(condition, "type")
+	switch conditionType {
+	case "blank":
+		return value == nil
+	case "notBlank":
+		return value != nil
+	}
+	if value == nil {
+		return false
+	}
+
+	from, err := time.Parse(agGridDateLayout, stringParam(condition, "dateFrom"))
+	if err != nil {
+		return true
+	}
+	day := truncateToDay(*value)
+	from = truncateToDay(from)
+
+	switch conditionType {
+	case "notEqual":
+		return !day.Equal(from)
+	case "lessThan":
+		return day.Before(from)
+	case "greaterThan":
+		return day.After(from)
+	case "inRange":
+		to, err := time.Parse(agGridDateLayout, stringParam(condition, "dateTo"))
+		if err != nil {
+			return !day.Before(from)
+		}
+		return day.After(from) && day.Before(truncateToDay(to))
+	default:
+		return day.Equal(from)
+	}
+}
+
+// matchesNumberCondition compares like the grid's number filter, rows without a value only
+// match blank conditions
+func matchesNumberCondition(condition map[string]interface{}, value *float64) bool {
+	conditionType := stringParam(condition, "type")
+	switch conditionType {
+	case "blank":
+		return value == nil
+	case "notBlank":
+		return value != nil
+	}
+	if value == nil {
+		return false
+	}
+
+	filter, ok := condition["filter"].(float64)
+	if !ok {
+		return true
+	}
+
+	switch conditionType {
+	case "notEqual":
+		return *value != filter
+	case "lessThan":
+		return *value < filter
+	case "lessThanOrEqual":
+		return *value <= filter
+	case "greaterThan":
+		return *value > filter
+	case "greaterThanOrEqual":
+		return *value >= filter
+	case "inRange":
+		filterTo, ok := condition["filterTo"].(float64)
+		if !ok {
+			return *value > filter
+		}
+		return *value > math.Min(filter, filterTo) && *value < math.Max(filter, filterTo)
+	default:
+		return *value == filter
+	}
+}
+
+// GridCellValue returns the value a grid column holds or displays for a row
+func GridCellValue(row shipmentDto.ShipmentDetailsResponse, field string, now time.Time) string {
+	switch field {
+	case "shipmentNumber":
+		return row.ShipmentNumber
+	case "shippingStatus":
+		return row.ShippingStatus
+	case "carrier":
+		if row.SealineName != "" {
+			return row.SealineName
+		}
+		return row.SealineCode
+	case "originPort":
+		return RoutePointLabel(row.Route.Pol)
+	case "destinationPort":
+		return RoutePointLabel(row.Route.Pod)
+	case "vesselInfo":
+		if len(row.Vessels) == 0 {
+			return ""
+		}
+		vessel := row.Vessels[0]
+		return fmt.Sprintf("%s (%d)", vessel.Name, vessel.Imo)
+	case "containerCount":
+		return fmt.Sprint(len(row.Containers))
+	case "containerNumbers":
+		numbers := make([]string, len(row.Containers))
+		for i, container := range row.Containers {
+			numbers[i] = container.Number
+		}
+		return strings.Join(numbers, ", ")
+	case "nextETA":
+		if eta := NextETA(row, now); eta != nil {
+			return eta.Format("2006-01-02")
+		}
+		return ""
+	case "consignee":
+		return row.Consignee
+	case "recipient":
+		return row.Recipient
+	case "shipper":
+		return row.Shipper
+	case "assignedTo":
+		return row.AssignedTo
+	case "placeOfLoading":
+		return row.PlaceOfLoading
+	case "placeOfDelivery":
+		return row.PlaceOfDelivery
+	case "finalDestination":
+		return row.FinalDestination
+	case "containerType":
+		return row.ContainerType
+	case "mbl":
+		return row.MBL
+	case "customs":
+		return row.Customs
+	case "invoiceAmount":
+		return row.InvoiceAmount
+	case "cost":
+		return row.Cost
+	case "customsProcessed":
+		return fmt.Sprint(row.CustomsProcessed)
+	case "invoiced":
+		return fmt.Sprint(row.Invoiced)
+	case "paymentReceived":
+		return fmt.Sprint(row.PaymentReceived)
+	case "notes":
+		return row.Notes
+	}
+	return ""
+}
+
+// GridCellDate returns the date of a date column for a row
+func GridCellDate(row shipmentDto.ShipmentDetailsResponse, field string, now time.Time) *time.Time {
+	switch field {
+	case "nextETA":
+		return NextETA(row, now)
+	case "estimatedETA":
+		if row.RouteProgress == nil {
+			return nil
+		}
+		return row.RouteProgress.EstimatedETA
+	case "createdAt":
+		return &row.CreatedAt
+	case "updatedAt":
+		return &row.UpdatedAt
+	}
+	if milestone, ok := milestoneColumns[field]; ok {
+		return MilestoneDate(row, milestone)
+	}
+	return nil
+}
+
+// GridCellNumber returns the value of a number column for a row, nil when the cell is empty
+func GridCellNumber(row shipmentDto.ShipmentDetailsResponse, field string) *float64 {
+	switch field {
+	case "progressPercent":
+		if row.RouteProgress == nil {
+			return nil
+		}
+		return &row.RouteProgress.ProgressPercent
+	case "remainingKm":
+		if row.RouteProgress == nil {
+			return nil
+		}
+		return &row.RouteProgress.RemainingKm
+	case "etaDeltaHours":
+		if row.RouteProgress == nil {
+			return nil
+		}
+		return row.RouteProgress.ETADeltaHours
+	case "freeTimeLeft":
+		return FreeTimeLeft(row)
+	case "ddCharges":
+		if len(row.FreeTime) == 0 {
+			return nil
+		}
+		charges := 0.0
+		for _, freeTime := range row.FreeTime {
+			charges += freeTime.AccruedCharges
+		}
+		return &charges
+	case "co2eKg":
+		if row.Emissions == nil {
+			return nil
+		}
+		return &row.Emissions.TotalKg
+	}
+	return nil
+}
+
+// FreeTimeLeft is the number of free days left on the container closest to running out,
+// negative once charges are accruing, as shown in the Free Time column
+func FreeTimeLeft(row shipmentDto.ShipmentDetailsResponse) *float64 {
+	var left *float64
+	for _, freeTime := range row.FreeTime {
+		if freeTime.Status == shipmentDto.FreeTimeStatusClosed {
+			continue
+		}
+		days := float64(freeTime.DaysRemaining)
+		if freeTime.Status == shipmentDto.FreeTimeStatusOverdue {
+			days = -float64(freeTime.ChargeableDays)
+		}
+		if left == nil || days < *left {
+			left = &days
+		}
+	}
+	return left
+}
+
+// MilestoneDate is the actual date of a shipment milestone or else its estimate, as shown
+// in the milestone columns
+func MilestoneDate(row shipmentDto.ShipmentDetailsResponse, milestone shipmentModels.Milestone) *time.Time {
+	for _, entry := range row.Milestones {
+		if entry.Milestone != string(milestone) {
+			continue
+		}
+		if entry.ActualAt != nil {
+			return entry.ActualAt
+		}
+		return entry.EstimatedAt
+	}
+	return nil
+}
+
+// NextETA is the first future port date along the route, as shown in the Next ETA column
+func NextETA(row shipmentDto.ShipmentDetailsResponse, now time.Time) *time.Time {
+	for _, point := range []*shipmentDto.ShipmentRoutePoint{row.Route.Prepol, row.Route.Pol, row.Route.Pod, row.Route.Postpod} {
+		if point != nil && point.Date != nil && point.Date.After(now) {
+			return point.Date
+		}
+	}
+	return nil
+}
+
+// RoutePointLabel is a port with its locode, as shown in the port columns
+func RoutePointLabel(point *shipmentDto.ShipmentRoutePoint) string {
+	if point == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s (%s)", point.Location.Name, point.Location.Locode)
+}
+
+func stringParam(params map[string]interface{}, key string) string {
+	value, _ := params[key].(string)
+	return value
+}
+
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+)
+
+func parseFilterModel(t *testing.T, raw string) map[string]interface{} {
+	t.Helper()
+
+	var model map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &model); err != nil {
+		t.Fatalf("Invalid filter model: %v", err)
+	}
+	return model
+}
+
+func TestMatchesFilterModel(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	eta := time.Date(2025, 6, 4, 8, 0, 0, 0, time.UTC)
+	loadedAt := time.Date(2025, 5, 10, 14, 0, 0, 0, time.UTC)
+	ourETA := time.Date(2025, 6, 5, 2, 0, 0, 0, time.UTC)
+	etaDelta := 18.0
+	row := shipmentDto.ShipmentDetailsResponse{
+		ShipmentNumber: "MSCU1234567",
+		ShippingStatus: "IN_TRANSIT",
+		SealineCode:    "MSCU",
+		SealineName:    "MSC",
+		Consignee:      "Acme Imports",
+		ContainerType:  "40HC",
+		Containers: []shipmentDto.ShipmentContainerResponse{
+			{Number: "MSCU6639870"},
+			{Number: "CSQU3054383"},
+		},
+		Route: shipmentDto.ShipmentRouteResponse{
+			Pod: &shipmentDto.ShipmentRoutePoint{
+				Location: shipmentDto.ShipmentLocationResponse{Name: "Rotterdam", Locode: "NLRTM"},
+				Date:     &eta,
+			},
+		},
+		Milestones: []shipmentDto.ShipmentMilestoneResponse{
+			{Milestone: "loaded", ActualAt: &loadedAt},
+			{Milestone: "discharged", EstimatedAt: &eta},
+		},
+		RouteProgress: &shipmentDto.ShipmentRouteProgressResponse{
+			RemainingKm:     1850,
+			ProgressPercent: 82.5,
+			EstimatedETA:    &ourETA,
+			ETADeltaHours:   &etaDelta,
+		},
+		Emissions: &shipmentDto.ShipmentEmissionsResponse{TotalKg: 2450},
+		FreeTime: []shipmentDto.ContainerFreeTimeResponse{
+			{ContainerNumber: "MSCU6639870", Status: "expiring", DaysRemaining: 2},
+			{ContainerNumber: "CSQU3054383", Status: "overdue", ChargeableDays: 2, AccruedCharges: 151},
+			{ContainerNumber: "CSQU3054383", Status: "closed", AccruedCharges: 0},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		model string
+		want  bool
+	}{
+		{"empty model", `{}`, true},
+		{"text contains is case insensitive", `{"consignee":{"filterType":"text","type":"contains","filter":"acme"}}`, true},
+		{"text not contains", `{"consignee":{"filterType":"text","type":"notContains","filter":"acme"}}`, false},
+		{"text starts with", `{"shipmentNumber":{"filterType":"text","type":"startsWith","filter":"MAEU"}}`, false},
+		{"blank", `{"notes":{"filterType":"text","type":"blank"}}`, true},
+		{"set includes value", `{"shippingStatus":{"filterType":"set","values":["IN_TRANSIT","PLANNED"]}}`, true},
+		{"set excludes value", `{"containerType":{"filterType":"set","values":["20GP"]}}`, false},
+		{"carrier", `{"carrier":{"filterType":"set","values":["MSC","Maersk"]}}`, true},
+		{"container numbers", `{"containerNumbers":{"filterType":"text","type":"contains","filter":"csqu3054383"}}`, true},
+		{"displayed column value", `{"destinationPort":{"filterType":"text","type":"contains","filter":"NLRTM"}}`, true},
+		{"date in range", `{"nextETA":{"filterType":"date","type":"inRange","dateFrom":"2025-06-01 00:00:00","dateTo":"2025-06-07 00:00:00"}}`, true},
+		{"date less than", `{"nextETA":{"filterType":"date","type":"lessThan","dateFrom":"2025-06-04 00:00:00"}}`, false},
+		{"date equals compares days", `{"nextETA":{"filterType":"date","type":"equals","dateFrom":"2025-06-04 00:00:00"}}`, true},
+		{"actual milestone date", `{"loadedAt":{"filterType":"date","type":"equals","dateFrom":"2025-05-10 00:00:00"}}`, true},
+		{"estimated milestone date", `{"dischargedAt":{"filterType":"date","type":"greaterThan","dateFrom":"2025-06-01 00:00:00"}}`, true},
+		{"missing milestone", `{"gateOutAt":{"filterType":"date","type":"lessThan","dateFrom":"2025-06-01 00:00:00"}}`, false},
+		{"combined OR", `{"consignee":{"filterType":"text","operator":"OR","conditions":[{"filterType":"text","type":"equals","filter":"other"},{"filterType":"text","type":"contains","filter":"imports"}]}}`, true},
+		{"combined AND", `{"consignee":{"filterType":"text","operator":"AND","conditions":[{"filterType":"text","type":"contains","filter":"acme"},{"filterType":"text","type":"contains","filter":"exports"}]}}`, false},
+		{"legacy conditions", `{"consignee":{"filterType":"text","operator":"OR","condition1":{"filterType":"text","type":"equals","filter":"other"},"condition2":{"filterType":"text","type":"startsWith","filter":"acme"}}}`, true},
+		{"all columns must match", `{"consignee":{"filterType":"text","type":"contains","filter":"acme"},"shippingStatus":{"filterType":"set","values":["DELIVERED"]}}`, false},
+		{"our eta", `{"estimatedETA":{"filterType":"date","type":"equals","dateFrom":"2025-06-05 00:00:00"}}`, true},
+		{"progress", `{"progressPercent":{"filterType":"number","type":"greaterThan","filter":80}}`, true},
+		{"remaining distance", `{"remainingKm":{"filterType":"number","type":"lessThan","filter":1000}}`, false},
+		{"eta delta", `{"etaDeltaHours":{"filterType":"number","type":"greaterThanOrEqual","filter":12}}`, true},
+		{"free time less than", `{"freeTimeLeft":{"filterType":"number","type":"lessThan","filter":3}}`, true},
+		{"free time greater than", `{"freeTimeLeft":{"filterType":"number","type":"greaterThanOrEqual","filter":3}}`, false},
+		{"charges greater than", `{"ddCharges":{"filterType":"number","type":"greaterThan","filter":0}}`, true},
+		{"charges in range", `{"ddCharges":{"filterType":"number","type":"inRange","filter":100,"filterTo":200}}`, true},
+		{"co2e", `{"co2eKg":{"filterType":"number","type":"greaterThan","filter":2000}}`, true},
+		{"co2e in range", `{"co2eKg":{"filterType":"number","type":"inRange","filter":0,"filterTo":1000}}`, false},
+		{"empty number cell", `{"consignee":{"filterType":"number","type":"equals","filter":5}}`, false},
+		{"blank number cell", `{"consignee":{"filterType":"number","type":"blank"}}`, true},
+		{"unknown filter type is ignored", `{"consignee":{"filterType":"multi","filterModels":[]}}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesFilterModel(parseFilterModel(t, tt.model), row, now); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"go-starter/internal/modules/auth/services"
+	exportServices "go-starter/internal/modules/geoexport/services"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type ExportAPIHandler struct {
+	exportService *exportServices.ExportService
+}
+
+func NewExportAPIHandler(exportService *exportServices.ExportService) *ExportAPIHandler {
+	return &ExportAPIHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportShipment handles GET /api/exports/shipments/:id?format=geojson|kml|gpx
+func (h *ExportAPIHandler) ExportShipment(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid shipment ID",
+		})
+	}
+
+	format, err := exportServices.ParseFormat(c.QueryParam("format"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	shipmentNumber, features, err := h.exportService.ShipmentFeatures(ctx, userID, shipmentID)
+	if err != nil {
+		return h.serviceError(c, "Failed to export shipment", err)
+	}
+
+	return h.attachment(c, format, "shipment-"+shipmentNumber, features)
+}
+
+// ExportShipments handles GET /api/exports/shipments?format=geojson|kml|gpx&ids=a,b&filter_id=c.
+// Without ids and filter_id all shipments of the user are exported.
+func (h *ExportAPIHandler) ExportShipments(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	format, err := exportServices.ParseFormat(c.QueryParam("format"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	var shipmentIDs []uuid.UUID
+	if idsStr := c.QueryParam("ids"); idsStr != "" {
+		for _, idStr := range strings.Split(idsStr, ",") {
+			shipmentID, err := uuid.Parse(strings.TrimSpace(idStr))
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "Invalid shipment ID: " + idStr,
+				})
+			}
+			shipmentIDs = append(shipmentIDs, shipmentID)
+		}
+	}
+
+	var filterID *uuid.UUID
+	if filterIDStr := c.QueryParam("filter_id"); filterIDStr != "" {
+		parsed, err := uuid.Parse(filterIDStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid filter ID",
+			})
+		}
+		filterID = &parsed
+	}
+
+	now := time.Now()
+	features, err := h.exportService.FilteredFeatures(ctx, userID, shipmentIDs, filterID, now)
+	if err != nil {
+		return h.serviceError(c, "Failed to export shipments", err)
+	}
+
+	return h.attachment(c, format, "shipments-"+now.Format("20060102"), features)
+}
+
+// attachment encodes the features and sends them as a file download
+func (h *ExportAPIHandler) attachment(c echo.Context, format exportServices.Format, name string, features []exportServices.Feature) error {
+	var body bytes.Buffer
+	if err := exportServices.Encode(&body, format, name, features); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to encode export: " + err.Error(),
+		})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name+"."+format.Extension()))
+	return c.Blob(http.StatusOK, format.ContentType(), body.Bytes())
+}
+
+// serviceError maps service errors to status codes
+func (h *ExportAPIHandler) serviceError(c echo.Context, message string, err error) error {
+	if strings.Contains(err.Error(), "not found") {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message + ": " + err.Error(),
+	})
+}
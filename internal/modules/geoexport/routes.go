@@ -0,0 +1,28 @@
+package geoexport
+
+import (
+	"go-starter/internal/modules/auth/middlewares"
+	"go-starter/internal/modules/auth/services"
+	filterRepositories "go-starter/internal/modules/filters/repositories"
+	"go-starter/internal/modules/geoexport/handlers"
+	exportServices "go-starter/internal/modules/geoexport/services"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+	"go-starter/pkg/db"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRoutes(api *echo.Group, database *db.Database, shipmentService shipmentServices.ShipmentService) {
+	// Initialize dependencies, filtered exports reuse the user's saved grid filters
+	exportService := exportServices.NewExportService(shipmentService, filterRepositories.NewRepository(database))
+	exportAPIHandler := handlers.NewExportAPIHandler(exportService)
+
+	// Create JWT service for middleware
+	jwtService := services.NewJWTService()
+
+	// Create exports group with JWT middleware
+	exportsGroup := api.Group("/exports", middlewares.JWTMiddleware(jwtService))
+
+	exportsGroup.GET("/shipments", exportAPIHandler.ExportShipments)    // GET /api/exports/shipments
+	exportsGroup.GET("/shipments/:id", exportAPIHandler.ExportShipment) // GET /api/exports/shipments/:id
+}
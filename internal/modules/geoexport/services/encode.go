@@ -0,0 +1,288 @@
+package services
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is an export file format
+type Format string
+
+const (
+	FormatGeoJSON Format = "geojson"
+	FormatKML     Format = "kml"
+	FormatGPX     Format = "gpx"
+)
+
+// ParseFormat parses a format name, GeoJSON is the default
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(name))); format {
+	case "", "json":
+		return FormatGeoJSON, nil
+	case FormatGeoJSON, FormatKML, FormatGPX:
+		return format, nil
+	}
+	return "", fmt.Errorf("invalid format '%s', expected geojson, kml or gpx", name)
+}
+
+// ContentType is the media type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatKML:
+		return "application/vnd.google-earth.kml+xml"
+	case FormatGPX:
+		return "application/gpx+xml"
+	}
+	return "application/geo+json"
+}
+
+// Extension is the file name extension of the format
+func (f Format) Extension() string {
+	return string(f)
+}
+
+// Encode writes features in a format
+func Encode(w io.Writer, format Format, name string, features []Feature) error {
+	switch format {
+	case FormatKML:
+		return encodeKML(w, name, features)
+	case FormatGPX:
+		return encodeGPX(w, name, features)
+	}
+	return encodeGeoJSON(w, features)
+}
+
+// GeoJSON, RFC 7946. Positions are longitude first.
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+func encodeGeoJSON(w io.Writer, features []Feature) error {
+	collection := geoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]geoJSONFeature, 0, len(features)),
+	}
+
+	for _, f := range features {
+		properties := map[string]interface{}{
+			"shipmentId":     f.ShipmentID,
+			"shipmentNumber": f.ShipmentNumber,
+			"kind":           f.Kind,
+			"name":           f.Name,
+		}
+		for _, key := range f.propertyKeys() {
+			properties[key] = f.Properties[key]
+		}
+		if f.Time != nil {
+			properties["time"] = f.Time.UTC().Format(time.RFC3339)
+		}
+
+		geometry := geoJSONGeometry{Type: "Point"}
+		if f.Point != nil {
+			geometry.Coordinates = []float64{f.Point.Lng, f.Point.Lat}
+		} else {
+			line := make([][]float64, len(f.Line))
+			for i, c := range f.Line {
+				line[i] = []float64{c.Lng, c.Lat}
+			}
+			geometry.Type = "LineString"
+			geometry.Coordinates = line
+		}
+
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geometry,
+			Properties: properties,
+		})
+	}
+
+	return json.NewEncoder(w).Encode(collection)
+}
+
+// KML 2.2, one folder per shipment. Coordinates are lng,lat.
+
+type kmlDocument struct {
+	XMLName xml.Name    `xml:"http://www.opengis.net/kml/2.2 kml"`
+	Name    string      `xml:"Document>name"`
+	Styles  []kmlStyle  `xml:"Document>Style"`
+	Folders []kmlFolder `xml:"Document>Folder"`
+}
+
+type kmlStyle struct {
+	ID        string `xml:"id,attr"`
+	LineColor string `xml:"LineStyle>color,omitempty"`
+	LineWidth int    `xml:"LineStyle>width,omitempty"`
+	IconColor string `xml:"IconStyle>color,omitempty"`
+}
+
+type kmlFolder struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name         string         `xml:"name"`
+	Description  string         `xml:"description,omitempty"`
+	TimeStamp    string         `xml:"TimeStamp>when,omitempty"`
+	StyleURL     string         `xml:"styleUrl"`
+	ExtendedData []kmlData      `xml:"ExtendedData>Data,omitempty"`
+	Point        *kmlPoint      `xml:"Point,omitempty"`
+	LineString   *kmlLineString `xml:"LineString,omitempty"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlPoint struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlLineString struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"coordinates"`
+}
+
+// kmlStyles colors each kind, KML colors are aabbggrr
+var kmlStyles = []kmlStyle{
+	{ID: KindRoute, LineColor: "ffeb6325", LineWidth: 3},
+	{ID: KindPort, IconColor: "ff0b9ef5"},
+	{ID: KindFacility, IconColor: "ff81b910"},
+	{ID: KindVessel, IconColor: "ff2626dc"},
+}
+
+func encodeKML(w io.Writer, name string, features []Feature) error {
+	doc := kmlDocument{Name: name, Styles: kmlStyles}
+
+	folders := map[string]int{}
+	for _, f := range features {
+		index, ok := folders[f.ShipmentNumber]
+		if !ok {
+			index = len(doc.Folders)
+			folders[f.ShipmentNumber] = index
+			doc.Folders = append(doc.Folders, kmlFolder{Name: f.ShipmentNumber})
+		}
+
+		placemark := kmlPlacemark{
+			Name:        f.Name,
+			Description: f.description(),
+			StyleURL:    "#" + f.Kind,
+		}
+		placemark.ExtendedData = append(placemark.ExtendedData, kmlData{Name: "kind", Value: f.Kind})
+		for _, key := range f.propertyKeys() {
+			placemark.ExtendedData = append(placemark.ExtendedData, kmlData{Name: key, Value: f.Properties[key]})
+		}
+		if f.Time != nil {
+			placemark.TimeStamp = f.Time.UTC().Format(time.RFC3339)
+		}
+		if f.Point != nil {
+			placemark.Point = &kmlPoint{Coordinates: kmlCoordinate(*f.Point)}
+		} else {
+			coordinates := make([]string, len(f.Line))
+			for i, c := range f.Line {
+				coordinates[i] = kmlCoordinate(c)
+			}
+			placemark.LineString = &kmlLineString{Tessellate: 1, Coordinates: strings.Join(coordinates, " ")}
+		}
+
+		doc.Folders[index].Placemarks = append(doc.Folders[index].Placemarks, placemark)
+	}
+
+	return writeXML(w, doc)
+}
+
+func kmlCoordinate(c Coordinate) string {
+	return formatDegrees(c.Lng) + "," + formatDegrees(c.Lat)
+}
+
+// GPX 1.1. Ports, facilities and vessels are waypoints, planned routes are routes.
+
+type gpxDocument struct {
+	XMLName   xml.Name      `xml:"http://www.topografix.com/GPX/1/1 gpx"`
+	Version   string        `xml:"version,attr"`
+	Creator   string        `xml:"creator,attr"`
+	Name      string        `xml:"metadata>name"`
+	Waypoints []gpxWaypoint `xml:"wpt"`
+	Routes    []gpxRoute    `xml:"rte"`
+}
+
+type gpxWaypoint struct {
+	Lat         string `xml:"lat,attr"`
+	Lon         string `xml:"lon,attr"`
+	Time        string `xml:"time,omitempty"`
+	Name        string `xml:"name,omitempty"`
+	Description string `xml:"desc,omitempty"`
+	Type        string `xml:"type,omitempty"`
+}
+
+type gpxRoute struct {
+	Name        string        `xml:"name"`
+	Description string        `xml:"desc,omitempty"`
+	Type        string        `xml:"type,omitempty"`
+	Points      []gpxWaypoint `xml:"rtept"`
+}
+
+func encodeGPX(w io.Writer, name string, features []Feature) error {
+	doc := gpxDocument{Version: "1.1", Creator: "Shipment Management", Name: name}
+
+	for _, f := range features {
+		if f.Point != nil {
+			waypoint := gpxWaypoint{
+				Lat:         formatDegrees(f.Point.Lat),
+				Lon:         formatDegrees(f.Point.Lng),
+				Name:        f.Name,
+				Description: f.description(),
+				Type:        f.Kind,
+			}
+			if f.Time != nil {
+				waypoint.Time = f.Time.UTC().Format(time.RFC3339)
+			}
+			doc.Waypoints = append(doc.Waypoints, waypoint)
+			continue
+		}
+
+		route := gpxRoute{Name: f.Name, Description: f.description(), Type: f.Kind}
+		for _, c := range f.Line {
+			route.Points = append(route.Points, gpxWaypoint{Lat: formatDegrees(c.Lat), Lon: formatDegrees(c.Lng)})
+		}
+		doc.Routes = append(doc.Routes, route)
+	}
+
+	return writeXML(w, doc)
+}
+
+func writeXML(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func formatDegrees(degrees float64) string {
+	return strconv.FormatFloat(degrees, 'f', -1, 64)
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+
+	"github.com/google/uuid"
+)
+
+func testShipment() shipmentDto.ShipmentDetailsResponse {
+	lat, lng := 1.25, 103.8
+	updated := time.Date(2025, 5, 10, 6, 0, 0, 0, time.UTC)
+	voyage := "M7"
+	smdg := "PSA"
+	shanghai := shipmentDto.ShipmentLocationResponse{Name: "Shanghai", Locode: "CNSHA", Country: "China", Latitude: 31.23, Longitude: 121.47}
+	rotterdam := shipmentDto.ShipmentLocationResponse{Name: "Rotterdam", Locode: "NLRTM", Country: "Netherlands", Latitude: 51.95, Longitude: 4.14}
+
+	return shipmentDto.ShipmentDetailsResponse{
+		ID:             uuid.New(),
+		ShipmentNumber: "MSCU1234567",
+		Locations:      []shipmentDto.ShipmentLocationResponse{shanghai, rotterdam, {Name: "Unknown"}},
+		Route: shipmentDto.ShipmentRouteResponse{
+			Pol: &shipmentDto.ShipmentRoutePoint{Location: shanghai},
+			Pod: &shipmentDto.ShipmentRoutePoint{Location: rotterdam},
+		},
+		Facilities: []shipmentDto.ShipmentFacilityResponse{
+			{Name: "Pasir Panjang", Locode: "SGSIN", SmdgCode: &smdg, Latitude: 1.27, Longitude: 103.76},
+		},
+		RouteData: shipmentDto.ShipmentRouteDataResponse{
+			RouteSegments: []shipmentDto.ShipmentRouteSegmentResponse{
+				{SegmentOrder: 1, RouteType: "SEA", Path: []shipmentDto.ShipmentRouteSegmentPointResponse{
+					{Latitude: 1.25, Longitude: 103.8, PointOrder: 0},
+					{Latitude: 51.95, Longitude: 4.14, PointOrder: 1},
+				}},
+				{SegmentOrder: 0, RouteType: "SEA", Path: []shipmentDto.ShipmentRouteSegmentPointResponse{
+					{Latitude: 1.25, Longitude: 103.8, PointOrder: 1},
+					{Latitude: 31.23, Longitude: 121.47, PointOrder: 0},
+				}},
+			},
+			Ais: shipmentDto.ShipmentAisResponse{
+				Vessel:                   &shipmentDto.ShipmentVesselResponse{Name: "MSC OSCAR", Imo: 9703291, Mmsi: 355906000},
+				LastEventVoyage:          &voyage,
+				LastVesselPositionLat:    &lat,
+				LastVesselPositionLng:    &lng,
+				LastVesselPositionUpdate: &updated,
+			},
+		},
+	}
+}
+
+func TestShipmentFeatures(t *testing.T) {
+	features := ShipmentFeatures(testShipment())
+
+	kinds := map[string]int{}
+	for _, f := range features {
+		kinds[f.Kind]++
+	}
+	if kinds[KindRoute] != 2 || kinds[KindPort] != 2 || kinds[KindFacility] != 1 || kinds[KindVessel] != 1 {
+		t.Fatalf("Unexpected features %v", kinds)
+	}
+
+	first := features[0]
+	if first.Properties["segmentOrder"] != "0" || first.Line[0].Lat != 31.23 {
+		t.Errorf("Expected segments and points in order, got %+v", first)
+	}
+	if features[2].Properties["role"] != "pol" || features[3].Properties["role"] != "pod" {
+		t.Errorf("Expected port roles, got %q and %q", features[2].Properties["role"], features[3].Properties["role"])
+	}
+
+	vessel := features[len(features)-1]
+	if vessel.Name != "MSC OSCAR" || vessel.Properties["imo"] != "9703291" || vessel.Properties["voyage"] != "M7" {
+		t.Errorf("Unexpected vessel feature %+v", vessel)
+	}
+}
+
+func TestEncodeGeoJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, FormatGeoJSON, "test", ShipmentFeatures(testShipment())); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	var collection struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &collection); err != nil {
+		t.Fatalf("Invalid GeoJSON: %v", err)
+	}
+	if collection.Type != "FeatureCollection" || len(collection.Features) != 6 {
+		t.Fatalf("Unexpected collection %s with %d features", collection.Type, len(collection.Features))
+	}
+
+	vessel := collection.Features[5]
+	if vessel.Geometry.Type != "Point" || string(vessel.Geometry.Coordinates) != "[103.8,1.25]" {
+		t.Errorf("Expected a longitude first point, got %s %s", vessel.Geometry.Type, vessel.Geometry.Coordinates)
+	}
+	if vessel.Properties["time"] != "2025-05-10T06:00:00Z" || vessel.Properties["shipmentNumber"] != "MSCU1234567" {
+		t.Errorf("Unexpected vessel properties %v", vessel.Properties)
+	}
+}
+
+func TestEncodeKML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, FormatKML, "test", ShipmentFeatures(testShipment())); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	var doc kmlDocument
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid KML: %v", err)
+	}
+	if len(doc.Folders) != 1 || len(doc.Folders[0].Placemarks) != 6 {
+		t.Fatalf("Expected a folder with 6 placemarks, got %+v", doc.Folders)
+	}
+
+	route := doc.Folders[0].Placemarks[0]
+	if route.LineString == nil || route.LineString.Coordinates != "121.47,31.23 103.8,1.25" || route.StyleURL != "#route" {
+		t.Errorf("Unexpected route placemark %+v", route)
+	}
+}
+
+func TestEncodeGPX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, FormatGPX, "test", ShipmentFeatures(testShipment())); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Errorf("Expected an XML declaration")
+	}
+
+	var doc gpxDocument
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid GPX: %v", err)
+	}
+	if doc.Version != "1.1" || len(doc.Waypoints) != 4 || len(doc.Routes) != 2 {
+		t.Fatalf("Expected 4 waypoints and 2 routes, got %d and %d", len(doc.Waypoints), len(doc.Routes))
+	}
+	if doc.Waypoints[3].Type != KindVessel || doc.Waypoints[3].Time != "2025-05-10T06:00:00Z" {
+		t.Errorf("Unexpected vessel waypoint %+v", doc.Waypoints[3])
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"": FormatGeoJSON, "GeoJSON": FormatGeoJSON, "kml": FormatKML, " GPX ": FormatGPX} {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %s, %v, want %s", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("shp"); err == nil {
+		t.Errorf("Expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	filterRepositories "go-starter/internal/modules/filters/repositories"
+	filterServices "go-starter/internal/modules/filters/services"
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ExportService struct {
+	shipmentService shipmentServices.ShipmentService
+	filterRepo      *filterRepositories.FilterRepository
+}
+
+func NewExportService(shipmentService shipmentServices.ShipmentService, filterRepo *filterRepositories.FilterRepository) *ExportService {
+	return &ExportService{
+		shipmentService: shipmentService,
+		filterRepo:      filterRepo,
+	}
+}
+
+// ShipmentFeatures returns the features of a single shipment of the user
+func (s *ExportService) ShipmentFeatures(ctx context.Context, userID, shipmentID uuid.UUID) (string, []Feature, error) {
+	details, err := s.shipmentService.GetShipmentDetails(ctx, userID, shipmentID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return details.ShipmentNumber, ShipmentFeatures(*details), nil
+}
+
+// FilteredFeatures returns the features of the user's shipments that are among shipmentIDs,
+// when given, and pass the saved grid filter, when given
+func (s *ExportService) FilteredFeatures(ctx context.Context, userID uuid.UUID, shipmentIDs []uuid.UUID, filterID *uuid.UUID, now time.Time) ([]Feature, error) {
+	var filterModel map[string]interface{}
+	if filterID != nil {
+		filter, err := s.filterRepo.GetFilterByID(ctx, *filterID, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("saved filter not found")
+			}
+			return nil, fmt.Errorf("error retrieving saved filter: %w", err)
+		}
+		filterModel, _ = filter.FilterData.Filters.(map[string]interface{})
+	}
+
+	rows, err := s.rows(ctx, userID, shipmentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var features []Feature
+	for _, row := range rows {
+		if filterServices.MatchesFilterModel(filterModel, row, now) {
+			features = append(features, ShipmentFeatures(row)...)
+		}
+	}
+	return features, nil
+}
+
+// rows loads the grid rows of the user's shipments among shipmentIDs, all rows when there
+// are none. The saved filter may test any grid column, so rows carry everything the grid shows.
+func (s *ExportService) rows(ctx context.Context, userID uuid.UUID, shipmentIDs []uuid.UUID) ([]shipmentDto.ShipmentDetailsResponse, error) {
+	if len(shipmentIDs) > 0 {
+		return s.shipmentService.GetShipmentsForGridByIDs(ctx, userID, shipmentIDs)
+	}
+
+	grid, err := s.shipmentService.GetShipmentsForGrid(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return grid.Rows, nil
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+
+	"github.com/google/uuid"
+)
+
+// Feature kinds
+const (
+	KindRoute    = "route"
+	KindPort     = "port"
+	KindFacility = "facility"
+	KindVessel   = "vessel"
+)
+
+// Coordinate is a WGS84 position
+type Coordinate struct {
+	Lat float64
+	Lng float64
+}
+
+// Feature is a shipment geometry, either a point or a line, that every export format renders
+type Feature struct {
+	ShipmentID     uuid.UUID
+	ShipmentNumber string
+	Kind           string
+	Name           string
+	Point          *Coordinate
+	Line           []Coordinate
+	Time           *time.Time
+	// Properties are format independent attributes, e.g. the locode of a port
+	Properties map[string]string
+}
+
+// ShipmentFeatures collects the planned route, the port locations, the facilities and the
+// current vessel position of a shipment
+func ShipmentFeatures(row shipmentDto.ShipmentDetailsResponse) []Feature {
+	var features []Feature
+	feature := func(kind, name string) Feature {
+		return Feature{
+			ShipmentID:     row.ID,
+			ShipmentNumber: row.ShipmentNumber,
+			Kind:           kind,
+			Name:           name,
+			Properties:     map[string]string{},
+		}
+	}
+
+	segments := append([]shipmentDto.ShipmentRouteSegmentResponse(nil), row.RouteData.RouteSegments...)
+	sort.SliceStable(segments, func(i, j int) bool { return segments[i].SegmentOrder < segments[j].SegmentOrder })
+	for _, segment := range segments {
+		if len(segment.Path) < 2 {
+			continue
+		}
+		path := append([]shipmentDto.ShipmentRouteSegmentPointResponse(nil), segment.Path...)
+		sort.SliceStable(path, func(i, j int) bool { return path[i].PointOrder < path[j].PointOrder })
+
+		f := feature(KindRoute, fmt.Sprintf("%s route %d", row.ShipmentNumber, segment.SegmentOrder+1))
+		for _, point := range path {
+			f.Line = append(f.Line, Coordinate{Lat: point.Latitude, Lng: point.Longitude})
+		}
+		f.Properties["routeType"] = segment.RouteType
+		f.Properties["segmentOrder"] = strconv.Itoa(segment.SegmentOrder)
+		if leg := emissionLeg(row.Emissions, segment.SegmentOrder); leg != nil {
+			f.Properties["distanceKm"] = strconv.FormatFloat(leg.DistanceKm, 'f', 1, 64)
+			f.Properties["co2eKg"] = strconv.FormatFloat(leg.Kg, 'f', 1, 64)
+		}
+		features = append(features, f)
+	}
+
+	roles := portRoles(row.Route)
+	for _, location := range row.Locations {
+		if location.Latitude == 0 && location.Longitude == 0 {
+			continue
+		}
+		f := feature(KindPort, location.Name)
+		f.Point = &Coordinate{Lat: location.Latitude, Lng: location.Longitude}
+		f.Properties["locode"] = location.Locode
+		f.Properties["country"] = location.Country
+		if role, ok := roles[location.Locode]; ok {
+			f.Properties["role"] = role
+		}
+		features = append(features, f)
+	}
+
+	for _, facility := range row.Facilities {
+		if facility.Latitude == 0 && facility.Longitude == 0 {
+			continue
+		}
+		f := feature(KindFacility, facility.Name)
+		f.Point = &Coordinate{Lat: facility.Latitude, Lng: facility.Longitude}
+		f.Properties["locode"] = facility.Locode
+		if facility.SmdgCode != nil {
+			f.Properties["smdgCode"] = *facility.SmdgCode
+		}
+		if facility.BicCode != nil {
+			f.Properties["bicCode"] = *facility.BicCode
+		}
+		features = append(features, f)
+	}
+
+	ais := row.RouteData.Ais
+	if ais.LastVesselPositionLat != nil && ais.LastVesselPositionLng != nil {
+		name := "Vessel"
+		if ais.Vessel != nil && ais.Vessel.Name != "" {
+			name = ais.Vessel.Name
+		}
+		f := feature(KindVessel, name)
+		f.Point = &Coordinate{Lat: *ais.LastVesselPositionLat, Lng: *ais.LastVesselPositionLng}
+		f.Time = ais.LastVesselPositionUpdate
+		if ais.Vessel != nil {
+			f.Properties["imo"] = strconv.Itoa(ais.Vessel.Imo)
+			f.Properties["mmsi"] = strconv.Itoa(ais.Vessel.Mmsi)
+		}
+		if ais.LastEventVoyage != nil {
+			f.Properties["voyage"] = *ais.LastEventVoyage
+		}
+		features = append(features, f)
+	}
+
+	return features
+}
+
+// portRoles maps the locodes of a shipment's route to their role, e.g. pol. The port of
+// loading and the port of discharge win over inland points at the same locode.
+func portRoles(route shipmentDto.ShipmentRouteResponse) map[string]string {
+	roles := map[string]string{}
+	for _, point := range []struct {
+		role  string
+		point *shipmentDto.ShipmentRoutePoint
+	}{
+		{"prepol", route.Prepol},
+		{"postpod", route.Postpod},
+		{"pol", route.Pol},
+		{"pod", route.Pod},
+	} {
+		if point.point != nil && point.point.Location.Locode != "" {
+			roles[point.point.Location.Locode] = point.role
+		}
+	}
+	return roles
+}
+
+// emissionLeg returns the CO2e estimate of a route segment, nil without one
+func emissionLeg(emissions *shipmentDto.ShipmentEmissionsResponse, segmentOrder int) *shipmentDto.EmissionLegResponse {
+	if emissions == nil {
+		return nil
+	}
+	for i := range emissions.Legs {
+		if emissions.Legs[i].SegmentOrder == segmentOrder {
+			return &emissions.Legs[i]
+		}
+	}
+	return nil
+}
+
+// description summarizes a feature's properties for formats without structured attributes
+func (f Feature) description() string {
+	keys := f.propertyKeys()
+	parts := make([]string, 0, len(keys)+1)
+	parts = append(parts, "Shipment "+f.ShipmentNumber)
+	for _, key := range keys {
+		parts = append(parts, key+": "+f.Properties[key])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// propertyKeys returns the names of the non-empty properties in a stable order
+func (f Feature) propertyKeys() []string {
+	keys := make([]string, 0, len(f.Properties))
+	for key, value := range f.Properties {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
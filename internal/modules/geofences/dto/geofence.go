@@ -0,0 +1,32 @@
+package dto
+
+import "go-starter/internal/modules/geofences/models"
+
+// SaveGeofenceRequest represents the request to create or update a geofence. Polygon
+// geofences need at least three vertices, circle geofences a center and a radius.
+type SaveGeofenceRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	// Category defaults to other
+	Category        string          `json:"category" validate:"omitempty,oneof=port_approach anchorage warehouse other"`
+	Shape           models.Shape    `json:"shape" validate:"required,oneof=polygon circle"`
+	Polygon         []models.Vertex `json:"polygon" validate:"max=500"`
+	CenterLatitude  *float64        `json:"center_latitude"`
+	CenterLongitude *float64        `json:"center_longitude"`
+	RadiusMeters    float64         `json:"radius_meters" validate:"min=0"`
+	// NotifyOnEnter, NotifyOnExit and Enabled default to true
+	NotifyOnEnter *bool `json:"notify_on_enter"`
+	NotifyOnExit  *bool `json:"notify_on_exit"`
+	Enabled       *bool `json:"enabled"`
+}
+
+// GeofencesListResponse represents the response when listing geofences
+type GeofencesListResponse struct {
+	Geofences []models.Geofence `json:"geofences"`
+	Total     int               `json:"total"`
+}
+
+// EventsListResponse represents the response when listing geofence events
+type EventsListResponse struct {
+	Events []models.GeofenceEvent `json:"events"`
+	Total  int                    `json:"total"`
+}
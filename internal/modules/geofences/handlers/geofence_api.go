@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"go-starter/internal/modules/auth/services"
+	"go-starter/internal/modules/geofences/dto"
+	"go-starter/internal/modules/geofences/repositories"
+	geofenceServices "go-starter/internal/modules/geofences/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type GeofenceAPIHandler struct {
+	geofenceService *geofenceServices.GeofenceService
+	validator       *validator.Validate
+}
+
+func NewGeofenceAPIHandler(geofenceService *geofenceServices.GeofenceService) *GeofenceAPIHandler {
+	return &GeofenceAPIHandler{
+		geofenceService: geofenceService,
+		validator:       validator.New(),
+	}
+}
+
+// GetGeofences handles GET /api/geofences
+func (h *GeofenceAPIHandler) GetGeofences(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	response, err := h.geofenceService.GetGeofences(ctx, userID)
+	if err != nil {
+		return h.serviceError(c, "Failed to retrieve geofences", err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetGeofence handles GET /api/geofences/:id
+func (h *GeofenceAPIHandler) GetGeofence(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	geofenceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid geofence ID",
+		})
+	}
+
+	geofence, err := h.geofenceService.GetGeofence(ctx, userID, geofenceID)
+	if err != nil {
+		return h.serviceError(c, "Failed to retrieve geofence", err)
+	}
+
+	return c.JSON(http.StatusOK, geofence)
+}
+
+// CreateGeofence handles POST /api/geofences
+func (h *GeofenceAPIHandler) CreateGeofence(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.SaveGeofenceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Validation failed: " + err.Error(),
+		})
+	}
+
+	geofence, err := h.geofenceService.CreateGeofence(ctx, userID, &req)
+	if err != nil {
+		return h.serviceError(c, "Failed to create geofence", err)
+	}
+
+	return c.JSON(http.StatusCreated, geofence)
+}
+
+// UpdateGeofence handles PUT /api/geofences/:id
+func (h *GeofenceAPIHandler) UpdateGeofence(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	geofenceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid geofence ID",
+		})
+	}
+
+	var req dto.SaveGeofenceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Validation failed: " + err.Error(),
+		})
+	}
+
+	geofence, err := h.geofenceService.UpdateGeofence(ctx, userID, geofenceID, &req)
+	if err != nil {
+		return h.serviceError(c, "Failed to update geofence", err)
+	}
+
+	return c.JSON(http.StatusOK, geofence)
+}
+
+// DeleteGeofence handles DELETE /api/geofences/:id
+func (h *GeofenceAPIHandler) DeleteGeofence(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	geofenceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid geofence ID",
+		})
+	}
+
+	if err := h.geofenceService.DeleteGeofence(ctx, userID, geofenceID); err != nil {
+		return h.serviceError(c, "Failed to delete geofence", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Geofence deleted successfully",
+	})
+}
+
+// GetEvents handles GET /api/geofences/events?geofence_id=&shipment_id=&limit=
+func (h *GeofenceAPIHandler) GetEvents(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	var filter repositories.EventListFilter
+	if geofenceIDStr := c.QueryParam("geofence_id"); geofenceIDStr != "" {
+		geofenceID, err := uuid.Parse(geofenceIDStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid geofence ID",
+			})
+		}
+		filter.GeofenceID = &geofenceID
+	}
+	if shipmentIDStr := c.QueryParam("shipment_id"); shipmentIDStr != "" {
+		shipmentID, err := uuid.Parse(shipmentIDStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid shipment ID",
+			})
+		}
+		filter.ShipmentID = &shipmentID
+	}
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid limit",
+			})
+		}
+		filter.Limit = limit
+	}
+
+	response, err := h.geofenceService.GetEvents(ctx, userID, filter)
+	if err != nil {
+		return h.serviceError(c, "Failed to retrieve geofence events", err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// serviceError maps service errors to status codes, validation errors are returned as is
+func (h *GeofenceAPIHandler) serviceError(c echo.Context, message string, err error) error {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	case strings.HasPrefix(err.Error(), "invalid"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message + ": " + err.Error(),
+	})
+}
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go-starter/pkg/geo"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Shape is the geometry of a geofence
+type Shape string
+
+const (
+	// ShapePolygon is bounded by Polygon
+	ShapePolygon Shape = "polygon"
+	// ShapeCircle is the zone within RadiusMeters of the center
+	ShapeCircle Shape = "circle"
+)
+
+// IsValid reports whether the shape is known
+func (s Shape) IsValid() bool {
+	return s == ShapePolygon || s == ShapeCircle
+}
+
+// Geofence categories, they only label the zone in events and alerts
+const (
+	CategoryPortApproach = "port_approach"
+	CategoryAnchorage    = "anchorage"
+	CategoryWarehouse    = "warehouse"
+	CategoryOther        = "other"
+)
+
+// Vertex is a corner of a geofence polygon
+type Vertex struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Polygon is stored as a JSON array of vertices
+type Polygon []Vertex
+
+// Scan implements the sql.Scanner interface
+func (p *Polygon) Scan(value interface{}) error {
+	if value == nil {
+		*p = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into Polygon", value)
+	}
+
+	return json.Unmarshal(bytes, p)
+}
+
+// Value implements the driver.Valuer interface
+func (p Polygon) Value() (driver.Value, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return json.Marshal(p)
+}
+
+// Geofence is a user's zone, e.g. a port approach, an anchorage or a warehouse. Vessels of
+// the user's shipments entering or exiting it are logged as geofence events.
+type Geofence struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID   uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name     string    `json:"name" gorm:"type:varchar(100);not null"`
+	Category string    `json:"category" gorm:"type:varchar(30);not null;default:'other'"`
+	Shape    Shape     `json:"shape" gorm:"type:varchar(10);not null"`
+	// Polygon are the vertices of polygon geofences
+	Polygon Polygon `json:"polygon" gorm:"type:jsonb"`
+	// CenterLatitude, CenterLongitude and RadiusMeters describe circle geofences
+	CenterLatitude  *float64 `json:"center_latitude" gorm:"type:decimal(10,8)"`
+	CenterLongitude *float64 `json:"center_longitude" gorm:"type:decimal(11,8)"`
+	RadiusMeters    float64  `json:"radius_meters" gorm:"not null;default:0"`
+	// NotifyOnEnter and NotifyOnExit raise alerts for the events, they are logged either way
+	NotifyOnEnter bool      `json:"notify_on_enter" gorm:"not null"`
+	NotifyOnExit  bool      `json:"notify_on_exit" gorm:"not null"`
+	Enabled       bool      `json:"enabled" gorm:"not null"`
+	CreatedAt     time.Time `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for Geofence
+func (Geofence) TableName() string {
+	return "geofences"
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (g *Geofence) BeforeCreate(tx *gorm.DB) error {
+	if g.ID == uuid.Nil {
+		g.ID = uuid.New()
+	}
+	return nil
+}
+
+// Contains reports whether a position lies inside the geofence
+func (g *Geofence) Contains(p geo.Point) bool {
+	switch g.Shape {
+	case ShapePolygon:
+		polygon := make([]geo.Point, len(g.Polygon))
+		for i, vertex := range g.Polygon {
+			polygon[i] = geo.Point{Lat: vertex.Latitude, Lng: vertex.Longitude}
+		}
+		return geo.InPolygon(p, polygon)
+	case ShapeCircle:
+		if g.CenterLatitude == nil || g.CenterLongitude == nil {
+			return false
+		}
+		return geo.Distance(p, geo.Point{Lat: *g.CenterLatitude, Lng: *g.CenterLongitude}) <= g.RadiusMeters
+	}
+	return false
+}
+
+// EventType is whether a vessel entered or exited a geofence
+type EventType string
+
+const (
+	EventTypeEnter EventType = "enter"
+	EventTypeExit  EventType = "exit"
+)
+
+// GeofenceEvent logs a vessel of a shipment crossing a geofence, at the AIS position that
+// was first seen on the other side
+type GeofenceEvent struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	GeofenceID uuid.UUID `json:"geofence_id" gorm:"type:uuid;not null;index"`
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	ShipmentID uuid.UUID `json:"shipment_id" gorm:"type:uuid;not null;index"`
+	Type       EventType `json:"type" gorm:"type:varchar(10);not null"`
+	VesselName string    `json:"vessel_name" gorm:"type:varchar(255)"`
+	Imo        int       `json:"imo"`
+	Mmsi       int       `json:"mmsi"`
+	Latitude   float64   `json:"latitude" gorm:"type:decimal(10,8)"`
+	Longitude  float64   `json:"longitude" gorm:"type:decimal(11,8)"`
+	// OccurredAt is the time of the AIS position
+	OccurredAt time.Time `json:"occurred_at" gorm:"type:timestamptz;not null;index"`
+	CreatedAt  time.Time `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for GeofenceEvent
+func (GeofenceEvent) TableName() string {
+	return "geofence_events"
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (e *GeofenceEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
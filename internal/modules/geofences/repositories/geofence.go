@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go-starter/internal/modules/geofences/models"
+	shipmentModels "go-starter/internal/modules/shipments/models"
+	"go-starter/pkg/db"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GeofenceRepository struct {
+	db *db.Database
+}
+
+func NewGeofenceRepository(database *db.Database) *GeofenceRepository {
+	return &GeofenceRepository{
+		db: database,
+	}
+}
+
+// EventListFilter narrows down the geofence events returned for a user
+type EventListFilter struct {
+	GeofenceID *uuid.UUID
+	ShipmentID *uuid.UUID
+	Limit      int
+}
+
+// CreateGeofence creates a new geofence
+func (r *GeofenceRepository) CreateGeofence(ctx context.Context, geofence *models.Geofence) error {
+	return r.db.DB.WithContext(ctx).Create(geofence).Error
+}
+
+// GetGeofenceByID retrieves a geofence by its ID and user ID
+func (r *GeofenceRepository) GetGeofenceByID(ctx context.Context, geofenceID, userID uuid.UUID) (*models.Geofence, error) {
+	var geofence models.Geofence
+	err := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", geofenceID, userID).
+		First(&geofence).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &geofence, nil
+}
+
+// GetGeofencesByUserID retrieves all geofences of a user, oldest first
+func (r *GeofenceRepository) GetGeofencesByUserID(ctx context.Context, userID uuid.UUID) ([]models.Geofence, error) {
+	var geofences []models.Geofence
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&geofences).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return geofences, nil
+}
+
+// GetGeofencesForShipment retrieves the enabled geofences of all users tracking the shipment
+func (r *GeofenceRepository) GetGeofencesForShipment(ctx context.Context, shipmentID uuid.UUID) ([]models.Geofence, error) {
+	var geofences []models.Geofence
+	err := r.db.DB.WithContext(ctx).
+		Where("enabled = ?", true).
+		Where("user_id IN (?)", r.db.DB.Model(&shipmentModels.UserShipment{}).
+			Select("user_id").
+			Where("shipment_id = ?", shipmentID)).
+		Find(&geofences).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get geofences for shipment: %w", err)
+	}
+
+	return geofences, nil
+}
+
+// UpdateGeofence updates an existing geofence
+func (r *GeofenceRepository) UpdateGeofence(ctx context.Context, geofence *models.Geofence) error {
+	return r.db.DB.WithContext(ctx).
+		Model(geofence).
+		Where("id = ? AND user_id = ?", geofence.ID, geofence.UserID).
+		Updates(map[string]interface{}{
+			"name":             geofence.Name,
+			"category":         geofence.Category,
+			"shape":            geofence.Shape,
+			"polygon":          geofence.Polygon,
+			"center_latitude":  geofence.CenterLatitude,
+			"center_longitude": geofence.CenterLongitude,
+			"radius_meters":    geofence.RadiusMeters,
+			"notify_on_enter":  geofence.NotifyOnEnter,
+			"notify_on_exit":   geofence.NotifyOnExit,
+			"enabled":          geofence.Enabled,
+			"updated_at":       gorm.Expr("NOW()"),
+		}).Error
+}
+
+// DeleteGeofence deletes a geofence and its events by its ID and user ID
+func (r *GeofenceRepository) DeleteGeofence(ctx context.Context, geofenceID, userID uuid.UUID) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", geofenceID, userID).
+			Delete(&models.Geofence{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Where("geofence_id = ?", geofenceID).Delete(&models.GeofenceEvent{}).Error
+	})
+}
+
+// CreateEvent logs a geofence event
+func (r *GeofenceRepository) CreateEvent(ctx context.Context, event *models.GeofenceEvent) error {
+	return r.db.DB.WithContext(ctx).Create(event).Error
+}
+
+// GetLatestEvents retrieves the latest event of the shipment per geofence, keyed by geofence ID
+func (r *GeofenceRepository) GetLatestEvents(ctx context.Context, shipmentID uuid.UUID) (map[uuid.UUID]models.GeofenceEvent, error) {
+	var events []models.GeofenceEvent
+	err := r.db.DB.WithContext(ctx).
+		Select("DISTINCT ON (geofence_id) *").
+		Where("shipment_id = ?", shipmentID).
+		Order("geofence_id, occurred_at DESC, created_at DESC").
+		Find(&events).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest geofence events: %w", err)
+	}
+
+	latest := make(map[uuid.UUID]models.GeofenceEvent, len(events))
+	for _, event := range events {
+		latest[event.GeofenceID] = event
+	}
+
+	return latest, nil
+}
+
+// GetEventsByUserID retrieves the geofence events of a user, newest first
+func (r *GeofenceRepository) GetEventsByUserID(ctx context.Context, userID uuid.UUID, filter EventListFilter) ([]models.GeofenceEvent, error) {
+	query := r.db.DB.WithContext(ctx).Where("user_id = ?", userID)
+
+	if filter.GeofenceID != nil {
+		query = query.Where("geofence_id = ?", *filter.GeofenceID)
+	}
+	if filter.ShipmentID != nil {
+		query = query.Where("shipment_id = ?", *filter.ShipmentID)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var events []models.GeofenceEvent
+	if err := query.Order("occurred_at DESC, created_at DESC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,39 @@
+package geofences
+
+import (
+	alertRepositories "go-starter/internal/modules/alerts/repositories"
+	alertServices "go-starter/internal/modules/alerts/services"
+	"go-starter/internal/modules/auth/middlewares"
+	"go-starter/internal/modules/auth/services"
+	"go-starter/internal/modules/geofences/handlers"
+	"go-starter/internal/modules/geofences/repositories"
+	geofenceServices "go-starter/internal/modules/geofences/services"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+	"go-starter/pkg/db"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRoutes(api *echo.Group, database *db.Database) {
+	// Initialize dependencies, geofence alerts reach notifications through the alert listeners
+	geofenceRepo := repositories.NewGeofenceRepository(database)
+	alertService := alertServices.NewAlertService(alertRepositories.NewAlertRepository(database))
+	geofenceService := geofenceServices.NewGeofenceService(geofenceRepo, alertService)
+	geofenceAPIHandler := handlers.NewGeofenceAPIHandler(geofenceService)
+
+	// Check vessel positions against geofences after every shipment sync, including background refreshes
+	shipmentServices.RegisterSyncListener(geofenceService.HandleShipmentSync)
+
+	// Create JWT service for middleware
+	jwtService := services.NewJWTService()
+
+	// Create geofences group with JWT middleware
+	geofencesGroup := api.Group("/geofences", middlewares.JWTMiddleware(jwtService))
+
+	geofencesGroup.GET("", geofenceAPIHandler.GetGeofences)          // GET /api/geofences
+	geofencesGroup.POST("", geofenceAPIHandler.CreateGeofence)       // POST /api/geofences
+	geofencesGroup.GET("/events", geofenceAPIHandler.GetEvents)      // GET /api/geofences/events
+	geofencesGroup.GET("/:id", geofenceAPIHandler.GetGeofence)       // GET /api/geofences/:id
+	geofencesGroup.PUT("/:id", geofenceAPIHandler.UpdateGeofence)    // PUT /api/geofences/:id
+	geofencesGroup.DELETE("/:id", geofenceAPIHandler.DeleteGeofence) // DELETE /api/geofences/:id
+}
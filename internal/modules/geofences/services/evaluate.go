@@ -0,0 +1,63 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	alertModels "go-starter/internal/modules/alerts/models"
+	alertServices "go-starter/internal/modules/alerts/services"
+	"go-starter/internal/modules/geofences/models"
+	"go-starter/internal/modules/shipments/types"
+	"go-starter/pkg/geo"
+)
+
+// EvaluateGeofence checks a vessel position against a geofence. last is the latest event of
+// the shipment at the geofence, a vessel without events is outside. A crossing is reported
+// when the position is newer than the last event and on the other side of the boundary.
+func EvaluateGeofence(geofence models.Geofence, last *models.GeofenceEvent, position types.VesselPositionSnapshot) (models.EventType, bool) {
+	if last != nil && !position.RecordedAt.After(last.OccurredAt) {
+		return "", false
+	}
+
+	wasInside := last != nil && last.Type == models.EventTypeEnter
+	isInside := geofence.Contains(geo.Point{Lat: position.Latitude, Lng: position.Longitude})
+
+	switch {
+	case isInside && !wasInside:
+		return models.EventTypeEnter, true
+	case !isInside && wasInside:
+		return models.EventTypeExit, true
+	}
+	return "", false
+}
+
+// eventAlert builds the alert of a geofence event. Its dedup key is the crossing itself, so
+// a repeated sync with the same AIS position doesn't raise it twice.
+func eventAlert(geofence models.Geofence, shipmentNumber string, event models.GeofenceEvent) alertServices.AlertCandidate {
+	vessel := event.VesselName
+	if vessel == "" {
+		vessel = "The vessel"
+	}
+
+	verb, preposition := "entered", "at"
+	if event.Type == models.EventTypeExit {
+		verb, preposition = "exited", "from"
+	}
+
+	return alertServices.AlertCandidate{
+		Type:     alertModels.AlertTypeGeofence,
+		DedupKey: fmt.Sprintf("%s:%s:%s:%s:%d", alertModels.AlertTypeGeofence, geofence.ID, event.ShipmentID, event.Type, event.OccurredAt.Unix()),
+		Title:    fmt.Sprintf("%s of %s %s %s", vessel, shipmentNumber, verb, geofence.Name),
+		Message: fmt.Sprintf("%s carrying %s %s %s %s (%s) at %.4f, %.4f on %s UTC.",
+			vessel, shipmentNumber, verb, preposition, geofence.Name, categoryLabel(geofence.Category),
+			event.Latitude, event.Longitude, event.OccurredAt.UTC().Format("2006-01-02 15:04")),
+	}
+}
+
+// categoryLabel turns a geofence category into words
+func categoryLabel(category string) string {
+	if category == "" {
+		category = models.CategoryOther
+	}
+	return strings.ReplaceAll(category, "_", " ")
+}
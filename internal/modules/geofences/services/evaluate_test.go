@@ -0,0 +1,137 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	alertModels "go-starter/internal/modules/alerts/models"
+	"go-starter/internal/modules/geofences/dto"
+	"go-starter/internal/modules/geofences/models"
+	"go-starter/internal/modules/shipments/types"
+
+	"github.com/google/uuid"
+)
+
+func anchorage() models.Geofence {
+	return models.Geofence{
+		ID:       uuid.New(),
+		Name:     "Maasvlakte Anchorage",
+		Category: models.CategoryAnchorage,
+		Shape:    models.ShapePolygon,
+		Polygon: models.Polygon{
+			{Latitude: 51.95, Longitude: 3.70},
+			{Latitude: 51.95, Longitude: 3.90},
+			{Latitude: 52.05, Longitude: 3.90},
+			{Latitude: 52.05, Longitude: 3.70},
+		},
+	}
+}
+
+func TestEvaluateGeofence(t *testing.T) {
+	geofence := anchorage()
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	inside := types.VesselPositionSnapshot{Latitude: 52.0, Longitude: 3.8, RecordedAt: base}
+	outside := types.VesselPositionSnapshot{Latitude: 51.9, Longitude: 4.1, RecordedAt: base}
+	entered := &models.GeofenceEvent{Type: models.EventTypeEnter, OccurredAt: base.Add(-time.Hour)}
+	exited := &models.GeofenceEvent{Type: models.EventTypeExit, OccurredAt: base.Add(-time.Hour)}
+
+	tests := []struct {
+		name     string
+		last     *models.GeofenceEvent
+		position types.VesselPositionSnapshot
+		want     models.EventType
+		crossed  bool
+	}{
+		{"first position inside", nil, inside, models.EventTypeEnter, true},
+		{"first position outside", nil, outside, "", false},
+		{"still inside", entered, inside, "", false},
+		{"leaves", entered, outside, models.EventTypeExit, true},
+		{"returns", exited, inside, models.EventTypeEnter, true},
+		{"position already evaluated", &models.GeofenceEvent{Type: models.EventTypeExit, OccurredAt: base}, inside, "", false},
+	}
+
+	for _, tt := range tests {
+		got, crossed := EvaluateGeofence(geofence, tt.last, tt.position)
+		if got != tt.want || crossed != tt.crossed {
+			t.Errorf("%s: got %q, %v, want %q, %v", tt.name, got, crossed, tt.want, tt.crossed)
+		}
+	}
+}
+
+func TestCircleGeofence(t *testing.T) {
+	lat, lng := 1.264, 103.84
+	geofence := models.Geofence{Shape: models.ShapeCircle, CenterLatitude: &lat, CenterLongitude: &lng, RadiusMeters: 5000}
+
+	near := types.VesselPositionSnapshot{Latitude: 1.29, Longitude: 103.85, RecordedAt: time.Now()}
+	if got, crossed := EvaluateGeofence(geofence, nil, near); !crossed || got != models.EventTypeEnter {
+		t.Errorf("Expected a vessel 3 km from the center to enter, got %q, %v", got, crossed)
+	}
+
+	far := types.VesselPositionSnapshot{Latitude: 1.40, Longitude: 103.85, RecordedAt: time.Now()}
+	if _, crossed := EvaluateGeofence(geofence, nil, far); crossed {
+		t.Errorf("Expected a vessel 15 km from the center to stay outside")
+	}
+}
+
+func TestEventAlert(t *testing.T) {
+	geofence := anchorage()
+	event := models.GeofenceEvent{
+		ShipmentID: uuid.New(),
+		Type:       models.EventTypeEnter,
+		VesselName: "MSC OSCAR",
+		Latitude:   52.0,
+		Longitude:  3.8,
+		OccurredAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	candidate := eventAlert(geofence, "MSCU1234567", event)
+	if candidate.Type != alertModels.AlertTypeGeofence || candidate.Title != "MSC OSCAR of MSCU1234567 entered Maasvlakte Anchorage" {
+		t.Errorf("Unexpected alert %+v", candidate)
+	}
+	if !strings.Contains(candidate.Message, "(anchorage)") || !strings.Contains(candidate.Message, "2025-06-01 12:00") {
+		t.Errorf("Unexpected message %q", candidate.Message)
+	}
+
+	event.Type = models.EventTypeExit
+	if eventAlert(geofence, "MSCU1234567", event).DedupKey == candidate.DedupKey {
+		t.Errorf("Expected enter and exit at the same time to raise separate alerts")
+	}
+}
+
+func TestApplyGeofenceRequest(t *testing.T) {
+	lat, lng := 1.264, 103.84
+	notify := false
+	geofence := anchorage()
+
+	err := applyGeofenceRequest(&geofence, &dto.SaveGeofenceRequest{
+		Name:            " Singapore Strait ",
+		Shape:           models.ShapeCircle,
+		CenterLatitude:  &lat,
+		CenterLongitude: &lng,
+		RadiusMeters:    5000,
+		NotifyOnExit:    &notify,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if geofence.Name != "Singapore Strait" || geofence.Polygon != nil || geofence.Category != models.CategoryOther {
+		t.Errorf("Expected a circle without polygon, got %+v", geofence)
+	}
+	if !geofence.NotifyOnEnter || geofence.NotifyOnExit || !geofence.Enabled {
+		t.Errorf("Unexpected flags %+v", geofence)
+	}
+
+	invalid := []dto.SaveGeofenceRequest{
+		{Name: "a", Shape: models.ShapePolygon, Polygon: []models.Vertex{{}, {}}},
+		{Name: "a", Shape: models.ShapePolygon, Polygon: []models.Vertex{{Latitude: 95}, {}, {}}},
+		{Name: "a", Shape: models.ShapeCircle, RadiusMeters: 100},
+		{Name: "a", Shape: models.ShapeCircle, CenterLatitude: &lat, CenterLongitude: &lng},
+		{Name: "a", Shape: "square"},
+	}
+	for _, req := range invalid {
+		if err := applyGeofenceRequest(&models.Geofence{}, &req); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Errorf("Expected a validation error for %+v, got %v", req, err)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+
+	alertServices "go-starter/internal/modules/alerts/services"
+	"go-starter/internal/modules/geofences/dto"
+	"go-starter/internal/modules/geofences/models"
+	"go-starter/internal/modules/geofences/repositories"
+	"go-starter/internal/modules/shipments/types"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxRadiusMeters bounds circle geofences, larger zones should be polygons
+	maxRadiusMeters = 500000
+	// defaultEventLimit and maxEventLimit bound the listed geofence events
+	defaultEventLimit = 100
+	maxEventLimit     = 1000
+)
+
+// AlertRaiser raises alerts for a user, implemented by the alert service
+type AlertRaiser interface {
+	CreateAlert(ctx context.Context, userID, shipmentID uuid.UUID, ruleID *uuid.UUID, candidate alertServices.AlertCandidate) (bool, error)
+}
+
+type GeofenceService struct {
+	geofenceRepo *repositories.GeofenceRepository
+	alerts       AlertRaiser
+}
+
+func NewGeofenceService(geofenceRepo *repositories.GeofenceRepository, alerts AlertRaiser) *GeofenceService {
+	return &GeofenceService{
+		geofenceRepo: geofenceRepo,
+		alerts:       alerts,
+	}
+}
+
+// HandleShipmentSync checks the synced vessel position against the geofences of all users
+// tracking the shipment, logs the crossings and raises alerts for them. It is registered
+// as a shipment sync listener.
+func (s *GeofenceService) HandleShipmentSync(ctx context.Context, event types.ShipmentSyncEvent) {
+	if event.After == nil || event.After.VesselPosition == nil {
+		return
+	}
+	position := *event.After.VesselPosition
+
+	geofences, err := s.geofenceRepo.GetGeofencesForShipment(ctx, event.ShipmentID)
+	if err != nil {
+		log.Printf("Warning: Failed to load geofences for shipment %s: %v", event.ShipmentID, err)
+		return
+	}
+	if len(geofences) == 0 {
+		return
+	}
+
+	latest, err := s.geofenceRepo.GetLatestEvents(ctx, event.ShipmentID)
+	if err != nil {
+		log.Printf("Warning: Failed to load geofence events for shipment %s: %v", event.ShipmentID, err)
+		return
+	}
+
+	for _, geofence := range geofences {
+		var last *models.GeofenceEvent
+		if previous, ok := latest[geofence.ID]; ok {
+			last = &previous
+		}
+
+		eventType, crossed := EvaluateGeofence(geofence, last, position)
+		if !crossed {
+			continue
+		}
+
+		geofenceEvent := models.GeofenceEvent{
+			GeofenceID: geofence.ID,
+			UserID:     geofence.UserID,
+			ShipmentID: event.ShipmentID,
+			Type:       eventType,
+			VesselName: position.VesselName,
+			Imo:        position.Imo,
+			Mmsi:       position.Mmsi,
+			Latitude:   position.Latitude,
+			Longitude:  position.Longitude,
+			OccurredAt: position.RecordedAt,
+		}
+		if err := s.geofenceRepo.CreateEvent(ctx, &geofenceEvent); err != nil {
+			log.Printf("Warning: Failed to log geofence %s event for shipment %s: %v", eventType, event.ShipmentID, err)
+			continue
+		}
+		log.Printf("Logged geofence %s event for shipment %s at %s of user %s", eventType, event.ShipmentID, geofence.Name, geofence.UserID)
+
+		if (eventType == models.EventTypeEnter && !geofence.NotifyOnEnter) || (eventType == models.EventTypeExit && !geofence.NotifyOnExit) {
+			continue
+		}
+		candidate := eventAlert(geofence, event.After.ShipmentNumber, geofenceEvent)
+		if _, err := s.alerts.CreateAlert(ctx, geofence.UserID, event.ShipmentID, nil, candidate); err != nil {
+			log.Printf("Warning: Failed to raise geofence alert for shipment %s: %v", event.ShipmentID, err)
+		}
+	}
+}
+
+// CreateGeofence validates and creates a new geofence
+func (s *GeofenceService) CreateGeofence(ctx context.Context, userID uuid.UUID, req *dto.SaveGeofenceRequest) (*models.Geofence, error) {
+	geofence := &models.Geofence{UserID: userID}
+	if err := applyGeofenceRequest(geofence, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.geofenceRepo.CreateGeofence(ctx, geofence); err != nil {
+		return nil, fmt.Errorf("error creating geofence: %w", err)
+	}
+
+	return geofence, nil
+}
+
+// GetGeofences retrieves all geofences of a user
+func (s *GeofenceService) GetGeofences(ctx context.Context, userID uuid.UUID) (*dto.GeofencesListResponse, error) {
+	geofences, err := s.geofenceRepo.GetGeofencesByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving geofences: %w", err)
+	}
+
+	return &dto.GeofencesListResponse{
+		Geofences: geofences,
+		Total:     len(geofences),
+	}, nil
+}
+
+// GetGeofence retrieves a geofence of a user
+func (s *GeofenceService) GetGeofence(ctx context.Context, userID, geofenceID uuid.UUID) (*models.Geofence, error) {
+	geofence, err := s.geofenceRepo.GetGeofenceByID(ctx, geofenceID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("geofence not found")
+		}
+		return nil, fmt.Errorf("error retrieving geofence: %w", err)
+	}
+
+	return geofence, nil
+}
+
+// UpdateGeofence validates and updates an existing geofence
+func (s *GeofenceService) UpdateGeofence(ctx context.Context, userID, geofenceID uuid.UUID, req *dto.SaveGeofenceRequest) (*models.Geofence, error) {
+	geofence, err := s.GetGeofence(ctx, userID, geofenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyGeofenceRequest(geofence, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.geofenceRepo.UpdateGeofence(ctx, geofence); err != nil {
+		return nil, fmt.Errorf("error updating geofence: %w", err)
+	}
+
+	return geofence, nil
+}
+
+// DeleteGeofence deletes a geofence with its event log
+func (s *GeofenceService) DeleteGeofence(ctx context.Context, userID, geofenceID uuid.UUID) error {
+	if err := s.geofenceRepo.DeleteGeofence(ctx, geofenceID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("geofence not found")
+		}
+		return fmt.Errorf("error deleting geofence: %w", err)
+	}
+
+	return nil
+}
+
+// GetEvents retrieves the geofence events of a user, newest first
+func (s *GeofenceService) GetEvents(ctx context.Context, userID uuid.UUID, filter repositories.EventListFilter) (*dto.EventsListResponse, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultEventLimit
+	}
+	filter.Limit = min(filter.Limit, maxEventLimit)
+
+	events, err := s.geofenceRepo.GetEventsByUserID(ctx, userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving geofence events: %w", err)
+	}
+
+	return &dto.EventsListResponse{
+		Events: events,
+		Total:  len(events),
+	}, nil
+}
+
+// applyGeofenceRequest validates the request and copies it onto the geofence, only the
+// fields of the requested shape are kept
+func applyGeofenceRequest(geofence *models.Geofence, req *dto.SaveGeofenceRequest) error {
+	if !req.Shape.IsValid() {
+		return fmt.Errorf("invalid shape '%s'", req.Shape)
+	}
+
+	geofence.Name = strings.TrimSpace(req.Name)
+	geofence.Shape = req.Shape
+	geofence.Category = req.Category
+	if geofence.Category == "" {
+		geofence.Category = models.CategoryOther
+	}
+
+	switch req.Shape {
+	case models.ShapePolygon:
+		if len(req.Polygon) < 3 {
+			return fmt.Errorf("invalid polygon, at least 3 vertices are required")
+		}
+		for _, vertex := range req.Polygon {
+			if !validCoordinate(vertex.Latitude, vertex.Longitude) {
+				return fmt.Errorf("invalid polygon vertex %.6f, %.6f", vertex.Latitude, vertex.Longitude)
+			}
+		}
+		geofence.Polygon = models.Polygon(req.Polygon)
+		geofence.CenterLatitude, geofence.CenterLongitude, geofence.RadiusMeters = nil, nil, 0
+	case models.ShapeCircle:
+		if req.CenterLatitude == nil || req.CenterLongitude == nil || !validCoordinate(*req.CenterLatitude, *req.CenterLongitude) {
+			return fmt.Errorf("invalid circle, a valid center is required")
+		}
+		if req.RadiusMeters <= 0 || req.RadiusMeters > maxRadiusMeters {
+			return fmt.Errorf("invalid radius, expected more than 0 and at most %d meters", maxRadiusMeters)
+		}
+		geofence.CenterLatitude, geofence.CenterLongitude = req.CenterLatitude, req.CenterLongitude
+		geofence.RadiusMeters = req.RadiusMeters
+		geofence.Polygon = nil
+	}
+
+	geofence.NotifyOnEnter = req.NotifyOnEnter == nil || *req.NotifyOnEnter
+	geofence.NotifyOnExit = req.NotifyOnExit == nil || *req.NotifyOnExit
+	geofence.Enabled = req.Enabled == nil || *req.Enabled
+
+	return nil
+}
+
+// validCoordinate reports whether a latitude and longitude are on the map
+func validCoordinate(lat, lng float64) bool {
+	return !math.IsNaN(lat) && !math.IsNaN(lng) && lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
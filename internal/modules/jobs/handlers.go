@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"go-starter/internal/jobs"
+	shipmentServices "go-starter/internal/modules/shipments/services"
 
 	"github.com/labstack/echo/v4"
 )
@@ -51,6 +52,11 @@ func (h *JobHandler) GetJobsStatus(c echo.Context) error {
 	})
 }
 
+// GetSyncStats returns shipment sync coordination counters since process start
+func (h *JobHandler) GetSyncStats(c echo.Context) error {
+	return h.sendSuccessResponse(c, "Shipment sync statistics", shipmentServices.GetSyncStats())
+}
+
 // HealthCheck provides a simple health check for job scheduler
 func (h *JobHandler) HealthCheck(c echo.Context) error {
 	isHealthy := h.scheduler.IsRunning()
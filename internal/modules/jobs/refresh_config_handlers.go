@@ -0,0 +1,109 @@
+package jobs
+
+import (
+	"net/http"
+	"time"
+
+	"go-starter/internal/jobs"
+	authServices "go-starter/internal/modules/auth/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RefreshConfigHandler exposes the shipment refresh job configuration to admins
+type RefreshConfigHandler struct {
+	job   *jobs.ShipmentRefreshJob
+	store *jobs.RefreshSettingsStore
+}
+
+// NewRefreshConfigHandler creates a new refresh config handler
+func NewRefreshConfigHandler(job *jobs.ShipmentRefreshJob, store *jobs.RefreshSettingsStore) *RefreshConfigHandler {
+	return &RefreshConfigHandler{
+		job:   job,
+		store: store,
+	}
+}
+
+// RefreshConfigPayload is the API representation of the refresh configuration.
+// Durations use Go duration syntax, e.g. "15m" or "1h30m".
+type RefreshConfigPayload struct {
+	RefreshInterval     string `json:"refresh_interval"`
+	ConcurrentWorkers   int    `json:"concurrent_workers"`
+	MaxShipmentsPerRun  int    `json:"max_shipments_per_run"`
+	SkipRecentlyUpdated string `json:"skip_recently_updated"`
+}
+
+// GetConfig returns the configuration the refresh job is currently running with
+func (h *RefreshConfigHandler) GetConfig(c echo.Context) error {
+	return h.sendSuccessResponse(c, "Shipment refresh configuration", toRefreshConfigPayload(h.job.GetConfig()))
+}
+
+// UpdateConfig validates, persists and applies a new refresh configuration
+func (h *RefreshConfigHandler) UpdateConfig(c echo.Context) error {
+	userID, err := authServices.GetUserIDFromContext(c)
+	if err != nil {
+		return h.sendErrorResponse(c, http.StatusUnauthorized, "Invalid or expired token")
+	}
+
+	var payload RefreshConfigPayload
+	if err := c.Bind(&payload); err != nil {
+		return h.sendErrorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	refreshInterval, err := time.ParseDuration(payload.RefreshInterval)
+	if err != nil {
+		return h.sendErrorResponse(c, http.StatusBadRequest, "invalid refresh_interval")
+	}
+	skipRecentlyUpdated, err := time.ParseDuration(payload.SkipRecentlyUpdated)
+	if err != nil {
+		return h.sendErrorResponse(c, http.StatusBadRequest, "invalid skip_recently_updated")
+	}
+
+	newConfig := jobs.ShipmentRefreshConfig{
+		RefreshInterval:     refreshInterval,
+		ConcurrentWorkers:   payload.ConcurrentWorkers,
+		MaxShipmentsPerRun:  payload.MaxShipmentsPerRun,
+		SkipRecentlyUpdated: skipRecentlyUpdated,
+	}
+	if err := newConfig.Validate(); err != nil {
+		return h.sendErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Persist first so a failed save doesn't leave a change that is lost on restart
+	if err := h.store.Save(c.Request().Context(), newConfig, userID); err != nil {
+		return h.sendErrorResponse(c, http.StatusInternalServerError, "failed to save configuration")
+	}
+	if err := h.job.UpdateConfig(newConfig); err != nil {
+		return h.sendErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return h.sendSuccessResponse(c, "Shipment refresh configuration updated", toRefreshConfigPayload(newConfig))
+}
+
+func toRefreshConfigPayload(config jobs.ShipmentRefreshConfig) RefreshConfigPayload {
+	return RefreshConfigPayload{
+		RefreshInterval:     config.RefreshInterval.String(),
+		ConcurrentWorkers:   config.ConcurrentWorkers,
+		MaxShipmentsPerRun:  config.MaxShipmentsPerRun,
+		SkipRecentlyUpdated: config.SkipRecentlyUpdated.String(),
+	}
+}
+
+// sendErrorResponse sends a standardized error response
+func (h *RefreshConfigHandler) sendErrorResponse(c echo.Context, statusCode int, message string) error {
+	return c.JSON(statusCode, ErrorResponse{
+		Status:  "error",
+		Message: message,
+		Code:    statusCode,
+	})
+}
+
+// sendSuccessResponse sends a standardized success response
+func (h *RefreshConfigHandler) sendSuccessResponse(c echo.Context, message string, data interface{}) error {
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Status:    "success",
+		Message:   message,
+		Data:      data,
+		Timestamp: time.Now(),
+	})
+}
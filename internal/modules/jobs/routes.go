@@ -2,15 +2,26 @@ package jobs
 
 import (
 	"go-starter/internal/jobs"
+	"go-starter/internal/modules/auth/middlewares"
+	authServices "go-starter/internal/modules/auth/services"
 	"go-starter/pkg/config"
-	"go-starter/pkg/db"
 
 	"github.com/labstack/echo/v4"
 )
 
 // RegisterRoutes registers job management routes
-func RegisterRoutes(e *echo.Echo, api *echo.Group, database *db.Database, cfg *config.Config, jobScheduler *jobs.JobScheduler) {
+func RegisterRoutes(
+	e *echo.Echo,
+	api *echo.Group,
+	cfg *config.Config,
+	jobScheduler *jobs.JobScheduler,
+	shipmentRefreshJob *jobs.ShipmentRefreshJob,
+	refreshSettings *jobs.RefreshSettingsStore,
+) {
+	jwtService := authServices.NewJWTService()
+
 	jobHandler := NewJobHandler(jobScheduler)
+	refreshConfigHandler := NewRefreshConfigHandler(shipmentRefreshJob, refreshSettings)
 
 	// Public health check endpoint
 	api.GET("/jobs/health", jobHandler.HealthCheck)
@@ -22,4 +33,18 @@ func RegisterRoutes(e *echo.Echo, api *echo.Group, database *db.Database, cfg *c
 	// jobsAPI.Use(middlewares.JWTMiddleware(jwtService))
 
 	jobsAPI.GET("/status", jobHandler.GetJobsStatus)
+
+	// Admin only: shipment sync coordination counters
+	jobsAPI.GET("/sync-stats", jobHandler.GetSyncStats,
+		middlewares.JWTMiddleware(jwtService),
+		middlewares.AdminMiddleware(cfg.AdminEmails),
+	)
+
+	// Admin only: runtime refresh job configuration
+	refreshConfigAPI := jobsAPI.Group("/shipment-refresh/config")
+	refreshConfigAPI.Use(middlewares.JWTMiddleware(jwtService))
+	refreshConfigAPI.Use(middlewares.AdminMiddleware(cfg.AdminEmails))
+
+	refreshConfigAPI.GET("", refreshConfigHandler.GetConfig)
+	refreshConfigAPI.PUT("", refreshConfigHandler.UpdateConfig)
 }
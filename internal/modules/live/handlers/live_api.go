@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-starter/internal/modules/auth/services"
+	liveServices "go-starter/internal/modules/live/services"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// heartbeatInterval keeps idle streams from being closed by proxies
+const heartbeatInterval = 25 * time.Second
+
+type LiveAPIHandler struct {
+	broker *liveServices.Broker
+}
+
+func NewLiveAPIHandler(broker *liveServices.Broker) *LiveAPIHandler {
+	return &LiveAPIHandler{
+		broker: broker,
+	}
+}
+
+// StreamShipmentEvents handles GET /api/shipments/events
+func (h *LiveAPIHandler) StreamShipmentEvents(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	// The stream stays open far longer than the server's write timeout
+	if err := http.NewResponseController(c.Response()).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("Warning: Failed to clear write deadline of event stream: %v", err)
+	}
+
+	sub := h.broker.Subscribe(userID)
+	defer h.broker.Unsubscribe(sub)
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	// Reconnect after 5 seconds when the stream ends
+	if _, err := fmt.Fprint(res, "retry: 5000\n\n"); err != nil {
+		return nil
+	}
+	res.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("Warning: Failed to encode live event: %v", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return nil
+			}
+			res.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
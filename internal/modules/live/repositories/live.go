@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+
+	shipmentModels "go-starter/internal/modules/shipments/models"
+	"go-starter/pkg/db"
+
+	"github.com/google/uuid"
+)
+
+type LiveRepository struct {
+	db *db.Database
+}
+
+func NewLiveRepository(database *db.Database) *LiveRepository {
+	return &LiveRepository{
+		db: database,
+	}
+}
+
+// Notify publishes the payload on a Postgres NOTIFY channel, every connection listening
+// on it receives the payload, including the ones of other replicas
+func (r *LiveRepository) Notify(ctx context.Context, channel, payload string) error {
+	return r.db.DB.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", channel, payload).Error
+}
+
+// GetTrackingUserIDs returns which of the given users track the shipment
+func (r *LiveRepository) GetTrackingUserIDs(ctx context.Context, shipmentID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var tracking []uuid.UUID
+	err := r.db.DB.WithContext(ctx).
+		Model(&shipmentModels.UserShipment{}).
+		Where("shipment_id = ? AND user_id IN ?", shipmentID, userIDs).
+		Pluck("user_id", &tracking).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tracking, nil
+}
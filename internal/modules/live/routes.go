@@ -0,0 +1,27 @@
+package live
+
+import (
+	"go-starter/internal/modules/auth/middlewares"
+	"go-starter/internal/modules/auth/services"
+	"go-starter/internal/modules/live/handlers"
+	liveServices "go-starter/internal/modules/live/services"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterRoutes exposes the broker's event stream. The broker is shared with the
+// background job that listens for changes, so it is created by the server.
+func RegisterRoutes(api *echo.Group, broker *liveServices.Broker) {
+	liveAPIHandler := handlers.NewLiveAPIHandler(broker)
+
+	// Publish shipment changes to the clients of every replica
+	shipmentServices.RegisterSyncListener(broker.HandleShipmentSync)
+	shipmentServices.RegisterEditListener(broker.HandleShipmentEdited)
+	shipmentServices.RegisterRemoveListener(broker.HandleShipmentsRemoved)
+
+	// Create JWT service for middleware
+	jwtService := services.NewJWTService()
+
+	api.GET("/shipments/events", liveAPIHandler.StreamShipmentEvents, middlewares.JWTMiddleware(jwtService)) // GET /api/shipments/events
+}
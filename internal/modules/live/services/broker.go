@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"go-starter/internal/modules/live/repositories"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// notifyChannel is the Postgres channel shipment changes are published on
+const notifyChannel = "shipment_changes"
+
+// maxShipmentsPerNotify keeps NOTIFY payloads well below Postgres' 8000 byte limit
+const maxShipmentsPerNotify = 100
+
+// subscriptionBuffer is how many events a slow client may fall behind before it is dropped
+const subscriptionBuffer = 64
+
+// listenerPingInterval is how often an idle LISTEN connection is checked, a dead
+// connection otherwise isn't noticed until the next notification
+const listenerPingInterval = 90 * time.Second
+
+// Change kinds
+const (
+	ChangeSynced  = "synced"
+	ChangeEdited  = "edited"
+	ChangeRemoved = "removed"
+)
+
+// Event types sent to clients
+const (
+	EventShipmentChanged = "shipment.changed"
+	EventShipmentRemoved = "shipment.removed"
+	// EventResync tells clients that changes may have been missed and they should reload
+	EventResync = "resync"
+)
+
+// Change is published with NOTIFY. It only carries IDs, each replica looks up which of
+// its connected users are affected.
+type Change struct {
+	Kind        string      `json:"kind"`
+	ShipmentIDs []uuid.UUID `json:"shipment_ids"`
+	// UserID is the user who edited or removed the shipments, nil for syncs
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+	// Fields are the edited fields, empty when unknown
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Event is what a subscribed client receives
+type Event struct {
+	Type        string      `json:"type"`
+	ShipmentIDs []uuid.UUID `json:"shipment_ids,omitempty"`
+	// Source is what caused a shipment.changed event, a sync or an edit
+	Source string   `json:"source,omitempty"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+// changeStore is the part of the repository the broker uses
+type changeStore interface {
+	Notify(ctx context.Context, channel, payload string) error
+	GetTrackingUserIDs(ctx context.Context, shipmentID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error)
+}
+
+// Subscription receives the events of one connected client
+type Subscription struct {
+	userID uuid.UUID
+	events chan Event
+}
+
+// Events is closed when the subscription ends, because the client fell too far
+// behind or the broker stopped
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Broker fans shipment changes out to the connected clients of the users tracking them.
+// Changes go through Postgres LISTEN/NOTIFY so clients connected to any replica get them.
+type Broker struct {
+	store changeStore
+	dsn   string
+
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[*Subscription]struct{}
+	stopped     bool
+}
+
+func NewBroker(liveRepo *repositories.LiveRepository, dsn string) *Broker {
+	return newBroker(liveRepo, dsn)
+}
+
+func newBroker(store changeStore, dsn string) *Broker {
+	return &Broker{
+		store:       store,
+		dsn:         dsn,
+		subscribers: make(map[uuid.UUID]map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe registers a client of the user. The subscription must be ended with Unsubscribe.
+func (b *Broker) Subscribe(userID uuid.UUID) *Subscription {
+	sub := &Subscription{
+		userID: userID,
+		events: make(chan Event, subscriptionBuffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stopped {
+		close(sub.events)
+		return sub
+	}
+
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[*Subscription]struct{})
+	}
+	b.subscribers[userID][sub] = struct{}{}
+
+	return sub
+}
+
+// Unsubscribe removes the subscription, it is safe to call more than once
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.removeLocked(sub)
+}
+
+func (b *Broker) removeLocked(sub *Subscription) {
+	subs, ok := b.subscribers[sub.userID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	close(sub.events)
+	if len(subs) == 0 {
+		delete(b.subscribers, sub.userID)
+	}
+}
+
+// Publish sends the change to every replica, large changes are split into several notifications
+func (b *Broker) Publish(ctx context.Context, change Change) error {
+	for start := 0; start < len(change.ShipmentIDs); start += maxShipmentsPerNotify {
+		part := change
+		part.ShipmentIDs = change.ShipmentIDs[start:min(start+maxShipmentsPerNotify, len(change.ShipmentIDs))]
+
+		payload, err := json.Marshal(part)
+		if err != nil {
+			return fmt.Errorf("failed to encode shipment change: %w", err)
+		}
+		if err := b.store.Notify(ctx, notifyChannel, string(payload)); err != nil {
+			return fmt.Errorf("failed to publish shipment change: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Run listens for changes until ctx is done, then ends all subscriptions
+func (b *Broker) Run(ctx context.Context) {
+	defer b.stop()
+
+	listener := pq.NewListener(b.dsn, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("Live updates listener: %v", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(notifyChannel); err != nil {
+		log.Printf("Failed to listen for shipment changes: %v", err)
+		return
+	}
+
+	log.Printf("Listening for shipment changes on %s", notifyChannel)
+
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification := <-listener.Notify:
+			// A nil notification means the connection was re-established, anything
+			// published while it was down is lost
+			if notification == nil {
+				b.broadcast(Event{Type: EventResync})
+				continue
+			}
+			b.dispatch(ctx, notification.Extra)
+		case <-ticker.C:
+			go func() {
+				if err := listener.Ping(); err != nil {
+					log.Printf("Live updates listener ping failed: %v", err)
+				}
+			}()
+		}
+	}
+}
+
+// dispatch delivers a published change to the local subscribers it concerns
+func (b *Broker) dispatch(ctx context.Context, payload string) {
+	var change Change
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		log.Printf("Warning: Ignoring malformed shipment change: %v", err)
+		return
+	}
+
+	switch change.Kind {
+	case ChangeRemoved:
+		// Only the user who removed the shipments stops seeing them
+		if change.UserID != nil {
+			b.send([]uuid.UUID{*change.UserID}, Event{Type: EventShipmentRemoved, ShipmentIDs: change.ShipmentIDs})
+		}
+	case ChangeSynced, ChangeEdited:
+		userIDs := b.connectedUserIDs()
+		// The editor's grid already shows the new values
+		if change.Kind == ChangeEdited && change.UserID != nil {
+			userIDs = without(userIDs, *change.UserID)
+		}
+		if len(userIDs) == 0 {
+			return
+		}
+
+		for _, shipmentID := range change.ShipmentIDs {
+			tracking, err := b.store.GetTrackingUserIDs(ctx, shipmentID, userIDs)
+			if err != nil {
+				log.Printf("Warning: Failed to get users tracking shipment %s: %v", shipmentID, err)
+				continue
+			}
+			b.send(tracking, Event{
+				Type:        EventShipmentChanged,
+				ShipmentIDs: []uuid.UUID{shipmentID},
+				Source:      change.Kind,
+				Fields:      change.Fields,
+			})
+		}
+	default:
+		log.Printf("Warning: Ignoring shipment change of unknown kind '%s'", change.Kind)
+	}
+}
+
+func (b *Broker) connectedUserIDs() []uuid.UUID {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	userIDs := make([]uuid.UUID, 0, len(b.subscribers))
+	for userID := range b.subscribers {
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs
+}
+
+// send queues the event for every subscription of the users. A subscription that is
+// too far behind is ended, its client reconnects and reloads.
+func (b *Broker) send(userIDs []uuid.UUID, event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, userID := range userIDs {
+		for sub := range b.subscribers[userID] {
+			b.sendLocked(sub, event)
+		}
+	}
+}
+
+func (b *Broker) broadcast(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, subs := range b.subscribers {
+		for sub := range subs {
+			b.sendLocked(sub, event)
+		}
+	}
+}
+
+func (b *Broker) sendLocked(sub *Subscription, event Event) {
+	select {
+	case sub.events <- event:
+	default:
+		log.Printf("Warning: Dropping live updates of user %s, the client is too slow", sub.userID)
+		b.removeLocked(sub)
+	}
+}
+
+// stop ends all subscriptions and rejects new ones
+func (b *Broker) stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stopped = true
+	for _, subs := range b.subscribers {
+		for sub := range subs {
+			b.removeLocked(sub)
+		}
+	}
+}
+
+func without(userIDs []uuid.UUID, userID uuid.UUID) []uuid.UUID {
+	filtered := make([]uuid.UUID, 0, len(userIDs))
+	for _, id := range userIDs {
+		if id != userID {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered
+}
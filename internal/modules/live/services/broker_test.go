@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type fakeStore struct {
+	payloads []string
+	// tracking maps shipment IDs to the users tracking them
+	tracking map[uuid.UUID][]uuid.UUID
+}
+
+func (f *fakeStore) Notify(ctx context.Context, channel, payload string) error {
+	f.payloads = append(f.payloads, payload)
+	return nil
+}
+
+func (f *fakeStore) GetTrackingUserIDs(ctx context.Context, shipmentID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var tracking []uuid.UUID
+	for _, trackingID := range f.tracking[shipmentID] {
+		for _, userID := range userIDs {
+			if trackingID == userID {
+				tracking = append(tracking, userID)
+			}
+		}
+	}
+	return tracking, nil
+}
+
+func publishAndDispatch(t *testing.T, broker *Broker, store *fakeStore, change Change) {
+	t.Helper()
+
+	store.payloads = nil
+	if err := broker.Publish(context.Background(), change); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, payload := range store.payloads {
+		broker.dispatch(context.Background(), payload)
+	}
+}
+
+func receive(sub *Subscription) []Event {
+	var events []Event
+	for {
+		select {
+		case event, ok := <-sub.events:
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestBroker_SyncReachesTrackingUsers(t *testing.T) {
+	shipmentID := uuid.New()
+	tracker, other := uuid.New(), uuid.New()
+	store := &fakeStore{tracking: map[uuid.UUID][]uuid.UUID{shipmentID: {tracker}}}
+	broker := newBroker(store, "")
+
+	trackerSub := broker.Subscribe(tracker)
+	otherSub := broker.Subscribe(other)
+
+	publishAndDispatch(t, broker, store, Change{Kind: ChangeSynced, ShipmentIDs: []uuid.UUID{shipmentID}})
+
+	events := receive(trackerSub)
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event for the tracking user, got %d", len(events))
+	}
+	if events[0].Type != EventShipmentChanged || events[0].Source != ChangeSynced || events[0].ShipmentIDs[0] != shipmentID {
+		t.Errorf("Unexpected event %+v", events[0])
+	}
+	if events := receive(otherSub); len(events) != 0 {
+		t.Errorf("Expected no events for a user not tracking the shipment, got %d", len(events))
+	}
+}
+
+func TestBroker_EditSkipsEditor(t *testing.T) {
+	shipmentID := uuid.New()
+	editor, colleague := uuid.New(), uuid.New()
+	store := &fakeStore{tracking: map[uuid.UUID][]uuid.UUID{shipmentID: {editor, colleague}}}
+	broker := newBroker(store, "")
+
+	editorSub := broker.Subscribe(editor)
+	colleagueSub := broker.Subscribe(colleague)
+
+	publishAndDispatch(t, broker, store, Change{
+		Kind:        ChangeEdited,
+		ShipmentIDs: []uuid.UUID{shipmentID},
+		UserID:      &editor,
+		Fields:      []string{"notes"},
+	})
+
+	if events := receive(editorSub); len(events) != 0 {
+		t.Errorf("Expected no events for the editor, got %d", len(events))
+	}
+	events := receive(colleagueSub)
+	if len(events) != 1 || events[0].Source != ChangeEdited || events[0].Fields[0] != "notes" {
+		t.Errorf("Expected the edit to reach the other user, got %+v", events)
+	}
+}
+
+func TestBroker_RemovalOnlyReachesRemovingUser(t *testing.T) {
+	shipmentID := uuid.New()
+	remover, colleague := uuid.New(), uuid.New()
+	store := &fakeStore{tracking: map[uuid.UUID][]uuid.UUID{shipmentID: {colleague}}}
+	broker := newBroker(store, "")
+
+	removerSub := broker.Subscribe(remover)
+	colleagueSub := broker.Subscribe(colleague)
+
+	publishAndDispatch(t, broker, store, Change{Kind: ChangeRemoved, ShipmentIDs: []uuid.UUID{shipmentID}, UserID: &remover})
+
+	events := receive(removerSub)
+	if len(events) != 1 || events[0].Type != EventShipmentRemoved {
+		t.Errorf("Expected a removal event, got %+v", events)
+	}
+	if events := receive(colleagueSub); len(events) != 0 {
+		t.Errorf("Expected no events for other users, got %d", len(events))
+	}
+}
+
+func TestBroker_PublishSplitsLargeChanges(t *testing.T) {
+	store := &fakeStore{}
+	broker := newBroker(store, "")
+
+	shipmentIDs := make([]uuid.UUID, maxShipmentsPerNotify+1)
+	for i := range shipmentIDs {
+		shipmentIDs[i] = uuid.New()
+	}
+	userID := uuid.New()
+
+	if err := broker.Publish(context.Background(), Change{Kind: ChangeRemoved, ShipmentIDs: shipmentIDs, UserID: &userID}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(store.payloads) != 2 {
+		t.Fatalf("Expected 2 notifications, got %d", len(store.payloads))
+	}
+	for _, payload := range store.payloads {
+		if len(payload) >= 8000 {
+			t.Errorf("Payload of %d bytes exceeds the NOTIFY limit", len(payload))
+		}
+		var change Change
+		if err := json.Unmarshal([]byte(payload), &change); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if change.UserID == nil || *change.UserID != userID {
+			t.Errorf("Expected every part to keep the user, got %v", change.UserID)
+		}
+	}
+}
+
+func TestBroker_DropsSlowSubscriber(t *testing.T) {
+	broker := newBroker(&fakeStore{}, "")
+	sub := broker.Subscribe(uuid.New())
+
+	for range subscriptionBuffer + 1 {
+		broker.broadcast(Event{Type: EventResync})
+	}
+
+	events := receive(sub)
+	if len(events) != subscriptionBuffer {
+		t.Errorf("Expected the buffered events before the subscription ended, got %d", len(events))
+	}
+	if _, ok := <-sub.events; ok {
+		t.Error("Expected the subscription to be closed")
+	}
+
+	// Unsubscribing an ended subscription must not panic
+	broker.Unsubscribe(sub)
+}
+
+func TestBroker_StopEndsSubscriptions(t *testing.T) {
+	broker := newBroker(&fakeStore{}, "")
+	sub := broker.Subscribe(uuid.New())
+
+	broker.stop()
+
+	if _, ok := <-sub.events; ok {
+		t.Error("Expected the subscription to be closed")
+	}
+	if _, ok := <-broker.Subscribe(uuid.New()).events; ok {
+		t.Error("Expected subscriptions after stopping to be closed")
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"go-starter/internal/modules/shipments/types"
+
+	"github.com/google/uuid"
+)
+
+// HandleShipmentSync publishes a shipment that was created or synced, including syncs
+// of the background refresh job
+func (b *Broker) HandleShipmentSync(ctx context.Context, event types.ShipmentSyncEvent) {
+	b.publishOrLog(ctx, Change{
+		Kind:        ChangeSynced,
+		ShipmentIDs: []uuid.UUID{event.ShipmentID},
+	})
+}
+
+// HandleShipmentEdited publishes a user's edit of the shared shipment info
+func (b *Broker) HandleShipmentEdited(ctx context.Context, event types.ShipmentEditedEvent) {
+	userID := event.UserID
+	b.publishOrLog(ctx, Change{
+		Kind:        ChangeEdited,
+		ShipmentIDs: []uuid.UUID{event.ShipmentID},
+		UserID:      &userID,
+		Fields:      event.Fields,
+	})
+}
+
+// HandleShipmentsRemoved publishes shipments a user stopped tracking
+func (b *Broker) HandleShipmentsRemoved(ctx context.Context, event types.ShipmentsRemovedEvent) {
+	userID := event.UserID
+	b.publishOrLog(ctx, Change{
+		Kind:        ChangeRemoved,
+		ShipmentIDs: event.ShipmentIDs,
+		UserID:      &userID,
+	})
+}
+
+// publishOrLog publishes the change, live updates are best effort and never fail the caller
+func (b *Broker) publishOrLog(ctx context.Context, change Change) {
+	if err := b.Publish(context.WithoutCancel(ctx), change); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
@@ -0,0 +1,12 @@
+package dto
+
+// SaveChannelRequest represents the request to create or update a chat channel.
+// An empty WebhookURL keeps the current URL when a channel is updated.
+type SaveChannelRequest struct {
+	Name         string   `json:"name" validate:"required,max=100"`
+	Type         string   `json:"type" validate:"required"`
+	WebhookURL   string   `json:"webhook_url" validate:"max=2048"`
+	EventTypes   []string `json:"event_types"`
+	DailySummary bool     `json:"daily_summary"`
+	Enabled      *bool    `json:"enabled"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "go-starter/internal/modules/notifications/models"
+
+// InboxResponse lists a user's in-app notifications
+type InboxResponse struct {
+	Notifications []models.Notification `json:"notifications"`
+	Unread        int64                 `json:"unread"`
+	Total         int                   `json:"total"`
+}
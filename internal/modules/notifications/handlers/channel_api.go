@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"go-starter/internal/modules/auth/services"
+	"go-starter/internal/modules/notifications/dto"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// GetChannels handles GET /api/notifications/channels
+func (h *NotificationAPIHandler) GetChannels(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	channels, err := h.notificationService.GetChannels(ctx, userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve channels: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"channels": channels,
+		"total":    len(channels),
+	})
+}
+
+// CreateChannel handles POST /api/notifications/channels
+func (h *NotificationAPIHandler) CreateChannel(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.SaveChannelRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Validation failed: " + err.Error(),
+		})
+	}
+
+	channel, err := h.notificationService.CreateChannel(ctx, userID, &req)
+	if err != nil {
+		return h.channelError(c, "Failed to create channel", err)
+	}
+
+	return c.JSON(http.StatusCreated, channel)
+}
+
+// UpdateChannel handles PUT /api/notifications/channels/:id
+func (h *NotificationAPIHandler) UpdateChannel(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	channelID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid channel ID",
+		})
+	}
+
+	var req dto.SaveChannelRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Validation failed: " + err.Error(),
+		})
+	}
+
+	channel, err := h.notificationService.UpdateChannel(ctx, userID, channelID, &req)
+	if err != nil {
+		return h.channelError(c, "Failed to update channel", err)
+	}
+
+	return c.JSON(http.StatusOK, channel)
+}
+
+// DeleteChannel handles DELETE /api/notifications/channels/:id
+func (h *NotificationAPIHandler) DeleteChannel(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	channelID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid channel ID",
+		})
+	}
+
+	if err := h.notificationService.DeleteChannel(ctx, userID, channelID); err != nil {
+		return h.channelError(c, "Failed to delete channel", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Channel deleted successfully",
+	})
+}
+
+// SendTestChannelMessage handles POST /api/notifications/channels/:id/test
+func (h *NotificationAPIHandler) SendTestChannelMessage(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	channelID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid channel ID",
+		})
+	}
+
+	delivery, err := h.notificationService.SendTestChannelMessage(ctx, userID, channelID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusBadGateway, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, delivery)
+}
+
+// channelError maps channel service errors to status codes, validation errors are returned as is
+func (h *NotificationAPIHandler) channelError(c echo.Context, message string, err error) error {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	case strings.HasPrefix(err.Error(), "invalid"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message + ": " + err.Error(),
+	})
+}
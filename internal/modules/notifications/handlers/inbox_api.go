@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"go-starter/internal/modules/auth/services"
+	notificationServices "go-starter/internal/modules/notifications/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultInboxLimit is how many notifications the inbox returns by default
+const defaultInboxLimit = 20
+
+type InboxAPIHandler struct {
+	inboxService *notificationServices.InboxService
+}
+
+func NewInboxAPIHandler(inboxService *notificationServices.InboxService) *InboxAPIHandler {
+	return &InboxAPIHandler{
+		inboxService: inboxService,
+	}
+}
+
+// GetNotifications handles GET /api/notifications/inbox
+func (h *InboxAPIHandler) GetNotifications(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	limit := defaultInboxLimit
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 200 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid limit",
+			})
+		}
+	}
+
+	response, err := h.inboxService.GetNotifications(ctx, userID, c.QueryParam("unread") == "true", limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve notifications: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetUnreadCount handles GET /api/notifications/inbox/unread-count
+func (h *InboxAPIHandler) GetUnreadCount(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	unread, err := h.inboxService.CountUnread(ctx, userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to count notifications: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]int64{
+		"unread": unread,
+	})
+}
+
+// MarkRead handles POST /api/notifications/inbox/:id/read
+func (h *InboxAPIHandler) MarkRead(c echo.Context) error {
+	return h.setRead(c, true)
+}
+
+// MarkUnread handles POST /api/notifications/inbox/:id/unread
+func (h *InboxAPIHandler) MarkUnread(c echo.Context) error {
+	return h.setRead(c, false)
+}
+
+func (h *InboxAPIHandler) setRead(c echo.Context, read bool) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid notification ID",
+		})
+	}
+
+	markFn := h.inboxService.MarkUnread
+	if read {
+		markFn = h.inboxService.MarkRead
+	}
+
+	notification, err := markFn(ctx, userID, notificationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update notification: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, notification)
+}
+
+// MarkAllRead handles POST /api/notifications/inbox/read-all
+func (h *InboxAPIHandler) MarkAllRead(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	marked, err := h.inboxService.MarkAllRead(ctx, userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update notifications: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]int64{
+		"marked": marked,
+	})
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"go-starter/internal/modules/auth/services"
+	notificationServices "go-starter/internal/modules/notifications/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultDeliveriesLimit is how many deliveries the delivery log returns by default
+const defaultDeliveriesLimit = 50
+
+type NotificationAPIHandler struct {
+	notificationService *notificationServices.NotificationService
+	validator           *validator.Validate
+}
+
+func NewNotificationAPIHandler(notificationService *notificationServices.NotificationService) *NotificationAPIHandler {
+	return &NotificationAPIHandler{
+		notificationService: notificationService,
+		validator:           validator.New(),
+	}
+}
+
+// UpdatePreferenceRequest represents the request to change notification preferences
+type UpdatePreferenceRequest struct {
+	EmailEnabled bool     `json:"email_enabled"`
+	EventTypes   []string `json:"event_types"`
+	Delivery     string   `json:"delivery"`
+}
+
+// GetPreference handles GET /api/notifications/preferences
+func (h *NotificationAPIHandler) GetPreference(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	preference, err := h.notificationService.GetPreference(ctx, userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve preferences: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, preference)
+}
+
+// UpdatePreference handles PUT /api/notifications/preferences
+func (h *NotificationAPIHandler) UpdatePreference(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req UpdatePreferenceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	preference, err := h.notificationService.UpdatePreference(ctx, userID, req.EmailEnabled, req.EventTypes, req.Delivery)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update preferences: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, preference)
+}
+
+// GetDeliveries handles GET /api/notifications/deliveries
+func (h *NotificationAPIHandler) GetDeliveries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	limit := defaultDeliveriesLimit
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 500 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid limit",
+			})
+		}
+	}
+
+	deliveries, err := h.notificationService.GetDeliveries(ctx, userID, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve deliveries: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"deliveries": deliveries,
+		"total":      len(deliveries),
+	})
+}
+
+// RetryDelivery handles POST /api/notifications/deliveries/:id/retry
+func (h *NotificationAPIHandler) RetryDelivery(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid delivery ID",
+		})
+	}
+
+	if err := h.notificationService.RetryDelivery(ctx, userID, deliveryID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retry delivery: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Delivery scheduled for retry",
+	})
+}
+
+// SendTestEmail handles POST /api/notifications/test-email
+func (h *NotificationAPIHandler) SendTestEmail(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	delivery, err := h.notificationService.SendTestEmail(ctx, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadGateway, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, delivery)
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"go-starter/internal/modules/auth/services"
+	notificationServices "go-starter/internal/modules/notifications/services"
+	"go-starter/internal/modules/notifications/views"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// inboxChangedTrigger makes htmx refresh the unread badge after the inbox changed
+const inboxChangedTrigger = "inboxChanged"
+
+type NotificationWEBHandler struct {
+	inboxService *notificationServices.InboxService
+}
+
+func NewNotificationWEBHandler(inboxService *notificationServices.InboxService) *NotificationWEBHandler {
+	return &NotificationWEBHandler{
+		inboxService: inboxService,
+	}
+}
+
+// ViewNotificationsPage handles GET /notifications
+func (h *NotificationWEBHandler) ViewNotificationsPage(c echo.Context) error {
+	component := views.NotificationsPage()
+	return component.Render(c.Request().Context(), c.Response().Writer)
+}
+
+// GetInboxBadgeHTML handles GET /api/notifications/inbox/badge-html
+func (h *NotificationWEBHandler) GetInboxBadgeHTML(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.NoContent(http.StatusUnauthorized)
+	}
+
+	unread, err := h.inboxService.CountUnread(ctx, userID)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to count notifications")
+	}
+
+	component := views.InboxBadge(unread)
+	return component.Render(ctx, c.Response().Writer)
+}
+
+// GetInboxListHTML handles GET /api/notifications/inbox/list-html
+func (h *NotificationWEBHandler) GetInboxListHTML(c echo.Context) error {
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.NoContent(http.StatusUnauthorized)
+	}
+
+	return h.renderInboxList(c, userID)
+}
+
+// MarkReadHTML handles POST /api/notifications/inbox/:id/read-html
+func (h *NotificationWEBHandler) MarkReadHTML(c echo.Context) error {
+	return h.setReadHTML(c, true)
+}
+
+// MarkUnreadHTML handles POST /api/notifications/inbox/:id/unread-html
+func (h *NotificationWEBHandler) MarkUnreadHTML(c echo.Context) error {
+	return h.setReadHTML(c, false)
+}
+
+func (h *NotificationWEBHandler) setReadHTML(c echo.Context, read bool) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.NoContent(http.StatusUnauthorized)
+	}
+
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid notification ID")
+	}
+
+	markFn := h.inboxService.MarkUnread
+	if read {
+		markFn = h.inboxService.MarkRead
+	}
+
+	notification, err := markFn(ctx, userID, notificationID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Notification not found")
+	}
+
+	c.Response().Header().Set("HX-Trigger", inboxChangedTrigger)
+	component := views.InboxItem(*notification)
+	return component.Render(ctx, c.Response().Writer)
+}
+
+// MarkAllReadHTML handles POST /api/notifications/inbox/read-all-html
+func (h *NotificationWEBHandler) MarkAllReadHTML(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.NoContent(http.StatusUnauthorized)
+	}
+
+	if _, err := h.inboxService.MarkAllRead(ctx, userID); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update notifications")
+	}
+
+	c.Response().Header().Set("HX-Trigger", inboxChangedTrigger)
+	return h.renderInboxList(c, userID)
+}
+
+// OpenNotification handles GET /notifications/inbox/:id/open
+func (h *NotificationWEBHandler) OpenNotification(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.Redirect(http.StatusTemporaryRedirect, "/login")
+	}
+
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.Redirect(http.StatusSeeOther, "/shipments")
+	}
+
+	link, err := h.inboxService.Open(ctx, userID, notificationID)
+	if err != nil {
+		return c.Redirect(http.StatusSeeOther, "/shipments")
+	}
+
+	return c.Redirect(http.StatusSeeOther, link)
+}
+
+func (h *NotificationWEBHandler) renderInboxList(c echo.Context, userID uuid.UUID) error {
+	ctx := c.Request().Context()
+
+	response, err := h.inboxService.GetNotifications(ctx, userID, false, defaultInboxLimit)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to retrieve notifications")
+	}
+
+	component := views.InboxList(response.Notifications, response.Unread)
+	return component.Render(ctx, c.Response().Writer)
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Kinds of in-app notifications
+const (
+	InboxKindAlert         = "alert"
+	InboxKindShipmentEvent = "shipment_event"
+)
+
+// Notification is an entry in a user's in-app inbox
+type Notification struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Kind       string     `json:"kind" gorm:"type:varchar(20);not null"`
+	ShipmentID *uuid.UUID `json:"shipment_id" gorm:"type:uuid;index"`
+	AlertID    *uuid.UUID `json:"alert_id" gorm:"type:uuid"`
+	Title      string     `json:"title" gorm:"type:varchar(255);not null"`
+	Message    string     `json:"message" gorm:"type:text"`
+	// ReadAt is nil while the notification is unread
+	ReadAt    *time.Time `json:"read_at" gorm:"type:timestamptz"`
+	CreatedAt time.Time  `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP;index"`
+}
+
+// TableName specifies the table name for Notification
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsRead reports whether the user has read the notification
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Delivery modes of a notification preference
+const (
+	DeliveryImmediate = "immediate"
+	DeliveryBatched   = "batched"
+)
+
+// Notification channels, Slack and Teams deliveries go to a NotificationChannel
+const (
+	ChannelEmail = "email"
+	ChannelSlack = "slack"
+	ChannelTeams = "teams"
+)
+
+// Delivery statuses. Batched notifications are queued until they are combined into one
+// pending delivery, pending deliveries are sent and retried until they are sent or failed.
+const (
+	DeliveryStatusQueued  = "queued"
+	DeliveryStatusBatched = "batched"
+	DeliveryStatusPending = "pending"
+	DeliveryStatusSent    = "sent"
+	DeliveryStatusFailed  = "failed"
+)
+
+// NotificationPreference holds which shipment events a user is notified about and how.
+// Users without a row get every event by email immediately.
+type NotificationPreference struct {
+	UserID       uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	EmailEnabled bool      `json:"email_enabled" gorm:"not null;default:true"`
+	// EventTypes are the alert types to notify about, empty means all
+	EventTypes pq.StringArray `json:"event_types" gorm:"type:text[]"`
+	Delivery   string         `json:"delivery" gorm:"type:varchar(20);not null;default:'immediate'"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time      `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for NotificationPreference
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// DefaultNotificationPreference returns the preference used for users that never changed theirs
+func DefaultNotificationPreference(userID uuid.UUID) NotificationPreference {
+	return NotificationPreference{
+		UserID:       userID,
+		EmailEnabled: true,
+		EventTypes:   pq.StringArray{},
+		Delivery:     DeliveryImmediate,
+	}
+}
+
+// Wants reports whether the user wants to be notified about the event type
+func (p *NotificationPreference) Wants(eventType string) bool {
+	if len(p.EventTypes) == 0 {
+		return true
+	}
+	for _, wanted := range p.EventTypes {
+		if wanted == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationDelivery is the delivery log. Each row is one message to one recipient,
+// with everything needed to retry it.
+type NotificationDelivery struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Channel   string     `json:"channel" gorm:"type:varchar(20);not null"`
+	ChannelID *uuid.UUID `json:"channel_id" gorm:"type:uuid;index"`
+	Recipient string     `json:"recipient" gorm:"type:varchar(255);not null"`
+	EventType string     `json:"event_type" gorm:"type:varchar(50);not null"`
+	AlertID   *uuid.UUID `json:"alert_id" gorm:"type:uuid;index"`
+	// BatchID points queued notifications to the delivery they were combined into
+	BatchID  *uuid.UUID `json:"batch_id" gorm:"type:uuid;index"`
+	Subject  string     `json:"subject" gorm:"type:varchar(255);not null"`
+	TextBody string     `json:"-" gorm:"type:text"`
+	HTMLBody string     `json:"-" gorm:"type:text"`
+	// Payload is the formatted JSON message of chat deliveries
+	Payload       string     `json:"-" gorm:"type:text"`
+	Status        string     `json:"status" gorm:"type:varchar(20);not null;index"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	LastError     string     `json:"last_error" gorm:"type:text"`
+	NextAttemptAt *time.Time `json:"next_attempt_at" gorm:"type:timestamptz;index"`
+	SentAt        *time.Time `json:"sent_at" gorm:"type:timestamptz"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for NotificationDelivery
+func (NotificationDelivery) TableName() string {
+	return "notification_deliveries"
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (d *NotificationDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+// NotificationChannel is a Slack or Teams incoming webhook that receives a user's alerts
+// and, if enabled, a daily summary
+type NotificationChannel struct {
+	ID     uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name   string    `json:"name" gorm:"type:varchar(100);not null"`
+	Type   string    `json:"type" gorm:"type:varchar(20);not null"`
+	// WebhookURL grants posting to the chat, it is never returned by the API
+	WebhookURL string `json:"-" gorm:"type:varchar(2048);not null"`
+	// EventTypes are the alert types to post, empty means all
+	EventTypes    pq.StringArray `json:"event_types" gorm:"type:text[]"`
+	DailySummary  bool           `json:"daily_summary" gorm:"not null;default:false"`
+	Enabled       bool           `json:"enabled" gorm:"not null;default:true"`
+	LastSummaryAt *time.Time     `json:"last_summary_at" gorm:"type:timestamptz"`
+	CreatedAt     time.Time      `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt     time.Time      `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for NotificationChannel
+func (NotificationChannel) TableName() string {
+	return "notification_channels"
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (c *NotificationChannel) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// Wants reports whether the channel receives alerts of the event type
+func (c *NotificationChannel) Wants(eventType string) bool {
+	if len(c.EventTypes) == 0 {
+		return true
+	}
+	for _, wanted := range c.EventTypes {
+		if wanted == eventType {
+			return true
+		}
+	}
+	return false
+}
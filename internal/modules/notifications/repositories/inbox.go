@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go-starter/internal/modules/notifications/models"
+	shipmentModels "go-starter/internal/modules/shipments/models"
+	"go-starter/pkg/db"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InboxRepository struct {
+	db *db.Database
+}
+
+func NewInboxRepository(database *db.Database) *InboxRepository {
+	return &InboxRepository{
+		db: database,
+	}
+}
+
+// CreateNotifications adds notifications to the inboxes of their users
+func (r *InboxRepository) CreateNotifications(ctx context.Context, notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	return r.db.DB.WithContext(ctx).Create(&notifications).Error
+}
+
+// GetNotifications retrieves the most recent notifications of a user
+func (r *InboxRepository) GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]models.Notification, error) {
+	query := r.db.DB.WithContext(ctx).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	err := query.
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&notifications).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// CountUnread counts the unread notifications of a user
+func (r *InboxRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.DB.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+
+	return count, err
+}
+
+// GetNotificationByID retrieves a notification by its ID and user ID
+func (r *InboxRepository) GetNotificationByID(ctx context.Context, notificationID, userID uuid.UUID) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", notificationID, userID).
+		First(&notification).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &notification, nil
+}
+
+// SetReadAt marks a notification read at the given time, or unread when readAt is nil
+func (r *InboxRepository) SetReadAt(ctx context.Context, notificationID, userID uuid.UUID, readAt *time.Time) error {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", notificationID, userID).
+		Update("read_at", readAt)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// MarkAllRead marks every unread notification of a user read
+func (r *InboxRepository) MarkAllRead(ctx context.Context, userID uuid.UUID, readAt time.Time) (int64, error) {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", readAt)
+
+	return result.RowsAffected, result.Error
+}
+
+// GetTrackingUserIDs retrieves the users tracking a shipment
+func (r *InboxRepository) GetTrackingUserIDs(ctx context.Context, shipmentID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.DB.WithContext(ctx).
+		Model(&shipmentModels.UserShipment{}).
+		Where("shipment_id = ?", shipmentID).
+		Pluck("user_id", &userIDs).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+// DeleteCreatedBefore deletes up to limit notifications created before the given time
+func (r *InboxRepository) DeleteCreatedBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	result := r.db.DB.WithContext(ctx).
+		Where("id IN (?)", r.db.DB.Model(&models.Notification{}).
+			Select("id").
+			Where("created_at < ?", before).
+			Limit(limit)).
+		Delete(&models.Notification{})
+
+	return result.RowsAffected, result.Error
+}
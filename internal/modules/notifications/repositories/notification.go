@@ -0,0 +1,381 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	alertModels "go-starter/internal/modules/alerts/models"
+	authModels "go-starter/internal/modules/auth/models"
+	"go-starter/internal/modules/notifications/models"
+	shipmentModels "go-starter/internal/modules/shipments/models"
+	"go-starter/pkg/db"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct {
+	db *db.Database
+}
+
+func NewNotificationRepository(database *db.Database) *NotificationRepository {
+	return &NotificationRepository{
+		db: database,
+	}
+}
+
+// GetPreference retrieves the notification preference of a user, or the default one
+func (r *NotificationRepository) GetPreference(ctx context.Context, userID uuid.UUID) (*models.NotificationPreference, error) {
+	var preference models.NotificationPreference
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(&preference).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		defaultPreference := models.DefaultNotificationPreference(userID)
+		return &defaultPreference, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &preference, nil
+}
+
+// SavePreference creates or replaces the notification preference of a user
+func (r *NotificationRepository) SavePreference(ctx context.Context, preference *models.NotificationPreference) error {
+	preference.UpdatedAt = time.Now()
+
+	return r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"email_enabled", "event_types", "delivery", "updated_at"}),
+		}).
+		Create(preference).Error
+}
+
+// GetUser retrieves the user a notification is sent to
+func (r *NotificationRepository) GetUser(ctx context.Context, userID uuid.UUID) (*authModels.User, error) {
+	var user authModels.User
+	if err := r.db.DB.WithContext(ctx).First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetShipmentNumber retrieves the number of a shipment for display in notifications
+func (r *NotificationRepository) GetShipmentNumber(ctx context.Context, shipmentID uuid.UUID) (string, error) {
+	var shipment shipmentModels.Shipment
+	err := r.db.DB.WithContext(ctx).
+		Select("shipment_number").
+		First(&shipment, "id = ?", shipmentID).Error
+
+	if err != nil {
+		return "", err
+	}
+
+	return shipment.ShipmentNumber, nil
+}
+
+// CreateDelivery adds a delivery to the log
+func (r *NotificationRepository) CreateDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	return r.db.DB.WithContext(ctx).Create(delivery).Error
+}
+
+// GetDeliveriesByUserID retrieves the most recent deliveries of a user
+func (r *NotificationRepository) GetDeliveriesByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]models.NotificationDelivery, error) {
+	var deliveries []models.NotificationDelivery
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// ClaimDueDeliveries picks pending deliveries that are due and leases them so other
+// instances running the delivery job skip them while they are being sent
+func (r *NotificationRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.NotificationDelivery, error) {
+	var deliveries []models.NotificationDelivery
+
+	err := r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.DeliveryStatusPending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+		return tx.Model(&models.NotificationDelivery{}).
+			Where("id IN ?", ids).
+			UpdateColumn("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// UpdateDeliveryFields updates the given columns of a delivery
+func (r *NotificationRepository) UpdateDeliveryFields(ctx context.Context, deliveryID uuid.UUID, updates map[string]interface{}) error {
+	updates["updated_at"] = gorm.Expr("NOW()")
+
+	return r.db.DB.WithContext(ctx).
+		Model(&models.NotificationDelivery{}).
+		Where("id = ?", deliveryID).
+		Updates(updates).Error
+}
+
+// RetryDelivery makes a failed delivery of a user pending again
+func (r *NotificationRepository) RetryDelivery(ctx context.Context, deliveryID, userID uuid.UUID, now time.Time) error {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.NotificationDelivery{}).
+		Where("id = ? AND user_id = ? AND status = ?", deliveryID, userID, models.DeliveryStatusFailed).
+		Updates(map[string]interface{}{
+			"status":          models.DeliveryStatusPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+			"updated_at":      gorm.Expr("NOW()"),
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// CombineQueuedDeliveries combines each user's queued notifications into one pending delivery
+// built by combine. Queued rows are locked so concurrent runs don't batch them twice.
+func (r *NotificationRepository) CombineQueuedDeliveries(
+	ctx context.Context,
+	combine func(userID uuid.UUID, items []models.NotificationDelivery) (*models.NotificationDelivery, error),
+) (int, error) {
+	batches := 0
+
+	err := r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var queued []models.NotificationDelivery
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", models.DeliveryStatusQueued).
+			Order("user_id, created_at ASC").
+			Find(&queued).Error
+		if err != nil {
+			return err
+		}
+
+		byUser := make(map[uuid.UUID][]models.NotificationDelivery)
+		var users []uuid.UUID
+		for _, item := range queued {
+			if _, ok := byUser[item.UserID]; !ok {
+				users = append(users, item.UserID)
+			}
+			byUser[item.UserID] = append(byUser[item.UserID], item)
+		}
+
+		for _, userID := range users {
+			items := byUser[userID]
+			batch, err := combine(userID, items)
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(batch).Error; err != nil {
+				return err
+			}
+
+			ids := make([]uuid.UUID, 0, len(items))
+			for _, item := range items {
+				ids = append(ids, item.ID)
+			}
+			err = tx.Model(&models.NotificationDelivery{}).
+				Where("id IN ?", ids).
+				Updates(map[string]interface{}{
+					"status":     models.DeliveryStatusBatched,
+					"batch_id":   batch.ID,
+					"updated_at": gorm.Expr("NOW()"),
+				}).Error
+			if err != nil {
+				return err
+			}
+			batches++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to combine queued deliveries: %w", err)
+	}
+
+	return batches, nil
+}
+
+// CreateChannel creates a new chat channel
+func (r *NotificationRepository) CreateChannel(ctx context.Context, channel *models.NotificationChannel) error {
+	return r.db.DB.WithContext(ctx).Create(channel).Error
+}
+
+// GetChannelByID retrieves a chat channel by its ID and user ID
+func (r *NotificationRepository) GetChannelByID(ctx context.Context, channelID, userID uuid.UUID) (*models.NotificationChannel, error) {
+	var channel models.NotificationChannel
+	err := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", channelID, userID).
+		First(&channel).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &channel, nil
+}
+
+// GetChannelsByUserID retrieves all chat channels of a user
+func (r *NotificationRepository) GetChannelsByUserID(ctx context.Context, userID uuid.UUID) ([]models.NotificationChannel, error) {
+	var channels []models.NotificationChannel
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&channels).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return channels, nil
+}
+
+// GetEnabledChannelsByUserID retrieves the enabled chat channels of a user
+func (r *NotificationRepository) GetEnabledChannelsByUserID(ctx context.Context, userID uuid.UUID) ([]models.NotificationChannel, error) {
+	var channels []models.NotificationChannel
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ? AND enabled = ?", userID, true).
+		Find(&channels).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return channels, nil
+}
+
+// UpdateChannel updates the settings of an existing chat channel
+func (r *NotificationRepository) UpdateChannel(ctx context.Context, channel *models.NotificationChannel) error {
+	return r.db.DB.WithContext(ctx).
+		Model(channel).
+		Where("id = ? AND user_id = ?", channel.ID, channel.UserID).
+		Updates(map[string]interface{}{
+			"name":          channel.Name,
+			"type":          channel.Type,
+			"webhook_url":   channel.WebhookURL,
+			"event_types":   channel.EventTypes,
+			"daily_summary": channel.DailySummary,
+			"enabled":       channel.Enabled,
+			"updated_at":    gorm.Expr("NOW()"),
+		}).Error
+}
+
+// DeleteChannel deletes a chat channel by its ID and user ID
+func (r *NotificationRepository) DeleteChannel(ctx context.Context, channelID, userID uuid.UUID) error {
+	result := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", channelID, userID).
+		Delete(&models.NotificationChannel{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// GetChannel retrieves a chat channel by its ID, regardless of the user
+func (r *NotificationRepository) GetChannel(ctx context.Context, channelID uuid.UUID) (*models.NotificationChannel, error) {
+	var channel models.NotificationChannel
+	if err := r.db.DB.WithContext(ctx).First(&channel, "id = ?", channelID).Error; err != nil {
+		return nil, err
+	}
+
+	return &channel, nil
+}
+
+// GetChannelsDueForSummary retrieves the enabled channels with a daily summary that
+// haven't had one since the given time
+func (r *NotificationRepository) GetChannelsDueForSummary(ctx context.Context, since time.Time) ([]models.NotificationChannel, error) {
+	var channels []models.NotificationChannel
+	err := r.db.DB.WithContext(ctx).
+		Where("enabled = ? AND daily_summary = ?", true, true).
+		Where("last_summary_at IS NULL OR last_summary_at < ?", since).
+		Find(&channels).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channels due for a summary: %w", err)
+	}
+
+	return channels, nil
+}
+
+// ClaimSummary records that a channel's summary is sent at now, unless another instance
+// already did so since the given time. It reports whether the claim succeeded.
+func (r *NotificationRepository) ClaimSummary(ctx context.Context, channelID uuid.UUID, since, now time.Time) (bool, error) {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.NotificationChannel{}).
+		Where("id = ?", channelID).
+		Where("last_summary_at IS NULL OR last_summary_at < ?", since).
+		UpdateColumn("last_summary_at", now)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// GetAlertsSince retrieves the alerts of a user triggered since the given time, newest first
+func (r *NotificationRepository) GetAlertsSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]alertModels.Alert, error) {
+	var alerts []alertModels.Alert
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ? AND last_triggered_at >= ?", userID, since).
+		Order("last_triggered_at DESC").
+		Find(&alerts).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+// CountOpenAlerts counts the open alerts of a user
+func (r *NotificationRepository) CountOpenAlerts(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.DB.WithContext(ctx).
+		Model(&alertModels.Alert{}).
+		Where("user_id = ? AND status = ?", userID, alertModels.AlertStatusOpen).
+		Count(&count).Error
+
+	return count, err
+}
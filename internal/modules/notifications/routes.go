@@ -0,0 +1,94 @@
+package notifications
+
+import (
+	alertServices "go-starter/internal/modules/alerts/services"
+	"go-starter/internal/modules/auth/middlewares"
+	"go-starter/internal/modules/auth/services"
+	"go-starter/internal/modules/notifications/handlers"
+	"go-starter/internal/modules/notifications/repositories"
+	notificationServices "go-starter/internal/modules/notifications/services"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+	"go-starter/pkg/chat"
+	"go-starter/pkg/config"
+	"go-starter/pkg/db"
+	"go-starter/pkg/mailer"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRoutes(e *echo.Echo, api *echo.Group, database *db.Database, cfg *config.Config) {
+	// Initialize dependencies
+	notificationRepo := repositories.NewNotificationRepository(database)
+	notificationService := notificationServices.NewNotificationService(
+		notificationRepo,
+		NewMailer(cfg),
+		NewChatSender(cfg),
+		notificationServices.NewDeliverySettingsFromConfig(cfg),
+	)
+	inboxService := notificationServices.NewInboxService(repositories.NewInboxRepository(database))
+	notificationAPIHandler := handlers.NewNotificationAPIHandler(notificationService)
+	inboxAPIHandler := handlers.NewInboxAPIHandler(inboxService)
+	notificationWEBHandler := handlers.NewNotificationWEBHandler(inboxService)
+
+	// Queue emails and chat messages for new alerts, they are sent by the notification delivery job
+	alertServices.RegisterAlertListener(notificationService.HandleAlert)
+
+	// Add alerts and shipment changes to the in-app inbox
+	alertServices.RegisterAlertListener(inboxService.HandleAlert)
+	shipmentServices.RegisterSyncListener(inboxService.HandleShipmentSync)
+
+	// Create JWT service for middleware
+	jwtService := services.NewJWTService()
+
+	// Create notifications group with JWT middleware
+	notificationsGroup := api.Group("/notifications", middlewares.JWTMiddleware(jwtService))
+
+	notificationsGroup.GET("/preferences", notificationAPIHandler.GetPreference)           // GET /api/notifications/preferences
+	notificationsGroup.PUT("/preferences", notificationAPIHandler.UpdatePreference)        // PUT /api/notifications/preferences
+	notificationsGroup.GET("/deliveries", notificationAPIHandler.GetDeliveries)            // GET /api/notifications/deliveries
+	notificationsGroup.POST("/deliveries/:id/retry", notificationAPIHandler.RetryDelivery) // POST /api/notifications/deliveries/:id/retry
+	notificationsGroup.POST("/test-email", notificationAPIHandler.SendTestEmail)           // POST /api/notifications/test-email
+
+	// Slack and Teams channel routes
+	notificationsGroup.GET("/channels", notificationAPIHandler.GetChannels)                      // GET /api/notifications/channels
+	notificationsGroup.POST("/channels", notificationAPIHandler.CreateChannel)                   // POST /api/notifications/channels
+	notificationsGroup.PUT("/channels/:id", notificationAPIHandler.UpdateChannel)                // PUT /api/notifications/channels/:id
+	notificationsGroup.DELETE("/channels/:id", notificationAPIHandler.DeleteChannel)             // DELETE /api/notifications/channels/:id
+	notificationsGroup.POST("/channels/:id/test", notificationAPIHandler.SendTestChannelMessage) // POST /api/notifications/channels/:id/test
+
+	// In-app inbox routes
+	notificationsGroup.GET("/inbox", inboxAPIHandler.GetNotifications)            // GET /api/notifications/inbox
+	notificationsGroup.GET("/inbox/unread-count", inboxAPIHandler.GetUnreadCount) // GET /api/notifications/inbox/unread-count
+	notificationsGroup.POST("/inbox/:id/read", inboxAPIHandler.MarkRead)          // POST /api/notifications/inbox/:id/read
+	notificationsGroup.POST("/inbox/:id/unread", inboxAPIHandler.MarkUnread)      // POST /api/notifications/inbox/:id/unread
+	notificationsGroup.POST("/inbox/read-all", inboxAPIHandler.MarkAllRead)       // POST /api/notifications/inbox/read-all
+
+	// htmx partials of the inbox dropdown in the header
+	notificationsGroup.GET("/inbox/badge-html", notificationWEBHandler.GetInboxBadgeHTML)    // GET /api/notifications/inbox/badge-html
+	notificationsGroup.GET("/inbox/list-html", notificationWEBHandler.GetInboxListHTML)      // GET /api/notifications/inbox/list-html
+	notificationsGroup.POST("/inbox/:id/read-html", notificationWEBHandler.MarkReadHTML)     // POST /api/notifications/inbox/:id/read-html
+	notificationsGroup.POST("/inbox/:id/unread-html", notificationWEBHandler.MarkUnreadHTML) // POST /api/notifications/inbox/:id/unread-html
+	notificationsGroup.POST("/inbox/read-all-html", notificationWEBHandler.MarkAllReadHTML)  // POST /api/notifications/inbox/read-all-html
+
+	e.GET("/notifications", notificationWEBHandler.ViewNotificationsPage, middlewares.WebJWTMiddleware(jwtService))
+	e.GET("/notifications/inbox/:id/open", notificationWEBHandler.OpenNotification, middlewares.WebJWTMiddleware(jwtService))
+}
+
+// NewMailer creates the SMTP mailer from the application config
+func NewMailer(cfg *config.Config) *mailer.SMTPMailer {
+	return mailer.NewSMTPMailer(mailer.Config{
+		Host:     cfg.Mail.Host,
+		Port:     cfg.Mail.Port,
+		Username: cfg.Mail.Username,
+		Password: cfg.Mail.Password,
+		From:     cfg.Mail.From,
+		FromName: cfg.Mail.FromName,
+		TLSMode:  cfg.Mail.TLSMode,
+		Timeout:  cfg.Mail.Timeout,
+	})
+}
+
+// NewChatSender creates the sender for Slack and Teams channels from the application config
+func NewChatSender(cfg *config.Config) *chat.HTTPSender {
+	return chat.NewHTTPSender(cfg.BackgroundJobs.NotificationChatTimeout)
+}
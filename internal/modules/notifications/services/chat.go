@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	alertModels "go-starter/internal/modules/alerts/models"
+	"go-starter/internal/modules/notifications/dto"
+	"go-starter/internal/modules/notifications/models"
+	"go-starter/pkg/chat"
+	"go-starter/pkg/outbound"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// summaryPeriod is how far back a daily summary looks for alerts
+const summaryPeriod = 24 * time.Hour
+
+// notifyAlertChat queues a chat message for every channel of the user that wants the alert
+func (s *NotificationService) notifyAlertChat(ctx context.Context, alert alertModels.Alert) error {
+	channels, err := s.notificationRepo.GetEnabledChannelsByUserID(ctx, alert.UserID)
+	if err != nil {
+		return fmt.Errorf("error retrieving channels: %w", err)
+	}
+
+	msg := s.alertChatMessage(alert)
+	alertID := alert.ID
+	for _, channel := range channels {
+		if !channel.Wants(string(alert.Type)) {
+			continue
+		}
+		delivery, err := s.newChatDelivery(channel, string(alert.Type), msg)
+		if err != nil {
+			return err
+		}
+		delivery.AlertID = &alertID
+		if err := s.notificationRepo.CreateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *NotificationService) alertChatMessage(alert alertModels.Alert) chat.Message {
+	return chat.Message{
+		Title:   alert.Title,
+		Summary: alert.Message,
+		Links: []chat.Link{
+			{Text: "View shipment", URL: s.shipmentURL(alert.ShipmentID)},
+			{Text: "Open shipments", URL: s.shipmentsURL()},
+		},
+	}
+}
+
+// summaryChatMessage lists the alerts of the last day, newest first
+func (s *NotificationService) summaryChatMessage(alerts []alertModels.Alert, openAlerts int64) chat.Message {
+	msg := chat.Message{
+		Title:   "Daily shipment summary",
+		Summary: fmt.Sprintf("%d alerts in the last 24 hours, %d open in total.", len(alerts), openAlerts),
+		Links:   []chat.Link{{Text: "Open shipments", URL: s.shipmentsURL()}},
+	}
+	for _, alert := range alerts {
+		msg.Items = append(msg.Items, chat.Item{
+			Title: alert.Title,
+			Text:  alert.Message,
+			URL:   s.shipmentURL(alert.ShipmentID),
+		})
+	}
+	return msg
+}
+
+// newChatDelivery formats the message for the channel's platform as a pending delivery
+func (s *NotificationService) newChatDelivery(channel models.NotificationChannel, eventType string, msg chat.Message) (*models.NotificationDelivery, error) {
+	payload, err := chat.Format(channel.Type, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	channelID := channel.ID
+	return &models.NotificationDelivery{
+		UserID:        channel.UserID,
+		Channel:       channel.Type,
+		ChannelID:     &channelID,
+		Recipient:     channel.Name,
+		EventType:     eventType,
+		Subject:       msg.Title,
+		TextBody:      msg.Summary,
+		Payload:       string(payload),
+		Status:        models.DeliveryStatusPending,
+		NextAttemptAt: &now,
+	}, nil
+}
+
+// SendDailySummaries queues the daily summary of every channel that has one enabled, once
+// the summary hour of the day has passed. Channels are claimed one by one so every instance
+// running the delivery job can call it. It returns how many summaries were queued.
+func (s *NotificationService) SendDailySummaries(ctx context.Context, now time.Time) (int, error) {
+	now = now.UTC()
+	dueAt := now.Truncate(24 * time.Hour).Add(time.Duration(s.settings.DailySummaryHour) * time.Hour)
+	if now.Before(dueAt) {
+		return 0, nil
+	}
+
+	channels, err := s.notificationRepo.GetChannelsDueForSummary(ctx, dueAt)
+	if err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	for _, channel := range channels {
+		claimed, err := s.notificationRepo.ClaimSummary(ctx, channel.ID, dueAt, now)
+		if err != nil {
+			return queued, fmt.Errorf("error claiming summary of channel %s: %w", channel.ID, err)
+		}
+		if !claimed {
+			continue
+		}
+
+		alerts, err := s.notificationRepo.GetAlertsSince(ctx, channel.UserID, now.Add(-summaryPeriod))
+		if err != nil {
+			log.Printf("Warning: Failed to get alerts for summary of channel %s: %v", channel.ID, err)
+			continue
+		}
+		openAlerts, err := s.notificationRepo.CountOpenAlerts(ctx, channel.UserID)
+		if err != nil {
+			log.Printf("Warning: Failed to count open alerts for summary of channel %s: %v", channel.ID, err)
+			continue
+		}
+		// Nothing to report, a quiet day doesn't need a message
+		if len(alerts) == 0 && openAlerts == 0 {
+			continue
+		}
+
+		delivery, err := s.newChatDelivery(channel, "daily_summary", s.summaryChatMessage(alerts, openAlerts))
+		if err != nil {
+			log.Printf("Warning: Failed to format summary of channel %s: %v", channel.ID, err)
+			continue
+		}
+		if err := s.notificationRepo.CreateDelivery(ctx, delivery); err != nil {
+			log.Printf("Warning: Failed to queue summary of channel %s: %v", channel.ID, err)
+			continue
+		}
+		queued++
+	}
+
+	return queued, nil
+}
+
+// SendTestChannelMessage posts a message to the channel right away to check its setup
+func (s *NotificationService) SendTestChannelMessage(ctx context.Context, userID, channelID uuid.UUID) (*models.NotificationDelivery, error) {
+	channel, err := s.getChannel(ctx, userID, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.newChatDelivery(*channel, "test", chat.Message{
+		Title:   "Test notification",
+		Summary: fmt.Sprintf("Shipment alerts will be posted to %s.", channel.Name),
+		Links:   []chat.Link{{Text: "Open shipments", URL: s.shipmentsURL()}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.notificationRepo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("error creating delivery: %w", err)
+	}
+
+	if !s.deliver(ctx, *delivery) {
+		return nil, fmt.Errorf("failed to send test message, it will be retried")
+	}
+
+	now := time.Now()
+	delivery.Status = models.DeliveryStatusSent
+	delivery.Attempts = 1
+	delivery.SentAt = &now
+	delivery.NextAttemptAt = nil
+	return delivery, nil
+}
+
+// GetChannels retrieves the chat channels of a user
+func (s *NotificationService) GetChannels(ctx context.Context, userID uuid.UUID) ([]models.NotificationChannel, error) {
+	channels, err := s.notificationRepo.GetChannelsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving channels: %w", err)
+	}
+
+	return channels, nil
+}
+
+// CreateChannel validates and creates a chat channel
+func (s *NotificationService) CreateChannel(ctx context.Context, userID uuid.UUID, req *dto.SaveChannelRequest) (*models.NotificationChannel, error) {
+	if strings.TrimSpace(req.WebhookURL) == "" {
+		return nil, fmt.Errorf("invalid channel: webhook_url is required")
+	}
+
+	channel := &models.NotificationChannel{UserID: userID}
+	if err := applyChannelRequest(ctx, channel, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.notificationRepo.CreateChannel(ctx, channel); err != nil {
+		return nil, fmt.Errorf("error creating channel: %w", err)
+	}
+
+	return channel, nil
+}
+
+// UpdateChannel validates and updates a chat channel
+func (s *NotificationService) UpdateChannel(ctx context.Context, userID, channelID uuid.UUID, req *dto.SaveChannelRequest) (*models.NotificationChannel, error) {
+	channel, err := s.getChannel(ctx, userID, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyChannelRequest(ctx, channel, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.notificationRepo.UpdateChannel(ctx, channel); err != nil {
+		return nil, fmt.Errorf("error updating channel: %w", err)
+	}
+
+	return channel, nil
+}
+
+// DeleteChannel deletes a chat channel
+func (s *NotificationService) DeleteChannel(ctx context.Context, userID, channelID uuid.UUID) error {
+	if err := s.notificationRepo.DeleteChannel(ctx, channelID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("channel not found")
+		}
+		return fmt.Errorf("error deleting channel: %w", err)
+	}
+
+	return nil
+}
+
+func (s *NotificationService) getChannel(ctx context.Context, userID, channelID uuid.UUID) (*models.NotificationChannel, error) {
+	channel, err := s.notificationRepo.GetChannelByID(ctx, channelID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("channel not found")
+		}
+		return nil, fmt.Errorf("error retrieving channel: %w", err)
+	}
+
+	return channel, nil
+}
+
+// applyChannelRequest validates the request and copies it onto the channel. The webhook URL
+// has to point at a public address.
+func applyChannelRequest(ctx context.Context, channel *models.NotificationChannel, req *dto.SaveChannelRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("invalid channel: name is required")
+	}
+	if req.Type != models.ChannelSlack && req.Type != models.ChannelTeams {
+		return fmt.Errorf("invalid channel type '%s'", req.Type)
+	}
+
+	if webhookURL := strings.TrimSpace(req.WebhookURL); webhookURL != "" {
+		parsed, err := url.Parse(webhookURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid webhook_url: must be an absolute http or https URL")
+		}
+		if err := outbound.ValidateHost(ctx, parsed.Hostname()); err != nil {
+			return fmt.Errorf("invalid webhook_url: %w", err)
+		}
+		channel.WebhookURL = webhookURL
+	}
+
+	eventTypes := pq.StringArray{}
+	for _, eventType := range req.EventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if !alertModels.RuleType(eventType).IsNotificationType() {
+			return fmt.Errorf("invalid event type '%s'", eventType)
+		}
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	channel.Name = name
+	channel.Type = req.Type
+	channel.EventTypes = eventTypes
+	channel.DailySummary = req.DailySummary
+	channel.Enabled = true
+	if req.Enabled != nil {
+		channel.Enabled = *req.Enabled
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	alertModels "go-starter/internal/modules/alerts/models"
+	"go-starter/internal/modules/notifications/dto"
+	"go-starter/internal/modules/notifications/models"
+
+	"github.com/google/uuid"
+)
+
+func TestApplyChannelRequest(t *testing.T) {
+	disabled := false
+
+	// Receivers are given by public addresses, names would have to be resolved
+
+	tests := []struct {
+		name    string
+		req     dto.SaveChannelRequest
+		wantErr string
+	}{
+		{
+			name: "valid slack channel",
+			req:  dto.SaveChannelRequest{Name: "#logistics", Type: models.ChannelSlack, WebhookURL: "https://8.8.8.8/services/T0/B0/x"},
+		},
+		{
+			name: "valid teams channel with alert types",
+			req:  dto.SaveChannelRequest{Name: "Ops", Type: models.ChannelTeams, WebhookURL: "https://[2606:4700::1111]/x", EventTypes: []string{"eta_slip"}, Enabled: &disabled},
+		},
+		{
+			name: "demurrage alerts only",
+			req:  dto.SaveChannelRequest{Name: "D&D", Type: models.ChannelSlack, WebhookURL: "https://8.8.8.8/services/T0/B0/y", EventTypes: []string{"free_time"}},
+		},
+		{
+			name: "geofence alerts only",
+			req:  dto.SaveChannelRequest{Name: "Port ops", Type: models.ChannelTeams, WebhookURL: "https://[2606:4700::1111]/y", EventTypes: []string{"geofence"}},
+		},
+		{
+			name:    "missing name",
+			req:     dto.SaveChannelRequest{Name: " ", Type: models.ChannelSlack},
+			wantErr: "name is required",
+		},
+		{
+			name:    "unknown type",
+			req:     dto.SaveChannelRequest{Name: "Ops", Type: "email"},
+			wantErr: "invalid channel type",
+		},
+		{
+			name:    "relative webhook URL",
+			req:     dto.SaveChannelRequest{Name: "Ops", Type: models.ChannelSlack, WebhookURL: "/hooks"},
+			wantErr: "invalid webhook_url",
+		},
+		{
+			name:    "cloud metadata address",
+			req:     dto.SaveChannelRequest{Name: "Ops", Type: models.ChannelSlack, WebhookURL: "http://169.254.169.254/latest/meta-data"},
+			wantErr: "invalid webhook_url",
+		},
+		{
+			name:    "internal service",
+			req:     dto.SaveChannelRequest{Name: "Ops", Type: models.ChannelTeams, WebhookURL: "http://localhost:8080/admin"},
+			wantErr: "invalid webhook_url",
+		},
+		{
+			name:    "unknown alert type",
+			req:     dto.SaveChannelRequest{Name: "Ops", Type: models.ChannelSlack, EventTypes: []string{"shipment.synced"}},
+			wantErr: "invalid event type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel := &models.NotificationChannel{WebhookURL: "https://hooks.slack.com/services/old"}
+			err := applyChannelRequest(context.Background(), channel, &tt.req)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if channel.WebhookURL != tt.req.WebhookURL {
+				t.Errorf("Expected webhook URL %q, got %q", tt.req.WebhookURL, channel.WebhookURL)
+			}
+			if want := tt.req.Enabled == nil || *tt.req.Enabled; channel.Enabled != want {
+				t.Errorf("Expected enabled %v, got %v", want, channel.Enabled)
+			}
+		})
+	}
+}
+
+func TestApplyChannelRequest_KeepsWebhookURL(t *testing.T) {
+	channel := &models.NotificationChannel{WebhookURL: "https://hooks.slack.com/services/old"}
+	req := dto.SaveChannelRequest{Name: "Ops", Type: models.ChannelSlack}
+
+	if err := applyChannelRequest(context.Background(), channel, &req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if channel.WebhookURL != "https://hooks.slack.com/services/old" {
+		t.Errorf("Expected the webhook URL to be kept, got %q", channel.WebhookURL)
+	}
+}
+
+func TestAlertChatMessage_LinksToShipment(t *testing.T) {
+	service := &NotificationService{settings: DeliverySettings{BaseURL: "https://app.example.com"}}
+	shipmentID := uuid.New()
+
+	msg := service.alertChatMessage(alertModels.Alert{
+		ShipmentID: shipmentID,
+		Title:      "ETA slipped for ABC123",
+		Message:    "The ETA moved 3 days later",
+	})
+
+	if len(msg.Links) == 0 {
+		t.Fatal("Expected links in the message")
+	}
+	want := "https://app.example.com/shipments?shipment=" + shipmentID.String()
+	if msg.Links[0].URL != want {
+		t.Errorf("Expected link %q, got %q", want, msg.Links[0].URL)
+	}
+}
+
+func TestSummaryChatMessage(t *testing.T) {
+	service := &NotificationService{settings: DeliverySettings{BaseURL: "https://app.example.com"}}
+	alerts := []alertModels.Alert{
+		{ShipmentID: uuid.New(), Title: "Status changed for ABC123"},
+		{ShipmentID: uuid.New(), Title: "Vessel arrived for DEF456"},
+	}
+
+	msg := service.summaryChatMessage(alerts, 5)
+
+	if !strings.Contains(msg.Summary, "2 alerts") || !strings.Contains(msg.Summary, "5 open") {
+		t.Errorf("Unexpected summary %q", msg.Summary)
+	}
+	if len(msg.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(msg.Items))
+	}
+	if !strings.HasSuffix(msg.Items[1].URL, alerts[1].ShipmentID.String()) {
+		t.Errorf("Expected the item to link to its shipment, got %q", msg.Items[1].URL)
+	}
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	alertModels "go-starter/internal/modules/alerts/models"
+	"go-starter/internal/modules/notifications/dto"
+	"go-starter/internal/modules/notifications/models"
+	"go-starter/internal/modules/notifications/repositories"
+	"go-starter/internal/modules/shipments/types"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InboxEntry is an in-app notification before it is added to the inboxes of its users
+type InboxEntry struct {
+	Title   string
+	Message string
+}
+
+type InboxService struct {
+	inboxRepo *repositories.InboxRepository
+}
+
+func NewInboxService(inboxRepo *repositories.InboxRepository) *InboxService {
+	return &InboxService{
+		inboxRepo: inboxRepo,
+	}
+}
+
+// HandleAlert adds a new alert to the inbox of its user
+func (s *InboxService) HandleAlert(ctx context.Context, alert alertModels.Alert) {
+	shipmentID := alert.ShipmentID
+	alertID := alert.ID
+
+	err := s.inboxRepo.CreateNotifications(ctx, []models.Notification{{
+		UserID:     alert.UserID,
+		Kind:       models.InboxKindAlert,
+		ShipmentID: &shipmentID,
+		AlertID:    &alertID,
+		Title:      alert.Title,
+		Message:    alert.Message,
+	}})
+	if err != nil {
+		log.Printf("Warning: Failed to add alert %s to the inbox: %v", alert.ID, err)
+	}
+}
+
+// HandleShipmentSync adds the changes of a sync to the inboxes of all users tracking the shipment
+func (s *InboxService) HandleShipmentSync(ctx context.Context, event types.ShipmentSyncEvent) {
+	entries := InboxEntriesFromSync(event)
+	if len(entries) == 0 {
+		return
+	}
+
+	userIDs, err := s.inboxRepo.GetTrackingUserIDs(ctx, event.ShipmentID)
+	if err != nil {
+		log.Printf("Warning: Failed to get users tracking shipment %s: %v", event.ShipmentID, err)
+		return
+	}
+
+	shipmentID := event.ShipmentID
+	notifications := make([]models.Notification, 0, len(userIDs)*len(entries))
+	for _, userID := range userIDs {
+		for _, entry := range entries {
+			notifications = append(notifications, models.Notification{
+				UserID:     userID,
+				Kind:       models.InboxKindShipmentEvent,
+				ShipmentID: &shipmentID,
+				Title:      entry.Title,
+				Message:    entry.Message,
+			})
+		}
+	}
+
+	if err := s.inboxRepo.CreateNotifications(ctx, notifications); err != nil {
+		log.Printf("Warning: Failed to add sync of shipment %s to the inbox: %v", event.ShipmentID, err)
+	}
+}
+
+// InboxEntriesFromSync describes what changed in a sync. Syncs of new shipments and
+// syncs without changes produce no entries, new container events are combined into one.
+func InboxEntriesFromSync(event types.ShipmentSyncEvent) []InboxEntry {
+	before, after := event.Before, event.After
+	if before == nil || after == nil {
+		return nil
+	}
+
+	var entries []InboxEntry
+
+	if before.ShippingStatus != after.ShippingStatus {
+		entries = append(entries, InboxEntry{
+			Title:   fmt.Sprintf("%s is now %s", after.ShipmentNumber, after.ShippingStatus),
+			Message: fmt.Sprintf("The status changed from %s to %s.", before.ShippingStatus, after.ShippingStatus),
+		})
+	}
+
+	if !sameTime(before.PodETA, after.PodETA) && after.PodETA != nil {
+		message := fmt.Sprintf("The ETA at the port of discharge is now %s.", after.PodETA.Format("2 Jan 2006"))
+		if before.PodETA != nil {
+			message = fmt.Sprintf("The ETA at the port of discharge moved from %s to %s.",
+				before.PodETA.Format("2 Jan 2006"), after.PodETA.Format("2 Jan 2006"))
+		}
+		entries = append(entries, InboxEntry{
+			Title:   fmt.Sprintf("ETA changed for %s", after.ShipmentNumber),
+			Message: message,
+		})
+	}
+
+	var added []types.ContainerEventSnapshot
+	for _, containerEvent := range after.ContainerEvents {
+		if containerEvent.IsActual && !before.HasEvent(containerEvent.ContainerNumber, containerEvent.EventCode, containerEvent.Date) {
+			added = append(added, containerEvent)
+		}
+	}
+	if len(added) > 0 {
+		latest := added[0]
+		for _, containerEvent := range added[1:] {
+			if containerEvent.Date.After(latest.Date) {
+				latest = containerEvent
+			}
+		}
+
+		title := fmt.Sprintf("New container event for %s", after.ShipmentNumber)
+		if len(added) > 1 {
+			title = fmt.Sprintf("%d new container events for %s", len(added), after.ShipmentNumber)
+		}
+		entries = append(entries, InboxEntry{
+			Title:   title,
+			Message: fmt.Sprintf("%s: %s at %s on %s.", latest.ContainerNumber, latest.Description, latest.LocationName, latest.Date.Format("2 Jan 2006")),
+		})
+	}
+
+	return entries
+}
+
+// GetNotifications retrieves the most recent notifications of a user with the unread count
+func (s *InboxService) GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) (*dto.InboxResponse, error) {
+	notifications, err := s.inboxRepo.GetNotifications(ctx, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving notifications: %w", err)
+	}
+
+	unread, err := s.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.InboxResponse{
+		Notifications: notifications,
+		Unread:        unread,
+		Total:         len(notifications),
+	}, nil
+}
+
+// CountUnread counts the unread notifications of a user
+func (s *InboxService) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	unread, err := s.inboxRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("error counting unread notifications: %w", err)
+	}
+
+	return unread, nil
+}
+
+// MarkRead marks a notification read
+func (s *InboxService) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) (*models.Notification, error) {
+	now := time.Now()
+	return s.setReadAt(ctx, userID, notificationID, &now)
+}
+
+// MarkUnread marks a notification unread
+func (s *InboxService) MarkUnread(ctx context.Context, userID, notificationID uuid.UUID) (*models.Notification, error) {
+	return s.setReadAt(ctx, userID, notificationID, nil)
+}
+
+// MarkAllRead marks every notification of a user read and returns how many were unread
+func (s *InboxService) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	marked, err := s.inboxRepo.MarkAllRead(ctx, userID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("error marking notifications read: %w", err)
+	}
+
+	return marked, nil
+}
+
+// Open marks a notification read and returns where it links to
+func (s *InboxService) Open(ctx context.Context, userID, notificationID uuid.UUID) (string, error) {
+	notification, err := s.MarkRead(ctx, userID, notificationID)
+	if err != nil {
+		return "", err
+	}
+
+	return NotificationLink(*notification), nil
+}
+
+// NotificationLink is the page a notification links to, the shipment's details when it has one
+func NotificationLink(notification models.Notification) string {
+	if notification.ShipmentID == nil {
+		return "/shipments"
+	}
+	return "/shipments?shipment=" + notification.ShipmentID.String()
+}
+
+// DeleteExpired deletes up to limit notifications created before the given time
+func (s *InboxService) DeleteExpired(ctx context.Context, before time.Time, limit int) (int, error) {
+	deleted, err := s.inboxRepo.DeleteCreatedBefore(ctx, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting expired notifications: %w", err)
+	}
+
+	return int(deleted), nil
+}
+
+func (s *InboxService) setReadAt(ctx context.Context, userID, notificationID uuid.UUID, readAt *time.Time) (*models.Notification, error) {
+	if err := s.inboxRepo.SetReadAt(ctx, notificationID, userID, readAt); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("notification not found")
+		}
+		return nil, fmt.Errorf("error updating notification: %w", err)
+	}
+
+	notification, err := s.inboxRepo.GetNotificationByID(ctx, notificationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving notification: %w", err)
+	}
+
+	return notification, nil
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
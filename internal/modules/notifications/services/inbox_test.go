@@ -0,0 +1,141 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-starter/internal/modules/notifications/models"
+	"go-starter/internal/modules/shipments/types"
+
+	"github.com/google/uuid"
+)
+
+func TestInboxEntriesFromSync(t *testing.T) {
+	etaBefore := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	etaAfter := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	loaded := types.ContainerEventSnapshot{
+		ContainerNumber: "MSKU1234565",
+		EventCode:       "LOAD",
+		Description:     "Loaded on vessel",
+		LocationName:    "Shanghai",
+		Date:            time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC),
+		IsActual:        true,
+	}
+	departed := types.ContainerEventSnapshot{
+		ContainerNumber: "MSKU1234565",
+		EventCode:       "DEPA",
+		Description:     "Vessel departure",
+		LocationName:    "Shanghai",
+		Date:            time.Date(2026, 4, 2, 8, 0, 0, 0, time.UTC),
+		IsActual:        true,
+	}
+	planned := types.ContainerEventSnapshot{
+		ContainerNumber: "MSKU1234565",
+		EventCode:       "DISC",
+		Date:            etaAfter,
+	}
+
+	before := &types.ShipmentSnapshot{
+		ShipmentNumber:  "ABC123",
+		ShippingStatus:  "PLANNED",
+		PodETA:          &etaBefore,
+		ContainerEvents: []types.ContainerEventSnapshot{loaded},
+	}
+
+	tests := []struct {
+		name   string
+		before *types.ShipmentSnapshot
+		after  *types.ShipmentSnapshot
+		want   []string
+	}{
+		{
+			name:   "new shipment",
+			before: nil,
+			after:  before,
+			want:   nil,
+		},
+		{
+			name:   "no changes",
+			before: before,
+			after:  before,
+			want:   nil,
+		},
+		{
+			name:   "status and ETA changed",
+			before: before,
+			after: &types.ShipmentSnapshot{
+				ShipmentNumber:  "ABC123",
+				ShippingStatus:  "IN_TRANSIT",
+				PodETA:          &etaAfter,
+				ContainerEvents: []types.ContainerEventSnapshot{loaded},
+			},
+			want: []string{"ABC123 is now IN_TRANSIT", "ETA changed for ABC123"},
+		},
+		{
+			name:   "one new container event",
+			before: before,
+			after: &types.ShipmentSnapshot{
+				ShipmentNumber:  "ABC123",
+				ShippingStatus:  "PLANNED",
+				PodETA:          &etaBefore,
+				ContainerEvents: []types.ContainerEventSnapshot{loaded, departed, planned},
+			},
+			want: []string{"New container event for ABC123"},
+		},
+		{
+			name:   "several new container events",
+			before: &types.ShipmentSnapshot{ShipmentNumber: "ABC123", ShippingStatus: "PLANNED", PodETA: &etaBefore},
+			after: &types.ShipmentSnapshot{
+				ShipmentNumber:  "ABC123",
+				ShippingStatus:  "PLANNED",
+				PodETA:          &etaBefore,
+				ContainerEvents: []types.ContainerEventSnapshot{departed, loaded},
+			},
+			want: []string{"2 new container events for ABC123"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := InboxEntriesFromSync(types.ShipmentSyncEvent{Before: tt.before, After: tt.after})
+
+			if len(entries) != len(tt.want) {
+				t.Fatalf("Expected %d entries, got %+v", len(tt.want), entries)
+			}
+			for i, entry := range entries {
+				if entry.Title != tt.want[i] {
+					t.Errorf("Expected title %q, got %q", tt.want[i], entry.Title)
+				}
+			}
+		})
+	}
+}
+
+func TestInboxEntriesFromSync_DescribesLatestContainerEvent(t *testing.T) {
+	before := &types.ShipmentSnapshot{ShipmentNumber: "ABC123"}
+	after := &types.ShipmentSnapshot{
+		ShipmentNumber: "ABC123",
+		ContainerEvents: []types.ContainerEventSnapshot{
+			{ContainerNumber: "MSKU1234565", EventCode: "DEPA", Description: "Vessel departure", LocationName: "Shanghai", Date: time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC), IsActual: true},
+			{ContainerNumber: "MSKU1234565", EventCode: "LOAD", Description: "Loaded on vessel", LocationName: "Shanghai", Date: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), IsActual: true},
+		},
+	}
+
+	entries := InboxEntriesFromSync(types.ShipmentSyncEvent{Before: before, After: after})
+
+	if len(entries) != 1 || !strings.Contains(entries[0].Message, "Vessel departure") {
+		t.Errorf("Expected the latest event to be described, got %+v", entries)
+	}
+}
+
+func TestNotificationLink(t *testing.T) {
+	shipmentID := uuid.New()
+
+	if got := NotificationLink(models.Notification{ShipmentID: &shipmentID}); got != "/shipments?shipment="+shipmentID.String() {
+		t.Errorf("Unexpected link %q", got)
+	}
+	if got := NotificationLink(models.Notification{}); got != "/shipments" {
+		t.Errorf("Expected notifications without a shipment to link to the shipments page, got %q", got)
+	}
+}
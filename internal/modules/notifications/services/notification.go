@@ -0,0 +1,356 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	alertModels "go-starter/internal/modules/alerts/models"
+	"go-starter/internal/modules/notifications/models"
+	"go-starter/internal/modules/notifications/repositories"
+	"go-starter/internal/modules/notifications/views"
+	"go-starter/pkg/chat"
+	"go-starter/pkg/config"
+	"go-starter/pkg/deliveryqueue"
+	"go-starter/pkg/mailer"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// DeliverySettings controls how often failed deliveries are retried
+type DeliverySettings struct {
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	// BaseURL is the public URL of the app used for links in emails and chat messages
+	BaseURL string
+	// DailySummaryHour is the hour of the day (UTC) daily chat summaries are sent at
+	DailySummaryHour int
+}
+
+// NewDeliverySettingsFromConfig creates delivery settings from the application config
+func NewDeliverySettingsFromConfig(cfg *config.Config) DeliverySettings {
+	return DeliverySettings{
+		MaxAttempts:      cfg.BackgroundJobs.NotificationMaxAttempts,
+		RetryBackoff:     cfg.BackgroundJobs.NotificationRetryBackoff,
+		BaseURL:          cfg.AppBaseURL,
+		DailySummaryHour: cfg.BackgroundJobs.NotificationDailySummaryHour,
+	}
+}
+
+type NotificationService struct {
+	notificationRepo *repositories.NotificationRepository
+	mailer           mailer.Mailer
+	chatSender       chat.Sender
+	settings         DeliverySettings
+}
+
+func NewNotificationService(
+	notificationRepo *repositories.NotificationRepository,
+	mailer mailer.Mailer,
+	chatSender chat.Sender,
+	settings DeliverySettings,
+) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		mailer:           mailer,
+		chatSender:       chatSender,
+		settings:         settings,
+	}
+}
+
+// HandleAlert queues an email and chat messages for a new alert according to the user's
+// preference and channels. It is registered as an alert listener.
+func (s *NotificationService) HandleAlert(ctx context.Context, alert alertModels.Alert) {
+	if err := s.notifyAlert(ctx, alert); err != nil {
+		log.Printf("Warning: Failed to queue notification for alert %s: %v", alert.ID, err)
+	}
+	if err := s.notifyAlertChat(ctx, alert); err != nil {
+		log.Printf("Warning: Failed to queue chat messages for alert %s: %v", alert.ID, err)
+	}
+}
+
+func (s *NotificationService) notifyAlert(ctx context.Context, alert alertModels.Alert) error {
+	preference, err := s.notificationRepo.GetPreference(ctx, alert.UserID)
+	if err != nil {
+		return fmt.Errorf("error retrieving preference: %w", err)
+	}
+	if !preference.EmailEnabled || !preference.Wants(string(alert.Type)) {
+		return nil
+	}
+
+	user, err := s.notificationRepo.GetUser(ctx, alert.UserID)
+	if err != nil {
+		return fmt.Errorf("error retrieving user: %w", err)
+	}
+
+	alertID := alert.ID
+	delivery := &models.NotificationDelivery{
+		UserID:    alert.UserID,
+		Channel:   models.ChannelEmail,
+		Recipient: user.Email,
+		EventType: string(alert.Type),
+		AlertID:   &alertID,
+	}
+
+	// Batched notifications only keep the alert text, the email is rendered when the batch is sent
+	if preference.Delivery == models.DeliveryBatched {
+		delivery.Status = models.DeliveryStatusQueued
+		delivery.Subject = alert.Title
+		delivery.TextBody = alert.Message
+		return s.notificationRepo.CreateDelivery(ctx, delivery)
+	}
+
+	email, err := views.RenderAlertEmail(ctx, views.AlertEmailData{
+		RecipientName: user.FirstName,
+		Item:          views.EmailItem{Title: alert.Title, Message: alert.Message, At: alert.LastTriggeredAt},
+		ShipmentsURL:  s.shipmentsURL(),
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	delivery.Status = models.DeliveryStatusPending
+	delivery.Subject = email.Subject
+	delivery.TextBody = email.TextBody
+	delivery.HTMLBody = email.HTMLBody
+	delivery.NextAttemptAt = &now
+	return s.notificationRepo.CreateDelivery(ctx, delivery)
+}
+
+// CombineBatches combines the queued notifications of every user with batched delivery
+// into one email per user
+func (s *NotificationService) CombineBatches(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	return s.notificationRepo.CombineQueuedDeliveries(ctx, func(userID uuid.UUID, items []models.NotificationDelivery) (*models.NotificationDelivery, error) {
+		recipientName := ""
+		if user, err := s.notificationRepo.GetUser(ctx, userID); err == nil {
+			recipientName = user.FirstName
+		}
+
+		emailItems := make([]views.EmailItem, 0, len(items))
+		for _, item := range items {
+			emailItems = append(emailItems, views.EmailItem{Title: item.Subject, Message: item.TextBody, At: item.CreatedAt})
+		}
+
+		email, err := views.RenderBatchEmail(ctx, views.BatchEmailData{
+			RecipientName: recipientName,
+			Items:         emailItems,
+			ShipmentsURL:  s.shipmentsURL(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.NotificationDelivery{
+			UserID:        userID,
+			Channel:       models.ChannelEmail,
+			Recipient:     items[len(items)-1].Recipient,
+			EventType:     "batch",
+			Subject:       email.Subject,
+			TextBody:      email.TextBody,
+			HTMLBody:      email.HTMLBody,
+			Status:        models.DeliveryStatusPending,
+			NextAttemptAt: &now,
+		}, nil
+	})
+}
+
+// DeliverDue sends pending deliveries that are due. Failed attempts are retried with
+// exponential backoff until MaxAttempts is reached. It returns how many were sent.
+func (s *NotificationService) DeliverDue(ctx context.Context, limit int) (int, error) {
+	deliveries, err := s.notificationRepo.ClaimDueDeliveries(ctx, time.Now(), limit, deliveryqueue.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			// Claimed deliveries become due again once their lease runs out
+			break
+		}
+		if s.deliver(ctx, delivery) {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// deliver sends one delivery and records the outcome, it reports whether it was sent
+func (s *NotificationService) deliver(ctx context.Context, delivery models.NotificationDelivery) bool {
+	attempts := delivery.Attempts + 1
+
+	err := s.send(ctx, delivery)
+
+	now := time.Now()
+	updates := map[string]interface{}{"attempts": attempts}
+	switch {
+	case err == nil:
+		updates["status"] = models.DeliveryStatusSent
+		updates["sent_at"] = now
+		updates["next_attempt_at"] = nil
+		updates["last_error"] = ""
+	case attempts >= s.settings.MaxAttempts:
+		updates["status"] = models.DeliveryStatusFailed
+		updates["next_attempt_at"] = nil
+		updates["last_error"] = err.Error()
+		log.Printf("Giving up on notification %s to %s after %d attempts: %v", delivery.ID, delivery.Recipient, attempts, err)
+	default:
+		updates["next_attempt_at"] = now.Add(deliveryqueue.RetryDelay(s.settings.RetryBackoff, attempts))
+		updates["last_error"] = err.Error()
+		log.Printf("Notification %s to %s failed (attempt %d), retrying: %v", delivery.ID, delivery.Recipient, attempts, err)
+	}
+
+	// Record the outcome even when the job is being stopped
+	if updateErr := s.notificationRepo.UpdateDeliveryFields(context.WithoutCancel(ctx), delivery.ID, updates); updateErr != nil {
+		log.Printf("Warning: Failed to record outcome of notification %s: %v", delivery.ID, updateErr)
+	}
+
+	return err == nil
+}
+
+// send hands a delivery to the mailer or posts it to its chat channel
+func (s *NotificationService) send(ctx context.Context, delivery models.NotificationDelivery) error {
+	switch delivery.Channel {
+	case models.ChannelSlack, models.ChannelTeams:
+		if delivery.ChannelID == nil {
+			return fmt.Errorf("chat delivery without channel")
+		}
+		channel, err := s.notificationRepo.GetChannel(ctx, *delivery.ChannelID)
+		if err != nil {
+			return fmt.Errorf("error retrieving channel: %w", err)
+		}
+		return s.chatSender.Send(ctx, channel.WebhookURL, []byte(delivery.Payload))
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:       []string{delivery.Recipient},
+		Subject:  delivery.Subject,
+		TextBody: delivery.TextBody,
+		HTMLBody: delivery.HTMLBody,
+	})
+}
+
+// SendTestEmail sends an email to the user right away to check the mail setup
+func (s *NotificationService) SendTestEmail(ctx context.Context, userID uuid.UUID) (*models.NotificationDelivery, error) {
+	user, err := s.notificationRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving user: %w", err)
+	}
+
+	now := time.Now()
+	email, err := views.RenderAlertEmail(ctx, views.AlertEmailData{
+		RecipientName: user.FirstName,
+		Item: views.EmailItem{
+			Title:   "Test notification",
+			Message: "Email notifications are set up correctly.",
+			At:      now,
+		},
+		ShipmentsURL: s.shipmentsURL(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &models.NotificationDelivery{
+		UserID:        userID,
+		Channel:       models.ChannelEmail,
+		Recipient:     user.Email,
+		EventType:     "test",
+		Subject:       email.Subject,
+		TextBody:      email.TextBody,
+		HTMLBody:      email.HTMLBody,
+		Status:        models.DeliveryStatusPending,
+		NextAttemptAt: &now,
+	}
+	if err := s.notificationRepo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("error creating delivery: %w", err)
+	}
+
+	if !s.deliver(ctx, *delivery) {
+		return nil, fmt.Errorf("failed to send test email, it will be retried")
+	}
+
+	delivery.Status = models.DeliveryStatusSent
+	delivery.Attempts = 1
+	delivery.SentAt = &now
+	delivery.NextAttemptAt = nil
+	return delivery, nil
+}
+
+// GetPreference retrieves the notification preference of a user
+func (s *NotificationService) GetPreference(ctx context.Context, userID uuid.UUID) (*models.NotificationPreference, error) {
+	preference, err := s.notificationRepo.GetPreference(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving preference: %w", err)
+	}
+
+	return preference, nil
+}
+
+// UpdatePreference validates and stores the notification preference of a user
+func (s *NotificationService) UpdatePreference(ctx context.Context, userID uuid.UUID, emailEnabled bool, eventTypes []string, delivery string) (*models.NotificationPreference, error) {
+	if delivery != models.DeliveryImmediate && delivery != models.DeliveryBatched {
+		return nil, fmt.Errorf("invalid delivery '%s'", delivery)
+	}
+
+	normalized := pq.StringArray{}
+	for _, eventType := range eventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if !alertModels.RuleType(eventType).IsNotificationType() {
+			return nil, fmt.Errorf("invalid event type '%s'", eventType)
+		}
+		normalized = append(normalized, eventType)
+	}
+
+	preference := &models.NotificationPreference{
+		UserID:       userID,
+		EmailEnabled: emailEnabled,
+		EventTypes:   normalized,
+		Delivery:     delivery,
+	}
+	if err := s.notificationRepo.SavePreference(ctx, preference); err != nil {
+		return nil, fmt.Errorf("error saving preference: %w", err)
+	}
+
+	return preference, nil
+}
+
+// GetDeliveries retrieves the most recent deliveries of a user
+func (s *NotificationService) GetDeliveries(ctx context.Context, userID uuid.UUID, limit int) ([]models.NotificationDelivery, error) {
+	deliveries, err := s.notificationRepo.GetDeliveriesByUserID(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// RetryDelivery schedules a failed delivery to be sent again
+func (s *NotificationService) RetryDelivery(ctx context.Context, userID, deliveryID uuid.UUID) error {
+	if err := s.notificationRepo.RetryDelivery(ctx, deliveryID, userID, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed delivery not found")
+		}
+		return fmt.Errorf("error retrying delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (s *NotificationService) shipmentsURL() string {
+	return s.settings.BaseURL + "/shipments"
+}
+
+// shipmentURL links to the shipments page with the shipment's details opened
+func (s *NotificationService) shipmentURL(shipmentID uuid.UUID) string {
+	return s.shipmentsURL() + "?shipment=" + shipmentID.String()
+}
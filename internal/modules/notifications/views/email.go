@@ -0,0 +1,104 @@
+package views
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// EmailItem is one shipment event in a notification email
+type EmailItem struct {
+	Title   string
+	Message string
+	At      time.Time
+}
+
+// AlertEmailData is rendered into the email sent for a single alert
+type AlertEmailData struct {
+	RecipientName string
+	Item          EmailItem
+	ShipmentsURL  string
+}
+
+// BatchEmailData is rendered into the email combining several alerts
+type BatchEmailData struct {
+	RecipientName string
+	Items         []EmailItem
+	ShipmentsURL  string
+}
+
+// RenderedEmail is an email ready to be sent
+type RenderedEmail struct {
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+var alertTextTemplate = template.Must(template.New("alert").Parse(`Hello {{.RecipientName}},
+
+{{.Item.Title}}
+
+{{.Item.Message}}
+
+Open your shipments: {{.ShipmentsURL}}
+
+You receive this email because of your shipment alert rules.
+`))
+
+var batchTextTemplate = template.Must(template.New("batch").Parse(`Hello {{.RecipientName}},
+
+There are {{len .Items}} new shipment alerts:
+{{range .Items}}
+- {{.Title}} ({{.At.Format "2006-01-02 15:04"}})
+  {{.Message}}
+{{end}}
+Open your shipments: {{.ShipmentsURL}}
+
+You receive this email because of your shipment alert rules.
+`))
+
+// RenderAlertEmail renders the HTML and plain-text email for a single alert
+func RenderAlertEmail(ctx context.Context, data AlertEmailData) (*RenderedEmail, error) {
+	var text bytes.Buffer
+	if err := alertTextTemplate.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to render alert email text: %w", err)
+	}
+
+	var html bytes.Buffer
+	if err := AlertEmail(data).Render(ctx, &html); err != nil {
+		return nil, fmt.Errorf("failed to render alert email html: %w", err)
+	}
+
+	return &RenderedEmail{
+		Subject:  data.Item.Title,
+		TextBody: text.String(),
+		HTMLBody: html.String(),
+	}, nil
+}
+
+// RenderBatchEmail renders the HTML and plain-text email combining several alerts
+func RenderBatchEmail(ctx context.Context, data BatchEmailData) (*RenderedEmail, error) {
+	var text bytes.Buffer
+	if err := batchTextTemplate.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to render batch email text: %w", err)
+	}
+
+	var html bytes.Buffer
+	if err := BatchEmail(data).Render(ctx, &html); err != nil {
+		return nil, fmt.Errorf("failed to render batch email html: %w", err)
+	}
+
+	subject := fmt.Sprintf("%d new shipment alerts", len(data.Items))
+	if len(data.Items) == 1 {
+		subject = data.Items[0].Title
+	}
+
+	return &RenderedEmail{
+		Subject:  subject,
+		TextBody: strings.TrimLeft(text.String(), "\n"),
+		HTMLBody: html.String(),
+	}, nil
+}
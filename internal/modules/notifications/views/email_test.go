@@ -0,0 +1,60 @@
+package views
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderAlertEmail(t *testing.T) {
+	email, err := RenderAlertEmail(context.Background(), AlertEmailData{
+		RecipientName: "Ada",
+		Item: EmailItem{
+			Title:   "ETA of MSCU1234567 slipped by 3 days",
+			Message: "The ETA moved from 2025-06-01 to 2025-06-04 <via transshipment>.",
+			At:      time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		},
+		ShipmentsURL: "https://tracker.example.com/shipments",
+	})
+	if err != nil {
+		t.Fatalf("RenderAlertEmail failed: %v", err)
+	}
+
+	if email.Subject != "ETA of MSCU1234567 slipped by 3 days" {
+		t.Errorf("Unexpected subject %q", email.Subject)
+	}
+	if !strings.Contains(email.TextBody, "<via transshipment>") {
+		t.Errorf("Expected plain text to be unescaped, got:\n%s", email.TextBody)
+	}
+	if !strings.Contains(email.HTMLBody, "&lt;via transshipment&gt;") {
+		t.Errorf("Expected HTML to be escaped, got:\n%s", email.HTMLBody)
+	}
+	if !strings.Contains(email.HTMLBody, `href="https://tracker.example.com/shipments"`) {
+		t.Errorf("Expected a link to the shipments page, got:\n%s", email.HTMLBody)
+	}
+}
+
+func TestRenderBatchEmail(t *testing.T) {
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	email, err := RenderBatchEmail(context.Background(), BatchEmailData{
+		RecipientName: "Ada",
+		Items: []EmailItem{
+			{Title: "MSCU1234567 is now DELIVERED", Message: "The shipping status changed.", At: at},
+			{Title: "No events for MAEU7654321 in 9 days", Message: "The last container event was on 2025-05-23.", At: at},
+		},
+		ShipmentsURL: "https://tracker.example.com/shipments",
+	})
+	if err != nil {
+		t.Fatalf("RenderBatchEmail failed: %v", err)
+	}
+
+	if email.Subject != "2 new shipment alerts" {
+		t.Errorf("Unexpected subject %q", email.Subject)
+	}
+	for _, title := range []string{"MSCU1234567 is now DELIVERED", "No events for MAEU7654321 in 9 days"} {
+		if !strings.Contains(email.TextBody, title) || !strings.Contains(email.HTMLBody, title) {
+			t.Errorf("Expected both bodies to contain %q", title)
+		}
+	}
+}
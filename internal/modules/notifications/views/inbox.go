@@ -0,0 +1,40 @@
+package views
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"go-starter/internal/modules/notifications/models"
+)
+
+// unreadLabel caps the badge so it stays small
+func unreadLabel(unread int64) string {
+	if unread > 99 {
+		return "99+"
+	}
+	return strconv.FormatInt(unread, 10)
+}
+
+func kindLabel(kind string) string {
+	if kind == models.InboxKindAlert {
+		return "Alert"
+	}
+	return "Shipment update"
+}
+
+// timeAgo formats how long ago t was, falling back to the date after a week
+func timeAgo(t, now time.Time) string {
+	elapsed := now.Sub(t)
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%dm ago", int(elapsed.Minutes()))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(elapsed.Hours()))
+	case elapsed < 7*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(elapsed.Hours()/24))
+	}
+	return t.Format("2 Jan 2006")
+}
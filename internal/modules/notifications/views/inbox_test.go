@@ -0,0 +1,89 @@
+package views
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go-starter/internal/modules/notifications/models"
+
+	"github.com/google/uuid"
+)
+
+func TestTimeAgo(t *testing.T) {
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{at: now.Add(-30 * time.Second), want: "just now"},
+		{at: now.Add(-5 * time.Minute), want: "5m ago"},
+		{at: now.Add(-3 * time.Hour), want: "3h ago"},
+		{at: now.Add(-50 * time.Hour), want: "2d ago"},
+		{at: now.Add(-10 * 24 * time.Hour), want: "10 Mar 2026"},
+	}
+
+	for _, tt := range tests {
+		if got := timeAgo(tt.at, now); got != tt.want {
+			t.Errorf("timeAgo(%v) = %q, want %q", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestInboxBadge(t *testing.T) {
+	var buf bytes.Buffer
+	if err := InboxBadge(0).Render(context.Background(), &buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "" {
+		t.Errorf("Expected no badge without unread notifications, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := InboxBadge(120).Render(context.Background(), &buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "99+") {
+		t.Errorf("Expected the badge to be capped, got %q", buf.String())
+	}
+}
+
+func TestInboxItem(t *testing.T) {
+	shipmentID := uuid.New()
+	notification := models.Notification{
+		ID:         uuid.New(),
+		Kind:       models.InboxKindAlert,
+		ShipmentID: &shipmentID,
+		Title:      "ETA slipped for <ABC123>",
+		CreatedAt:  time.Now(),
+	}
+
+	var buf bytes.Buffer
+	if err := InboxItem(notification).Render(context.Background(), &buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	html := buf.String()
+
+	if !strings.Contains(html, "/notifications/inbox/"+notification.ID.String()+"/open") {
+		t.Error("Expected the item to link through the open route")
+	}
+	if !strings.Contains(html, "/read-html") || strings.Contains(html, "/unread-html") {
+		t.Error("Expected an unread item to offer marking it read")
+	}
+	if strings.Contains(html, "<ABC123>") {
+		t.Error("Expected the title to be escaped")
+	}
+
+	readAt := time.Now()
+	notification.ReadAt = &readAt
+	buf.Reset()
+	if err := InboxItem(notification).Render(context.Background(), &buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "/unread-html") {
+		t.Error("Expected a read item to offer marking it unread")
+	}
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "go-starter/internal/modules/shipments/models"
+
+// CarrierResponse is a carrier of the carrier registry
+type CarrierResponse struct {
+	Scac              string   `json:"scac"`
+	Name              string   `json:"name"`
+	Aliases           []string `json:"aliases"`
+	ContainerPrefixes []string `json:"containerPrefixes"`
+	BLPatterns        []string `json:"blPatterns"`
+}
+
+// CarriersListResponse represents the response when listing the carrier registry
+type CarriersListResponse struct {
+	Carriers []CarrierResponse `json:"carriers"`
+	Total    int               `json:"total"`
+}
+
+// ToCarrierResponse converts a Carrier model to CarrierResponse DTO
+func ToCarrierResponse(carrier *models.Carrier) CarrierResponse {
+	return CarrierResponse{
+		Scac:              carrier.Scac,
+		Name:              carrier.Name,
+		Aliases:           nonNilStrings(carrier.Aliases),
+		ContainerPrefixes: nonNilStrings(carrier.ContainerPrefixes),
+		BLPatterns:        nonNilStrings(carrier.BLPatterns),
+	}
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/google/uuid"
+
+// MapDataResponse holds the map features of the user's shipments inside a bounding box,
+// clustered and simplified for the requested zoom level
+type MapDataResponse struct {
+	Zoom      int                 `json:"zoom"`
+	Positions []MapMarkerResponse `json:"positions"`
+	Ports     []MapMarkerResponse `json:"ports"`
+	Routes    []MapRouteResponse  `json:"routes"`
+}
+
+// MapMarkerResponse is a single marker or, with a count above one, a cluster of markers.
+// The details are only set for single markers.
+type MapMarkerResponse struct {
+	Latitude       float64    `json:"latitude"`
+	Longitude      float64    `json:"longitude"`
+	Count          int        `json:"count"`
+	ShipmentID     *uuid.UUID `json:"shipmentId,omitempty"`
+	ShipmentNumber string     `json:"shipmentNumber,omitempty"`
+	Status         string     `json:"status,omitempty"`
+	Name           string     `json:"name,omitempty"`
+	Locode         string     `json:"locode,omitempty"`
+}
+
+type MapRouteResponse struct {
+	ShipmentID uuid.UUID    `json:"shipmentId"`
+	RouteType  string       `json:"routeType"`
+	Path       [][2]float64 `json:"path"`
+}
@@ -15,6 +15,11 @@ type ShipmentDetailsResponse struct {
 	ShippingStatus string    `json:"shippingStatus"`
 	CreatedAt      time.Time `json:"createdAt"`
 	UpdatedAt      time.Time `json:"updatedAt"`
+	// Tracking lifecycle
+	ProviderStatus    string     `json:"providerStatus"`
+	TrackingState     string     `json:"trackingState"`
+	TerminalSince     *time.Time `json:"terminalSince"`
+	TrackingStoppedAt *time.Time `json:"trackingStoppedAt"`
 	// Shipment Information Fields
 	Consignee        string `json:"consignee"`
 	Recipient        string `json:"recipient"`
@@ -40,6 +45,14 @@ type ShipmentDetailsResponse struct {
 	Facilities       []ShipmentFacilityResponse  `json:"facilities"`
 	Containers       []ShipmentContainerResponse `json:"containers"`
 	RouteData        ShipmentRouteDataResponse   `json:"routeData"`
+	Milestones       []ShipmentMilestoneResponse `json:"milestones"`
+	VoyageLegs       []ShipmentVoyageLegResponse `json:"voyageLegs"`
+	// RouteProgress is nil when neither the route geometry nor the ports locate the shipment
+	RouteProgress *ShipmentRouteProgressResponse `json:"routeProgress"`
+	// FreeTime is filled in by the demurrage module from the user's free-time agreements
+	FreeTime []ContainerFreeTimeResponse `json:"freeTime"`
+	// Emissions is filled in by the emissions module from the user's emission factors
+	Emissions *ShipmentEmissionsResponse `json:"emissions"`
 }
 
 type ShipmentLocationResponse struct {
@@ -51,6 +64,11 @@ type ShipmentLocationResponse struct {
 	Latitude    float64 `json:"latitude"`
 	Longitude   float64 `json:"longitude"`
 	Timezone    string  `json:"timezone"`
+	// Subdivision and FunctionCodes come from the UN/LOCODE reference, Mismatches lists what
+	// the provider reported differently from it, e.g. name,coordinates
+	Subdivision   string `json:"subdivision,omitempty"`
+	FunctionCodes string `json:"functionCodes,omitempty"`
+	Mismatches    string `json:"mismatches,omitempty"`
 }
 
 type ShipmentRouteResponse struct {
@@ -86,11 +104,43 @@ type ShipmentFacilityResponse struct {
 }
 
 type ShipmentContainerResponse struct {
-	Number   string                           `json:"number"`
-	IsoCode  string                           `json:"isoCode"`
-	SizeType string                           `json:"sizeType"`
-	Status   string                           `json:"status"`
-	Events   []ShipmentContainerEventResponse `json:"events"`
+	Number     string                           `json:"number"`
+	IsoCode    string                           `json:"isoCode"`
+	SizeType   string                           `json:"sizeType"`
+	Status     string                           `json:"status"`
+	Events     []ShipmentContainerEventResponse `json:"events"`
+	Milestones []ShipmentMilestoneResponse      `json:"milestones"`
+}
+
+// ShipmentMilestoneResponse is a standardized milestone of a container or a shipment
+type ShipmentMilestoneResponse struct {
+	Milestone   string     `json:"milestone"`
+	ActualAt    *time.Time `json:"actualAt"`
+	EstimatedAt *time.Time `json:"estimatedAt"`
+	Locode      string     `json:"locode,omitempty"`
+}
+
+// Voyage leg port roles
+const (
+	LegPortPol           = "pol"
+	LegPortTransshipment = "transshipment"
+	LegPortPod           = "pod"
+)
+
+// ShipmentVoyageLegResponse is a sea leg of a shipment, FromRole and ToRole tell the port
+// of loading and the port of discharge from transshipment ports
+type ShipmentVoyageLegResponse struct {
+	Sequence        int                      `json:"sequence"`
+	Vessel          *ShipmentVesselResponse  `json:"vessel"`
+	Voyage          string                   `json:"voyage"`
+	From            ShipmentLocationResponse `json:"from"`
+	FromRole        string                   `json:"fromRole"`
+	To              ShipmentLocationResponse `json:"to"`
+	ToRole          string                   `json:"toRole"`
+	DepartureAt     *time.Time               `json:"departureAt"`
+	DepartureActual bool                     `json:"departureActual"`
+	ArrivalAt       *time.Time               `json:"arrivalAt"`
+	ArrivalActual   bool                     `json:"arrivalActual"`
 }
 
 type ShipmentContainerEventResponse struct {
@@ -109,6 +159,62 @@ type ShipmentContainerEventResponse struct {
 	Voyage            *string                   `json:"voyage"`
 }
 
+// Route progress methods
+const (
+	// ProgressMethodRoute measures along the planned route geometry
+	ProgressMethodRoute = "route"
+	// ProgressMethodGreatCircle measures great-circle distances between the ports and the
+	// vessel, used without route geometry or when the vessel is far off the planned route
+	ProgressMethodGreatCircle = "great_circle"
+)
+
+// ShipmentRouteProgressResponse is how far a shipment got on its way to the port of
+// discharge, with an ETA estimated from the remaining distance at typical speed
+type ShipmentRouteProgressResponse struct {
+	Method          string  `json:"method"`
+	TotalKm         float64 `json:"totalKm"`
+	SailedKm        float64 `json:"sailedKm"`
+	RemainingKm     float64 `json:"remainingKm"`
+	ProgressPercent float64 `json:"progressPercent"`
+	// PositionAt is the time of the vessel position the progress is based on
+	PositionAt *time.Time `json:"positionAt"`
+	// EstimatedETA is our ETA at the port of discharge, nil before departure and after arrival
+	EstimatedETA *time.Time `json:"estimatedEta"`
+	// CarrierETA is the carrier's predictive ETA, its planned date without one
+	CarrierETA *time.Time `json:"carrierEta"`
+	// ETADeltaHours is how much later our ETA is than the carrier's, negative when earlier
+	ETADeltaHours *float64 `json:"etaDeltaHours"`
+}
+
+// Free-time statuses
+const (
+	// FreeTimeStatusRunning is a running clock with more than the agreement's alert days left
+	FreeTimeStatusRunning = "running"
+	// FreeTimeStatusExpiring is a running clock that runs out within the agreement's alert days
+	FreeTimeStatusExpiring = "expiring"
+	// FreeTimeStatusOverdue is a running clock past its free time, charges are accruing
+	FreeTimeStatusOverdue = "overdue"
+	// FreeTimeStatusClosed is a clock that stopped, its charges are final
+	FreeTimeStatusClosed = "closed"
+)
+
+// ContainerFreeTimeResponse is the demurrage or detention clock of a container
+type ContainerFreeTimeResponse struct {
+	ContainerNumber string     `json:"containerNumber"`
+	ChargeType      string     `json:"chargeType"`
+	AgreementID     uuid.UUID  `json:"agreementId"`
+	AgreementName   string     `json:"agreementName"`
+	Status          string     `json:"status"`
+	StartedAt       time.Time  `json:"startedAt"`
+	StoppedAt       *time.Time `json:"stoppedAt"`
+	FreeDays        int        `json:"freeDays"`
+	FreeTimeEndsAt  time.Time  `json:"freeTimeEndsAt"`
+	DaysRemaining   int        `json:"daysRemaining"`
+	ChargeableDays  int        `json:"chargeableDays"`
+	AccruedCharges  float64    `json:"accruedCharges"`
+	Currency        string     `json:"currency"`
+}
+
 type ShipmentRouteDataResponse struct {
 	RouteSegments []ShipmentRouteSegmentResponse `json:"routeSegments"`
 	Coordinates   ShipmentCoordinatesResponse    `json:"coordinates"`
@@ -159,3 +265,47 @@ type ShipmentAisResponse struct {
 	LastVesselPositionUpdate *time.Time              `json:"lastVesselPositionUpdate"`
 	UpdatedAt                time.Time               `json:"updatedAt"`
 }
+
+// ShipmentTrackResponse is the recorded track of the vessels that carried a shipment
+type ShipmentTrackResponse struct {
+	ShipmentID uuid.UUID                    `json:"shipmentId"`
+	From       *time.Time                   `json:"from"`
+	To         *time.Time                   `json:"to"`
+	Points     []ShipmentTrackPointResponse `json:"points"`
+}
+
+type ShipmentTrackPointResponse struct {
+	Latitude    float64   `json:"latitude"`
+	Longitude   float64   `json:"longitude"`
+	RecordedAt  time.Time `json:"recordedAt"`
+	Vessel      string    `json:"vessel"`
+	Imo         int       `json:"imo"`
+	Mmsi        int       `json:"mmsi"`
+	LegSequence int       `json:"legSequence,omitempty"`
+}
+
+// ShipmentEmissionsResponse is the well-to-wheel CO2e estimate of a shipment's containers
+// over the legs of its planned route
+type ShipmentEmissionsResponse struct {
+	TotalKg    float64                      `json:"totalKg"`
+	DistanceKm float64                      `json:"distanceKm"`
+	TEU        float64                      `json:"teu"`
+	Legs       []EmissionLegResponse        `json:"legs"`
+	Containers []ContainerEmissionsResponse `json:"containers"`
+}
+
+// EmissionLegResponse is the CO2e of all containers on a route segment
+type EmissionLegResponse struct {
+	SegmentOrder int     `json:"segmentOrder"`
+	Mode         string  `json:"mode"`
+	DistanceKm   float64 `json:"distanceKm"`
+	Kg           float64 `json:"kg"`
+}
+
+// ContainerEmissionsResponse is the CO2e of a container over all legs
+type ContainerEmissionsResponse struct {
+	ContainerNumber string  `json:"containerNumber"`
+	SizeType        string  `json:"sizeType"`
+	TEU             float64 `json:"teu"`
+	Kg              float64 `json:"kg"`
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"go-starter/internal/modules/shipments/dto"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetCarriers handles GET /api/carriers
+func (h *shipmentAPIHandler) GetCarriers(c echo.Context) error {
+	carriers, err := h.shipmentService.GetCarriers(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to fetch carriers",
+		})
+	}
+
+	response := dto.CarriersListResponse{
+		Carriers: make([]dto.CarrierResponse, len(carriers)),
+		Total:    len(carriers),
+	}
+	for i := range carriers {
+		response.Carriers[i] = dto.ToCarrierResponse(&carriers[i])
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// DetectCarrier handles GET /api/carriers/detect?number=MSCU1234565&type=CT, the type is optional
+func (h *shipmentAPIHandler) DetectCarrier(c echo.Context) error {
+	number := strings.TrimSpace(c.QueryParam("number"))
+	if number == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "number is required",
+		})
+	}
+	shipmentType := strings.ToUpper(strings.TrimSpace(c.QueryParam("type")))
+
+	carrier, err := h.shipmentService.DetectCarrier(c.Request().Context(), number, shipmentType)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to detect carrier",
+		})
+	}
+
+	return c.JSON(http.StatusOK, dto.ToCarrierResponse(carrier))
+}
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"fmt"
 	authService "go-starter/internal/modules/auth/services"
 	"go-starter/internal/modules/shipments/dto"
 	shipmentServices "go-starter/internal/modules/shipments/services"
+	"go-starter/pkg/geo"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -144,6 +148,48 @@ func (h *shipmentAPIHandler) GetShipmentsForGrid(c echo.Context) error {
 	return c.JSON(http.StatusOK, gridData)
 }
 
+// GetMapData handles GET /api/shipments/map?bbox=minLng,minLat,maxLng,maxLat&zoom=5.
+// Without a bbox the whole world is returned.
+func (h *shipmentAPIHandler) GetMapData(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := authService.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "unauthorized",
+		})
+	}
+
+	bbox := geo.World
+	if bboxStr := c.QueryParam("bbox"); bboxStr != "" {
+		bbox, err = geo.ParseBBox(bboxStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	zoom := 2
+	if zoomStr := c.QueryParam("zoom"); zoomStr != "" {
+		zoom, err = strconv.Atoi(zoomStr)
+		if err != nil || zoom < 0 || zoom > shipmentServices.MaxMapZoom {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("invalid zoom, expected 0 to %d", shipmentServices.MaxMapZoom),
+			})
+		}
+	}
+
+	mapData, err := h.shipmentService.GetMapData(ctx, userID, bbox, zoom)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to fetch map data",
+		})
+	}
+
+	return c.JSON(http.StatusOK, mapData)
+}
+
 func (h *shipmentAPIHandler) GetShipmentByID(c echo.Context) error {
 	ctx := c.Request().Context()
 
@@ -220,6 +266,48 @@ func (h *shipmentAPIHandler) RefreshShipment(c echo.Context) error {
 
 }
 
+func (h *shipmentAPIHandler) ResumeTracking(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := authService.GetUserIDFromContext(c)
+	if err != nil {
+		return c.Redirect(http.StatusTemporaryRedirect, "/login")
+	}
+
+	idStr := c.Param("id")
+	shipmentID, err := uuid.Parse(idStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid shipment id",
+		})
+	}
+
+	shipment, err := h.shipmentService.ResumeTracking(ctx, userID, shipmentID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	shipmentDetails, err := h.shipmentService.GetShipmentDetails(ctx, userID, shipment.ID)
+	if err != nil {
+		return c.JSON(http.StatusOK, map[string]any{
+			"message": "success",
+			"shipment": map[string]any{
+				"id":             shipment.ID,
+				"shipmentNumber": shipment.ShipmentNumber,
+				"shippingStatus": shipment.ShippingStatus,
+				"trackingState":  shipment.TrackingState,
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"message":  "success",
+		"shipment": shipmentDetails,
+	})
+}
+
 func (h *shipmentAPIHandler) GetShipmentDetails(c echo.Context) error {
 	ctx := c.Request().Context()
 
@@ -249,6 +337,68 @@ func (h *shipmentAPIHandler) GetShipmentDetails(c echo.Context) error {
 	})
 }
 
+// GetShipmentTrack handles GET /api/shipments/:id/track, from and to are optional RFC3339 times
+func (h *shipmentAPIHandler) GetShipmentTrack(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := authService.GetUserIDFromContext(c)
+	if err != nil {
+		return c.Redirect(http.StatusTemporaryRedirect, "/login")
+	}
+
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid shipment id",
+		})
+	}
+
+	from, err := parseTimeParam(c, "from")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	to, err := parseTimeParam(c, "to")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "from must be before to",
+		})
+	}
+
+	track, err := h.shipmentService.GetShipmentTrack(ctx, userID, shipmentID, from, to)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, track)
+}
+
+// parseTimeParam parses an optional RFC3339 query parameter
+func parseTimeParam(c echo.Context, name string) (*time.Time, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s, expected an RFC3339 time", name)
+	}
+	return &t, nil
+}
+
 func (h *shipmentAPIHandler) DeleteUserShipment(c echo.Context) error {
 	ctx := c.Request().Context()
 
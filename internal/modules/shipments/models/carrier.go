@@ -0,0 +1,212 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// containerNumberPattern is the shape of an ISO 6346 container number
+var containerNumberPattern = regexp.MustCompile(`^[A-Z]{3}[UJZ][0-9]{7}$`)
+
+// Carrier is an ocean carrier of the carrier registry. Shipments are matched to carriers by
+// their SCAC, the owner prefix of their container numbers or the shape of their BL and
+// booking numbers, and shown under the canonical carrier name.
+type Carrier struct {
+	Scac string `json:"scac" gorm:"type:varchar(4);primaryKey"`
+	Name string `json:"name" gorm:"type:varchar(100);not null"`
+	// Aliases are other names and codes the carrier goes by, e.g. MSC for MSCU
+	Aliases pq.StringArray `json:"aliases" gorm:"type:text[];default:'{}'"`
+	// ContainerPrefixes are the BIC owner codes of the carrier's containers, e.g. MSCU and MEDU
+	ContainerPrefixes pq.StringArray `json:"container_prefixes" gorm:"type:text[];default:'{}'"`
+	// BLPatterns are regular expressions of the carrier's BL and booking numbers. Numbers
+	// starting with the SCAC or a container prefix match without a pattern.
+	BLPatterns pq.StringArray `json:"bl_patterns" gorm:"type:text[];default:'{}'"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time      `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+
+	// blPatterns are the compiled BLPatterns, see CompilePatterns
+	blPatterns []*regexp.Regexp
+}
+
+// TableName specifies the table name for Carrier
+func (Carrier) TableName() string {
+	return "carriers"
+}
+
+// IsContainerNumber reports whether a normalized shipment number is shaped like a container number
+func IsContainerNumber(number string) bool {
+	return containerNumberPattern.MatchString(number)
+}
+
+// HasName reports whether the carrier goes by a name or code, ignoring case
+func (c *Carrier) HasName(name string) bool {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return false
+	}
+	if strings.EqualFold(c.Scac, name) || strings.EqualFold(c.Name, name) {
+		return true
+	}
+	for _, alias := range c.Aliases {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// OwnsContainer reports whether a container number carries one of the carrier's owner prefixes
+func (c *Carrier) OwnsContainer(number string) bool {
+	if len(number) < 4 {
+		return false
+	}
+	for _, prefix := range c.ContainerPrefixes {
+		if strings.EqualFold(prefix, number[:4]) {
+			return true
+		}
+	}
+	return false
+}
+
+// AfterFind compiles the BL patterns once when the registry is loaded
+func (c *Carrier) AfterFind(tx *gorm.DB) error {
+	c.CompilePatterns()
+	return nil
+}
+
+// CompilePatterns compiles the BL patterns MatchesReference checks. Carriers loaded from the
+// database are compiled already, others have to be compiled after their patterns are set.
+// Invalid patterns are left out, so they never match.
+func (c *Carrier) CompilePatterns() {
+	c.blPatterns = nil
+	for _, pattern := range c.BLPatterns {
+		if re, err := regexp.Compile(pattern); err == nil {
+			c.blPatterns = append(c.blPatterns, re)
+		}
+	}
+}
+
+// MatchesReference reports whether a BL or booking number belongs to the carrier
+func (c *Carrier) MatchesReference(number string) bool {
+	if c.Scac != "" && strings.HasPrefix(number, c.Scac) {
+		return true
+	}
+	for _, prefix := range c.ContainerPrefixes {
+		if prefix != "" && strings.HasPrefix(number, prefix) {
+			return true
+		}
+	}
+	for _, re := range c.blPatterns {
+		if re.MatchString(number) {
+			return true
+		}
+	}
+	return false
+}
+
+// SeedCarriers adds the default carriers to the registry, carriers that are already in it
+// are left as they are
+func SeedCarriers(db *gorm.DB) error {
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(DefaultCarriers()).Error
+}
+
+// DefaultCarriers are the major ocean carriers the registry starts with
+func DefaultCarriers() []Carrier {
+	return []Carrier{
+		{
+			Scac:              "MSCU",
+			Name:              "MSC",
+			Aliases:           pq.StringArray{"Mediterranean Shipping Company", "MSC Mediterranean Shipping Company"},
+			ContainerPrefixes: pq.StringArray{"MSCU", "MEDU", "MSDU", "MSMU", "MSNU"},
+		},
+		{
+			Scac:              "MAEU",
+			Name:              "Maersk",
+			Aliases:           pq.StringArray{"Maersk Line", "A.P. Moller-Maersk", "MAERSK"},
+			ContainerPrefixes: pq.StringArray{"MAEU", "MSKU", "MRKU", "MRSU"},
+		},
+		{
+			Scac:              "CMDU",
+			Name:              "CMA CGM",
+			Aliases:           pq.StringArray{"CMA-CGM", "CMA"},
+			ContainerPrefixes: pq.StringArray{"CMAU", "CGMU", "ECMU"},
+		},
+		{
+			Scac:              "COSU",
+			Name:              "COSCO Shipping Lines",
+			Aliases:           pq.StringArray{"COSCO", "China COSCO Shipping"},
+			ContainerPrefixes: pq.StringArray{"CBHU", "CCLU", "CSNU", "CSLU"},
+		},
+		{
+			Scac:              "HLCU",
+			Name:              "Hapag-Lloyd",
+			Aliases:           pq.StringArray{"Hapag Lloyd", "HAPAG"},
+			ContainerPrefixes: pq.StringArray{"HLCU", "HLXU", "HAMU", "UACU"},
+		},
+		{
+			Scac:              "ONEY",
+			Name:              "Ocean Network Express",
+			Aliases:           pq.StringArray{"ONE"},
+			ContainerPrefixes: pq.StringArray{"ONEU", "NYKU", "MOLU", "KKFU"},
+		},
+		{
+			Scac:              "EGLV",
+			Name:              "Evergreen",
+			Aliases:           pq.StringArray{"Evergreen Line", "Evergreen Marine"},
+			ContainerPrefixes: pq.StringArray{"EGHU", "EGSU", "EISU", "EMCU"},
+		},
+		{
+			Scac:              "HDMU",
+			Name:              "HMM",
+			Aliases:           pq.StringArray{"Hyundai Merchant Marine"},
+			ContainerPrefixes: pq.StringArray{"HDMU", "HMMU"},
+		},
+		{
+			Scac:              "YMLU",
+			Name:              "Yang Ming",
+			Aliases:           pq.StringArray{"Yang Ming Marine Transport", "YML"},
+			ContainerPrefixes: pq.StringArray{"YMLU", "YMMU"},
+		},
+		{
+			Scac:              "ZIMU",
+			Name:              "ZIM",
+			Aliases:           pq.StringArray{"ZIM Integrated Shipping Services"},
+			ContainerPrefixes: pq.StringArray{"ZIMU", "ZCSU"},
+		},
+		{
+			Scac:              "OOLU",
+			Name:              "OOCL",
+			Aliases:           pq.StringArray{"Orient Overseas Container Line"},
+			ContainerPrefixes: pq.StringArray{"OOLU", "OOCU"},
+		},
+		{
+			Scac:              "APLU",
+			Name:              "APL",
+			Aliases:           pq.StringArray{"American President Lines"},
+			ContainerPrefixes: pq.StringArray{"APLU", "APZU", "APHU"},
+		},
+		{
+			Scac:              "SUDU",
+			Name:              "Hamburg Süd",
+			Aliases:           pq.StringArray{"Hamburg Sud", "Hamburg Sued"},
+			ContainerPrefixes: pq.StringArray{"SUDU"},
+		},
+		{
+			Scac:              "PABV",
+			Name:              "PIL",
+			Aliases:           pq.StringArray{"Pacific International Lines"},
+			ContainerPrefixes: pq.StringArray{"PCIU"},
+		},
+		{
+			Scac:              "WHLC",
+			Name:              "Wan Hai Lines",
+			Aliases:           pq.StringArray{"Wan Hai"},
+			ContainerPrefixes: pq.StringArray{"WHLU", "WHSU"},
+		},
+	}
+}
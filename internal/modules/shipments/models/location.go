@@ -1,15 +1,30 @@
 package models
 
 import (
+	"strings"
 	"time"
 
+	"go-starter/pkg/geo"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// Location mismatches between the provider and the UN/LOCODE reference
+const (
+	MismatchName        = "name"
+	MismatchCountry     = "country"
+	MismatchCoordinates = "coordinates"
+)
+
+// maxCoordinateMismatchMeters is how far provider coordinates may be from the reference
+// before they are flagged, reference coordinates only have minute precision
+const maxCoordinateMismatchMeters = 50000
+
+// Location is identified by its LOCODE, names repeat across countries
 type Location struct {
 	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
-	Name        string    `json:"name" gorm:"type:varchar(50);not null;uniqueIndex:idx_locations_name"`
+	Name        string    `json:"name" gorm:"type:varchar(100);not null;index:idx_locations_name_search"`
 	State       *string   `json:"state" gorm:"type:varchar(50)"`
 	Country     string    `json:"country" gorm:"type:varchar(50);not null"`
 	CountryCode string    `json:"countryCode" gorm:"type:varchar(10);not null"`
@@ -17,8 +32,17 @@ type Location struct {
 	Latitude    float64   `json:"latitude" gorm:"type:decimal(10,8)"`
 	Longitude   float64   `json:"longitude" gorm:"type:decimal(11,8)"`
 	Timezone    string    `json:"timezone" gorm:"type:varchar(50)"`
-	CreatedAt   time.Time `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
-	UpdatedAt   time.Time `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	// ProviderName is the name the tracking provider reported, Name is the official one
+	// once the location matched the UN/LOCODE reference
+	ProviderName string `json:"providerName" gorm:"type:varchar(100)"`
+	// Subdivision and FunctionCodes come from the UN/LOCODE reference, e.g. ZH and 12345---
+	Subdivision   string `json:"subdivision" gorm:"type:varchar(3)"`
+	FunctionCodes string `json:"functionCodes" gorm:"type:varchar(8)"`
+	// Mismatches lists, comma separated, what the provider reported differently from the
+	// UN/LOCODE reference, e.g. name,coordinates
+	Mismatches string    `json:"mismatches" gorm:"type:varchar(100)"`
+	CreatedAt  time.Time `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
 }
 
 func (Location) TableName() string {
@@ -44,6 +68,49 @@ func (l *Location) BeforeUpdate(tx *gorm.DB) error {
 	return nil
 }
 
+// EnrichFromReference takes the official name, the country, the subdivision and the
+// function codes from the location's UN/LOCODE entry and flags what the provider reported
+// differently. Provider coordinates are kept as they are more precise, the reference only
+// fills them in when they are missing.
+func (l *Location) EnrichFromReference(reference *UnLocode) {
+	if l.ProviderName == "" {
+		l.ProviderName = l.Name
+	}
+
+	var mismatches []string
+	if l.ProviderName != "" && !strings.EqualFold(l.ProviderName, reference.Name) && !strings.EqualFold(l.ProviderName, reference.NameWoDiacritics) {
+		mismatches = append(mismatches, MismatchName)
+	}
+	if l.CountryCode != "" && !strings.EqualFold(l.CountryCode, reference.CountryCode) {
+		mismatches = append(mismatches, MismatchCountry)
+	}
+	if reference.Latitude != nil && reference.Longitude != nil {
+		official := geo.Point{Lat: *reference.Latitude, Lng: *reference.Longitude}
+		if l.Latitude == 0 && l.Longitude == 0 {
+			l.Latitude, l.Longitude = official.Lat, official.Lng
+		} else if geo.Distance(geo.Point{Lat: l.Latitude, Lng: l.Longitude}, official) > maxCoordinateMismatchMeters {
+			mismatches = append(mismatches, MismatchCoordinates)
+		}
+	}
+
+	if reference.Name != "" {
+		l.Name = reference.Name
+	}
+	l.CountryCode = reference.CountryCode
+	l.Subdivision = reference.Subdivision
+	l.FunctionCodes = reference.Function
+	l.Mismatches = strings.Join(mismatches, ",")
+}
+
+// DropUniqueNameIndex drops the unique index on location names of older schemas, locations
+// are identified by their LOCODE and same-name places exist in different countries
+func DropUniqueNameIndex(db *gorm.DB) error {
+	if !db.Migrator().HasIndex(&Location{}, "idx_locations_name") {
+		return nil
+	}
+	return db.Migrator().DropIndex(&Location{}, "idx_locations_name")
+}
+
 func AutoMigrateLocations(db *gorm.DB) error {
 	return db.AutoMigrate(&Location{})
 }
@@ -0,0 +1,32 @@
+package models
+
+import "testing"
+
+func TestLocationEnrichFromReference(t *testing.T) {
+	lat, lng := 57.7, 11.95
+	goteborg := &UnLocode{
+		Locode: "SEGOT", CountryCode: "SE", Name: "Göteborg", NameWoDiacritics: "Goteborg",
+		Subdivision: "O", Function: "12345---", Latitude: &lat, Longitude: &lng,
+	}
+
+	matching := Location{Name: "GOTEBORG", CountryCode: "SE", Locode: "SEGOT", Latitude: 57.69, Longitude: 11.9}
+	matching.EnrichFromReference(goteborg)
+	if matching.Name != "Göteborg" || matching.ProviderName != "GOTEBORG" || matching.Subdivision != "O" || matching.FunctionCodes != "12345---" {
+		t.Errorf("Expected the official name and codes, got %+v", matching)
+	}
+	if matching.Mismatches != "" || matching.Latitude != 57.69 {
+		t.Errorf("Expected no mismatches and the provider coordinates, got %q at %v", matching.Mismatches, matching.Latitude)
+	}
+
+	mismatching := Location{Name: "Gothenburg", CountryCode: "NO", Locode: "SEGOT", Latitude: 59.9, Longitude: 10.7}
+	mismatching.EnrichFromReference(goteborg)
+	if mismatching.Mismatches != "name,country,coordinates" || mismatching.CountryCode != "SE" {
+		t.Errorf("Expected all mismatches flagged, got %q", mismatching.Mismatches)
+	}
+
+	missing := Location{Name: "Goteborg", Locode: "SEGOT"}
+	missing.EnrichFromReference(goteborg)
+	if missing.Latitude != lat || missing.Longitude != lng || missing.Mismatches != "" {
+		t.Errorf("Expected the reference coordinates to fill in, got %+v", missing)
+	}
+}
@@ -0,0 +1,287 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Milestone is a standardized step in a container's journey.
+// Provider event codes are free strings, NormalizeMilestone maps them onto these.
+type Milestone string
+
+const (
+	MilestoneEmptyPickup   Milestone = "empty_pickup"
+	MilestoneGateIn        Milestone = "gate_in"
+	MilestoneLoaded        Milestone = "loaded"
+	MilestoneDeparted      Milestone = "departed"
+	MilestoneTransshipment Milestone = "transshipment"
+	MilestoneArrived       Milestone = "arrived"
+	MilestoneDischarged    Milestone = "discharged"
+	MilestoneGateOut       Milestone = "gate_out"
+	MilestoneEmptyReturn   Milestone = "empty_return"
+)
+
+// Milestones returns all milestones in the order a container reaches them
+func Milestones() []Milestone {
+	return []Milestone{
+		MilestoneEmptyPickup,
+		MilestoneGateIn,
+		MilestoneLoaded,
+		MilestoneDeparted,
+		MilestoneTransshipment,
+		MilestoneArrived,
+		MilestoneDischarged,
+		MilestoneGateOut,
+		MilestoneEmptyReturn,
+	}
+}
+
+// milestoneEventCodes maps provider event codes to a milestone. SafeCube sends its own
+// three-letter codes, some carriers pass DCSA codes through.
+var milestoneEventCodes = map[string]Milestone{
+	// SafeCube
+	"CEP": MilestoneEmptyPickup,
+	"CGI": MilestoneGateIn,
+	"CLL": MilestoneLoaded,
+	"VDL": MilestoneDeparted,
+	"VAT": MilestoneTransshipment,
+	"CDT": MilestoneTransshipment,
+	"TSD": MilestoneTransshipment,
+	"TSL": MilestoneTransshipment,
+	"CLT": MilestoneTransshipment,
+	"VDT": MilestoneTransshipment,
+	"VAD": MilestoneArrived,
+	"CDD": MilestoneDischarged,
+	"CGO": MilestoneGateOut,
+	"CER": MilestoneEmptyReturn,
+	// DCSA
+	"GTIN": MilestoneGateIn,
+	"LOAD": MilestoneLoaded,
+	"DEPA": MilestoneDeparted,
+	"ARRI": MilestoneArrived,
+	"DISC": MilestoneDischarged,
+	"GTOT": MilestoneGateOut,
+}
+
+// NormalizeMilestone maps a provider event to a milestone. The event code wins, the
+// description is the fallback for providers that send none or an unknown one.
+func NormalizeMilestone(eventCode, description string) (Milestone, bool) {
+	desc := strings.ToLower(description)
+	empty := strings.Contains(desc, "empty")
+
+	if milestone, ok := milestoneEventCodes[strings.ToUpper(strings.TrimSpace(eventCode))]; ok {
+		// DCSA has no codes of its own for empty moves, they are gate events of an empty container
+		switch {
+		case milestone == MilestoneGateOut && empty:
+			return MilestoneEmptyPickup, true
+		case milestone == MilestoneGateIn && empty:
+			return MilestoneEmptyReturn, true
+		}
+		return milestone, true
+	}
+
+	switch {
+	case empty && strings.Contains(desc, "return"):
+		return MilestoneEmptyReturn, true
+	case empty && (strings.Contains(desc, "pick") || strings.Contains(desc, "to shipper") ||
+		strings.Contains(desc, "release") || strings.Contains(desc, "gate out")):
+		return MilestoneEmptyPickup, true
+	case strings.Contains(desc, "transship") || strings.Contains(desc, "t/s"):
+		return MilestoneTransshipment, true
+	case containsAny(desc, "gate out", "gate-out", "gated out"):
+		return MilestoneGateOut, true
+	case containsAny(desc, "gate in", "gate-in", "gated in"):
+		return MilestoneGateIn, true
+	case containsAny(desc, "discharg", "unload"):
+		return MilestoneDischarged, true
+	case strings.Contains(desc, "load"):
+		return MilestoneLoaded, true
+	case containsAny(desc, "depart", "sailed"):
+		return MilestoneDeparted, true
+	case containsAny(desc, "arriv", "berth"):
+		return MilestoneArrived, true
+	}
+	return "", false
+}
+
+// takesLastEvent reports whether a milestone is the last matching event rather than the first,
+// so that arrival and discharge are at the final port and not at a transshipment port
+func (m Milestone) takesLastEvent() bool {
+	switch m {
+	case MilestoneArrived, MilestoneDischarged, MilestoneGateOut, MilestoneEmptyReturn:
+		return true
+	}
+	return false
+}
+
+// ContainerMilestone is when a container reached, or is estimated to reach, a milestone
+type ContainerMilestone struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	ContainerID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_container_milestone"`
+	Milestone   Milestone  `gorm:"type:varchar(30);not null;uniqueIndex:idx_container_milestone"`
+	ActualAt    *time.Time `gorm:"type:timestamptz"`
+	EstimatedAt *time.Time `gorm:"type:timestamptz"`
+	LocationID  *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt   time.Time  `gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time  `gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+
+	Container Container `gorm:"foreignKey:ContainerID;constraint:OnDelete:CASCADE"`
+	Location  *Location `gorm:"foreignKey:LocationID"`
+}
+
+func (ContainerMilestone) TableName() string {
+	return "container_milestones"
+}
+
+func (cm *ContainerMilestone) BeforeCreate(tx *gorm.DB) error {
+	if cm.ID == uuid.Nil {
+		cm.ID = uuid.New()
+	}
+	now := time.Now()
+	if cm.CreatedAt.IsZero() {
+		cm.CreatedAt = now
+	}
+	if cm.UpdatedAt.IsZero() {
+		cm.UpdatedAt = now
+	}
+	return nil
+}
+
+// ShipmentMilestone is a milestone across all containers of a shipment
+type ShipmentMilestone struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	ShipmentID  uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_shipment_milestone"`
+	Milestone   Milestone  `gorm:"type:varchar(30);not null;uniqueIndex:idx_shipment_milestone"`
+	ActualAt    *time.Time `gorm:"type:timestamptz"`
+	EstimatedAt *time.Time `gorm:"type:timestamptz"`
+	CreatedAt   time.Time  `gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time  `gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+
+	Shipment Shipment `gorm:"foreignKey:ShipmentID;constraint:OnDelete:CASCADE"`
+}
+
+func (ShipmentMilestone) TableName() string {
+	return "shipment_milestones"
+}
+
+func (sm *ShipmentMilestone) BeforeCreate(tx *gorm.DB) error {
+	if sm.ID == uuid.Nil {
+		sm.ID = uuid.New()
+	}
+	now := time.Now()
+	if sm.CreatedAt.IsZero() {
+		sm.CreatedAt = now
+	}
+	if sm.UpdatedAt.IsZero() {
+		sm.UpdatedAt = now
+	}
+	return nil
+}
+
+// DeriveContainerMilestones picks the actual and the estimated time of each milestone from a
+// container's events. Milestones without any matching event are left out.
+func DeriveContainerMilestones(containerID uuid.UUID, events []ContainerEvent) []ContainerMilestone {
+	byMilestone := map[Milestone]*ContainerMilestone{}
+
+	for _, event := range events {
+		eventCode := ""
+		if event.EventCode != nil {
+			eventCode = *event.EventCode
+		}
+		milestone, ok := NormalizeMilestone(eventCode, event.Description)
+		if !ok {
+			continue
+		}
+
+		cm, ok := byMilestone[milestone]
+		if !ok {
+			cm = &ContainerMilestone{ContainerID: containerID, Milestone: milestone}
+			byMilestone[milestone] = cm
+		}
+
+		date := event.Date
+		locationID := event.LocationID
+		if event.IsActual {
+			if pickEvent(milestone, cm.ActualAt, date) {
+				cm.ActualAt = &date
+				cm.LocationID = &locationID
+			}
+		} else if pickEvent(milestone, cm.EstimatedAt, date) {
+			cm.EstimatedAt = &date
+			if cm.ActualAt == nil {
+				cm.LocationID = &locationID
+			}
+		}
+	}
+
+	milestones := make([]ContainerMilestone, 0, len(byMilestone))
+	for _, milestone := range Milestones() {
+		if cm, ok := byMilestone[milestone]; ok {
+			milestones = append(milestones, *cm)
+		}
+	}
+	return milestones
+}
+
+// DeriveShipmentMilestones combines the milestones of a shipment's containers. A shipment
+// reaches a milestone once every container that reports it did, until then the latest
+// estimate of the containers still pending is its estimate.
+func DeriveShipmentMilestones(shipmentID uuid.UUID, containerMilestones []ContainerMilestone) []ShipmentMilestone {
+	byMilestone := map[Milestone]*ShipmentMilestone{}
+	pending := map[Milestone]bool{}
+
+	for _, cm := range containerMilestones {
+		sm, ok := byMilestone[cm.Milestone]
+		if !ok {
+			sm = &ShipmentMilestone{ShipmentID: shipmentID, Milestone: cm.Milestone}
+			byMilestone[cm.Milestone] = sm
+		}
+
+		if cm.ActualAt == nil {
+			pending[cm.Milestone] = true
+			if cm.EstimatedAt != nil && (sm.EstimatedAt == nil || cm.EstimatedAt.After(*sm.EstimatedAt)) {
+				sm.EstimatedAt = cm.EstimatedAt
+			}
+			continue
+		}
+		if sm.ActualAt == nil || cm.ActualAt.After(*sm.ActualAt) {
+			sm.ActualAt = cm.ActualAt
+		}
+	}
+
+	milestones := make([]ShipmentMilestone, 0, len(byMilestone))
+	for _, milestone := range Milestones() {
+		sm, ok := byMilestone[milestone]
+		if !ok {
+			continue
+		}
+		if pending[milestone] {
+			sm.ActualAt = nil
+		}
+		milestones = append(milestones, *sm)
+	}
+	return milestones
+}
+
+// pickEvent reports whether an event at date replaces the current time of a milestone
+func pickEvent(milestone Milestone, current *time.Time, date time.Time) bool {
+	if current == nil {
+		return true
+	}
+	if milestone.takesLastEvent() {
+		return date.After(*current)
+	}
+	return date.Before(*current)
+}
+
+func containsAny(s string, substrings ...string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
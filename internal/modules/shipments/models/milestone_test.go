@@ -0,0 +1,104 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizeMilestone(t *testing.T) {
+	tests := []struct {
+		eventCode   string
+		description string
+		want        Milestone
+		ok          bool
+	}{
+		{"CEP", "Empty to shipper", MilestoneEmptyPickup, true},
+		{"CGI", "Gate in at first POL", MilestoneGateIn, true},
+		{"cll", "", MilestoneLoaded, true},
+		{"VDL", "Vessel departure", MilestoneDeparted, true},
+		{"CDT", "Discharge at T/S port", MilestoneTransshipment, true},
+		{"VAD", "Vessel arrival at POD", MilestoneArrived, true},
+		{"DISC", "Discharged", MilestoneDischarged, true},
+		{"GTOT", "Gate out", MilestoneGateOut, true},
+		{"GTOT", "Gate out empty", MilestoneEmptyPickup, true},
+		{"GTIN", "Empty container gate in", MilestoneEmptyReturn, true},
+		{"", "Empty container returned", MilestoneEmptyReturn, true},
+		{"", "Transshipment loaded", MilestoneTransshipment, true},
+		{"", "Gate out from terminal", MilestoneGateOut, true},
+		{"", "Unloaded from vessel", MilestoneDischarged, true},
+		{"", "Loaded on board", MilestoneLoaded, true},
+		{"XYZ", "Vessel berthed", MilestoneArrived, true},
+		{"", "Customs release", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := NormalizeMilestone(tt.eventCode, tt.description)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("NormalizeMilestone(%q, %q) = %s, %v, want %s, %v", tt.eventCode, tt.description, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDeriveContainerMilestones(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 5, d, 0, 0, 0, 0, time.UTC) }
+	code := func(c string) *string { return &c }
+	containerID := uuid.New()
+
+	events := []ContainerEvent{
+		{EventCode: code("CLL"), Date: day(2), IsActual: true},
+		{EventCode: code("LOAD"), Date: day(12), IsActual: true},
+		{EventCode: code("DISC"), Date: day(10), IsActual: true},
+		{EventCode: code("DISC"), Date: day(20)},
+		{EventCode: code("GTOT"), Date: day(22)},
+		{Description: "Customs release", Date: day(21)},
+	}
+
+	got := DeriveContainerMilestones(containerID, events)
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 milestones, got %d", len(got))
+	}
+
+	loaded, discharged, gateOut := got[0], got[1], got[2]
+	if loaded.Milestone != MilestoneLoaded || !loaded.ActualAt.Equal(day(2)) {
+		t.Errorf("Expected loaded on the first loading, got %+v", loaded)
+	}
+	if discharged.Milestone != MilestoneDischarged || !discharged.ActualAt.Equal(day(10)) || !discharged.EstimatedAt.Equal(day(20)) {
+		t.Errorf("Expected discharged actual and estimate, got %+v", discharged)
+	}
+	if gateOut.Milestone != MilestoneGateOut || gateOut.ActualAt != nil || !gateOut.EstimatedAt.Equal(day(22)) {
+		t.Errorf("Expected estimated gate out, got %+v", gateOut)
+	}
+	for _, milestone := range got {
+		if milestone.ContainerID != containerID {
+			t.Errorf("Expected container ID %s, got %s", containerID, milestone.ContainerID)
+		}
+	}
+}
+
+func TestDeriveShipmentMilestones(t *testing.T) {
+	day := func(d int) *time.Time {
+		date := time.Date(2025, 5, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+
+	got := DeriveShipmentMilestones(uuid.New(), []ContainerMilestone{
+		{Milestone: MilestoneDischarged, ActualAt: day(10)},
+		{Milestone: MilestoneDischarged, ActualAt: day(11)},
+		{Milestone: MilestoneGateOut, ActualAt: day(12)},
+		{Milestone: MilestoneGateOut, EstimatedAt: day(14)},
+		{Milestone: MilestoneGateOut, EstimatedAt: day(13)},
+	})
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 milestones, got %d", len(got))
+	}
+
+	discharged, gateOut := got[0], got[1]
+	if !discharged.ActualAt.Equal(*day(11)) {
+		t.Errorf("Expected shipment discharged with its last container, got %+v", discharged)
+	}
+	if gateOut.ActualAt != nil || !gateOut.EstimatedAt.Equal(*day(14)) {
+		t.Errorf("Expected gate out pending with the latest estimate, got %+v", gateOut)
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PendingSync records a shipment sync that was interrupted by a shutdown.
+// Pending syncs are requeued ahead of all other refresh work on the next start.
+type PendingSync struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	ShipmentID uuid.UUID `json:"shipment_id" gorm:"type:uuid;not null;uniqueIndex"`
+	Reason     string    `json:"reason" gorm:"type:varchar(255)"`
+	CreatedAt  time.Time `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+
+	Shipment Shipment `gorm:"foreignKey:ShipmentID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for PendingSync
+func (PendingSync) TableName() string {
+	return "pending_syncs"
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (ps *PendingSync) BeforeCreate(tx *gorm.DB) error {
+	if ps.ID == uuid.Nil {
+		ps.ID = uuid.New()
+	}
+	if ps.CreatedAt.IsZero() {
+		ps.CreatedAt = time.Now()
+	}
+	return nil
+}
@@ -19,6 +19,16 @@ type Shipment struct {
 	UpdatedAt      time.Time      `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
 	Warnings       pq.StringArray `json:"warnings" gorm:"type:text[];default:'{}'"`
 
+	// Tracking lifecycle, shipping status holds the normalized ShipmentStatus
+	ProviderStatus    string     `json:"provider_status" gorm:"type:varchar(50)"`
+	TrackingState     string     `json:"tracking_state" gorm:"type:varchar(20);not null;default:'active';index"`
+	TerminalSince     *time.Time `json:"terminal_since" gorm:"type:timestamptz"`
+	TrackingStoppedAt *time.Time `json:"tracking_stopped_at" gorm:"type:timestamptz"`
+
+	// Adaptive refresh scheduling
+	NextRefreshAt *time.Time `json:"next_refresh_at" gorm:"type:timestamptz;index"`
+	RefreshTier   string     `json:"refresh_tier" gorm:"type:varchar(20)"`
+
 	// Shipment information
 	Consignee        string `json:"consignee" gorm:"type:varchar(255)"`
 	Recipient        string `json:"recipient" gorm:"type:varchar(255)"`
@@ -59,6 +69,9 @@ func (s *Shipment) BeforeCreate(tx *gorm.DB) error {
 	if len(s.Warnings) == 0 {
 		s.Warnings = pq.StringArray{}
 	}
+	if s.TrackingState == "" {
+		s.TrackingState = TrackingStateActive
+	}
 	return nil
 }
 
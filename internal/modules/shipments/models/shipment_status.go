@@ -0,0 +1,126 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ShipmentStatus is the normalized shipping status stored on a shipment.
+// Provider values are free strings, NormalizeShipmentStatus maps them onto these.
+type ShipmentStatus string
+
+const (
+	ShipmentStatusPlanned       ShipmentStatus = "PLANNED"
+	ShipmentStatusInTransit     ShipmentStatus = "IN_TRANSIT"
+	ShipmentStatusDelivered     ShipmentStatus = "DELIVERED"
+	ShipmentStatusEmptyReturned ShipmentStatus = "EMPTY_RETURNED"
+	ShipmentStatusCancelled     ShipmentStatus = "CANCELLED"
+	ShipmentStatusUnknown       ShipmentStatus = "UNKNOWN"
+)
+
+// Tracking states of a shipment
+const (
+	TrackingStateActive    = "active"
+	TrackingStateCompleted = "completed"
+)
+
+// providerStatusAliases maps known provider spellings, after normalizeStatusKey, to a status.
+// ARRIVED is arrival at the POD, discharge, gate-out and empty return are still ahead.
+var providerStatusAliases = map[string]ShipmentStatus{
+	"PLANNED":        ShipmentStatusPlanned,
+	"BOOKED":         ShipmentStatusPlanned,
+	"BOOKING":        ShipmentStatusPlanned,
+	"PENDING":        ShipmentStatusPlanned,
+	"IN_TRANSIT":     ShipmentStatusInTransit,
+	"INTRANSIT":      ShipmentStatusInTransit,
+	"TRANSIT":        ShipmentStatusInTransit,
+	"SAILING":        ShipmentStatusInTransit,
+	"LOADED":         ShipmentStatusInTransit,
+	"DISCHARGED":     ShipmentStatusInTransit,
+	"ARRIVED":        ShipmentStatusInTransit,
+	"DELIVERED":      ShipmentStatusDelivered,
+	"COMPLETED":      ShipmentStatusDelivered,
+	"EMPTY_RETURNED": ShipmentStatusEmptyReturned,
+	"EMPTY_RETURN":   ShipmentStatusEmptyReturned,
+	"RETURNED_EMPTY": ShipmentStatusEmptyReturned,
+	"CANCELLED":      ShipmentStatusCancelled,
+	"CANCELED":       ShipmentStatusCancelled,
+	"UNKNOWN":        ShipmentStatusUnknown,
+}
+
+// NormalizeShipmentStatus maps a provider status such as "delivered", "In Transit"
+// or "EMPTY-RETURNED" to a ShipmentStatus. Unrecognized values become UNKNOWN.
+func NormalizeShipmentStatus(providerStatus string) ShipmentStatus {
+	if status, ok := providerStatusAliases[normalizeStatusKey(providerStatus)]; ok {
+		return status
+	}
+	return ShipmentStatusUnknown
+}
+
+// IsTerminal reports whether the shipment has reached a state it will not leave on its own
+func (s ShipmentStatus) IsTerminal() bool {
+	switch s {
+	case ShipmentStatusDelivered, ShipmentStatusEmptyReturned, ShipmentStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// TerminalShipmentStatuses returns all terminal statuses, e.g. for use in queries
+func TerminalShipmentStatuses() []string {
+	return []string{
+		string(ShipmentStatusDelivered),
+		string(ShipmentStatusEmptyReturned),
+		string(ShipmentStatusCancelled),
+	}
+}
+
+// normalizeStatusKey upper-cases the value and turns spaces and dashes into underscores
+func normalizeStatusKey(value string) string {
+	key := strings.ToUpper(strings.TrimSpace(value))
+	key = strings.NewReplacer(" ", "_", "-", "_").Replace(key)
+	return key
+}
+
+// NormalizeExistingShipmentStatuses converts statuses stored before normalization was introduced.
+// The original value is kept in provider_status, shipments that already have one are left alone.
+func NormalizeExistingShipmentStatuses(db *gorm.DB) error {
+	var rawStatuses []string
+	err := db.Model(&Shipment{}).
+		Where("provider_status IS NULL OR provider_status = ''").
+		Distinct().
+		Pluck("shipping_status", &rawStatuses).Error
+	if err != nil {
+		return err
+	}
+
+	for _, raw := range rawStatuses {
+		err := db.Model(&Shipment{}).
+			Where("shipping_status = ? AND (provider_status IS NULL OR provider_status = '')", raw).
+			UpdateColumns(map[string]any{
+				"provider_status": raw,
+				"shipping_status": string(NormalizeShipmentStatus(raw)),
+			}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReopenArrivedShipments resumes tracking of shipments whose provider status ARRIVED was stored
+// as DELIVERED, arrival at the POD used to be taken for delivery
+func ReopenArrivedShipments(db *gorm.DB) error {
+	return db.Model(&Shipment{}).
+		Where("UPPER(provider_status) = ? AND shipping_status = ?", "ARRIVED", string(ShipmentStatusDelivered)).
+		UpdateColumns(map[string]any{
+			"shipping_status":     string(ShipmentStatusInTransit),
+			"terminal_since":      nil,
+			"tracking_state":      TrackingStateActive,
+			"tracking_stopped_at": nil,
+			"next_refresh_at":     time.Now(),
+		}).Error
+}
@@ -0,0 +1,44 @@
+package models
+
+import "testing"
+
+func TestNormalizeShipmentStatus(t *testing.T) {
+	tests := []struct {
+		provider string
+		want     ShipmentStatus
+	}{
+		{"DELIVERED", ShipmentStatusDelivered},
+		{"delivered", ShipmentStatusDelivered},
+		{"In Transit", ShipmentStatusInTransit},
+		{"IN_TRANSIT", ShipmentStatusInTransit},
+		{"Arrived", ShipmentStatusInTransit},
+		{"planned", ShipmentStatusPlanned},
+		{"Empty-Returned", ShipmentStatusEmptyReturned},
+		{"canceled", ShipmentStatusCancelled},
+		{"", ShipmentStatusUnknown},
+		{"something new", ShipmentStatusUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeShipmentStatus(tt.provider); got != tt.want {
+			t.Errorf("NormalizeShipmentStatus(%q) = %s, want %s", tt.provider, got, tt.want)
+		}
+	}
+}
+
+func TestShipmentStatus_IsTerminal(t *testing.T) {
+	terminal := map[ShipmentStatus]bool{
+		ShipmentStatusPlanned:       false,
+		ShipmentStatusInTransit:     false,
+		ShipmentStatusUnknown:       false,
+		ShipmentStatusDelivered:     true,
+		ShipmentStatusEmptyReturned: true,
+		ShipmentStatusCancelled:     true,
+	}
+
+	for status, want := range terminal {
+		if got := status.IsTerminal(); got != want {
+			t.Errorf("%s.IsTerminal() = %v, want %v", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// UnLocode is an entry of the official UN/LOCODE code list, the reference locations are
+// identified by and enriched from
+type UnLocode struct {
+	Locode           string    `json:"locode" gorm:"type:varchar(5);primaryKey"`
+	CountryCode      string    `json:"country_code" gorm:"type:varchar(2);not null;index"`
+	Name             string    `json:"name" gorm:"type:varchar(100);not null"`
+	NameWoDiacritics string    `json:"name_wo_diacritics" gorm:"type:varchar(100)"`
+	Subdivision      string    `json:"subdivision" gorm:"type:varchar(3)"`
+	Status           string    `json:"status" gorm:"type:varchar(2)"`
+	Function         string    `json:"function" gorm:"type:varchar(8)"`
+	IATA             string    `json:"iata" gorm:"type:varchar(3)"`
+	Latitude         *float64  `json:"latitude" gorm:"type:decimal(10,8)"`
+	Longitude        *float64  `json:"longitude" gorm:"type:decimal(11,8)"`
+	Remarks          string    `json:"remarks" gorm:"type:varchar(255)"`
+	ImportedAt       time.Time `json:"imported_at" gorm:"type:timestamptz;not null"`
+}
+
+// TableName specifies the table name for UnLocode
+func (UnLocode) TableName() string {
+	return "un_locodes"
+}
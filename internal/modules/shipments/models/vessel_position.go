@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VesselPosition is an AIS position report of a vessel. Unlike Ais, which only holds the
+// latest position of a shipment, positions are appended on every sync and shared by all
+// shipments on board, a report is stored once per vessel and timestamp.
+type VesselPosition struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Imo        int       `gorm:"not null;uniqueIndex:idx_vessel_position;index"`
+	Mmsi       int       `gorm:"not null;uniqueIndex:idx_vessel_position;index"`
+	RecordedAt time.Time `gorm:"type:timestamptz;not null;uniqueIndex:idx_vessel_position"`
+	Latitude   float64   `gorm:"type:decimal(10,8);not null"`
+	Longitude  float64   `gorm:"type:decimal(11,8);not null"`
+	CreatedAt  time.Time `gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+func (VesselPosition) TableName() string {
+	return "vessel_positions"
+}
+
+func (vp *VesselPosition) BeforeCreate(tx *gorm.DB) error {
+	if vp.ID == uuid.Nil {
+		vp.ID = uuid.New()
+	}
+	if vp.CreatedAt.IsZero() {
+		vp.CreatedAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VoyageLeg is one sea leg of a shipment, from the port a vessel loaded the cargo to the
+// port it discharged it. A shipment with transshipments has several legs in LegOrder.
+type VoyageLeg struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	ShipmentID      uuid.UUID  `gorm:"type:uuid;not null;index"`
+	LegOrder        int        `gorm:"not null"`
+	VesselID        *uuid.UUID `gorm:"type:uuid;index"`
+	Voyage          string     `gorm:"type:varchar(255)"`
+	FromLocationID  uuid.UUID  `gorm:"type:uuid;not null"`
+	ToLocationID    uuid.UUID  `gorm:"type:uuid;not null"`
+	DepartureAt     *time.Time `gorm:"type:timestamptz"`
+	DepartureActual bool       `gorm:"type:boolean;default:false"`
+	ArrivalAt       *time.Time `gorm:"type:timestamptz"`
+	ArrivalActual   bool       `gorm:"type:boolean;default:false"`
+	CreatedAt       time.Time  `gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time  `gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+
+	Shipment     Shipment `gorm:"foreignKey:ShipmentID;constraint:OnDelete:CASCADE"`
+	Vessel       *Vessel  `gorm:"foreignKey:VesselID"`
+	FromLocation Location `gorm:"foreignKey:FromLocationID"`
+	ToLocation   Location `gorm:"foreignKey:ToLocationID"`
+}
+
+func (VoyageLeg) TableName() string {
+	return "voyage_legs"
+}
+
+func (vl *VoyageLeg) BeforeCreate(tx *gorm.DB) error {
+	if vl.ID == uuid.Nil {
+		vl.ID = uuid.New()
+	}
+	now := time.Now()
+	if vl.CreatedAt.IsZero() {
+		vl.CreatedAt = now
+	}
+	if vl.UpdatedAt.IsZero() {
+		vl.UpdatedAt = now
+	}
+	return nil
+}
+
+// DeriveVoyageLegs splits the vessel events of each container into legs, a new leg starts
+// whenever the vessel or the voyage changes. Containers travelling together share legs,
+// so identical legs are merged, preferring actual times over estimates.
+func DeriveVoyageLegs(shipmentID uuid.UUID, eventsByContainer map[uuid.UUID][]ContainerEvent) []VoyageLeg {
+	var legs []VoyageLeg
+
+	for _, events := range eventsByContainer {
+		for _, leg := range containerVoyageLegs(shipmentID, events) {
+			legs = mergeVoyageLeg(legs, leg)
+		}
+	}
+
+	sort.SliceStable(legs, func(i, j int) bool {
+		a, b := legs[i].DepartureAt, legs[j].DepartureAt
+		switch {
+		case a == nil:
+			return false
+		case b == nil:
+			return true
+		}
+		return a.Before(*b)
+	})
+	for i := range legs {
+		legs[i].LegOrder = i + 1
+	}
+
+	return legs
+}
+
+// containerVoyageLegs derives the legs of a single container. Events without a vessel or a
+// voyage happen on land and are skipped, as are runs of events that never leave a port.
+func containerVoyageLegs(shipmentID uuid.UUID, events []ContainerEvent) []VoyageLeg {
+	sorted := make([]ContainerEvent, 0, len(events))
+	for _, event := range events {
+		if event.VesselID != nil || voyageOf(event) != "" {
+			sorted = append(sorted, event)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	var legs []VoyageLeg
+	for start := 0; start < len(sorted); {
+		end := start + 1
+		for end < len(sorted) && sameVoyage(sorted[start], sorted[end]) {
+			end++
+		}
+		if leg, ok := voyageLeg(shipmentID, sorted[start:end]); ok {
+			legs = append(legs, leg)
+		}
+		start = end
+	}
+	return legs
+}
+
+// voyageLeg builds a leg from the events of one vessel and voyage. The vessel departs with
+// the last event at the first port and arrives with the first event at the last port.
+func voyageLeg(shipmentID uuid.UUID, events []ContainerEvent) (VoyageLeg, bool) {
+	from, to := events[0].LocationID, events[len(events)-1].LocationID
+	if from == to {
+		return VoyageLeg{}, false
+	}
+
+	leg := VoyageLeg{
+		ShipmentID:     shipmentID,
+		VesselID:       events[0].VesselID,
+		Voyage:         voyageOf(events[0]),
+		FromLocationID: from,
+		ToLocationID:   to,
+	}
+
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].LocationID == from {
+			date := events[i].Date
+			leg.DepartureAt = &date
+			leg.DepartureActual = events[i].IsActual
+			break
+		}
+	}
+	for _, event := range events {
+		if event.LocationID == to {
+			date := event.Date
+			leg.ArrivalAt = &date
+			leg.ArrivalActual = event.IsActual
+			break
+		}
+	}
+
+	return leg, true
+}
+
+// mergeVoyageLeg adds a leg unless another container already reported it, in which case
+// the actual times of either are kept
+func mergeVoyageLeg(legs []VoyageLeg, leg VoyageLeg) []VoyageLeg {
+	for i := range legs {
+		existing := &legs[i]
+		if !sameVessel(existing.VesselID, leg.VesselID) || existing.Voyage != leg.Voyage ||
+			existing.FromLocationID != leg.FromLocationID || existing.ToLocationID != leg.ToLocationID {
+			continue
+		}
+		if leg.DepartureAt != nil && (existing.DepartureAt == nil || leg.DepartureActual && !existing.DepartureActual) {
+			existing.DepartureAt, existing.DepartureActual = leg.DepartureAt, leg.DepartureActual
+		}
+		if leg.ArrivalAt != nil && (existing.ArrivalAt == nil || leg.ArrivalActual && !existing.ArrivalActual) {
+			existing.ArrivalAt, existing.ArrivalActual = leg.ArrivalAt, leg.ArrivalActual
+		}
+		return legs
+	}
+	return append(legs, leg)
+}
+
+// sameVoyage reports whether two events belong to the same vessel and voyage
+func sameVoyage(a, b ContainerEvent) bool {
+	return sameVessel(a.VesselID, b.VesselID) && voyageOf(a) == voyageOf(b)
+}
+
+func sameVessel(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func voyageOf(event ContainerEvent) string {
+	if event.Voyage == nil {
+		return ""
+	}
+	return *event.Voyage
+}
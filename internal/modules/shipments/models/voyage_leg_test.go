@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDeriveVoyageLegs(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 5, d, 0, 0, 0, 0, time.UTC) }
+	voyage := func(v string) *string { return &v }
+	shipmentID := uuid.New()
+	shanghai, singapore, rotterdam := uuid.New(), uuid.New(), uuid.New()
+	mother, feeder := uuid.New(), uuid.New()
+
+	first := []ContainerEvent{
+		{LocationID: shanghai, Description: "Gate in", Date: day(1), IsActual: true},
+		{LocationID: shanghai, VesselID: &feeder, Voyage: voyage("F12"), Date: day(2), IsActual: true},
+		{LocationID: shanghai, VesselID: &feeder, Voyage: voyage("F12"), Date: day(3), IsActual: true},
+		{LocationID: singapore, VesselID: &feeder, Voyage: voyage("F12"), Date: day(8), IsActual: true},
+		{LocationID: singapore, VesselID: &feeder, Voyage: voyage("F12"), Date: day(9), IsActual: true},
+		{LocationID: singapore, VesselID: &mother, Voyage: voyage("M7"), Date: day(12)},
+		{LocationID: rotterdam, VesselID: &mother, Voyage: voyage("M7"), Date: day(30)},
+		{LocationID: rotterdam, Description: "Gate out", Date: day(31)},
+	}
+	// The second container reports the same legs, the mother vessel already loaded it
+	second := []ContainerEvent{
+		{LocationID: singapore, VesselID: &mother, Voyage: voyage("M7"), Date: day(13), IsActual: true},
+		{LocationID: shanghai, VesselID: &feeder, Voyage: voyage("F12"), Date: day(3), IsActual: true},
+		{LocationID: singapore, VesselID: &feeder, Voyage: voyage("F12"), Date: day(8), IsActual: true},
+		{LocationID: rotterdam, VesselID: &mother, Voyage: voyage("M7"), Date: day(29)},
+	}
+
+	got := DeriveVoyageLegs(shipmentID, map[uuid.UUID][]ContainerEvent{uuid.New(): first, uuid.New(): second})
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 legs, got %d", len(got))
+	}
+
+	feederLeg, motherLeg := got[0], got[1]
+	if feederLeg.LegOrder != 1 || *feederLeg.VesselID != feeder || feederLeg.Voyage != "F12" ||
+		feederLeg.FromLocationID != shanghai || feederLeg.ToLocationID != singapore {
+		t.Errorf("Unexpected first leg %+v", feederLeg)
+	}
+	if !feederLeg.DepartureAt.Equal(day(3)) || !feederLeg.DepartureActual || !feederLeg.ArrivalAt.Equal(day(8)) || !feederLeg.ArrivalActual {
+		t.Errorf("Expected the feeder to depart on day 3 and arrive on day 8, got %v and %v", feederLeg.DepartureAt, feederLeg.ArrivalAt)
+	}
+
+	if motherLeg.LegOrder != 2 || *motherLeg.VesselID != mother || motherLeg.FromLocationID != singapore || motherLeg.ToLocationID != rotterdam {
+		t.Errorf("Unexpected second leg %+v", motherLeg)
+	}
+	if !motherLeg.DepartureAt.Equal(day(13)) || !motherLeg.DepartureActual {
+		t.Errorf("Expected the actual departure of the second container, got %v actual %v", motherLeg.DepartureAt, motherLeg.DepartureActual)
+	}
+	if motherLeg.ArrivalActual {
+		t.Errorf("Expected an estimated arrival, got an actual one")
+	}
+}
+
+func TestDeriveVoyageLegsSkipsPortStays(t *testing.T) {
+	voyage := "V1"
+	vessel, port := uuid.New(), uuid.New()
+
+	events := []ContainerEvent{
+		{LocationID: port, VesselID: &vessel, Voyage: &voyage, Date: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{LocationID: port, VesselID: &vessel, Voyage: &voyage, Date: time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	if got := DeriveVoyageLegs(uuid.New(), map[uuid.UUID][]ContainerEvent{uuid.New(): events}); len(got) != 0 {
+		t.Errorf("Expected no legs for a vessel that never left the port, got %d", len(got))
+	}
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go-starter/internal/modules/shipments/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetCarriers returns the carrier registry in SCAC order
+func (r *shipmentRepository) GetCarriers(ctx context.Context) ([]models.Carrier, error) {
+	var carriers []models.Carrier
+	if err := r.db.DB.WithContext(ctx).Order("scac").Find(&carriers).Error; err != nil {
+		return nil, fmt.Errorf("failed to get carriers: %w", err)
+	}
+	return carriers, nil
+}
+
+// UpdateSealineCode sets the sealine code of a shipment that was stored without one
+func (r *shipmentRepository) UpdateSealineCode(ctx context.Context, shipmentID uuid.UUID, sealineCode string) error {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.Shipment{}).
+		Where("id = ?", shipmentID).
+		Updates(map[string]interface{}{
+			"sealine_code": sealineCode,
+			"updated_at":   gorm.Expr("NOW()"),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update sealine code: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
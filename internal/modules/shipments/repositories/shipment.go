@@ -2,16 +2,21 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"go-starter/internal/modules/shipments/dto"
 	"go-starter/internal/modules/shipments/models"
+	"go-starter/internal/modules/shipments/types"
 	"go-starter/pkg/db"
+	"go-starter/pkg/geo"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ShipmentRepository interface {
@@ -25,6 +30,7 @@ type ShipmentRepository interface {
 	CheckShipmentExists(ctx context.Context, shipmentNumber string) (bool, error)
 	AddExistingShipmentToUser(ctx context.Context, userID uuid.UUID, shipmentNumber string) (*models.Shipment, error)
 	UpdateShipment(ctx context.Context, id uuid.UUID, shipment *models.Shipment) (*models.Shipment, error)
+	LockShipmentForSync(ctx context.Context, shipmentID uuid.UUID) (*models.Shipment, error)
 
 	CreateLocation(ctx context.Context, shipmentID *uuid.UUID, location *models.Location) (*models.Location, error)
 	FindLocationByLocode(ctx context.Context, locode string) (*models.Location, error)
@@ -42,6 +48,16 @@ type ShipmentRepository interface {
 
 	CreateContainer(ctx context.Context, shipmentID *uuid.UUID, container *models.Container) (*models.Container, error)
 	CreateContainerEvent(ctx context.Context, containerEvent *models.ContainerEvent) (*models.ContainerEvent, error)
+	ReplaceContainerMilestones(ctx context.Context, containerID uuid.UUID, milestones []models.ContainerMilestone) error
+	ReplaceShipmentMilestones(ctx context.Context, shipmentID uuid.UUID, milestones []models.ShipmentMilestone) error
+	ReplaceVoyageLegs(ctx context.Context, shipmentID uuid.UUID, legs []models.VoyageLeg) error
+	GetVoyageLegs(ctx context.Context, shipmentID uuid.UUID) ([]models.VoyageLeg, error)
+	CreateVesselPosition(ctx context.Context, position *models.VesselPosition) (bool, error)
+	GetVesselPositions(ctx context.Context, imo, mmsi int, from, to *time.Time) ([]models.VesselPosition, error)
+
+	GetMapPositions(ctx context.Context, userID uuid.UUID, bbox geo.BBox) ([]MapPosition, error)
+	GetMapPorts(ctx context.Context, userID uuid.UUID, bbox geo.BBox) ([]models.Location, error)
+	GetMapRouteSegments(ctx context.Context, userID uuid.UUID, bbox geo.BBox) ([]models.RouteSegment, error)
 
 	CreateRouteSegment(ctx context.Context, routeSegment *models.RouteSegment) (*models.RouteSegment, error)
 	CreateRouteSegmentPoint(ctx context.Context, point *models.RouteSegmentPoint) (*models.RouteSegmentPoint, error)
@@ -67,9 +83,25 @@ type ShipmentRepository interface {
 	GetShipmentDataSummary(ctx context.Context, shipmentID uuid.UUID) (*ShipmentDataSummary, error)
 
 	GetShipmentsForGrid(ctx context.Context, userID uuid.UUID) ([]models.Shipment, error)
+	GetShipmentsForGridByIDs(ctx context.Context, userID uuid.UUID, shipmentIDs []uuid.UUID) ([]models.Shipment, error)
+	GetShipmentsByContainer(ctx context.Context, userID uuid.UUID, number string, partial bool) ([]dto.ShipmentDetailsResponse, error)
+	GetShipmentsByVessel(ctx context.Context, userID uuid.UUID, imo int) ([]dto.ShipmentDetailsResponse, error)
+	GetShipmentsWithRunningClocks(ctx context.Context, userID uuid.UUID) ([]dto.ShipmentDetailsResponse, error)
+	GetShipmentsDepartedBetween(ctx context.Context, userID uuid.UUID, from, to *time.Time) ([]dto.ShipmentDetailsResponse, error)
 	DeleteUserShipment(ctx context.Context, userID, shipmentID uuid.UUID) error
 	BulkDeleteUserShipments(ctx context.Context, userID uuid.UUID, shipmentIDs []uuid.UUID) error
 	GetAllShipmentsForRefresh(ctx context.Context, skipRecentlyUpdated time.Duration) ([]ShipmentForRefresh, error)
+	GetRefreshSignals(ctx context.Context, shipmentID uuid.UUID) (*RefreshSignals, error)
+	GetShipmentSnapshot(ctx context.Context, shipmentID uuid.UUID) (*types.ShipmentSnapshot, error)
+	UpdateRefreshSchedule(ctx context.Context, shipmentID uuid.UUID, nextRefreshAt time.Time, tier string) error
+	StopShipmentTracking(ctx context.Context, shipmentID uuid.UUID, stoppedAt time.Time) error
+	ResumeShipmentTracking(ctx context.Context, shipmentID uuid.UUID) error
+
+	RecordPendingSyncs(ctx context.Context, shipmentIDs []uuid.UUID, reason string) error
+	RequeuePendingSyncs(ctx context.Context) (int64, error)
+
+	GetCarriers(ctx context.Context) ([]models.Carrier, error)
+	UpdateSealineCode(ctx context.Context, shipmentID uuid.UUID, sealineCode string) error
 }
 
 type shipmentRepository struct {
@@ -86,6 +118,14 @@ func NewShipmentRepository(db *db.Database) ShipmentRepository {
 		log.Printf("failed to fix shipment route constraint: %s", err)
 	}
 
+	// Move free-form provider statuses into provider_status and normalize shipping_status
+	if err := models.NormalizeExistingShipmentStatuses(db.DB); err != nil {
+		log.Printf("failed to normalize shipment statuses: %s", err)
+	}
+	if err := models.ReopenArrivedShipments(db.DB); err != nil {
+		log.Printf("failed to reopen arrived shipments: %s", err)
+	}
+
 	return &shipmentRepository{
 		db: db,
 	}
@@ -221,6 +261,12 @@ func (r *shipmentRepository) UpdateShipment(ctx context.Context, id uuid.UUID, u
 func (r *shipmentRepository) CreateLocation(ctx context.Context, shipmentID *uuid.UUID, location *models.Location) (*models.Location, error) {
 	db := r.getDBFromContext(ctx)
 
+	// Keep what the provider reported apart from the official UN/LOCODE data
+	location.ProviderName = location.Name
+	if err := r.enrichLocation(ctx, db, location); err != nil {
+		return nil, err
+	}
+
 	// Try to find existing location by locode
 	var existingLocation models.Location
 	err := db.WithContext(ctx).Where("locode = ?", location.Locode).First(&existingLocation).Error
@@ -244,6 +290,10 @@ func (r *shipmentRepository) CreateLocation(ctx context.Context, shipmentID *uui
 		existingLocation.Latitude = location.Latitude
 		existingLocation.Longitude = location.Longitude
 		existingLocation.Timezone = location.Timezone
+		existingLocation.ProviderName = location.ProviderName
+		existingLocation.Subdivision = location.Subdivision
+		existingLocation.FunctionCodes = location.FunctionCodes
+		existingLocation.Mismatches = location.Mismatches
 
 		err = db.WithContext(ctx).Save(&existingLocation).Error
 		if err != nil {
@@ -267,6 +317,28 @@ func (r *shipmentRepository) CreateLocation(ctx context.Context, shipmentID *uui
 	return location, nil
 }
 
+// enrichLocation fills a location in from its UN/LOCODE reference entry, locations without
+// one are left as the provider reported them
+func (r *shipmentRepository) enrichLocation(ctx context.Context, db *gorm.DB, location *models.Location) error {
+	if location.Locode == "" {
+		return nil
+	}
+
+	var references []models.UnLocode
+	err := db.WithContext(ctx).
+		Where("locode = ?", strings.ToUpper(location.Locode)).
+		Limit(1).
+		Find(&references).Error
+	if err != nil {
+		return fmt.Errorf("failed to look up UN/LOCODE %s: %w", location.Locode, err)
+	}
+
+	if len(references) > 0 {
+		location.EnrichFromReference(&references[0])
+	}
+	return nil
+}
+
 func (r *shipmentRepository) FindLocationByLocode(ctx context.Context, locode string) (*models.Location, error) {
 	var location models.Location
 	err := r.db.DB.WithContext(ctx).Where("locode = ?", locode).First(&location).Error
@@ -508,6 +580,191 @@ func (r *shipmentRepository) CreateContainerEvent(ctx context.Context, container
 	return containerEvent, nil
 }
 
+// ReplaceContainerMilestones replaces the milestones of a container with freshly derived ones
+func (r *shipmentRepository) ReplaceContainerMilestones(ctx context.Context, containerID uuid.UUID, milestones []models.ContainerMilestone) error {
+	db := r.getDBFromContext(ctx)
+	if err := db.WithContext(ctx).Where("container_id = ?", containerID).Delete(&models.ContainerMilestone{}).Error; err != nil {
+		return fmt.Errorf("failed to delete container milestones: %w", err)
+	}
+	if len(milestones) == 0 {
+		return nil
+	}
+	if err := db.WithContext(ctx).Create(&milestones).Error; err != nil {
+		return fmt.Errorf("failed to create container milestones: %w", err)
+	}
+	return nil
+}
+
+// ReplaceShipmentMilestones replaces the milestones of a shipment with freshly derived ones
+func (r *shipmentRepository) ReplaceShipmentMilestones(ctx context.Context, shipmentID uuid.UUID, milestones []models.ShipmentMilestone) error {
+	db := r.getDBFromContext(ctx)
+	if err := db.WithContext(ctx).Where("shipment_id = ?", shipmentID).Delete(&models.ShipmentMilestone{}).Error; err != nil {
+		return fmt.Errorf("failed to delete shipment milestones: %w", err)
+	}
+	if len(milestones) == 0 {
+		return nil
+	}
+	if err := db.WithContext(ctx).Create(&milestones).Error; err != nil {
+		return fmt.Errorf("failed to create shipment milestones: %w", err)
+	}
+	return nil
+}
+
+// ReplaceVoyageLegs replaces the voyage legs of a shipment with freshly derived ones
+func (r *shipmentRepository) ReplaceVoyageLegs(ctx context.Context, shipmentID uuid.UUID, legs []models.VoyageLeg) error {
+	db := r.getDBFromContext(ctx)
+	if err := db.WithContext(ctx).Where("shipment_id = ?", shipmentID).Delete(&models.VoyageLeg{}).Error; err != nil {
+		return fmt.Errorf("failed to delete voyage legs: %w", err)
+	}
+	if len(legs) == 0 {
+		return nil
+	}
+	if err := db.WithContext(ctx).Create(&legs).Error; err != nil {
+		return fmt.Errorf("failed to create voyage legs: %w", err)
+	}
+	return nil
+}
+
+// GetVoyageLegs returns the voyage legs of a shipment with their vessels in sailing order
+func (r *shipmentRepository) GetVoyageLegs(ctx context.Context, shipmentID uuid.UUID) ([]models.VoyageLeg, error) {
+	var legs []models.VoyageLeg
+	err := r.getDBFromContext(ctx).WithContext(ctx).
+		Preload("Vessel").
+		Where("shipment_id = ?", shipmentID).
+		Order("leg_order ASC").
+		Find(&legs).Error
+	if err != nil {
+		return nil, err
+	}
+	return legs, nil
+}
+
+// CreateVesselPosition appends a position to a vessel's history, a position the vessel
+// already reported at the same time is ignored. It reports whether the position was new.
+func (r *shipmentRepository) CreateVesselPosition(ctx context.Context, position *models.VesselPosition) (bool, error) {
+	result := r.getDBFromContext(ctx).WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(position)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetVesselPositions returns the positions of a vessel in time order, from and to are optional
+func (r *shipmentRepository) GetVesselPositions(ctx context.Context, imo, mmsi int, from, to *time.Time) ([]models.VesselPosition, error) {
+	query := r.db.DB.WithContext(ctx).Where("imo = ? AND mmsi = ?", imo, mmsi)
+	if from != nil {
+		query = query.Where("recorded_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("recorded_at <= ?", *to)
+	}
+
+	var positions []models.VesselPosition
+	if err := query.Order("recorded_at ASC").Find(&positions).Error; err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// MapPosition is the latest position of one of the user's shipments
+type MapPosition struct {
+	ShipmentID     uuid.UUID
+	ShipmentNumber string
+	ShippingStatus string
+	Latitude       float64
+	Longitude      float64
+}
+
+// GetMapPositions returns the latest position of each of the user's shipments inside the bounding box
+func (r *shipmentRepository) GetMapPositions(ctx context.Context, userID uuid.UUID, bbox geo.BBox) ([]MapPosition, error) {
+	db := r.db.DB.WithContext(ctx)
+
+	latest := db.Model(&models.Coordinate{}).
+		Select("DISTINCT ON (coordinates.shipment_id) coordinates.shipment_id, s.shipment_number, s.shipping_status, "+
+			"coordinates.latitude, coordinates.longitude").
+		Joins("JOIN shipments s ON s.id = coordinates.shipment_id").
+		Joins("JOIN user_shipments us ON us.shipment_id = coordinates.shipment_id").
+		Where("us.user_id = ?", userID).
+		Order("coordinates.shipment_id, coordinates.updated_at DESC")
+
+	condition, args := bboxCondition("latest.latitude", "latest.longitude", bbox)
+
+	var positions []MapPosition
+	err := db.Table("(?) AS latest", latest).
+		Where(condition, args...).
+		Where("NOT (latest.latitude = 0 AND latest.longitude = 0)").
+		Order("latest.shipment_number ASC").
+		Scan(&positions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get map positions: %w", err)
+	}
+	return positions, nil
+}
+
+// GetMapPorts returns the distinct locations of the user's shipments inside the bounding box
+func (r *shipmentRepository) GetMapPorts(ctx context.Context, userID uuid.UUID, bbox geo.BBox) ([]models.Location, error) {
+	condition, args := bboxCondition("locations.latitude", "locations.longitude", bbox)
+
+	var locations []models.Location
+	err := r.db.DB.WithContext(ctx).
+		Where("locations.id IN (?)", r.db.DB.Table("shipment_locations sl").
+			Select("sl.location_id").
+			Joins("JOIN user_shipments us ON us.shipment_id = sl.shipment_id").
+			Where("us.user_id = ?", userID)).
+		Where(condition, args...).
+		Where("NOT (locations.latitude = 0 AND locations.longitude = 0)").
+		Order("locations.locode ASC").
+		Find(&locations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get map ports: %w", err)
+	}
+	return locations, nil
+}
+
+// GetMapRouteSegments returns the route segments of the user's shipments whose extent intersects
+// the bounding box, with their points in order
+func (r *shipmentRepository) GetMapRouteSegments(ctx context.Context, userID uuid.UUID, bbox geo.BBox) ([]models.RouteSegment, error) {
+	lngOperator := "AND"
+	if bbox.CrossesAntimeridian() {
+		lngOperator = "OR"
+	}
+
+	intersecting := r.db.DB.Table("route_segment_points p").
+		Select("p.segment_id").
+		Joins("JOIN route_segments rs ON rs.id = p.segment_id").
+		Joins("JOIN user_shipments us ON us.shipment_id = rs.shipment_id").
+		Where("us.user_id = ?", userID).
+		Group("p.segment_id").
+		Having("MAX(p.latitude) >= ? AND MIN(p.latitude) <= ?", bbox.MinLat, bbox.MaxLat).
+		Having(fmt.Sprintf("(MAX(p.longitude) >= ? %s MIN(p.longitude) <= ?)", lngOperator), bbox.MinLng, bbox.MaxLng)
+
+	var segments []models.RouteSegment
+	err := r.db.DB.WithContext(ctx).
+		Preload("Points", func(db *gorm.DB) *gorm.DB {
+			return db.Order("point_order ASC")
+		}).
+		Where("id IN (?)", intersecting).
+		Order("shipment_id, segment_order ASC").
+		Find(&segments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get map route segments: %w", err)
+	}
+	return segments, nil
+}
+
+// bboxCondition builds a where clause matching coordinates inside the bounding box
+func bboxCondition(latColumn, lngColumn string, bbox geo.BBox) (string, []interface{}) {
+	lngOperator := "AND"
+	if bbox.CrossesAntimeridian() {
+		lngOperator = "OR"
+	}
+
+	condition := fmt.Sprintf("%s BETWEEN ? AND ? AND (%s >= ? %s %s <= ?)", latColumn, lngColumn, lngOperator, lngColumn)
+	return condition, []interface{}{bbox.MinLat, bbox.MaxLat, bbox.MinLng, bbox.MaxLng}
+}
+
 func (r *shipmentRepository) CreateRouteSegment(ctx context.Context, routeSegment *models.RouteSegment) (*models.RouteSegment, error) {
 	db := r.getDBFromContext(ctx)
 	err := db.WithContext(ctx).Where(routeSegment).FirstOrCreate(routeSegment).Error
@@ -643,6 +900,16 @@ func (r *shipmentRepository) GetShipmentDetails(ctx context.Context, userID, shi
 		return nil, err
 	}
 
+	milestones, err := r.getShipmentMilestones(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	voyageLegs, err := r.getShipmentVoyageLegs(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &dto.ShipmentDetailsResponse{
 		ID:               shipment.ID,
 		ShipmentType:     shipment.ShipmentType,
@@ -674,6 +941,13 @@ func (r *shipmentRepository) GetShipmentDetails(ctx context.Context, userID, shi
 		Facilities:       facilities,
 		Containers:       containers,
 		RouteData:        routeData,
+		Milestones:       milestones,
+		VoyageLegs:       voyageLegs,
+
+		ProviderStatus:    shipment.ProviderStatus,
+		TrackingState:     shipment.TrackingState,
+		TerminalSince:     shipment.TerminalSince,
+		TrackingStoppedAt: shipment.TrackingStoppedAt,
 	}, nil
 }
 
@@ -755,12 +1029,18 @@ func (r *shipmentRepository) getShipmentContainers(ctx context.Context, shipment
 			return nil, err
 		}
 
+		milestones, err := r.getContainerMilestones(ctx, container.ID)
+		if err != nil {
+			return nil, err
+		}
+
 		containersResponse[i] = dto.ShipmentContainerResponse{
-			Number:   container.Number,
-			IsoCode:  container.IsoCode,
-			SizeType: container.SizeType,
-			Status:   container.Status,
-			Events:   containerEvents,
+			Number:     container.Number,
+			IsoCode:    container.IsoCode,
+			SizeType:   container.SizeType,
+			Status:     container.Status,
+			Events:     containerEvents,
+			Milestones: milestones,
 		}
 	}
 
@@ -789,6 +1069,117 @@ func (r *shipmentRepository) getContainerEvents(ctx context.Context, containerID
 	return eventResponses, nil
 }
 
+// getContainerMilestones fetches and converts container milestones in journey order
+func (r *shipmentRepository) getContainerMilestones(ctx context.Context, containerID uuid.UUID) ([]dto.ShipmentMilestoneResponse, error) {
+	var milestones []models.ContainerMilestone
+	err := r.db.DB.WithContext(ctx).
+		Preload("Location").
+		Where("container_id = ?", containerID).
+		Find(&milestones).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byMilestone := make(map[models.Milestone]models.ContainerMilestone, len(milestones))
+	for _, milestone := range milestones {
+		byMilestone[milestone.Milestone] = milestone
+	}
+
+	milestonesResponse := make([]dto.ShipmentMilestoneResponse, 0, len(milestones))
+	for _, key := range models.Milestones() {
+		milestone, ok := byMilestone[key]
+		if !ok {
+			continue
+		}
+		response := dto.ShipmentMilestoneResponse{
+			Milestone:   string(milestone.Milestone),
+			ActualAt:    milestone.ActualAt,
+			EstimatedAt: milestone.EstimatedAt,
+		}
+		if milestone.Location != nil {
+			response.Locode = milestone.Location.Locode
+		}
+		milestonesResponse = append(milestonesResponse, response)
+	}
+
+	return milestonesResponse, nil
+}
+
+// getShipmentMilestones fetches and converts shipment milestones in journey order
+func (r *shipmentRepository) getShipmentMilestones(ctx context.Context, shipmentID uuid.UUID) ([]dto.ShipmentMilestoneResponse, error) {
+	var milestones []models.ShipmentMilestone
+	err := r.db.DB.WithContext(ctx).
+		Where("shipment_id = ?", shipmentID).
+		Find(&milestones).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byMilestone := make(map[models.Milestone]models.ShipmentMilestone, len(milestones))
+	for _, milestone := range milestones {
+		byMilestone[milestone.Milestone] = milestone
+	}
+
+	milestonesResponse := make([]dto.ShipmentMilestoneResponse, 0, len(milestones))
+	for _, key := range models.Milestones() {
+		if milestone, ok := byMilestone[key]; ok {
+			milestonesResponse = append(milestonesResponse, dto.ShipmentMilestoneResponse{
+				Milestone:   string(milestone.Milestone),
+				ActualAt:    milestone.ActualAt,
+				EstimatedAt: milestone.EstimatedAt,
+			})
+		}
+	}
+
+	return milestonesResponse, nil
+}
+
+// getShipmentVoyageLegs fetches and converts the voyage legs of a shipment in sailing order.
+// The first leg starts at the port of loading, the last one ends at the port of discharge,
+// every port in between is a transshipment port.
+func (r *shipmentRepository) getShipmentVoyageLegs(ctx context.Context, shipmentID uuid.UUID) ([]dto.ShipmentVoyageLegResponse, error) {
+	var legs []models.VoyageLeg
+	err := r.db.DB.WithContext(ctx).
+		Preload("Vessel").
+		Preload("FromLocation").
+		Preload("ToLocation").
+		Where("shipment_id = ?", shipmentID).
+		Order("leg_order ASC").
+		Find(&legs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	legResponses := make([]dto.ShipmentVoyageLegResponse, len(legs))
+	for i, leg := range legs {
+		legResponse := dto.ShipmentVoyageLegResponse{
+			Sequence:        leg.LegOrder,
+			Voyage:          leg.Voyage,
+			From:            r.convertLocationToDTO(leg.FromLocation),
+			FromRole:        dto.LegPortTransshipment,
+			To:              r.convertLocationToDTO(leg.ToLocation),
+			ToRole:          dto.LegPortTransshipment,
+			DepartureAt:     leg.DepartureAt,
+			DepartureActual: leg.DepartureActual,
+			ArrivalAt:       leg.ArrivalAt,
+			ArrivalActual:   leg.ArrivalActual,
+		}
+		if leg.Vessel != nil {
+			vessel := r.convertVesselToDTO(*leg.Vessel)
+			legResponse.Vessel = &vessel
+		}
+		if i == 0 {
+			legResponse.FromRole = dto.LegPortPol
+		}
+		if i == len(legs)-1 {
+			legResponse.ToRole = dto.LegPortPod
+		}
+		legResponses[i] = legResponse
+	}
+
+	return legResponses, nil
+}
+
 // convertContainerEventToDTO converts a container event to DTO with related data
 func (r *shipmentRepository) convertContainerEventToDTO(ctx context.Context, event models.ContainerEvent) (dto.ShipmentContainerEventResponse, error) {
 	location, err := r.FindLocationByID(ctx, event.LocationID)
@@ -938,14 +1329,17 @@ func (r *shipmentRepository) convertLocationsToDTO(locations []models.Location)
 
 func (r *shipmentRepository) convertLocationToDTO(location models.Location) dto.ShipmentLocationResponse {
 	return dto.ShipmentLocationResponse{
-		Name:        location.Name,
-		State:       location.State,
-		Country:     location.Country,
-		CountryCode: location.CountryCode,
-		Locode:      location.Locode,
-		Latitude:    location.Latitude,
-		Longitude:   location.Longitude,
-		Timezone:    location.Timezone,
+		Name:          location.Name,
+		State:         location.State,
+		Country:       location.Country,
+		CountryCode:   location.CountryCode,
+		Locode:        location.Locode,
+		Latitude:      location.Latitude,
+		Longitude:     location.Longitude,
+		Timezone:      location.Timezone,
+		Subdivision:   location.Subdivision,
+		FunctionCodes: location.FunctionCodes,
+		Mismatches:    location.Mismatches,
 	}
 }
 
@@ -1333,6 +1727,341 @@ func (r *shipmentRepository) GetShipmentsForGrid(ctx context.Context, userID uui
 	return shipments, nil
 }
 
+// GetShipmentsForGridByIDs returns the user's shipments among shipmentIDs
+func (r *shipmentRepository) GetShipmentsForGridByIDs(ctx context.Context, userID uuid.UUID, shipmentIDs []uuid.UUID) ([]models.Shipment, error) {
+	var shipments []models.Shipment
+	err := r.db.DB.WithContext(ctx).Model(&models.Shipment{}).
+		Joins("JOIN user_shipments us ON us.shipment_id = shipments.id").
+		Where("us.user_id = ? AND shipments.id IN ?", userID, shipmentIDs).
+		Find(&shipments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch shipments: %w", err)
+	}
+
+	return shipments, nil
+}
+
+// containerNumberColumn is the container number without the spaces and dashes it may be
+// stored with, so it compares with normalized numbers
+const containerNumberColumn = "REPLACE(REPLACE(UPPER(containers.number), ' ', ''), '-', '')"
+
+// likeEscaper escapes the LIKE wildcards in a search term
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containerMatch is a container of a shipment a query picked
+type containerMatch struct {
+	ShipmentID  uuid.UUID
+	ContainerID uuid.UUID
+}
+
+// GetShipmentsByContainer loads the user's shipments holding a container with the number, or
+// with a number containing it when partial is set. Each shipment only carries its route and
+// the matching containers with their events.
+func (r *shipmentRepository) GetShipmentsByContainer(ctx context.Context, userID uuid.UUID, number string, partial bool) ([]dto.ShipmentDetailsResponse, error) {
+	condition, value := containerNumberColumn+" = ?", number
+	if partial {
+		condition, value = containerNumberColumn+" LIKE ?", "%"+likeEscaper.Replace(number)+"%"
+	}
+
+	var matches []containerMatch
+	err := r.db.DB.WithContext(ctx).
+		Table("shipment_containers sc").
+		Select("sc.shipment_id, sc.container_id").
+		Joins("JOIN containers ON containers.id = sc.container_id").
+		Joins("JOIN user_shipments us ON us.shipment_id = sc.shipment_id").
+		Where("us.user_id = ?", userID).
+		Where(condition, value).
+		Order("sc.added_at ASC").
+		Scan(&matches).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find container shipments: %w", err)
+	}
+
+	return r.getMatchedContainerShipments(ctx, matches, func(container *dto.ShipmentContainerResponse, containerID uuid.UUID) error {
+		events, err := r.getContainerEvents(ctx, containerID)
+		container.Events = events
+		return err
+	})
+}
+
+// GetShipmentsWithRunningClocks loads the user's shipments with containers whose demurrage or
+// detention clock runs: discharged but not gated out, or gated out but not returned empty, by
+// actual milestones. Each shipment only carries its route and those containers with their
+// milestones.
+func (r *shipmentRepository) GetShipmentsWithRunningClocks(ctx context.Context, userID uuid.UUID) ([]dto.ShipmentDetailsResponse, error) {
+	var matches []containerMatch
+	err := r.db.DB.WithContext(ctx).
+		Table("shipment_containers sc").
+		Select("sc.shipment_id, sc.container_id").
+		Joins("JOIN user_shipments us ON us.shipment_id = sc.shipment_id").
+		Where("us.user_id = ?", userID).
+		Where(`EXISTS (
+			SELECT 1 FROM container_milestones started
+			WHERE started.container_id = sc.container_id AND started.actual_at IS NOT NULL
+				AND started.milestone IN (@discharged, @gate_out)
+				AND NOT EXISTS (
+					SELECT 1 FROM container_milestones stopped
+					WHERE stopped.container_id = sc.container_id AND stopped.actual_at IS NOT NULL
+						AND stopped.milestone = CASE started.milestone WHEN @discharged THEN @gate_out ELSE @empty_return END
+				)
+		)`,
+			sql.Named("discharged", string(models.MilestoneDischarged)),
+			sql.Named("gate_out", string(models.MilestoneGateOut)),
+			sql.Named("empty_return", string(models.MilestoneEmptyReturn))).
+		Order("sc.added_at ASC").
+		Scan(&matches).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find containers with running clocks: %w", err)
+	}
+
+	return r.getMatchedContainerShipments(ctx, matches, func(container *dto.ShipmentContainerResponse, containerID uuid.UUID) error {
+		milestones, err := r.getContainerMilestones(ctx, containerID)
+		container.Milestones = milestones
+		return err
+	})
+}
+
+// getMatchedContainerShipments loads the shipments of the matches with their route and only
+// the matched containers, in the order of the matches. load adds what the caller needs of
+// each container.
+func (r *shipmentRepository) getMatchedContainerShipments(ctx context.Context, matches []containerMatch, load func(container *dto.ShipmentContainerResponse, containerID uuid.UUID) error) ([]dto.ShipmentDetailsResponse, error) {
+	if len(matches) == 0 {
+		return []dto.ShipmentDetailsResponse{}, nil
+	}
+	db := r.db.DB.WithContext(ctx)
+
+	containerIDs := map[uuid.UUID][]uuid.UUID{}
+	shipmentIDs := []uuid.UUID{}
+	for _, match := range matches {
+		if _, ok := containerIDs[match.ShipmentID]; !ok {
+			shipmentIDs = append(shipmentIDs, match.ShipmentID)
+		}
+		containerIDs[match.ShipmentID] = append(containerIDs[match.ShipmentID], match.ContainerID)
+	}
+
+	var shipments []models.Shipment
+	if err := db.Where("id IN ?", shipmentIDs).Find(&shipments).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch shipments: %w", err)
+	}
+	shipmentsByID := make(map[uuid.UUID]models.Shipment, len(shipments))
+	for _, shipment := range shipments {
+		shipmentsByID[shipment.ID] = shipment
+	}
+
+	rows := make([]dto.ShipmentDetailsResponse, 0, len(shipmentIDs))
+	for _, shipmentID := range shipmentIDs {
+		shipment, ok := shipmentsByID[shipmentID]
+		if !ok {
+			continue
+		}
+
+		route, err := r.getShipmentRoute(ctx, shipment.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		var containers []models.Container
+		if err := db.Where("id IN ?", containerIDs[shipment.ID]).Find(&containers).Error; err != nil {
+			return nil, fmt.Errorf("failed to fetch containers: %w", err)
+		}
+		containersByID := make(map[uuid.UUID]models.Container, len(containers))
+		for _, container := range containers {
+			containersByID[container.ID] = container
+		}
+
+		containersResponse := make([]dto.ShipmentContainerResponse, 0, len(containers))
+		for _, containerID := range containerIDs[shipment.ID] {
+			container, ok := containersByID[containerID]
+			if !ok {
+				continue
+			}
+			response := dto.ShipmentContainerResponse{
+				Number:   container.Number,
+				IsoCode:  container.IsoCode,
+				SizeType: container.SizeType,
+				Status:   container.Status,
+			}
+			if err := load(&response, container.ID); err != nil {
+				return nil, err
+			}
+			containersResponse = append(containersResponse, response)
+		}
+
+		rows = append(rows, dto.ShipmentDetailsResponse{
+			ID:             shipment.ID,
+			ShipmentType:   shipment.ShipmentType,
+			ShipmentNumber: shipment.ShipmentNumber,
+			SealineCode:    shipment.SealineCode,
+			SealineName:    shipment.SealineName,
+			ShippingStatus: shipment.ShippingStatus,
+			CreatedAt:      shipment.CreatedAt,
+			UpdatedAt:      shipment.UpdatedAt,
+			Route:          route,
+			Containers:     containersResponse,
+		})
+	}
+
+	return rows, nil
+}
+
+// GetShipmentsByVessel loads the user's shipments with a voyage leg, an AIS snapshot or a
+// vessel entry on the vessel. Each shipment only carries what tells about its voyages on
+// vessels: its route, containers, vessels, voyage legs and latest AIS snapshot.
+func (r *shipmentRepository) GetShipmentsByVessel(ctx context.Context, userID uuid.UUID, imo int) ([]dto.ShipmentDetailsResponse, error) {
+	db := r.db.DB.WithContext(ctx)
+
+	var shipments []models.Shipment
+	err := db.Model(&models.Shipment{}).
+		Joins("JOIN user_shipments us ON us.shipment_id = shipments.id").
+		Where("us.user_id = ?", userID).
+		Where(`shipments.id IN (
+			SELECT vl.shipment_id FROM voyage_legs vl JOIN vessels v ON v.id = vl.vessel_id WHERE v.imo = @imo
+			UNION SELECT ais.shipment_id FROM ais JOIN vessels v ON v.id = ais.vessel_id WHERE v.imo = @imo
+			UNION SELECT sv.shipment_id FROM shipment_vessels sv JOIN vessels v ON v.id = sv.vessel_id WHERE v.imo = @imo
+		)`, sql.Named("imo", imo)).
+		Order("shipments.created_at ASC").
+		Find(&shipments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find vessel shipments: %w", err)
+	}
+
+	rows := make([]dto.ShipmentDetailsResponse, 0, len(shipments))
+	for _, shipment := range shipments {
+		route, err := r.getShipmentRoute(ctx, shipment.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		containers, err := r.getShipmentContainersWithoutEvents(ctx, shipment.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		vessels, err := r.getShipmentVessels(ctx, shipment.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		voyageLegs, err := r.getShipmentVoyageLegs(ctx, shipment.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		row := dto.ShipmentDetailsResponse{
+			ID:             shipment.ID,
+			ShipmentType:   shipment.ShipmentType,
+			ShipmentNumber: shipment.ShipmentNumber,
+			SealineCode:    shipment.SealineCode,
+			SealineName:    shipment.SealineName,
+			ShippingStatus: shipment.ShippingStatus,
+			CreatedAt:      shipment.CreatedAt,
+			UpdatedAt:      shipment.UpdatedAt,
+			Route:          route,
+			Containers:     containers,
+			Vessels:        vessels,
+			VoyageLegs:     voyageLegs,
+		}
+
+		ais, err := r.GetShipmentAisData(ctx, shipment.ID)
+		if err != nil {
+			return nil, err
+		}
+		if ais != nil {
+			row.RouteData.Ais = *ais
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// GetShipmentsDepartedBetween loads the user's shipments with route segments and containers
+// that left the port of loading, or were added when it has no date, from from up to to. Both
+// bounds are optional. Each shipment only carries its consignee, route, containers without
+// events and route segments.
+func (r *shipmentRepository) GetShipmentsDepartedBetween(ctx context.Context, userID uuid.UUID, from, to *time.Time) ([]dto.ShipmentDetailsResponse, error) {
+	departure := `COALESCE((SELECT sr.date FROM shipment_routes sr WHERE sr.shipment_id = shipments.id AND sr.route_type = 'POL'), shipments.created_at)`
+
+	query := r.db.DB.WithContext(ctx).Model(&models.Shipment{}).
+		Joins("JOIN user_shipments us ON us.shipment_id = shipments.id").
+		Where("us.user_id = ?", userID).
+		Where("EXISTS (SELECT 1 FROM route_segments rs WHERE rs.shipment_id = shipments.id)").
+		Where("EXISTS (SELECT 1 FROM shipment_containers sc WHERE sc.shipment_id = shipments.id)")
+	if from != nil {
+		query = query.Where(departure+" >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where(departure+" < ?", *to)
+	}
+
+	var shipments []models.Shipment
+	if err := query.Order("shipments.created_at ASC").Find(&shipments).Error; err != nil {
+		return nil, fmt.Errorf("failed to find departed shipments: %w", err)
+	}
+
+	rows := make([]dto.ShipmentDetailsResponse, 0, len(shipments))
+	for _, shipment := range shipments {
+		route, err := r.getShipmentRoute(ctx, shipment.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		containers, err := r.getShipmentContainersWithoutEvents(ctx, shipment.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		routeSegments, err := r.getShipmentRouteSegments(ctx, shipment.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		rows = append(rows, dto.ShipmentDetailsResponse{
+			ID:             shipment.ID,
+			ShipmentType:   shipment.ShipmentType,
+			ShipmentNumber: shipment.ShipmentNumber,
+			SealineCode:    shipment.SealineCode,
+			SealineName:    shipment.SealineName,
+			ShippingStatus: shipment.ShippingStatus,
+			CreatedAt:      shipment.CreatedAt,
+			UpdatedAt:      shipment.UpdatedAt,
+			Consignee:      shipment.Consignee,
+			Route:          route,
+			Containers:     containers,
+			RouteData:      dto.ShipmentRouteDataResponse{RouteSegments: routeSegments},
+		})
+	}
+
+	return rows, nil
+}
+
+// getShipmentContainersWithoutEvents fetches and converts shipment containers without their
+// events and milestones
+func (r *shipmentRepository) getShipmentContainersWithoutEvents(ctx context.Context, shipmentID uuid.UUID) ([]dto.ShipmentContainerResponse, error) {
+	var containers []models.Container
+	err := r.db.DB.WithContext(ctx).
+		Joins("JOIN shipment_containers sc ON sc.container_id = containers.id").
+		Where("sc.shipment_id = ?", shipmentID).
+		Order("sc.added_at ASC").
+		Find(&containers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch containers: %w", err)
+	}
+
+	containersResponse := make([]dto.ShipmentContainerResponse, len(containers))
+	for i, container := range containers {
+		containersResponse[i] = dto.ShipmentContainerResponse{
+			Number:   container.Number,
+			IsoCode:  container.IsoCode,
+			SizeType: container.SizeType,
+			Status:   container.Status,
+		}
+	}
+
+	return containersResponse, nil
+}
+
 func (r *shipmentRepository) UpdateShipmentInfo(ctx context.Context, userID, shipmentID uuid.UUID, req *dto.UpdateShipmentInfoRequest) error {
 	db := r.db.DB.WithContext(ctx)
 
@@ -1469,24 +2198,327 @@ type ShipmentForRefresh struct {
 	models.Shipment
 }
 
-// GetAllShipmentsForRefresh gets all shipments that need refreshing for background processing
+// GetAllShipmentsForRefresh gets all shipments whose next refresh is due, most overdue first
 func (r *shipmentRepository) GetAllShipmentsForRefresh(ctx context.Context, skipRecentlyUpdated time.Duration) ([]ShipmentForRefresh, error) {
 	db := r.getDBFromContext(ctx)
 
 	var results []ShipmentForRefresh
 
+	now := time.Now()
 	query := db.Table("shipments").
-		Where("shipping_status != ?", "DELIVERED")
+		Where("tracking_state = ?", models.TrackingStateActive).
+		Where("next_refresh_at IS NULL OR next_refresh_at <= ?", now)
 
 	// Skip recently updated shipments if configured
 	if skipRecentlyUpdated > 0 {
-		cutoffTime := time.Now().Add(-skipRecentlyUpdated)
+		cutoffTime := now.Add(-skipRecentlyUpdated)
 		query = query.Where("updated_at < ?", cutoffTime)
 	}
 
+	query = query.Order("next_refresh_at ASC NULLS FIRST")
+
 	if err := query.Find(&results).Error; err != nil {
 		return nil, fmt.Errorf("failed to fetch shipments for refresh: %w", err)
 	}
 
 	return results, nil
 }
+
+// RefreshSignals holds the shipment state used to schedule its next refresh
+type RefreshSignals struct {
+	Terminal        bool
+	PodETA          *time.Time
+	PodActual       bool
+	VesselDeparture *time.Time
+	VesselArrival   *time.Time
+	LastEventAt     *time.Time
+}
+
+// GetRefreshSignals collects the terminal flag, POD ETA, vessel departure/arrival and the latest actual event for a shipment
+func (r *shipmentRepository) GetRefreshSignals(ctx context.Context, shipmentID uuid.UUID) (*RefreshSignals, error) {
+	if shipmentID == uuid.Nil {
+		return nil, fmt.Errorf("invalid shipment ID: cannot be nil")
+	}
+
+	db := r.getDBFromContext(ctx)
+	signals := &RefreshSignals{}
+
+	var shipment models.Shipment
+	if err := db.WithContext(ctx).Select("shipping_status").First(&shipment, "id = ?", shipmentID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get shipment: %w", err)
+	}
+	signals.Terminal = models.ShipmentStatus(shipment.ShippingStatus).IsTerminal()
+
+	var pod models.ShipmentRoute
+	err := db.WithContext(ctx).
+		Where("shipment_id = ? AND route_type = ?", shipmentID, "POD").
+		First(&pod).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get POD route: %w", err)
+	}
+	if err == nil {
+		signals.PodETA = pod.Date
+		if pod.PredictiveETA != nil {
+			signals.PodETA = pod.PredictiveETA
+		}
+		signals.PodActual = pod.Actual != nil && *pod.Actual
+	}
+
+	var ais models.Ais
+	err = db.WithContext(ctx).
+		Where("shipment_id = ?", shipmentID).
+		Order("updated_at DESC").
+		First(&ais).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get AIS data: %w", err)
+	}
+	if err == nil {
+		signals.VesselDeparture = ais.DeparturePortDate
+		signals.VesselArrival = ais.ArrivalPortDate
+	}
+
+	var lastEventAt *time.Time
+	err = db.WithContext(ctx).
+		Model(&models.ContainerEvent{}).
+		Select("MAX(container_events.date)").
+		Joins("JOIN shipment_containers sc ON sc.container_id = container_events.container_id").
+		Where("sc.shipment_id = ? AND container_events.is_actual = ?", shipmentID, true).
+		Scan(&lastEventAt).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last container event: %w", err)
+	}
+	signals.LastEventAt = lastEventAt
+
+	return signals, nil
+}
+
+// GetShipmentSnapshot captures the tracking state of a shipment, including its actual container events
+func (r *shipmentRepository) GetShipmentSnapshot(ctx context.Context, shipmentID uuid.UUID) (*types.ShipmentSnapshot, error) {
+	db := r.getDBFromContext(ctx)
+
+	var shipment models.Shipment
+	if err := db.WithContext(ctx).First(&shipment, "id = ?", shipmentID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get shipment: %w", err)
+	}
+
+	signals, err := r.GetRefreshSignals(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &types.ShipmentSnapshot{
+		ShipmentID:     shipment.ID,
+		ShipmentNumber: shipment.ShipmentNumber,
+		ShippingStatus: shipment.ShippingStatus,
+		PodETA:         signals.PodETA,
+		PodActual:      signals.PodActual,
+		VesselArrival:  signals.VesselArrival,
+		LastEventAt:    signals.LastEventAt,
+	}
+
+	var podLocode string
+	err = db.WithContext(ctx).
+		Model(&models.ShipmentRoute{}).
+		Select("l.locode").
+		Joins("JOIN locations l ON l.id = shipment_routes.location_id").
+		Where("shipment_routes.shipment_id = ? AND shipment_routes.route_type = ?", shipmentID, "POD").
+		Limit(1).
+		Scan(&podLocode).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get POD location: %w", err)
+	}
+	snapshot.PodLocode = podLocode
+
+	var events []struct {
+		ContainerNumber string
+		EventCode       *string
+		Description     string
+		LocationName    string
+		Date            time.Time
+		IsActual        bool
+	}
+	err = db.WithContext(ctx).
+		Model(&models.ContainerEvent{}).
+		Select("c.number AS container_number, container_events.event_code, container_events.description, "+
+			"COALESCE(l.name, '') AS location_name, container_events.date, container_events.is_actual").
+		Joins("JOIN shipment_containers sc ON sc.container_id = container_events.container_id").
+		Joins("JOIN containers c ON c.id = container_events.container_id").
+		Joins("LEFT JOIN locations l ON l.id = container_events.location_id").
+		Where("sc.shipment_id = ?", shipmentID).
+		Order("container_events.date ASC").
+		Scan(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container events: %w", err)
+	}
+
+	snapshot.ContainerEvents = make([]types.ContainerEventSnapshot, 0, len(events))
+	for _, event := range events {
+		eventCode := ""
+		if event.EventCode != nil {
+			eventCode = *event.EventCode
+		}
+		snapshot.ContainerEvents = append(snapshot.ContainerEvents, types.ContainerEventSnapshot{
+			ContainerNumber: event.ContainerNumber,
+			EventCode:       eventCode,
+			Description:     event.Description,
+			LocationName:    event.LocationName,
+			Date:            event.Date,
+			IsActual:        event.IsActual,
+		})
+	}
+
+	var positions []types.VesselPositionSnapshot
+	err = db.WithContext(ctx).
+		Model(&models.Ais{}).
+		Select("COALESCE(v.name, '') AS vessel_name, COALESCE(v.imo, 0) AS imo, COALESCE(v.mmsi, 0) AS mmsi, "+
+			"ais.last_vessel_position_lat AS latitude, ais.last_vessel_position_lng AS longitude, "+
+			"ais.last_vessel_position_update AS recorded_at").
+		Joins("LEFT JOIN vessels v ON v.id = ais.vessel_id").
+		Where("ais.shipment_id = ?", shipmentID).
+		Where("ais.last_vessel_position_lat IS NOT NULL AND ais.last_vessel_position_lng IS NOT NULL").
+		Where("ais.last_vessel_position_update IS NOT NULL").
+		Order("ais.updated_at DESC").
+		Limit(1).
+		Scan(&positions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vessel position: %w", err)
+	}
+	if len(positions) > 0 {
+		snapshot.VesselPosition = &positions[0]
+	}
+
+	return snapshot, nil
+}
+
+// UpdateRefreshSchedule stores when a shipment should be refreshed next
+func (r *shipmentRepository) UpdateRefreshSchedule(ctx context.Context, shipmentID uuid.UUID, nextRefreshAt time.Time, tier string) error {
+	db := r.getDBFromContext(ctx)
+
+	// UpdateColumns keeps updated_at untouched so the schedule doesn't count as a data change
+	err := db.WithContext(ctx).
+		Model(&models.Shipment{}).
+		Where("id = ?", shipmentID).
+		UpdateColumns(map[string]interface{}{
+			"next_refresh_at": nextRefreshAt,
+			"refresh_tier":    tier,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update refresh schedule: %w", err)
+	}
+
+	return nil
+}
+
+// LockShipmentForSync loads the shipment and locks its row until the surrounding transaction ends.
+// It must be called with a transaction in the context, other instances syncing the
+// same shipment block here until the current sync has committed or rolled back.
+func (r *shipmentRepository) LockShipmentForSync(ctx context.Context, shipmentID uuid.UUID) (*models.Shipment, error) {
+	tx, ok := ctx.Value("tx").(*gorm.DB)
+	if !ok {
+		return nil, fmt.Errorf("locking shipment requires a transaction")
+	}
+
+	var shipment models.Shipment
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&shipment, "id = ?", shipmentID).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock shipment: %w", err)
+	}
+
+	return &shipment, nil
+}
+
+// StopShipmentTracking marks the shipment as completed and removes it from the refresh schedule
+func (r *shipmentRepository) StopShipmentTracking(ctx context.Context, shipmentID uuid.UUID, stoppedAt time.Time) error {
+	db := r.getDBFromContext(ctx)
+
+	err := db.WithContext(ctx).
+		Model(&models.Shipment{}).
+		Where("id = ?", shipmentID).
+		UpdateColumns(map[string]interface{}{
+			"tracking_state":      models.TrackingStateCompleted,
+			"tracking_stopped_at": stoppedAt,
+			"next_refresh_at":     nil,
+			"refresh_tier":        "",
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to stop shipment tracking: %w", err)
+	}
+
+	return nil
+}
+
+// ResumeShipmentTracking makes a completed shipment active again and due for refresh immediately.
+// terminal_since is cleared so the stop policy starts counting again from the next sync.
+func (r *shipmentRepository) ResumeShipmentTracking(ctx context.Context, shipmentID uuid.UUID) error {
+	db := r.getDBFromContext(ctx)
+
+	err := db.WithContext(ctx).
+		Model(&models.Shipment{}).
+		Where("id = ?", shipmentID).
+		UpdateColumns(map[string]interface{}{
+			"tracking_state":      models.TrackingStateActive,
+			"tracking_stopped_at": nil,
+			"terminal_since":      nil,
+			"next_refresh_at":     nil,
+			"refresh_tier":        "",
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to resume shipment tracking: %w", err)
+	}
+
+	return nil
+}
+
+// RecordPendingSyncs stores syncs that were interrupted so they can be resumed on the next start
+func (r *shipmentRepository) RecordPendingSyncs(ctx context.Context, shipmentIDs []uuid.UUID, reason string) error {
+	if len(shipmentIDs) == 0 {
+		return nil
+	}
+
+	db := r.getDBFromContext(ctx)
+
+	pending := make([]models.PendingSync, 0, len(shipmentIDs))
+	for _, shipmentID := range shipmentIDs {
+		pending = append(pending, models.PendingSync{
+			ShipmentID: shipmentID,
+			Reason:     reason,
+		})
+	}
+
+	err := db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&pending).Error
+	if err != nil {
+		return fmt.Errorf("failed to record pending syncs: %w", err)
+	}
+
+	return nil
+}
+
+// RequeuePendingSyncs makes all shipments with a pending sync due immediately and clears the records.
+// Due shipments without a schedule are picked up first by the refresh job.
+func (r *shipmentRepository) RequeuePendingSyncs(ctx context.Context) (int64, error) {
+	var requeued int64
+
+	err := r.getDBFromContext(ctx).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Shipment{}).
+			Where("id IN (?)", tx.Model(&models.PendingSync{}).Select("shipment_id")).
+			UpdateColumns(map[string]interface{}{
+				"next_refresh_at": nil,
+				"refresh_tier":    "",
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		requeued = result.RowsAffected
+
+		return tx.Where("1 = 1").Delete(&models.PendingSync{}).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue pending syncs: %w", err)
+	}
+
+	return requeued, nil
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go-starter/internal/modules/shipments/models"
+	"go-starter/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// unLocodeBatchSize bounds the rows of a single insert of the reference data
+const unLocodeBatchSize = 1000
+
+type UnLocodeRepository struct {
+	db *db.Database
+}
+
+func NewUnLocodeRepository(database *db.Database) *UnLocodeRepository {
+	return &UnLocodeRepository{
+		db: database,
+	}
+}
+
+// UpsertUnLocodes inserts reference entries, entries of an earlier release are replaced
+func (r *UnLocodeRepository) UpsertUnLocodes(ctx context.Context, entries []models.UnLocode) error {
+	return r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "locode"}},
+			UpdateAll: true,
+		}).
+		CreateInBatches(entries, unLocodeBatchSize).Error
+}
+
+// EnrichLocations enriches every location that has a reference entry from it. It returns
+// how many locations matched and how many of them were flagged with mismatches.
+func (r *UnLocodeRepository) EnrichLocations(ctx context.Context) (int, int, error) {
+	matched, flagged := 0, 0
+
+	var locations []models.Location
+	err := r.db.DB.WithContext(ctx).
+		Where("locode <> ''").
+		FindInBatches(&locations, unLocodeBatchSize, func(tx *gorm.DB, batch int) error {
+			locodes := make([]string, len(locations))
+			for i, location := range locations {
+				locodes[i] = strings.ToUpper(location.Locode)
+			}
+
+			var references []models.UnLocode
+			if err := r.db.DB.WithContext(ctx).Where("locode IN ?", locodes).Find(&references).Error; err != nil {
+				return err
+			}
+			byLocode := make(map[string]*models.UnLocode, len(references))
+			for i := range references {
+				byLocode[references[i].Locode] = &references[i]
+			}
+
+			for i := range locations {
+				location := &locations[i]
+				reference, ok := byLocode[strings.ToUpper(location.Locode)]
+				if !ok {
+					continue
+				}
+
+				location.EnrichFromReference(reference)
+				err := r.db.DB.WithContext(ctx).
+					Model(location).
+					Updates(map[string]interface{}{
+						"name":           location.Name,
+						"country_code":   location.CountryCode,
+						"latitude":       location.Latitude,
+						"longitude":      location.Longitude,
+						"provider_name":  location.ProviderName,
+						"subdivision":    location.Subdivision,
+						"function_codes": location.FunctionCodes,
+						"mismatches":     location.Mismatches,
+						"updated_at":     gorm.Expr("NOW()"),
+					}).Error
+				if err != nil {
+					return fmt.Errorf("failed to enrich location %s: %w", location.Locode, err)
+				}
+
+				matched++
+				if location.Mismatches != "" {
+					flagged++
+				}
+			}
+			return nil
+		}).Error
+
+	if err != nil {
+		return matched, flagged, err
+	}
+
+	return matched, flagged, nil
+}
@@ -25,7 +25,12 @@ func RegisterRoutes(e *echo.Echo, api *echo.Group, database *db.Database, cfg *c
 		rateLimiter,
 	)
 	shipmentRepository := shipmentRespositories.NewShipmentRepository(database)
-	shipmentService := shipmentServices.NewShipmentService(shipmentRepository, safeCubeAPIService)
+	shipmentService := shipmentServices.NewShipmentService(
+		shipmentRepository,
+		safeCubeAPIService,
+		shipmentServices.NewRefreshPolicyFromConfig(cfg.BackgroundJobs),
+		shipmentServices.NewTrackingPolicyFromConfig(cfg.BackgroundJobs),
+	)
 	shipmentAPIHandler := handlers.NewShipmentAPIHandler(shipmentService, safeCubeAPIService)
 
 	shipmentWEBHandler := handlers.NewShipmentWEBHandler(shipmentService)
@@ -35,14 +40,23 @@ func RegisterRoutes(e *echo.Echo, api *echo.Group, database *db.Database, cfg *c
 
 	shipmentsAPI.POST("", shipmentAPIHandler.AddShipment)
 	shipmentsAPI.GET("/grid-data", shipmentAPIHandler.GetShipmentsForGrid)
+	shipmentsAPI.GET("/map", shipmentAPIHandler.GetMapData)
 	shipmentsAPI.GET("/:id/details", shipmentAPIHandler.GetShipmentDetails)
 	shipmentsAPI.GET("/:id/details-html", shipmentWEBHandler.GetShipmentDetailsHTML)
+	shipmentsAPI.GET("/:id/track", shipmentAPIHandler.GetShipmentTrack)
 	shipmentsAPI.GET("/:id", shipmentAPIHandler.GetShipmentByID)
 	shipmentsAPI.POST("/:id/refresh", shipmentAPIHandler.RefreshShipment)
+	shipmentsAPI.POST("/:id/resume-tracking", shipmentAPIHandler.ResumeTracking)
 	shipmentsAPI.PATCH("/:id/update-info", shipmentAPIHandler.UpdateUserShipmentInfo)
 	shipmentsAPI.DELETE("/:id", shipmentAPIHandler.DeleteUserShipment)
 	shipmentsAPI.DELETE("/bulk-delete", shipmentAPIHandler.BulkDeleteUserShipments)
 
+	carriersAPI := api.Group("/carriers")
+	carriersAPI.Use(middlewares.JWTMiddleware(jwtService))
+
+	carriersAPI.GET("", shipmentAPIHandler.GetCarriers)
+	carriersAPI.GET("/detect", shipmentAPIHandler.DetectCarrier)
+
 	e.GET("/shipments", shipmentWEBHandler.ViewShipmentPage, middlewares.WebJWTMiddleware(jwtService))
 	e.GET("/map", shipmentWEBHandler.ViewMapPage, middlewares.WebJWTMiddleware(jwtService))
 }
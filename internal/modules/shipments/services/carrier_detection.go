@@ -0,0 +1,80 @@
+package services
+
+import (
+	"strings"
+
+	"go-starter/internal/modules/shipments/dto"
+	"go-starter/internal/modules/shipments/models"
+)
+
+// normalizeReference uppercases a shipment number and drops the spaces and dashes it is
+// often written with
+func normalizeReference(number string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.ToUpper(strings.TrimSpace(number)))
+}
+
+// DetectCarrier infers the carrier of a shipment number. Container numbers, given as type CT
+// or recognized by their shape when the type is empty, are matched by their owner prefix,
+// BL and booking numbers by their prefix or pattern. A number more than one carrier claims
+// is ambiguous and gives no carrier.
+func DetectCarrier(carriers []models.Carrier, shipmentNumber, shipmentType string) *models.Carrier {
+	number := normalizeReference(shipmentNumber)
+	if number == "" {
+		return nil
+	}
+	isContainer := shipmentType == "CT" || (shipmentType == "" && models.IsContainerNumber(number))
+
+	var detected *models.Carrier
+	for i := range carriers {
+		carrier := &carriers[i]
+		matches := carrier.MatchesReference(number)
+		if isContainer {
+			matches = carrier.OwnsContainer(number)
+		}
+		if !matches {
+			continue
+		}
+		if detected != nil {
+			return nil
+		}
+		detected = carrier
+	}
+	return detected
+}
+
+// CanonicalCarrier finds the carrier a sealine code or name refers to, by SCAC first and by
+// name or alias otherwise
+func CanonicalCarrier(carriers []models.Carrier, code, name string) *models.Carrier {
+	code = strings.TrimSpace(code)
+	if code != "" {
+		for i := range carriers {
+			if strings.EqualFold(carriers[i].Scac, code) {
+				return &carriers[i]
+			}
+		}
+		for i := range carriers {
+			if carriers[i].HasName(code) {
+				return &carriers[i]
+			}
+		}
+	}
+	for i := range carriers {
+		if carriers[i].HasName(name) {
+			return &carriers[i]
+		}
+	}
+	return nil
+}
+
+// applyCanonicalCarriers shows shipments under the canonical name of their carrier, so the
+// grid and its filters group a carrier's shipments whatever the provider called it
+func applyCanonicalCarriers(carriers []models.Carrier, rows []dto.ShipmentDetailsResponse) {
+	for i := range rows {
+		carrier := CanonicalCarrier(carriers, rows[i].SealineCode, rows[i].SealineName)
+		if carrier == nil {
+			continue
+		}
+		rows[i].SealineCode = carrier.Scac
+		rows[i].SealineName = carrier.Name
+	}
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"testing"
+
+	"go-starter/internal/modules/shipments/dto"
+	"go-starter/internal/modules/shipments/models"
+
+	"github.com/lib/pq"
+)
+
+func TestDetectCarrier(t *testing.T) {
+	carriers := models.DefaultCarriers()
+
+	tests := []struct {
+		name, number, shipmentType string
+		want                       string
+	}{
+		{"container owner prefix", "MEDU1234567", "CT", "MSCU"},
+		{"container shape without type", "msku 123456-7", "", "MAEU"},
+		{"unknown owner prefix", "TGHU1234567", "CT", ""},
+		{"BL with SCAC prefix", "HLCUHAM250312345", "BL", "HLCU"},
+		{"booking with owner prefix", "MEDUAB123456", "BK", "MSCU"},
+		{"numeric BL without a pattern", "251234567", "BL", ""},
+		{"unknown BL", "XYZ123", "BL", ""},
+		{"empty number", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectCarrier(carriers, tt.number, tt.shipmentType)
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("DetectCarrier(%q) = %s, want none", tt.number, got.Scac)
+				}
+				return
+			}
+			if got == nil || got.Scac != tt.want {
+				t.Errorf("DetectCarrier(%q) = %v, want %s", tt.number, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectCarrierAmbiguous(t *testing.T) {
+	carriers := []models.Carrier{
+		{Scac: "AAAA", BLPatterns: pq.StringArray{`^[0-9]{9}$`}},
+		{Scac: "BBBB", BLPatterns: pq.StringArray{`^[0-9]+$`, `[invalid`}},
+	}
+	for i := range carriers {
+		carriers[i].CompilePatterns()
+	}
+
+	if got := DetectCarrier(carriers, "123456789", "BL"); got != nil {
+		t.Errorf("DetectCarrier = %s, want none for a number two carriers claim", got.Scac)
+	}
+	if got := DetectCarrier(carriers, "1234", "BL"); got == nil || got.Scac != "BBBB" {
+		t.Errorf("DetectCarrier = %v, want BBBB", got)
+	}
+}
+
+func TestApplyCanonicalCarriers(t *testing.T) {
+	rows := []dto.ShipmentDetailsResponse{
+		{SealineCode: "MSCU", SealineName: "MEDITERRANEAN SHIPPING CO"},
+		{SealineName: "hapag lloyd"},
+		{SealineCode: "CMA"},
+		{SealineCode: "XXXX", SealineName: "Unknown Line"},
+	}
+
+	applyCanonicalCarriers(models.DefaultCarriers(), rows)
+
+	want := []struct{ code, name string }{
+		{"MSCU", "MSC"},
+		{"HLCU", "Hapag-Lloyd"},
+		{"CMDU", "CMA CGM"},
+		{"XXXX", "Unknown Line"},
+	}
+	for i, w := range want {
+		if rows[i].SealineCode != w.code || rows[i].SealineName != w.name {
+			t.Errorf("row %d = %s %q, want %s %q", i, rows[i].SealineCode, rows[i].SealineName, w.code, w.name)
+		}
+	}
+}
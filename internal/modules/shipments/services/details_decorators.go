@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"go-starter/internal/modules/shipments/dto"
+
+	"github.com/google/uuid"
+)
+
+// DetailsDecorator adds data owned by other modules to shipment details before they are
+// returned to a user, both for the details view and for the grid
+type DetailsDecorator func(ctx context.Context, userID uuid.UUID, rows []dto.ShipmentDetailsResponse)
+
+var detailsDecorators = &detailsDecoratorRegistry{}
+
+type detailsDecoratorRegistry struct {
+	mu         sync.RWMutex
+	decorators []DetailsDecorator
+}
+
+// RegisterDetailsDecorator adds a decorator that is called for every shipment details response
+func RegisterDetailsDecorator(decorator DetailsDecorator) {
+	detailsDecorators.mu.Lock()
+	defer detailsDecorators.mu.Unlock()
+	detailsDecorators.decorators = append(detailsDecorators.decorators, decorator)
+}
+
+// decorate calls all decorators in registration order. A panicking decorator is logged and
+// the rows are returned with whatever the others added.
+func (r *detailsDecoratorRegistry) decorate(ctx context.Context, userID uuid.UUID, rows []dto.ShipmentDetailsResponse) {
+	r.mu.RLock()
+	decorators := append([]DetailsDecorator(nil), r.decorators...)
+	r.mu.RUnlock()
+
+	for _, decorator := range decorators {
+		func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					log.Printf("Warning: Details decorator panicked for user %s: %v", userID, rec)
+				}
+			}()
+			decorator(ctx, userID, rows)
+		}()
+	}
+}
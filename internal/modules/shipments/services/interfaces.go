@@ -5,6 +5,8 @@ import (
 	"go-starter/internal/modules/shipments/dto"
 	shipmentsDto "go-starter/internal/modules/shipments/dto"
 	"go-starter/internal/modules/shipments/models"
+	"go-starter/pkg/geo"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -14,14 +16,25 @@ type ShipmentService interface {
 	GetShipmentByNumber(ctx context.Context, userID uuid.UUID, shipmentNumber string) (*models.Shipment, error)
 	GetShipmentByID(ctx context.Context, userID, shipmentID uuid.UUID) (*models.Shipment, error)
 	GetShipmentDetails(ctx context.Context, userID, shipmentID uuid.UUID) (*dto.ShipmentDetailsResponse, error)
+	GetShipmentTrack(ctx context.Context, userID, shipmentID uuid.UUID, from, to *time.Time) (*dto.ShipmentTrackResponse, error)
 	UpdateShipmentInfo(ctx context.Context, userID, shipmentID uuid.UUID, req *dto.UpdateShipmentInfoRequest) error
 	UpdateShipmentInfoPartial(ctx context.Context, userID, shipmentID uuid.UUID, updates map[string]interface{}) error
 	SyncShipment(ctx context.Context, userID, shipmentID uuid.UUID) (*models.Shipment, error)
 	RefreshShipment(ctx context.Context, userID, shipmentID uuid.UUID) (*models.Shipment, error)
 	SystemRefreshShipment(ctx context.Context, shipmentID uuid.UUID) (*models.Shipment, error)
+	ResumeTracking(ctx context.Context, userID, shipmentID uuid.UUID) (*models.Shipment, error)
+	DrainSyncs(ctx context.Context) error
 	GetShipmentsForGrid(ctx context.Context, userID uuid.UUID) (*dto.GridDataResponse, error)
+	GetShipmentsForGridByIDs(ctx context.Context, userID uuid.UUID, shipmentIDs []uuid.UUID) ([]dto.ShipmentDetailsResponse, error)
+	GetShipmentsByContainer(ctx context.Context, userID uuid.UUID, number string, partial bool) ([]dto.ShipmentDetailsResponse, error)
+	GetShipmentsByVessel(ctx context.Context, userID uuid.UUID, imo int) ([]dto.ShipmentDetailsResponse, error)
+	GetShipmentsWithRunningClocks(ctx context.Context, userID uuid.UUID) ([]dto.ShipmentDetailsResponse, error)
+	GetShipmentsDepartedBetween(ctx context.Context, userID uuid.UUID, from, to *time.Time) ([]dto.ShipmentDetailsResponse, error)
+	GetMapData(ctx context.Context, userID uuid.UUID, bbox geo.BBox, zoom int) (*dto.MapDataResponse, error)
 	DeleteUserShipment(ctx context.Context, userID, shipmentID uuid.UUID) error
 	BulkDeleteUserShipments(ctx context.Context, userID uuid.UUID, shipmentIDs []uuid.UUID) error
+	GetCarriers(ctx context.Context) ([]models.Carrier, error)
+	DetectCarrier(ctx context.Context, shipmentNumber, shipmentType string) (*models.Carrier, error)
 }
 
 type SafeCubeAPIService interface {
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+
+	"go-starter/internal/modules/shipments/dto"
+	"go-starter/internal/modules/shipments/models"
+	"go-starter/internal/modules/shipments/repositories"
+	"go-starter/pkg/geo"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxMapZoom is the deepest zoom level the map data is prepared for
+	MaxMapZoom = 22
+
+	// positionClusterPixels and portClusterPixels are the cluster cell sizes on screen
+	positionClusterPixels = 60
+	portClusterPixels     = 40
+	// routeTolerancePixels is how far a simplified route may stray from the original on screen
+	routeTolerancePixels = 1.5
+)
+
+// GetMapData returns the positions, ports and route lines of the user's shipments inside the
+// bounding box. Positions and ports close together at the zoom level are clustered and the
+// routes are simplified to what is visible at that zoom level.
+func (s *shipmentService) GetMapData(ctx context.Context, userID uuid.UUID, bbox geo.BBox, zoom int) (*dto.MapDataResponse, error) {
+	positions, err := s.repo.GetMapPositions(ctx, userID, bbox)
+	if err != nil {
+		return nil, err
+	}
+	ports, err := s.repo.GetMapPorts(ctx, userID, bbox)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := s.repo.GetMapRouteSegments(ctx, userID, bbox)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.MapDataResponse{
+		Zoom:      zoom,
+		Positions: positionMarkers(positions, zoom),
+		Ports:     portMarkers(ports, zoom),
+		Routes:    simplifiedRoutes(segments, zoom),
+	}, nil
+}
+
+// positionMarkers clusters the shipment positions, a lone position keeps its shipment
+func positionMarkers(positions []repositories.MapPosition, zoom int) []dto.MapMarkerResponse {
+	points := make([]geo.Point, len(positions))
+	for i, position := range positions {
+		points[i] = geo.Point{Lat: position.Latitude, Lng: position.Longitude}
+	}
+
+	markers := make([]dto.MapMarkerResponse, 0, len(positions))
+	for _, cluster := range geo.ClusterPoints(points, zoom, positionClusterPixels) {
+		marker := clusterMarker(cluster)
+		if marker.Count == 1 {
+			position := positions[cluster.Members[0]]
+			marker.ShipmentID = &position.ShipmentID
+			marker.ShipmentNumber = position.ShipmentNumber
+			marker.Status = position.ShippingStatus
+		}
+		markers = append(markers, marker)
+	}
+	return markers
+}
+
+// portMarkers clusters the port locations, a lone port keeps its name and UN/LOCODE
+func portMarkers(locations []models.Location, zoom int) []dto.MapMarkerResponse {
+	points := make([]geo.Point, len(locations))
+	for i, location := range locations {
+		points[i] = geo.Point{Lat: location.Latitude, Lng: location.Longitude}
+	}
+
+	markers := make([]dto.MapMarkerResponse, 0, len(locations))
+	for _, cluster := range geo.ClusterPoints(points, zoom, portClusterPixels) {
+		marker := clusterMarker(cluster)
+		if marker.Count == 1 {
+			location := locations[cluster.Members[0]]
+			marker.Name = location.Name
+			marker.Locode = location.Locode
+		}
+		markers = append(markers, marker)
+	}
+	return markers
+}
+
+func clusterMarker(cluster geo.Cluster) dto.MapMarkerResponse {
+	return dto.MapMarkerResponse{
+		Latitude:  cluster.Center.Lat,
+		Longitude: cluster.Center.Lng,
+		Count:     len(cluster.Members),
+	}
+}
+
+// simplifiedRoutes drops the route points that are indistinguishable at the zoom level
+func simplifiedRoutes(segments []models.RouteSegment, zoom int) []dto.MapRouteResponse {
+	tolerance := routeTolerancePixels * geo.DegreesPerPixel(zoom)
+
+	routes := make([]dto.MapRouteResponse, 0, len(segments))
+	for _, segment := range segments {
+		if len(segment.Points) < 2 {
+			continue
+		}
+
+		line := make([]geo.Point, len(segment.Points))
+		for i, point := range segment.Points {
+			line[i] = geo.Point{Lat: point.Latitude, Lng: point.Longitude}
+		}
+
+		simplified := geo.Simplify(line, tolerance)
+		path := make([][2]float64, len(simplified))
+		for i, point := range simplified {
+			path[i] = [2]float64{point.Lat, point.Lng}
+		}
+
+		routes = append(routes, dto.MapRouteResponse{
+			ShipmentID: segment.ShipmentID,
+			RouteType:  segment.RouteType,
+			Path:       path,
+		})
+	}
+	return routes
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"testing"
+
+	"go-starter/internal/modules/shipments/models"
+	"go-starter/internal/modules/shipments/repositories"
+
+	"github.com/google/uuid"
+)
+
+func TestPositionMarkers(t *testing.T) {
+	positions := []repositories.MapPosition{
+		{ShipmentID: uuid.New(), ShipmentNumber: "A", Latitude: 51.90, Longitude: 4.10},
+		{ShipmentID: uuid.New(), ShipmentNumber: "B", Latitude: 51.95, Longitude: 4.15},
+		{ShipmentID: uuid.New(), ShipmentNumber: "C", ShippingStatus: "IN_TRANSIT", Latitude: 1.30, Longitude: 103.80},
+	}
+
+	markers := positionMarkers(positions, 3)
+	if len(markers) != 2 {
+		t.Fatalf("Expected 2 markers at zoom 3, got %d", len(markers))
+	}
+	if markers[0].Count != 2 || markers[0].ShipmentID != nil || markers[0].ShipmentNumber != "" {
+		t.Errorf("Expected an anonymous cluster of 2, got %+v", markers[0])
+	}
+	if markers[1].Count != 1 || *markers[1].ShipmentID != positions[2].ShipmentID || markers[1].Status != "IN_TRANSIT" {
+		t.Errorf("Expected the lone shipment marker, got %+v", markers[1])
+	}
+
+	if markers := positionMarkers(positions, 14); len(markers) != 3 {
+		t.Errorf("Expected every position at zoom 14, got %d markers", len(markers))
+	}
+}
+
+func TestSimplifiedRoutes(t *testing.T) {
+	segment := models.RouteSegment{ShipmentID: uuid.New(), RouteType: "SEA"}
+	for i := 0; i <= 100; i++ {
+		segment.Points = append(segment.Points, models.RouteSegmentPoint{Latitude: float64(i%2) * 0.001, Longitude: float64(i) * 0.1})
+	}
+	segments := []models.RouteSegment{segment, {RouteType: "SEA", Points: segment.Points[:1]}}
+
+	routes := simplifiedRoutes(segments, 2)
+	if len(routes) != 1 {
+		t.Fatalf("Expected segments without a line skipped, got %d routes", len(routes))
+	}
+	if len(routes[0].Path) != 2 || routes[0].Path[1] != [2]float64{0, 10} {
+		t.Errorf("Expected the zig-zag simplified away at zoom 2, got %v", routes[0].Path)
+	}
+
+	if routes := simplifiedRoutes(segments, MaxMapZoom); len(routes[0].Path) != len(segment.Points) {
+		t.Errorf("Expected every point kept at zoom %d, got %d", MaxMapZoom, len(routes[0].Path))
+	}
+}
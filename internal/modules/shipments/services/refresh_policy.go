@@ -0,0 +1,113 @@
+package services
+
+import (
+	"go-starter/internal/modules/shipments/repositories"
+	"go-starter/pkg/config"
+	"time"
+)
+
+// Refresh tiers recorded on the shipment so it is visible why it was scheduled
+const (
+	RefreshTierNearArrival = "near_arrival"
+	RefreshTierActive      = "active"
+	RefreshTierMidOcean    = "mid_ocean"
+	RefreshTierDormant     = "dormant"
+	RefreshTierRetry       = "retry"
+	RefreshTierTerminal    = "terminal"
+)
+
+// RefreshPolicy decides when a shipment should next be synced with SafeCube.
+// Shipments where changes are likely (close to arrival) are refreshed often,
+// shipments mid-ocean rarely and shipments without events for weeks almost never.
+type RefreshPolicy struct {
+	// NearArrivalInterval is used when POD ETA or vessel arrival is within ArrivalWindow
+	NearArrivalInterval time.Duration
+	// ActiveInterval is the default for shipments that fit no other tier
+	ActiveInterval time.Duration
+	// MidOceanInterval is used once the vessel has departed and arrival is still far away
+	MidOceanInterval time.Duration
+	// DormantInterval is used when no event has been seen for DormantAfter
+	DormantInterval time.Duration
+	// RetryInterval is used after a failed sync
+	RetryInterval time.Duration
+	// TerminalInterval is used while a delivered, returned or cancelled shipment is still tracked
+	TerminalInterval time.Duration
+	// ArrivalWindow is how close to arrival a shipment counts as near arrival
+	ArrivalWindow time.Duration
+	// DormantAfter is how long without events before a shipment counts as dormant
+	DormantAfter time.Duration
+}
+
+// RefreshSignals contains the shipment state the policy looks at
+type RefreshSignals = repositories.RefreshSignals
+
+// DefaultRefreshPolicy returns a default refresh policy
+func DefaultRefreshPolicy() RefreshPolicy {
+	return RefreshPolicy{
+		NearArrivalInterval: 1 * time.Hour,
+		ActiveInterval:      6 * time.Hour,
+		MidOceanInterval:    24 * time.Hour,
+		DormantInterval:     7 * 24 * time.Hour,
+		RetryInterval:       1 * time.Hour,
+		TerminalInterval:    24 * time.Hour,
+		ArrivalWindow:       72 * time.Hour,
+		DormantAfter:        21 * 24 * time.Hour,
+	}
+}
+
+// NewRefreshPolicyFromConfig builds a refresh policy from the background job configuration
+func NewRefreshPolicyFromConfig(cfg config.BackgroundJobsConfig) RefreshPolicy {
+	return RefreshPolicy{
+		NearArrivalInterval: cfg.ShipmentRefreshNearArrival,
+		ActiveInterval:      cfg.ShipmentRefreshActive,
+		MidOceanInterval:    cfg.ShipmentRefreshMidOcean,
+		DormantInterval:     cfg.ShipmentRefreshDormant,
+		RetryInterval:       cfg.ShipmentRefreshRetry,
+		TerminalInterval:    cfg.ShipmentRefreshTerminal,
+		ArrivalWindow:       cfg.ShipmentArrivalWindow,
+		DormantAfter:        cfg.ShipmentDormantAfter,
+	}
+}
+
+// Next returns when the shipment should be refreshed next and the tier that was applied
+func (p RefreshPolicy) Next(now time.Time, signals RefreshSignals) (time.Time, string) {
+	// Terminal shipments only need to pick up late corrections until tracking stops
+	if signals.Terminal {
+		return now.Add(p.TerminalInterval), RefreshTierTerminal
+	}
+
+	// Close to arrival changes are most likely, so this wins over every other tier
+	if !signals.PodActual && p.isNear(now, signals.PodETA) {
+		return now.Add(p.NearArrivalInterval), RefreshTierNearArrival
+	}
+	if p.isNear(now, signals.VesselArrival) {
+		return now.Add(p.NearArrivalInterval), RefreshTierNearArrival
+	}
+
+	if signals.LastEventAt != nil && now.Sub(*signals.LastEventAt) > p.DormantAfter {
+		return now.Add(p.DormantInterval), RefreshTierDormant
+	}
+
+	if signals.VesselDeparture != nil && signals.VesselDeparture.Before(now) {
+		return now.Add(p.MidOceanInterval), RefreshTierMidOcean
+	}
+
+	return now.Add(p.ActiveInterval), RefreshTierActive
+}
+
+// Retry returns when a shipment whose sync failed should be tried again
+func (p RefreshPolicy) Retry(now time.Time) (time.Time, string) {
+	return now.Add(p.RetryInterval), RefreshTierRetry
+}
+
+// isNear reports whether t lies within the arrival window around now
+func (p RefreshPolicy) isNear(now time.Time, t *time.Time) bool {
+	if t == nil {
+		return false
+	}
+	diff := t.Sub(now)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= p.ArrivalWindow
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRefreshPolicy_Next(t *testing.T) {
+	policy := DefaultRefreshPolicy()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	at := func(d time.Duration) *time.Time {
+		v := now.Add(d)
+		return &v
+	}
+
+	tests := []struct {
+		name     string
+		signals  RefreshSignals
+		wantTier string
+		wantIn   time.Duration
+	}{
+		{
+			name:     "no signals uses active interval",
+			signals:  RefreshSignals{},
+			wantTier: RefreshTierActive,
+			wantIn:   policy.ActiveInterval,
+		},
+		{
+			name:     "pod eta within window",
+			signals:  RefreshSignals{PodETA: at(24 * time.Hour), LastEventAt: at(-40 * 24 * time.Hour)},
+			wantTier: RefreshTierNearArrival,
+			wantIn:   policy.NearArrivalInterval,
+		},
+		{
+			name:     "actual pod is not near arrival",
+			signals:  RefreshSignals{PodETA: at(-2 * time.Hour), PodActual: true},
+			wantTier: RefreshTierActive,
+			wantIn:   policy.ActiveInterval,
+		},
+		{
+			name:     "vessel arrival within window",
+			signals:  RefreshSignals{VesselArrival: at(48 * time.Hour)},
+			wantTier: RefreshTierNearArrival,
+			wantIn:   policy.NearArrivalInterval,
+		},
+		{
+			name:     "departed and far from arrival",
+			signals:  RefreshSignals{VesselDeparture: at(-5 * 24 * time.Hour), PodETA: at(20 * 24 * time.Hour), LastEventAt: at(-5 * 24 * time.Hour)},
+			wantTier: RefreshTierMidOcean,
+			wantIn:   policy.MidOceanInterval,
+		},
+		{
+			name:     "terminal shipment",
+			signals:  RefreshSignals{Terminal: true, PodETA: at(-2 * time.Hour)},
+			wantTier: RefreshTierTerminal,
+			wantIn:   policy.TerminalInterval,
+		},
+		{
+			name:     "no events for weeks",
+			signals:  RefreshSignals{VesselDeparture: at(-30 * 24 * time.Hour), LastEventAt: at(-30 * 24 * time.Hour)},
+			wantTier: RefreshTierDormant,
+			wantIn:   policy.DormantInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, tier := policy.Next(now, tt.signals)
+			if tier != tt.wantTier {
+				t.Errorf("Expected tier %s, got %s", tt.wantTier, tier)
+			}
+			if got := next.Sub(now); got != tt.wantIn {
+				t.Errorf("Expected next refresh in %v, got %v", tt.wantIn, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"go-starter/internal/modules/shipments/dto"
+	"go-starter/pkg/geo"
+)
+
+const (
+	// typicalSpeedKnots is the service speed of a container vessel the ETA estimate assumes
+	typicalSpeedKnots = 14
+	// metersPerNauticalMile converts knots to meters per hour
+	metersPerNauticalMile = 1852
+	// maxRouteOffsetMeters is how far the vessel may be from the planned route before
+	// progress is measured with great-circle distances instead
+	maxRouteOffsetMeters = 100000
+)
+
+// addRouteProgress computes the route progress of every row
+func addRouteProgress(rows []dto.ShipmentDetailsResponse) {
+	for i := range rows {
+		rows[i].RouteProgress = routeProgress(rows[i])
+	}
+}
+
+// routeProgress combines the planned route with the last vessel position into the distance
+// sailed and remaining to the port of discharge. The ETA estimate assumes the vessel keeps
+// typical speed from its last position. Shipments that didn't depart yet have no progress,
+// arrived ones are complete.
+func routeProgress(row dto.ShipmentDetailsResponse) *dto.ShipmentRouteProgressResponse {
+	line := routeLine(row.RouteData.RouteSegments)
+	origin, destination := routeEnds(row.Route, line)
+	if origin == nil || destination == nil {
+		return nil
+	}
+
+	progress := &dto.ShipmentRouteProgressResponse{Method: dto.ProgressMethodGreatCircle}
+	total := geo.Distance(*origin, *destination)
+	if len(line) >= 2 {
+		progress.Method = dto.ProgressMethodRoute
+		total = geo.LineLength(line)
+	}
+
+	pod := row.Route.Pod
+	if pod != nil {
+		progress.CarrierETA = pod.Date
+		if pod.PredictiveETA != nil {
+			progress.CarrierETA = pod.PredictiveETA
+		}
+	}
+
+	if pod != nil && isActual(pod.Actual) {
+		setDistances(progress, total, total)
+		return progress
+	}
+
+	position, positionAt := vesselPosition(row)
+	if position == nil {
+		if row.Route.Pol != nil && isActual(row.Route.Pol.Actual) {
+			return nil
+		}
+		setDistances(progress, total, 0)
+		return progress
+	}
+	progress.PositionAt = positionAt
+
+	sailed := -1.0
+	if progress.Method == dto.ProgressMethodRoute {
+		if location, ok := geo.LocateOnLine(line, *position); ok && location.Offset <= maxRouteOffsetMeters {
+			sailed = location.Along
+		}
+	}
+	if sailed < 0 {
+		progress.Method = dto.ProgressMethodGreatCircle
+		sailed = geo.Distance(*origin, *position)
+		total = sailed + geo.Distance(*position, *destination)
+	}
+	setDistances(progress, total, sailed)
+
+	remaining := time.Duration((total - sailed) / (typicalSpeedKnots * metersPerNauticalMile) * float64(time.Hour))
+	eta := positionAt.Add(remaining).Truncate(time.Minute)
+	progress.EstimatedETA = &eta
+
+	if progress.CarrierETA != nil {
+		delta := math.Round(eta.Sub(*progress.CarrierETA).Hours()*10) / 10
+		progress.ETADeltaHours = &delta
+	}
+
+	return progress
+}
+
+// setDistances fills in the distances in km and the percentage from meters
+func setDistances(progress *dto.ShipmentRouteProgressResponse, total, sailed float64) {
+	sailed = math.Max(0, math.Min(sailed, total))
+
+	progress.TotalKm = roundKm(total)
+	progress.SailedKm = roundKm(sailed)
+	progress.RemainingKm = roundKm(total - sailed)
+	if total > 0 {
+		progress.ProgressPercent = math.Round(sailed/total*1000) / 10
+	}
+}
+
+func roundKm(meters float64) float64 {
+	return math.Round(meters/100) / 10
+}
+
+// routeLine joins the route segments into one line in segment and point order
+func routeLine(segments []dto.ShipmentRouteSegmentResponse) []geo.Point {
+	ordered := append([]dto.ShipmentRouteSegmentResponse(nil), segments...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].SegmentOrder < ordered[j].SegmentOrder
+	})
+
+	var line []geo.Point
+	for _, segment := range ordered {
+		path := append([]dto.ShipmentRouteSegmentPointResponse(nil), segment.Path...)
+		sort.SliceStable(path, func(i, j int) bool {
+			return path[i].PointOrder < path[j].PointOrder
+		})
+		for _, point := range path {
+			p := geo.Point{Lat: point.Latitude, Lng: point.Longitude}
+			if len(line) > 0 && line[len(line)-1] == p {
+				continue
+			}
+			line = append(line, p)
+		}
+	}
+	return line
+}
+
+// routeEnds returns the port of loading and the port of discharge, the ends of the route
+// line stand in for ports without coordinates
+func routeEnds(route dto.ShipmentRouteResponse, line []geo.Point) (*geo.Point, *geo.Point) {
+	var origin, destination *geo.Point
+	if route.Pol != nil && hasCoordinates(route.Pol.Location.Latitude, route.Pol.Location.Longitude) {
+		origin = &geo.Point{Lat: route.Pol.Location.Latitude, Lng: route.Pol.Location.Longitude}
+	}
+	if route.Pod != nil && hasCoordinates(route.Pod.Location.Latitude, route.Pod.Location.Longitude) {
+		destination = &geo.Point{Lat: route.Pod.Location.Latitude, Lng: route.Pod.Location.Longitude}
+	}
+
+	if len(line) >= 2 {
+		if origin == nil {
+			origin = &line[0]
+		}
+		if destination == nil {
+			destination = &line[len(line)-1]
+		}
+	}
+	return origin, destination
+}
+
+// vesselPosition returns the last AIS position of the vessel, the shipment coordinates without one
+func vesselPosition(row dto.ShipmentDetailsResponse) (*geo.Point, *time.Time) {
+	ais := row.RouteData.Ais
+	if ais.LastVesselPositionLat != nil && ais.LastVesselPositionLng != nil && ais.LastVesselPositionUpdate != nil &&
+		hasCoordinates(*ais.LastVesselPositionLat, *ais.LastVesselPositionLng) {
+		return &geo.Point{Lat: *ais.LastVesselPositionLat, Lng: *ais.LastVesselPositionLng}, ais.LastVesselPositionUpdate
+	}
+
+	coordinates := row.RouteData.Coordinates
+	if hasCoordinates(coordinates.Latitude, coordinates.Longitude) && !coordinates.UpdatedAt.IsZero() {
+		updatedAt := coordinates.UpdatedAt
+		return &geo.Point{Lat: coordinates.Latitude, Lng: coordinates.Longitude}, &updatedAt
+	}
+	return nil, nil
+}
+
+// hasCoordinates treats 0, 0 as a missing position
+func hasCoordinates(lat, lng float64) bool {
+	return lat != 0 || lng != 0
+}
+
+func isActual(actual *bool) bool {
+	return actual != nil && *actual
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go-starter/internal/modules/shipments/dto"
+)
+
+// progressShipment sails along the equator from 0 to 10 degrees east, about 1112 km
+func progressShipment(lat, lng float64, actualPod bool) dto.ShipmentDetailsResponse {
+	departed, arrived := true, actualPod
+	carrierETA := time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC)
+	positionAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	var path []dto.ShipmentRouteSegmentPointResponse
+	for i := 0; i <= 10; i++ {
+		path = append(path, dto.ShipmentRouteSegmentPointResponse{Longitude: float64(i), PointOrder: i})
+	}
+
+	return dto.ShipmentDetailsResponse{
+		Route: dto.ShipmentRouteResponse{
+			Pol: &dto.ShipmentRoutePoint{Location: dto.ShipmentLocationResponse{Latitude: 0, Longitude: 0.001}, Actual: &departed},
+			Pod: &dto.ShipmentRoutePoint{Location: dto.ShipmentLocationResponse{Latitude: 0, Longitude: 10}, Actual: &arrived, PredictiveETA: &carrierETA},
+		},
+		RouteData: dto.ShipmentRouteDataResponse{
+			RouteSegments: []dto.ShipmentRouteSegmentResponse{{RouteType: "SEA", Path: path}},
+			Ais: dto.ShipmentAisResponse{
+				LastVesselPositionLat:    &lat,
+				LastVesselPositionLng:    &lng,
+				LastVesselPositionUpdate: &positionAt,
+			},
+		},
+	}
+}
+
+func TestRouteProgressAlongRoute(t *testing.T) {
+	progress := routeProgress(progressShipment(0.2, 2.5, false))
+	if progress == nil {
+		t.Fatal("Expected route progress")
+	}
+
+	if progress.Method != dto.ProgressMethodRoute || progress.ProgressPercent != 25 {
+		t.Errorf("Expected 25%% along the route, got %s %.1f%%", progress.Method, progress.ProgressPercent)
+	}
+	if math.Abs(progress.TotalKm-1111.9) > 1 || math.Abs(progress.RemainingKm-834) > 1 {
+		t.Errorf("Unexpected distances %+v", progress)
+	}
+
+	// 834 km at 14 knots take about 32.2 hours
+	wantETA := time.Date(2025, 6, 2, 8, 10, 0, 0, time.UTC)
+	if progress.EstimatedETA == nil || progress.EstimatedETA.Sub(wantETA).Abs() > 10*time.Minute {
+		t.Errorf("Expected an ETA around %s, got %v", wantETA, progress.EstimatedETA)
+	}
+	if progress.ETADeltaHours == nil || math.Abs(*progress.ETADeltaHours+15.8) > 0.3 {
+		t.Errorf("Expected our ETA about 15.8 hours before the carrier's, got %v", progress.ETADeltaHours)
+	}
+}
+
+func TestRouteProgressOffRoute(t *testing.T) {
+	progress := routeProgress(progressShipment(5, 5, false))
+	if progress.Method != dto.ProgressMethodGreatCircle {
+		t.Fatalf("Expected great-circle progress far off the route, got %s", progress.Method)
+	}
+	if math.Abs(progress.ProgressPercent-50) > 0.5 || progress.TotalKm <= 1112 {
+		t.Errorf("Expected halfway on a longer detour, got %+v", progress)
+	}
+}
+
+func TestRouteProgressArrivedAndPending(t *testing.T) {
+	arrived := routeProgress(progressShipment(0, 2.5, true))
+	if arrived.ProgressPercent != 100 || arrived.RemainingKm != 0 || arrived.EstimatedETA != nil {
+		t.Errorf("Expected an arrived shipment to be complete, got %+v", arrived)
+	}
+
+	pending := progressShipment(0, 0, false)
+	pending.RouteData.Ais = dto.ShipmentAisResponse{}
+	notDeparted := false
+	pending.Route.Pol.Actual = &notDeparted
+	if progress := routeProgress(pending); progress == nil || progress.ProgressPercent != 0 || progress.RemainingKm != progress.TotalKm {
+		t.Errorf("Expected no progress before departure, got %+v", progress)
+	}
+
+	departed := true
+	pending.Route.Pol.Actual = &departed
+	if progress := routeProgress(pending); progress != nil {
+		t.Errorf("Expected unknown progress without a position after departure, got %+v", progress)
+	}
+
+	if progress := routeProgress(dto.ShipmentDetailsResponse{}); progress != nil {
+		t.Errorf("Expected no progress without ports or route, got %+v", progress)
+	}
+}
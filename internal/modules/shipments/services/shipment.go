@@ -10,6 +10,8 @@ import (
 	"go-starter/internal/modules/shipments/repositories"
 	"go-starter/internal/modules/shipments/types"
 	"log"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -18,12 +20,21 @@ import (
 type shipmentService struct {
 	repo               repositories.ShipmentRepository
 	safeCubeAPIService SafeCubeAPIService
+	refreshPolicy      RefreshPolicy
+	trackingPolicy     TrackingPolicy
 }
 
-func NewShipmentService(repo repositories.ShipmentRepository, safeCubeAPIService SafeCubeAPIService) ShipmentService {
+func NewShipmentService(
+	repo repositories.ShipmentRepository,
+	safeCubeAPIService SafeCubeAPIService,
+	refreshPolicy RefreshPolicy,
+	trackingPolicy TrackingPolicy,
+) ShipmentService {
 	return &shipmentService{
 		repo:               repo,
 		safeCubeAPIService: safeCubeAPIService,
+		refreshPolicy:      refreshPolicy,
+		trackingPolicy:     trackingPolicy,
 	}
 }
 
@@ -32,6 +43,8 @@ func (s *shipmentService) AddShipment(
 	userID uuid.UUID,
 	req *dto.AddShipmentRequest,
 ) (*models.Shipment, error) {
+	s.resolveSealine(ctx, req)
+
 	alreadyTracking, err := s.repo.CheckUserAlreadyTracking(ctx, userID, req.ShipmentNumber)
 	if err != nil {
 		return nil, err
@@ -91,7 +104,41 @@ func (s *shipmentService) AddShipment(
 		return shipment, nil
 	}
 
-	return s.createNewShipmentFromSafeCubeAPI(ctx, userID, req)
+	return s.coordinatedCreate(ctx, userID, req)
+}
+
+// coordinatedCreate makes sure a shipment that is added by several users at the same time
+// is fetched from SafeCube and created only once. Users whose request was collapsed into
+// another user's creation are linked to the created shipment afterwards.
+func (s *shipmentService) coordinatedCreate(ctx context.Context, userID uuid.UUID, req *dto.AddShipmentRequest) (*models.Shipment, error) {
+	result, err, shared := shipmentSyncGroup.Do("create:"+req.ShipmentNumber, func() (interface{}, error) {
+		createCtx, done, err := inFlightSyncs.begin(ctx, uuid.Nil)
+		if err != nil {
+			return nil, err
+		}
+		defer done()
+
+		return s.createNewShipmentFromSafeCubeAPI(createCtx, userID, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !shared {
+		return result.(*models.Shipment), nil
+	}
+
+	log.Printf("Creation of shipment %s was shared between concurrent callers", req.ShipmentNumber)
+
+	alreadyTracking, err := s.repo.CheckUserAlreadyTracking(ctx, userID, req.ShipmentNumber)
+	if err != nil {
+		return nil, err
+	}
+	if alreadyTracking {
+		shipment := *result.(*models.Shipment)
+		return &shipment, nil
+	}
+
+	return s.repo.AddExistingShipmentToUser(ctx, userID, req.ShipmentNumber)
 }
 
 func (s *shipmentService) createNewShipmentFromSafeCubeAPI(
@@ -109,12 +156,16 @@ func (s *shipmentService) createNewShipmentFromSafeCubeAPI(
 		return nil, err
 	}
 
+	status := models.NormalizeShipmentStatus(apiResponse.Metadata.ShippingStatus)
 	shipmentModel := &models.Shipment{
 		ShipmentNumber: apiResponse.Metadata.ShipmentNumber,
 		ShipmentType:   apiResponse.Metadata.ShipmentType,
 		SealineCode:    apiResponse.Metadata.Sealine,
 		SealineName:    apiResponse.Metadata.SealineName,
-		ShippingStatus: apiResponse.Metadata.ShippingStatus,
+		ShippingStatus: string(status),
+		ProviderStatus: apiResponse.Metadata.ShippingStatus,
+		TrackingState:  models.TrackingStateActive,
+		TerminalSince:  s.trackingPolicy.TerminalSince(nil, status, time.Now()),
 		Warnings:       apiResponse.Metadata.Warnings,
 	}
 
@@ -241,6 +292,7 @@ func (s *shipmentService) createNewShipmentFromSafeCubeAPI(
 	}
 
 	containers := make([]models.Container, 0, len(apiResponse.Containers))
+	eventsByContainer := make(map[uuid.UUID][]models.ContainerEvent, len(apiResponse.Containers))
 	for _, c := range apiResponse.Containers {
 		container := models.Container{
 			Number:   c.Number,
@@ -343,6 +395,15 @@ func (s *shipmentService) createNewShipmentFromSafeCubeAPI(
 			}
 			containerEvents = append(containerEvents, *createdContainerEvent)
 		}
+		eventsByContainer[createdContainer.ID] = containerEvents
+	}
+
+	if err := s.saveMilestones(ctx, shipment.ID, eventsByContainer); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReplaceVoyageLegs(ctx, shipment.ID, models.DeriveVoyageLegs(shipment.ID, eventsByContainer)); err != nil {
+		return nil, err
 	}
 
 	routeSegments := make([]models.RouteSegment, 0, len(apiResponse.RouteData.RouteSegments))
@@ -387,6 +448,9 @@ func (s *shipmentService) createNewShipmentFromSafeCubeAPI(
 
 	if apiResponse.RouteData.Ais.Data != nil {
 		aisData := apiResponse.RouteData.Ais.Data
+		if err := s.recordVesselPosition(ctx, aisData); err != nil {
+			return nil, err
+		}
 		aisVessel, err := s.repo.FindVesselByIMOAndMMSI(ctx, aisData.Vessel.Imo, aisData.Vessel.Mmsi)
 		if err != nil {
 			if !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -439,6 +503,9 @@ func (s *shipmentService) createNewShipmentFromSafeCubeAPI(
 
 	log.Printf("Created shipment %s in database with ID: %s", req.ShipmentNumber, shipment.ID)
 
+	s.afterSync(ctx, shipment)
+	s.publishSync(ctx, shipment, nil)
+
 	return shipment, nil
 }
 
@@ -448,6 +515,28 @@ func (s *shipmentService) GetShipmentsForGrid(ctx context.Context, userID uuid.U
 		return nil, fmt.Errorf("failed to fetch shipments for grid: %w", err)
 	}
 
+	return &dto.GridDataResponse{
+		Rows: s.gridRows(ctx, userID, shipments),
+	}, nil
+}
+
+// GetShipmentsForGridByIDs returns the grid rows of the user's shipments among shipmentIDs,
+// ids of other shipments are left out
+func (s *shipmentService) GetShipmentsForGridByIDs(ctx context.Context, userID uuid.UUID, shipmentIDs []uuid.UUID) ([]dto.ShipmentDetailsResponse, error) {
+	if len(shipmentIDs) == 0 {
+		return []dto.ShipmentDetailsResponse{}, nil
+	}
+
+	shipments, err := s.repo.GetShipmentsForGridByIDs(ctx, userID, shipmentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch shipments for grid: %w", err)
+	}
+
+	return s.gridRows(ctx, userID, shipments), nil
+}
+
+// gridRows loads the details of the shipments and adds what the grid shows beyond them
+func (s *shipmentService) gridRows(ctx context.Context, userID uuid.UUID, shipments []models.Shipment) []dto.ShipmentDetailsResponse {
 	detailedShipments := make([]dto.ShipmentDetailsResponse, len(shipments))
 	for i, shipment := range shipments {
 		// Get detailed shipment information for each shipment
@@ -464,6 +553,7 @@ func (s *shipmentService) GetShipmentsForGrid(ctx context.Context, userID uuid.U
 				ShippingStatus: shipment.ShippingStatus,
 				CreatedAt:      shipment.CreatedAt,
 				UpdatedAt:      shipment.UpdatedAt,
+				TrackingState:  shipment.TrackingState,
 				Locations:      []dto.ShipmentLocationResponse{},
 				Route:          dto.ShipmentRouteResponse{},
 				Vessels:        []dto.ShipmentVesselResponse{},
@@ -476,9 +566,57 @@ func (s *shipmentService) GetShipmentsForGrid(ctx context.Context, userID uuid.U
 		detailedShipments[i] = *shipmentDetails
 	}
 
-	return &dto.GridDataResponse{
-		Rows: detailedShipments,
-	}, nil
+	addRouteProgress(detailedShipments)
+	s.canonicalizeCarriers(ctx, detailedShipments)
+	detailsDecorators.decorate(ctx, userID, detailedShipments)
+
+	return detailedShipments
+}
+
+// GetShipmentsByContainer returns the user's shipments holding a container with the number,
+// or with a number containing it when partial is set, with only their route and those containers
+func (s *shipmentService) GetShipmentsByContainer(ctx context.Context, userID uuid.UUID, number string, partial bool) ([]dto.ShipmentDetailsResponse, error) {
+	rows, err := s.repo.GetShipmentsByContainer(ctx, userID, number, partial)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch container shipments: %w", err)
+	}
+
+	s.canonicalizeCarriers(ctx, rows)
+	return rows, nil
+}
+
+// GetShipmentsByVessel returns the user's shipments on the vessel, with only their route,
+// containers, vessels, voyage legs and AIS snapshot
+func (s *shipmentService) GetShipmentsByVessel(ctx context.Context, userID uuid.UUID, imo int) ([]dto.ShipmentDetailsResponse, error) {
+	rows, err := s.repo.GetShipmentsByVessel(ctx, userID, imo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch vessel shipments: %w", err)
+	}
+	return rows, nil
+}
+
+// GetShipmentsWithRunningClocks returns the user's shipments with containers whose demurrage
+// or detention clock runs, with only their route and those containers. Carriers are
+// canonical, as agreements name them.
+func (s *shipmentService) GetShipmentsWithRunningClocks(ctx context.Context, userID uuid.UUID) ([]dto.ShipmentDetailsResponse, error) {
+	rows, err := s.repo.GetShipmentsWithRunningClocks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch shipments with running clocks: %w", err)
+	}
+
+	s.canonicalizeCarriers(ctx, rows)
+	return rows, nil
+}
+
+// GetShipmentsDepartedBetween returns the user's shipments with route segments and containers
+// that departed within the optional bounds, with only their consignee, route, containers
+// and route segments
+func (s *shipmentService) GetShipmentsDepartedBetween(ctx context.Context, userID uuid.UUID, from, to *time.Time) ([]dto.ShipmentDetailsResponse, error) {
+	rows, err := s.repo.GetShipmentsDepartedBetween(ctx, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch departed shipments: %w", err)
+	}
+	return rows, nil
 }
 
 func (s *shipmentService) GetShipmentByNumber(
@@ -536,12 +674,72 @@ func (s *shipmentService) validateShipmentForSync(ctx context.Context, userID, s
 		return nil, fmt.Errorf("shipment %s has no shipment type", shipmentID)
 	}
 	if shipment.SealineCode == "" {
-		return nil, fmt.Errorf("shipment %s has no sealine code", shipmentID)
+		carrier, err := s.DetectCarrier(ctx, shipment.ShipmentNumber, shipment.ShipmentType)
+		if err != nil {
+			return nil, fmt.Errorf("shipment %s has no sealine code and carrier detection failed: %w", shipmentID, err)
+		}
+		if err := s.repo.UpdateSealineCode(ctx, shipment.ID, carrier.Scac); err != nil {
+			return nil, err
+		}
+		log.Printf("Detected carrier %s for shipment %s without sealine code", carrier.Scac, shipment.ShipmentNumber)
+		shipment.SealineCode = carrier.Scac
 	}
 
 	return shipment, nil
 }
 
+// resolveSealine fills in the sealine code of a new shipment from the carrier registry. A
+// code the registry knows by name or alias is replaced by the SCAC, a missing one is
+// inferred from the shipment number. Unknown codes are passed on to the provider as they are.
+func (s *shipmentService) resolveSealine(ctx context.Context, req *dto.AddShipmentRequest) {
+	carriers, err := s.repo.GetCarriers(ctx)
+	if err != nil {
+		log.Printf("Warning: Failed to load carrier registry: %v", err)
+		return
+	}
+
+	if req.SealineCode != "" {
+		if carrier := CanonicalCarrier(carriers, req.SealineCode, ""); carrier != nil {
+			req.SealineCode = carrier.Scac
+		}
+		return
+	}
+
+	if carrier := DetectCarrier(carriers, req.ShipmentNumber, req.ShipmentType); carrier != nil {
+		log.Printf("Detected carrier %s for shipment %s", carrier.Scac, req.ShipmentNumber)
+		req.SealineCode = carrier.Scac
+	}
+}
+
+// GetCarriers returns the carrier registry
+func (s *shipmentService) GetCarriers(ctx context.Context) ([]models.Carrier, error) {
+	return s.repo.GetCarriers(ctx)
+}
+
+// DetectCarrier infers the carrier of a shipment number from the carrier registry
+func (s *shipmentService) DetectCarrier(ctx context.Context, shipmentNumber, shipmentType string) (*models.Carrier, error) {
+	carriers, err := s.repo.GetCarriers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	carrier := DetectCarrier(carriers, shipmentNumber, shipmentType)
+	if carrier == nil {
+		return nil, fmt.Errorf("carrier not found for %s", shipmentNumber)
+	}
+	return carrier, nil
+}
+
+// canonicalizeCarriers shows the rows under the canonical carrier names of the registry
+func (s *shipmentService) canonicalizeCarriers(ctx context.Context, rows []dto.ShipmentDetailsResponse) {
+	carriers, err := s.repo.GetCarriers(ctx)
+	if err != nil {
+		log.Printf("Warning: Failed to load carrier registry: %v", err)
+		return
+	}
+	applyCanonicalCarriers(carriers, rows)
+}
+
 // recreateShipmentRelatedData recreates all shipment related data from API response
 func (s *shipmentService) recreateShipmentRelatedData(ctx context.Context, shipment *models.Shipment, apiResponse *shipmentsDto.SafeCubeAPIShipmentResponse) (*types.SyncStats, error) {
 	// Validate API response
@@ -662,6 +860,7 @@ func (s *shipmentService) recreateShipmentRelatedData(ctx context.Context, shipm
 
 	log.Printf("Creating %d containers for shipment %s", len(apiResponse.Containers), shipment.ShipmentNumber)
 	// Create containers and their events
+	eventsByContainer := make(map[uuid.UUID][]models.ContainerEvent, len(apiResponse.Containers))
 	for _, c := range apiResponse.Containers {
 		container := models.Container{
 			Number:   c.Number,
@@ -674,6 +873,7 @@ func (s *shipmentService) recreateShipmentRelatedData(ctx context.Context, shipm
 			return nil, fmt.Errorf("failed to create container: %w", err)
 		}
 		stats.ContainersCreated++
+		eventsByContainer[createdContainer.ID] = make([]models.ContainerEvent, 0, len(c.Events))
 
 		// Create container events
 		for _, ce := range c.Events {
@@ -762,10 +962,20 @@ func (s *shipmentService) recreateShipmentRelatedData(ctx context.Context, shipm
 			if err != nil {
 				return nil, fmt.Errorf("failed to create container event: %w", err)
 			}
+			eventsByContainer[createdContainer.ID] = append(eventsByContainer[createdContainer.ID], containerEvent)
 			stats.ContainerEventsCreated++
 		}
 	}
 
+	log.Printf("Deriving milestones and voyage legs for shipment %s", shipment.ShipmentNumber)
+	if err := s.saveMilestones(ctx, shipment.ID, eventsByContainer); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReplaceVoyageLegs(ctx, shipment.ID, models.DeriveVoyageLegs(shipment.ID, eventsByContainer)); err != nil {
+		return nil, err
+	}
+
 	log.Printf("Creating %d route segments for shipment %s", len(apiResponse.RouteData.RouteSegments), shipment.ShipmentNumber)
 	// Create route segments
 	for segIdx, rs := range apiResponse.RouteData.RouteSegments {
@@ -813,6 +1023,9 @@ func (s *shipmentService) recreateShipmentRelatedData(ctx context.Context, shipm
 	// Create AIS data
 	if apiResponse.RouteData.Ais.Data != nil {
 		aisData := apiResponse.RouteData.Ais.Data
+		if err := s.recordVesselPosition(ctx, aisData); err != nil {
+			return nil, err
+		}
 		aisVessel, err := s.repo.FindVesselByIMOAndMMSI(ctx, aisData.Vessel.Imo, aisData.Vessel.Mmsi)
 		if err != nil {
 			if !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -869,125 +1082,29 @@ func (s *shipmentService) recreateShipmentRelatedData(ctx context.Context, shipm
 	return stats, nil
 }
 
-func (s *shipmentService) SyncShipment(ctx context.Context, userID, shipmentID uuid.UUID) (*models.Shipment, error) {
-	// Validate shipment before starting sync
-	existingShipment, err := s.validateShipmentForSync(ctx, userID, shipmentID)
-	if err != nil {
-		return nil, err
-	}
-
-	log.Printf("Starting sync for shipment %s (ID: %s)", existingShipment.ShipmentNumber, shipmentID)
-
-	// Get data summary before sync
-	beforeSummary, err := s.repo.GetShipmentDataSummary(ctx, shipmentID)
-	if err != nil {
-		log.Printf("Warning: Failed to get before-sync summary for shipment %s: %v", shipmentID, err)
-	} else {
-		log.Printf("Before sync - Shipment %s data counts: locations=%d, routes=%d, vessels=%d, facilities=%d, containers=%d, events=%d, segments=%d, coordinates=%d, ais=%d",
-			existingShipment.ShipmentNumber, beforeSummary.LocationsCount, beforeSummary.RoutesCount, beforeSummary.VesselsCount,
-			beforeSummary.FacilitiesCount, beforeSummary.ContainersCount, beforeSummary.ContainerEventsCount,
-			beforeSummary.RouteSegmentsCount, beforeSummary.CoordinatesCount, beforeSummary.AisCount)
-	}
-	// Get fresh data from SafeCube API
-	apiResponse, err := s.safeCubeAPIService.GetShipmentDetails(
-		ctx,
-		existingShipment.ShipmentNumber,
-		existingShipment.ShipmentType,
-		existingShipment.SealineCode,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	log.Printf("Starting sync for shipment %s (ID: %s)", existingShipment.ShipmentNumber, shipmentID)
-
-	// Start a transaction to ensure data consistency
-	tx := s.repo.GetDB().DB.Begin()
-	if tx.Error != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", tx.Error)
-	}
-	defer func() {
-		if r := recover(); r != nil {
-			log.Printf("Panic occurred during shipment sync, rolling back transaction: %v", r)
-			tx.Rollback()
-			panic(r)
+// saveMilestones derives the standardized milestones of each container and of the shipment
+// from the events of a sync
+func (s *shipmentService) saveMilestones(ctx context.Context, shipmentID uuid.UUID, eventsByContainer map[uuid.UUID][]models.ContainerEvent) error {
+	var containerMilestones []models.ContainerMilestone
+	for containerID, events := range eventsByContainer {
+		milestones := models.DeriveContainerMilestones(containerID, events)
+		if err := s.repo.ReplaceContainerMilestones(ctx, containerID, milestones); err != nil {
+			return err
 		}
-	}()
-
-	// Create a context with the transaction
-	txCtx := context.WithValue(ctx, "tx", tx)
-
-	log.Printf("Cleaning up existing data for shipment %s (ID: %s)", existingShipment.ShipmentNumber, shipmentID)
-	// Delete all existing related data
-	err = s.repo.DeleteAllShipmentRelatedData(txCtx, shipmentID)
-	if err != nil {
-		log.Printf("Failed to delete existing data for shipment %s (ID: %s): %v", existingShipment.ShipmentNumber, shipmentID, err)
-		tx.Rollback()
-		return nil, fmt.Errorf("failed to delete existing shipment data: %w", err)
-	}
-	log.Printf("Successfully cleaned up existing data for shipment %s", existingShipment.ShipmentNumber)
-
-	// Update shipment metadata
-	shipmentModel := &models.Shipment{
-		ShipmentNumber: apiResponse.Metadata.ShipmentNumber,
-		ShipmentType:   apiResponse.Metadata.ShipmentType,
-		SealineCode:    apiResponse.Metadata.Sealine,
-		SealineName:    apiResponse.Metadata.SealineName,
-		ShippingStatus: apiResponse.Metadata.ShippingStatus,
-		Warnings:       apiResponse.Metadata.Warnings,
+		containerMilestones = append(containerMilestones, milestones...)
 	}
 
-	log.Printf("Updating shipment metadata for %s", existingShipment.ShipmentNumber)
-	// Use transaction context for update
-	err = tx.Model(&models.Shipment{}).Where("id = ?", shipmentID).Updates(shipmentModel).Error
-	if err != nil {
-		log.Printf("Failed to update shipment metadata for %s: %v", existingShipment.ShipmentNumber, err)
-		tx.Rollback()
-		return nil, fmt.Errorf("failed to update shipment: %w", err)
-	}
-
-	// Get the updated shipment
-	var shipment models.Shipment
-	err = tx.First(&shipment, "id = ?", shipmentID).Error
-	if err != nil {
-		log.Printf("Failed to retrieve updated shipment %s: %v", existingShipment.ShipmentNumber, err)
-		tx.Rollback()
-		return nil, fmt.Errorf("failed to get updated shipment: %w", err)
-	}
-
-	log.Printf("Recreating related data for shipment %s", shipment.ShipmentNumber)
-	// Recreate all related data from fresh API response
-	stats, err := s.recreateShipmentRelatedData(txCtx, &shipment, apiResponse)
-	if err != nil {
-		log.Printf("Failed to recreate related data for shipment %s: %v", shipment.ShipmentNumber, err)
-		tx.Rollback()
-		return nil, fmt.Errorf("failed to recreate shipment data: %w", err)
-	}
-
-	// Commit the transaction
-	if err := tx.Commit().Error; err != nil {
-		log.Printf("Failed to commit transaction for shipment %s: %v", shipment.ShipmentNumber, err)
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
-	}
+	return s.repo.ReplaceShipmentMilestones(ctx, shipmentID, models.DeriveShipmentMilestones(shipmentID, containerMilestones))
+}
 
-	// Get data summary after sync
-	afterSummary, err := s.repo.GetShipmentDataSummary(ctx, shipmentID)
+func (s *shipmentService) SyncShipment(ctx context.Context, userID, shipmentID uuid.UUID) (*models.Shipment, error) {
+	// Validate shipment before starting sync
+	existingShipment, err := s.validateShipmentForSync(ctx, userID, shipmentID)
 	if err != nil {
-		log.Printf("Warning: Failed to get after-sync summary for shipment %s: %v", shipmentID, err)
-	} else {
-		log.Printf("After sync - Shipment %s data counts: locations=%d, routes=%d, vessels=%d, facilities=%d, containers=%d, events=%d, segments=%d, coordinates=%d, ais=%d",
-			shipment.ShipmentNumber, afterSummary.LocationsCount, afterSummary.RoutesCount, afterSummary.VesselsCount,
-			afterSummary.FacilitiesCount, afterSummary.ContainersCount, afterSummary.ContainerEventsCount,
-			afterSummary.RouteSegmentsCount, afterSummary.CoordinatesCount, afterSummary.AisCount)
+		return nil, err
 	}
 
-	log.Printf("Successfully synced shipment %s (ID: %s) with fresh data from SafeCube API. Created: %d locations, %d routes, %d vessels, %d facilities, %d containers, %d events, %d segments, %d coordinates, %d AIS records",
-		shipment.ShipmentNumber, shipment.ID,
-		stats.LocationsCreated, stats.RoutesCreated, stats.VesselsCreated, stats.FacilitiesCreated,
-		stats.ContainersCreated, stats.ContainerEventsCreated, stats.RouteSegmentsCreated,
-		stats.CoordinatesCreated, stats.AisRecordsCreated)
-
-	return &shipment, nil
+	return s.coordinatedSync(ctx, existingShipment.ID)
 }
 
 func (s *shipmentService) RefreshShipment(ctx context.Context, userID, shipmentID uuid.UUID) (*models.Shipment, error) {
@@ -1021,6 +1138,17 @@ func (s *shipmentService) SystemRefreshShipment(ctx context.Context, shipmentID
 	shipment, err := s.SystemSyncShipment(ctx, shipmentID)
 	if err != nil {
 		log.Printf("Failed to system refresh shipment %s: %v", shipmentID, err)
+
+		// Rejected during shutdown, the shipment is still due on the next start
+		if errors.Is(err, ErrSyncShuttingDown) {
+			return nil, err
+		}
+
+		// Back off so a failing shipment doesn't burn quota on every run
+		nextRefreshAt, tier := s.refreshPolicy.Retry(time.Now())
+		if scheduleErr := s.repo.UpdateRefreshSchedule(ctx, shipmentID, nextRefreshAt, tier); scheduleErr != nil {
+			log.Printf("Warning: Failed to schedule retry for shipment %s: %v", shipmentID, scheduleErr)
+		}
 		return nil, err
 	}
 
@@ -1037,13 +1165,52 @@ func (s *shipmentService) SystemSyncShipment(ctx context.Context, shipmentID uui
 		return nil, fmt.Errorf("shipment not found: %w", err)
 	}
 
-	// Skip delivered shipments - no need to refresh completed shipments
-	if existingShipment.ShippingStatus == "delivered" {
-		log.Printf("Skipping delivered shipment %s", existingShipment.ShipmentNumber)
+	// Skip shipments whose tracking was stopped by the tracking policy
+	if existingShipment.TrackingState == models.TrackingStateCompleted {
+		log.Printf("Skipping completed shipment %s", existingShipment.ShipmentNumber)
 		return &existingShipment, nil
 	}
 
-	log.Printf("Starting system sync for shipment %s (ID: %s)", existingShipment.ShipmentNumber, shipmentID)
+	return s.coordinatedSync(ctx, shipmentID)
+}
+
+// coordinatedSync runs the sync through the shared single-flight group so that concurrent
+// callers for the same shipment share one SafeCube call and one result.
+// The work is detached from the caller's cancellation because other callers may be waiting on it,
+// it is only cancelled when a shutdown runs out of time.
+func (s *shipmentService) coordinatedSync(ctx context.Context, shipmentID uuid.UUID) (*models.Shipment, error) {
+	result, err, shared := shipmentSyncGroup.Do(shipmentID.String(), func() (interface{}, error) {
+		syncCtx, done, err := inFlightSyncs.begin(ctx, shipmentID)
+		if err != nil {
+			return nil, err
+		}
+		defer done()
+
+		return s.syncShipmentData(syncCtx, shipmentID)
+	})
+	if shared {
+		log.Printf("Sync of shipment %s was shared between concurrent callers", shipmentID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Hand every caller its own copy, the result is shared between goroutines
+	shipment := *result.(*models.Shipment)
+	return &shipment, nil
+}
+
+// syncShipmentData replaces all related data of a shipment with fresh data from SafeCube.
+// The shipment row is locked for the duration of the transaction so syncs running in
+// other instances wait instead of deleting and recreating the same rows concurrently.
+func (s *shipmentService) syncShipmentData(ctx context.Context, shipmentID uuid.UUID) (*models.Shipment, error) {
+	var existingShipment models.Shipment
+	err := s.repo.GetDB().DB.WithContext(ctx).First(&existingShipment, "id = ?", shipmentID).Error
+	if err != nil {
+		return nil, fmt.Errorf("shipment not found: %w", err)
+	}
+
+	log.Printf("Starting sync for shipment %s (ID: %s)", existingShipment.ShipmentNumber, shipmentID)
 
 	// Get data summary before sync
 	beforeSummary, err := s.repo.GetShipmentDataSummary(ctx, shipmentID)
@@ -1055,6 +1222,11 @@ func (s *shipmentService) SystemSyncShipment(ctx context.Context, shipmentID uui
 			beforeSummary.FacilitiesCount, beforeSummary.ContainersCount, beforeSummary.ContainerEventsCount,
 			beforeSummary.RouteSegmentsCount, beforeSummary.CoordinatesCount, beforeSummary.AisCount)
 	}
+	// Remember the state before the sync so listeners can see what changed
+	beforeSnapshot, err := s.repo.GetShipmentSnapshot(ctx, shipmentID)
+	if err != nil {
+		log.Printf("Warning: Failed to get before-sync snapshot for shipment %s: %v", shipmentID, err)
+	}
 
 	// Get fresh data from SafeCube API
 	apiResponse, err := s.safeCubeAPIService.GetShipmentDetails(
@@ -1068,13 +1240,13 @@ func (s *shipmentService) SystemSyncShipment(ctx context.Context, shipmentID uui
 	}
 
 	// Start a transaction to ensure data consistency
-	tx := s.repo.GetDB().DB.Begin()
+	tx := s.repo.GetDB().DB.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", tx.Error)
 	}
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("Panic occurred during system shipment sync, rolling back transaction: %v", r)
+			log.Printf("Panic occurred during shipment sync, rolling back transaction: %v", r)
 			tx.Rollback()
 			panic(r)
 		}
@@ -1083,22 +1255,31 @@ func (s *shipmentService) SystemSyncShipment(ctx context.Context, shipmentID uui
 	// Create a context with the transaction
 	txCtx := context.WithValue(ctx, "tx", tx)
 
-	log.Printf("Cleaning up existing data for shipment %s", existingShipment.ShipmentNumber)
+	// Serialize with syncs of the same shipment running elsewhere
+	if _, err := s.repo.LockShipmentForSync(txCtx, shipmentID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	log.Printf("Cleaning up existing data for shipment %s (ID: %s)", existingShipment.ShipmentNumber, shipmentID)
 	// Delete all existing related data
 	err = s.repo.DeleteAllShipmentRelatedData(txCtx, shipmentID)
 	if err != nil {
-		log.Printf("Failed to delete existing data for shipment %s: %v", existingShipment.ShipmentNumber, err)
+		log.Printf("Failed to delete existing data for shipment %s (ID: %s): %v", existingShipment.ShipmentNumber, shipmentID, err)
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to delete existing shipment data: %w", err)
 	}
+	log.Printf("Successfully cleaned up existing data for shipment %s", existingShipment.ShipmentNumber)
 
 	// Update shipment metadata
+	status := models.NormalizeShipmentStatus(apiResponse.Metadata.ShippingStatus)
 	shipmentModel := &models.Shipment{
 		ShipmentNumber: apiResponse.Metadata.ShipmentNumber,
 		ShipmentType:   apiResponse.Metadata.ShipmentType,
 		SealineCode:    apiResponse.Metadata.Sealine,
 		SealineName:    apiResponse.Metadata.SealineName,
-		ShippingStatus: apiResponse.Metadata.ShippingStatus,
+		ShippingStatus: string(status),
+		ProviderStatus: apiResponse.Metadata.ShippingStatus,
 		Warnings:       apiResponse.Metadata.Warnings,
 	}
 
@@ -1111,6 +1292,15 @@ func (s *shipmentService) SystemSyncShipment(ctx context.Context, shipmentID uui
 		return nil, fmt.Errorf("failed to update shipment: %w", err)
 	}
 
+	// Updates skips nil values, terminal_since has to be written explicitly so it can be cleared
+	terminalSince := s.trackingPolicy.TerminalSince(existingShipment.TerminalSince, status, time.Now())
+	err = tx.Model(&models.Shipment{}).Where("id = ?", shipmentID).UpdateColumn("terminal_since", terminalSince).Error
+	if err != nil {
+		log.Printf("Failed to update terminal status for %s: %v", existingShipment.ShipmentNumber, err)
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update shipment: %w", err)
+	}
+
 	// Get the updated shipment
 	var shipment models.Shipment
 	err = tx.First(&shipment, "id = ?", shipmentID).Error
@@ -1146,15 +1336,126 @@ func (s *shipmentService) SystemSyncShipment(ctx context.Context, shipmentID uui
 			afterSummary.RouteSegmentsCount, afterSummary.CoordinatesCount, afterSummary.AisCount)
 	}
 
-	log.Printf("Successfully system synced shipment %s (ID: %s) with fresh data from SafeCube API. Created: %d locations, %d routes, %d vessels, %d facilities, %d containers, %d events, %d segments, %d coordinates, %d AIS records",
+	log.Printf("Successfully synced shipment %s (ID: %s) with fresh data from SafeCube API. Created: %d locations, %d routes, %d vessels, %d facilities, %d containers, %d events, %d segments, %d coordinates, %d AIS records",
 		shipment.ShipmentNumber, shipment.ID,
 		stats.LocationsCreated, stats.RoutesCreated, stats.VesselsCreated, stats.FacilitiesCreated,
 		stats.ContainersCreated, stats.ContainerEventsCreated, stats.RouteSegmentsCreated,
 		stats.CoordinatesCreated, stats.AisRecordsCreated)
 
+	s.afterSync(ctx, &shipment)
+	// Without the previous state every existing event would look new to listeners
+	if beforeSnapshot != nil {
+		s.publishSync(ctx, &shipment, beforeSnapshot)
+	}
+
 	return &shipment, nil
 }
 
+// publishSync notifies sync listeners with the state of the shipment before and after the sync.
+// before is nil for newly created shipments.
+func (s *shipmentService) publishSync(ctx context.Context, shipment *models.Shipment, before *types.ShipmentSnapshot) {
+	after, err := s.repo.GetShipmentSnapshot(ctx, shipment.ID)
+	if err != nil {
+		log.Printf("Warning: Failed to get after-sync snapshot for shipment %s: %v", shipment.ShipmentNumber, err)
+		return
+	}
+
+	syncListeners.notify(ctx, types.ShipmentSyncEvent{
+		ShipmentID: shipment.ID,
+		Before:     before,
+		After:      after,
+		SyncedAt:   time.Now(),
+	})
+}
+
+// DrainSyncs stops accepting new syncs and waits for running ones until ctx is done.
+// Syncs that are still running then are cancelled and recorded as pending so they are
+// resumed on the next start. Draining is process wide, it affects every service instance.
+func (s *shipmentService) DrainSyncs(ctx context.Context) error {
+	interrupted := inFlightSyncs.drain(ctx)
+	if len(interrupted) == 0 {
+		log.Println("All shipment syncs finished")
+		return nil
+	}
+
+	log.Printf("Interrupted %d shipment syncs, recording them for the next start", len(interrupted))
+	if err := s.repo.RecordPendingSyncs(context.Background(), interrupted, "interrupted by shutdown"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// afterSync applies the tracking policy to a freshly synced shipment and schedules its next
+// refresh if it is still being tracked
+func (s *shipmentService) afterSync(ctx context.Context, shipment *models.Shipment) {
+	if shipment.TrackingState == models.TrackingStateCompleted {
+		return
+	}
+
+	now := time.Now()
+	if s.trackingPolicy.ShouldStop(shipment, now) {
+		if err := s.repo.StopShipmentTracking(ctx, shipment.ID, now); err != nil {
+			log.Printf("Warning: Failed to stop tracking shipment %s: %v", shipment.ShipmentNumber, err)
+		} else {
+			shipment.TrackingState = models.TrackingStateCompleted
+			shipment.TrackingStoppedAt = &now
+			shipment.NextRefreshAt = nil
+			shipment.RefreshTier = ""
+			log.Printf("Stopped tracking shipment %s, status %s since %s",
+				shipment.ShipmentNumber, shipment.ShippingStatus, shipment.TerminalSince.Format(time.RFC3339))
+			return
+		}
+	}
+
+	s.scheduleNextRefresh(ctx, shipment)
+}
+
+// ResumeTracking restarts tracking of a shipment that was completed by the tracking policy
+// and syncs it right away
+func (s *shipmentService) ResumeTracking(ctx context.Context, userID, shipmentID uuid.UUID) (*models.Shipment, error) {
+	owns, err := s.repo.CheckUserOwnsShipment(ctx, userID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !owns {
+		return nil, fmt.Errorf("shipment not found or access denied")
+	}
+
+	if err := s.repo.ResumeShipmentTracking(ctx, shipmentID); err != nil {
+		return nil, err
+	}
+	log.Printf("User %s resumed tracking of shipment %s", userID, shipmentID)
+
+	shipment, err := s.SyncShipment(ctx, userID, shipmentID)
+	if err != nil {
+		// Tracking is resumed either way, the background job retries the sync
+		log.Printf("Warning: Failed to sync shipment %s after resuming tracking: %v", shipmentID, err)
+		return s.repo.GetShipmentByID(ctx, userID, shipmentID)
+	}
+
+	return shipment, nil
+}
+
+// scheduleNextRefresh computes and stores when the shipment should be synced next.
+// Failures are only logged, a missing schedule just makes the shipment due on the next run.
+func (s *shipmentService) scheduleNextRefresh(ctx context.Context, shipment *models.Shipment) {
+	signals, err := s.repo.GetRefreshSignals(ctx, shipment.ID)
+	if err != nil {
+		log.Printf("Warning: Failed to get refresh signals for shipment %s: %v", shipment.ShipmentNumber, err)
+		return
+	}
+
+	nextRefreshAt, tier := s.refreshPolicy.Next(time.Now(), *signals)
+	if err := s.repo.UpdateRefreshSchedule(ctx, shipment.ID, nextRefreshAt, tier); err != nil {
+		log.Printf("Warning: Failed to schedule next refresh for shipment %s: %v", shipment.ShipmentNumber, err)
+		return
+	}
+
+	shipment.NextRefreshAt = &nextRefreshAt
+	shipment.RefreshTier = tier
+	log.Printf("Next refresh for shipment %s scheduled at %s (tier: %s)", shipment.ShipmentNumber, nextRefreshAt.Format(time.RFC3339), tier)
+}
+
 func (s *shipmentService) GetShipmentDetails(ctx context.Context, userID, shipmentID uuid.UUID) (*dto.ShipmentDetailsResponse, error) {
 	owns, err := s.repo.CheckUserOwnsShipment(ctx, userID, shipmentID)
 	if err != nil {
@@ -1169,7 +1470,12 @@ func (s *shipmentService) GetShipmentDetails(ctx context.Context, userID, shipme
 		return nil, err
 	}
 
-	return shipmentDetails, nil
+	rows := []dto.ShipmentDetailsResponse{*shipmentDetails}
+	addRouteProgress(rows)
+	s.canonicalizeCarriers(ctx, rows)
+	detailsDecorators.decorate(ctx, userID, rows)
+
+	return &rows[0], nil
 }
 
 func (s *shipmentService) UpdateShipmentInfo(ctx context.Context, userID, shipmentID uuid.UUID, req *dto.UpdateShipmentInfoRequest) error {
@@ -1181,7 +1487,12 @@ func (s *shipmentService) UpdateShipmentInfo(ctx context.Context, userID, shipme
 		return fmt.Errorf("shipment not found or access denied")
 	}
 
-	return s.repo.UpdateShipmentInfo(ctx, userID, shipmentID, req)
+	if err := s.repo.UpdateShipmentInfo(ctx, userID, shipmentID, req); err != nil {
+		return err
+	}
+
+	s.publishEdit(ctx, userID, shipmentID, nil)
+	return nil
 }
 
 func (s *shipmentService) UpdateShipmentInfoPartial(ctx context.Context, userID, shipmentID uuid.UUID, updates map[string]interface{}) error {
@@ -1193,7 +1504,27 @@ func (s *shipmentService) UpdateShipmentInfoPartial(ctx context.Context, userID,
 		return fmt.Errorf("shipment not found or access denied")
 	}
 
-	return s.repo.UpdateShipmentInfoPartial(ctx, userID, shipmentID, updates)
+	if err := s.repo.UpdateShipmentInfoPartial(ctx, userID, shipmentID, updates); err != nil {
+		return err
+	}
+
+	fields := make([]string, 0, len(updates))
+	for field := range updates {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	s.publishEdit(ctx, userID, shipmentID, fields)
+	return nil
+}
+
+func (s *shipmentService) publishEdit(ctx context.Context, userID, shipmentID uuid.UUID, fields []string) {
+	editListeners.notify(ctx, types.ShipmentEditedEvent{
+		UserID:     userID,
+		ShipmentID: shipmentID,
+		Fields:     fields,
+		EditedAt:   time.Now(),
+	})
 }
 
 func (s *shipmentService) DeleteUserShipment(ctx context.Context, userID, shipmentID uuid.UUID) error {
@@ -1201,6 +1532,8 @@ func (s *shipmentService) DeleteUserShipment(ctx context.Context, userID, shipme
 	if err != nil {
 		return err
 	}
+
+	s.publishRemove(ctx, userID, []uuid.UUID{shipmentID})
 	return nil
 }
 
@@ -1209,5 +1542,16 @@ func (s *shipmentService) BulkDeleteUserShipments(ctx context.Context, userID uu
 	if err != nil {
 		return err
 	}
+
+	s.publishRemove(ctx, userID, shipmentIDs)
 	return nil
 }
+
+// publishRemove notifies remove listeners that a user stopped tracking shipments
+func (s *shipmentService) publishRemove(ctx context.Context, userID uuid.UUID, shipmentIDs []uuid.UUID) {
+	removeListeners.notify(ctx, types.ShipmentsRemovedEvent{
+		UserID:      userID,
+		ShipmentIDs: shipmentIDs,
+		RemovedAt:   time.Now(),
+	})
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"go-starter/pkg/singleflight"
+
+	"github.com/google/uuid"
+)
+
+// rollbackGracePeriod is how long cancelled syncs get to roll back during shutdown
+const rollbackGracePeriod = 5 * time.Second
+
+// ErrSyncShuttingDown is returned for syncs requested while the server is shutting down
+var ErrSyncShuttingDown = errors.New("shipment syncs are not accepted during shutdown")
+
+// shipmentSyncGroup coordinates syncs within this process. It is shared by every
+// shipment service instance so user requests and background jobs collapse together.
+var shipmentSyncGroup = singleflight.NewGroup()
+
+// inFlightSyncs tracks running syncs so shutdown can wait for them
+var inFlightSyncs = newSyncTracker()
+
+// SyncStats is a snapshot of the shipment sync coordination counters
+type SyncStats = singleflight.Stats
+
+// GetSyncStats returns how many syncs were executed and how many were collapsed into a running one
+func GetSyncStats() SyncStats {
+	return shipmentSyncGroup.Stats()
+}
+
+// syncTracker keeps track of running syncs and refuses new ones once draining started
+type syncTracker struct {
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	draining bool
+	nextID   int
+	running  map[int]trackedSync
+}
+
+// trackedSync is a running sync, shipmentID is nil for shipments being created
+type trackedSync struct {
+	shipmentID uuid.UUID
+	cancel     context.CancelFunc
+}
+
+func newSyncTracker() *syncTracker {
+	return &syncTracker{
+		running: make(map[int]trackedSync),
+	}
+}
+
+// begin registers a sync and returns the context it has to run with.
+// The context is detached from the caller, other callers may be waiting on the result,
+// and is only cancelled when a shutdown runs out of time. done must be called when the sync ends.
+func (t *syncTracker) begin(ctx context.Context, shipmentID uuid.UUID) (context.Context, func(), error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.draining {
+		return nil, nil, ErrSyncShuttingDown
+	}
+
+	syncCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	id := t.nextID
+	t.nextID++
+	t.running[id] = trackedSync{shipmentID: shipmentID, cancel: cancel}
+	t.wg.Add(1)
+
+	done := func() {
+		t.mu.Lock()
+		delete(t.running, id)
+		t.mu.Unlock()
+		cancel()
+		t.wg.Done()
+	}
+	return syncCtx, done, nil
+}
+
+// drain refuses new syncs and waits for running ones until ctx is done.
+// Syncs still running after that are cancelled so their transactions roll back,
+// their shipment IDs are returned so they can be resumed later.
+func (t *syncTracker) drain(ctx context.Context) []uuid.UUID {
+	t.mu.Lock()
+	t.draining = true
+	t.mu.Unlock()
+
+	if waitGroupWithContext(ctx, &t.wg) {
+		return nil
+	}
+
+	t.mu.Lock()
+	interrupted := make([]uuid.UUID, 0, len(t.running))
+	for _, running := range t.running {
+		if running.shipmentID != uuid.Nil {
+			interrupted = append(interrupted, running.shipmentID)
+		}
+		running.cancel()
+	}
+	t.mu.Unlock()
+
+	// Cancelled syncs only need to roll back, give them a moment to do so
+	graceCtx, cancel := context.WithTimeout(context.Background(), rollbackGracePeriod)
+	defer cancel()
+	if !waitGroupWithContext(graceCtx, &t.wg) {
+		log.Printf("Warning: %d cancelled syncs did not finish within %v", len(interrupted), rollbackGracePeriod)
+	}
+	return interrupted
+}
+
+// waitGroupWithContext waits for wg and reports whether it finished before ctx was done
+func waitGroupWithContext(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSyncTracker_DrainWaitsForRunningSyncs(t *testing.T) {
+	tracker := newSyncTracker()
+
+	_, done, err := tracker.begin(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("begin failed: %v", err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		done()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if interrupted := tracker.drain(ctx); len(interrupted) != 0 {
+		t.Errorf("Expected no interrupted syncs, got %v", interrupted)
+	}
+}
+
+func TestSyncTracker_RefusesSyncsWhileDraining(t *testing.T) {
+	tracker := newSyncTracker()
+	tracker.drain(context.Background())
+
+	_, _, err := tracker.begin(context.Background(), uuid.New())
+	if !errors.Is(err, ErrSyncShuttingDown) {
+		t.Errorf("Expected ErrSyncShuttingDown, got %v", err)
+	}
+}
+
+func TestSyncTracker_DrainCancelsSyncsAfterBudget(t *testing.T) {
+	tracker := newSyncTracker()
+	shipmentID := uuid.New()
+
+	// Caller cancellation must not reach the sync, only the drain may cancel it
+	callerCtx, cancelCaller := context.WithCancel(context.Background())
+	syncCtx, done, err := tracker.begin(callerCtx, shipmentID)
+	if err != nil {
+		t.Fatalf("begin failed: %v", err)
+	}
+	cancelCaller()
+	if syncCtx.Err() != nil {
+		t.Fatal("Expected sync context to be detached from the caller")
+	}
+
+	// The sync finishes as soon as it is cancelled, like a rolled back transaction
+	go func() {
+		<-syncCtx.Done()
+		done()
+	}()
+
+	// A creation without a shipment ID yet is cancelled but not reported
+	createCtx, createDone, err := tracker.begin(context.Background(), uuid.Nil)
+	if err != nil {
+		t.Fatalf("begin failed: %v", err)
+	}
+	go func() {
+		<-createCtx.Done()
+		createDone()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	interrupted := tracker.drain(ctx)
+	if len(interrupted) != 1 || interrupted[0] != shipmentID {
+		t.Errorf("Expected [%s] to be interrupted, got %v", shipmentID, interrupted)
+	}
+}
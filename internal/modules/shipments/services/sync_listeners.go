@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+
+	"go-starter/internal/modules/shipments/types"
+)
+
+// SyncListener is notified after a shipment was created or synced with fresh provider data
+type SyncListener func(ctx context.Context, event types.ShipmentSyncEvent)
+
+// RemoveListener is notified after a user removed shipments from their list
+type RemoveListener func(ctx context.Context, event types.ShipmentsRemovedEvent)
+
+// EditListener is notified after a user edited the info of a shipment
+type EditListener func(ctx context.Context, event types.ShipmentEditedEvent)
+
+// The registries are shared by every shipment service instance so listeners also see
+// syncs started by the background refresh job
+var (
+	syncListeners = &listenerRegistry[types.ShipmentSyncEvent]{
+		describe: func(event types.ShipmentSyncEvent) string {
+			return "Sync listener panicked for shipment " + event.ShipmentID.String()
+		},
+	}
+	removeListeners = &listenerRegistry[types.ShipmentsRemovedEvent]{
+		describe: func(event types.ShipmentsRemovedEvent) string {
+			return "Remove listener panicked for user " + event.UserID.String()
+		},
+	}
+	editListeners = &listenerRegistry[types.ShipmentEditedEvent]{
+		describe: func(event types.ShipmentEditedEvent) string {
+			return "Edit listener panicked for shipment " + event.ShipmentID.String()
+		},
+	}
+)
+
+// listenerRegistry holds the listeners of one kind of shipment event
+type listenerRegistry[E any] struct {
+	mu        sync.RWMutex
+	listeners []func(ctx context.Context, event E)
+	// describe names the listener kind and the event in the log of a panicking listener
+	describe func(event E) string
+}
+
+func (r *listenerRegistry[E]) register(listener func(ctx context.Context, event E)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, listener)
+}
+
+// notify calls all listeners in registration order. A panicking listener is logged and
+// doesn't keep the others from running, the change itself has already been committed.
+func (r *listenerRegistry[E]) notify(ctx context.Context, event E) {
+	r.mu.RLock()
+	listeners := slices.Clone(r.listeners)
+	r.mu.RUnlock()
+
+	for _, listener := range listeners {
+		func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					log.Printf("Warning: %s: %v", r.describe(event), rec)
+				}
+			}()
+			listener(ctx, event)
+		}()
+	}
+}
+
+// RegisterSyncListener adds a listener that is called after every successful shipment sync
+func RegisterSyncListener(listener SyncListener) {
+	syncListeners.register(listener)
+}
+
+// RegisterRemoveListener adds a listener that is called after shipments were removed from a user's list
+func RegisterRemoveListener(listener RemoveListener) {
+	removeListeners.register(listener)
+}
+
+// RegisterEditListener adds a listener that is called after shipment info was edited
+func RegisterEditListener(listener EditListener) {
+	editListeners.register(listener)
+}
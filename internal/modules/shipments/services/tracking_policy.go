@@ -0,0 +1,51 @@
+package services
+
+import (
+	"go-starter/internal/modules/shipments/models"
+	"go-starter/pkg/config"
+	"time"
+)
+
+// TrackingPolicy decides when a shipment in a terminal status stops being tracked.
+// The terminal status has to last StopAfter so late provider corrections are still picked up.
+type TrackingPolicy struct {
+	// StopAfter is how long a shipment must stay terminal before tracking stops (0 = never stop)
+	StopAfter time.Duration
+}
+
+// DefaultTrackingPolicy returns a default tracking policy
+func DefaultTrackingPolicy() TrackingPolicy {
+	return TrackingPolicy{
+		StopAfter: 14 * 24 * time.Hour,
+	}
+}
+
+// NewTrackingPolicyFromConfig builds a tracking policy from the background job configuration
+func NewTrackingPolicyFromConfig(cfg config.BackgroundJobsConfig) TrackingPolicy {
+	return TrackingPolicy{
+		StopAfter: time.Duration(cfg.ShipmentStopTrackingAfterDays) * 24 * time.Hour,
+	}
+}
+
+// TerminalSince returns the terminal_since value for a shipment that now has the given status.
+// The existing timestamp is kept while the shipment stays terminal and cleared once it leaves.
+func (p TrackingPolicy) TerminalSince(previous *time.Time, status models.ShipmentStatus, now time.Time) *time.Time {
+	if !status.IsTerminal() {
+		return nil
+	}
+	if previous != nil {
+		return previous
+	}
+	return &now
+}
+
+// ShouldStop reports whether tracking of the shipment should stop
+func (p TrackingPolicy) ShouldStop(shipment *models.Shipment, now time.Time) bool {
+	if p.StopAfter <= 0 || shipment.TrackingState != models.TrackingStateActive {
+		return false
+	}
+	if !models.ShipmentStatus(shipment.ShippingStatus).IsTerminal() || shipment.TerminalSince == nil {
+		return false
+	}
+	return now.Sub(*shipment.TerminalSince) >= p.StopAfter
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"go-starter/internal/modules/shipments/models"
+	"testing"
+	"time"
+)
+
+func TestTrackingPolicy_TerminalSince(t *testing.T) {
+	policy := DefaultTrackingPolicy()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(-48 * time.Hour)
+
+	if got := policy.TerminalSince(nil, models.ShipmentStatusInTransit, now); got != nil {
+		t.Errorf("Expected nil for non-terminal status, got %v", got)
+	}
+	if got := policy.TerminalSince(&earlier, models.ShipmentStatusInTransit, now); got != nil {
+		t.Errorf("Expected terminal_since to be cleared when leaving terminal status, got %v", got)
+	}
+	if got := policy.TerminalSince(nil, models.ShipmentStatusDelivered, now); got == nil || !got.Equal(now) {
+		t.Errorf("Expected terminal_since to start now, got %v", got)
+	}
+	if got := policy.TerminalSince(&earlier, models.ShipmentStatusCancelled, now); got == nil || !got.Equal(earlier) {
+		t.Errorf("Expected terminal_since to be kept, got %v", got)
+	}
+}
+
+func TestTrackingPolicy_ShouldStop(t *testing.T) {
+	stopAfter := 7 * 24 * time.Hour
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	at := func(d time.Duration) *time.Time {
+		v := now.Add(d)
+		return &v
+	}
+
+	tests := []struct {
+		name     string
+		shipment models.Shipment
+		disabled bool
+		want     bool
+	}{
+		{
+			name:     "delivered longer than stop period",
+			shipment: models.Shipment{ShippingStatus: "DELIVERED", TrackingState: models.TrackingStateActive, TerminalSince: at(-8 * 24 * time.Hour)},
+			want:     true,
+		},
+		{
+			name:     "delivered recently",
+			shipment: models.Shipment{ShippingStatus: "DELIVERED", TrackingState: models.TrackingStateActive, TerminalSince: at(-2 * 24 * time.Hour)},
+			want:     false,
+		},
+		{
+			name:     "in transit",
+			shipment: models.Shipment{ShippingStatus: "IN_TRANSIT", TrackingState: models.TrackingStateActive, TerminalSince: at(-30 * 24 * time.Hour)},
+			want:     false,
+		},
+		{
+			name:     "already completed",
+			shipment: models.Shipment{ShippingStatus: "EMPTY_RETURNED", TrackingState: models.TrackingStateCompleted, TerminalSince: at(-30 * 24 * time.Hour)},
+			want:     false,
+		},
+		{
+			name:     "disabled policy",
+			shipment: models.Shipment{ShippingStatus: "CANCELLED", TrackingState: models.TrackingStateActive, TerminalSince: at(-30 * 24 * time.Hour)},
+			disabled: true,
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := TrackingPolicy{StopAfter: stopAfter}
+			if tt.disabled {
+				policy.StopAfter = 0
+			}
+			if got := policy.ShouldStop(&tt.shipment, now); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go-starter/internal/modules/shipments/models"
+	"go-starter/internal/modules/shipments/repositories"
+	"go-starter/pkg/unlocode"
+)
+
+// UnLocodeImportResult summarizes an import of the UN/LOCODE reference data
+type UnLocodeImportResult struct {
+	Entries int
+	// Matched locations were enriched from the reference, Flagged ones among them have
+	// provider mismatches
+	Matched int
+	Flagged int
+}
+
+type UnLocodeImportService struct {
+	unLocodeRepo *repositories.UnLocodeRepository
+}
+
+func NewUnLocodeImportService(unLocodeRepo *repositories.UnLocodeRepository) *UnLocodeImportService {
+	return &UnLocodeImportService{
+		unLocodeRepo: unLocodeRepo,
+	}
+}
+
+// ImportFiles imports the CSV files of a UN/LOCODE release into the reference table and
+// enriches the existing locations from it. Later files win for locodes listed twice.
+func (s *UnLocodeImportService) ImportFiles(ctx context.Context, paths ...string) (*UnLocodeImportResult, error) {
+	importedAt := time.Now()
+	byLocode := map[string]models.UnLocode{}
+	var order []string
+
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		entries, err := unlocode.Parse(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		for _, entry := range entries {
+			if _, ok := byLocode[entry.Locode]; !ok {
+				order = append(order, entry.Locode)
+			}
+			byLocode[entry.Locode] = referenceEntry(entry, importedAt)
+		}
+	}
+
+	references := make([]models.UnLocode, 0, len(order))
+	for _, locode := range order {
+		references = append(references, byLocode[locode])
+	}
+	if len(references) == 0 {
+		return nil, fmt.Errorf("invalid UN/LOCODE release, no entries found")
+	}
+
+	if err := s.unLocodeRepo.UpsertUnLocodes(ctx, references); err != nil {
+		return nil, fmt.Errorf("failed to store UN/LOCODE entries: %w", err)
+	}
+
+	matched, flagged, err := s.unLocodeRepo.EnrichLocations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enrich locations: %w", err)
+	}
+
+	return &UnLocodeImportResult{
+		Entries: len(references),
+		Matched: matched,
+		Flagged: flagged,
+	}, nil
+}
+
+// referenceEntry converts a code list entry, texts are cut to the column sizes
+func referenceEntry(entry unlocode.Entry, importedAt time.Time) models.UnLocode {
+	return models.UnLocode{
+		Locode:           entry.Locode,
+		CountryCode:      entry.CountryCode,
+		Name:             truncateRunes(entry.Name, 100),
+		NameWoDiacritics: truncateRunes(entry.NameWoDiacritics, 100),
+		Subdivision:      truncateRunes(entry.Subdivision, 3),
+		Status:           truncateRunes(entry.Status, 2),
+		Function:         truncateRunes(entry.Function, 8),
+		IATA:             truncateRunes(entry.IATA, 3),
+		Latitude:         entry.Latitude,
+		Longitude:        entry.Longitude,
+		Remarks:          truncateRunes(entry.Remarks, 255),
+		ImportedAt:       importedAt,
+	}
+}
+
+func truncateRunes(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit])
+}
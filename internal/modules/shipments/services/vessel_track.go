@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go-starter/internal/modules/shipments/dto"
+	"go-starter/internal/modules/shipments/models"
+
+	"github.com/google/uuid"
+)
+
+// trackWindow is the time a vessel carried a shipment
+type trackWindow struct {
+	legSequence int
+	vessel      dto.ShipmentVesselResponse
+	from, to    *time.Time
+}
+
+// GetShipmentTrack returns the recorded positions of the vessels that carried a shipment,
+// optionally limited to a time window. Each vessel contributes its positions between the
+// departure and the actual arrival of its voyage leg, a shipment without legs falls back to
+// the vessel of its AIS data.
+func (s *shipmentService) GetShipmentTrack(ctx context.Context, userID, shipmentID uuid.UUID, from, to *time.Time) (*dto.ShipmentTrackResponse, error) {
+	owns, err := s.repo.CheckUserOwnsShipment(ctx, userID, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !owns {
+		return nil, fmt.Errorf("shipment not found or access denied")
+	}
+
+	legs, err := s.repo.GetVoyageLegs(ctx, shipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load voyage legs: %w", err)
+	}
+
+	windows := legTrackWindows(legs, from, to)
+	if len(windows) == 0 {
+		ais, err := s.repo.GetShipmentAisData(ctx, shipmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AIS data: %w", err)
+		}
+		if ais != nil && ais.Vessel != nil {
+			windows = append(windows, trackWindow{vessel: *ais.Vessel, from: from, to: to})
+		}
+	}
+
+	track := &dto.ShipmentTrackResponse{
+		ShipmentID: shipmentID,
+		From:       from,
+		To:         to,
+		Points:     []dto.ShipmentTrackPointResponse{},
+	}
+	for _, window := range windows {
+		positions, err := s.repo.GetVesselPositions(ctx, window.vessel.Imo, window.vessel.Mmsi, window.from, window.to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load vessel positions: %w", err)
+		}
+		for _, position := range positions {
+			track.Points = append(track.Points, dto.ShipmentTrackPointResponse{
+				Latitude:    position.Latitude,
+				Longitude:   position.Longitude,
+				RecordedAt:  position.RecordedAt,
+				Vessel:      window.vessel.Name,
+				Imo:         window.vessel.Imo,
+				Mmsi:        window.vessel.Mmsi,
+				LegSequence: window.legSequence,
+			})
+		}
+	}
+
+	sort.SliceStable(track.Points, func(i, j int) bool {
+		return track.Points[i].RecordedAt.Before(track.Points[j].RecordedAt)
+	})
+
+	return track, nil
+}
+
+// legTrackWindows clips the voyage legs with a known vessel to a time window. A leg ends
+// with its actual arrival only, the vessel of a leg still at sea keeps reporting.
+func legTrackWindows(legs []models.VoyageLeg, from, to *time.Time) []trackWindow {
+	var windows []trackWindow
+	for _, leg := range legs {
+		if leg.Vessel == nil {
+			continue
+		}
+
+		start, end := leg.DepartureAt, (*time.Time)(nil)
+		if leg.ArrivalActual {
+			end = leg.ArrivalAt
+		}
+		if from != nil && (start == nil || from.After(*start)) {
+			start = from
+		}
+		if to != nil && (end == nil || to.Before(*end)) {
+			end = to
+		}
+		if start != nil && end != nil && start.After(*end) {
+			continue
+		}
+
+		windows = append(windows, trackWindow{
+			legSequence: leg.LegOrder,
+			vessel: dto.ShipmentVesselResponse{
+				Name:     leg.Vessel.Name,
+				Imo:      leg.Vessel.Imo,
+				Mmsi:     leg.Vessel.Mmsi,
+				CallSign: leg.Vessel.CallSign,
+				Flag:     leg.Vessel.Flag,
+			},
+			from: start,
+			to:   end,
+		})
+	}
+	return windows
+}
+
+// recordVesselPosition appends the latest AIS position of a sync to the vessel's history
+func (s *shipmentService) recordVesselPosition(ctx context.Context, aisData *dto.SafeCubeAisDetails) error {
+	if aisData.Vessel == nil || aisData.LastVesselPosition == nil {
+		return nil
+	}
+	position := aisData.LastVesselPosition
+	if position.Lat == nil || position.Lng == nil || position.UpdatedAt == nil {
+		return nil
+	}
+
+	_, err := s.repo.CreateVesselPosition(ctx, &models.VesselPosition{
+		Imo:        aisData.Vessel.Imo,
+		Mmsi:       aisData.Vessel.Mmsi,
+		RecordedAt: *position.UpdatedAt,
+		Latitude:   *position.Lat,
+		Longitude:  *position.Lng,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record vessel position: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"go-starter/internal/modules/shipments/models"
+)
+
+func TestLegTrackWindows(t *testing.T) {
+	day := func(d int) *time.Time {
+		v := time.Date(2025, 5, d, 0, 0, 0, 0, time.UTC)
+		return &v
+	}
+	feeder := &models.Vessel{Name: "FEEDER", Imo: 1, Mmsi: 11}
+	mother := &models.Vessel{Name: "MOTHER", Imo: 2, Mmsi: 22}
+
+	legs := []models.VoyageLeg{
+		{LegOrder: 1, Vessel: feeder, DepartureAt: day(3), DepartureActual: true, ArrivalAt: day(8), ArrivalActual: true},
+		{LegOrder: 2, DepartureAt: day(9), ArrivalAt: day(10), ArrivalActual: true},
+		{LegOrder: 3, Vessel: mother, DepartureAt: day(12), DepartureActual: true, ArrivalAt: day(30)},
+	}
+
+	t.Run("whole voyage", func(t *testing.T) {
+		windows := legTrackWindows(legs, nil, nil)
+		if len(windows) != 2 {
+			t.Fatalf("Expected a window per leg with a vessel, got %d", len(windows))
+		}
+		if windows[0].vessel.Imo != 1 || !windows[0].from.Equal(*day(3)) || !windows[0].to.Equal(*day(8)) {
+			t.Errorf("Unexpected feeder window %+v", windows[0])
+		}
+		if windows[1].legSequence != 3 || windows[1].to != nil {
+			t.Errorf("Expected the leg at sea to stay open, got %+v", windows[1])
+		}
+	})
+
+	t.Run("clipped", func(t *testing.T) {
+		windows := legTrackWindows(legs, day(5), day(20))
+		if len(windows) != 2 {
+			t.Fatalf("Expected 2 windows, got %d", len(windows))
+		}
+		if !windows[0].from.Equal(*day(5)) || !windows[0].to.Equal(*day(8)) {
+			t.Errorf("Expected the feeder window to start at the requested time, got %v - %v", windows[0].from, windows[0].to)
+		}
+		if !windows[1].from.Equal(*day(12)) || !windows[1].to.Equal(*day(20)) {
+			t.Errorf("Expected the mother window to end at the requested time, got %v - %v", windows[1].from, windows[1].to)
+		}
+	})
+
+	t.Run("outside the window", func(t *testing.T) {
+		windows := legTrackWindows(legs, day(9), day(11))
+		if len(windows) != 0 {
+			t.Errorf("Expected no windows between the legs, got %d", len(windows))
+		}
+	})
+}
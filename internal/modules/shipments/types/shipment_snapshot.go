@@ -0,0 +1,84 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShipmentSnapshot is the tracking state of a shipment at one point in time.
+// Snapshots taken before and after a sync let other modules react to what changed.
+type ShipmentSnapshot struct {
+	ShipmentID     uuid.UUID
+	ShipmentNumber string
+	ShippingStatus string
+	// PodETA is the POD date, the predictive ETA when the provider has one
+	PodETA    *time.Time
+	PodActual bool
+	// PodLocode is the UN/LOCODE of the port of discharge
+	PodLocode       string
+	VesselArrival   *time.Time
+	LastEventAt     *time.Time
+	ContainerEvents []ContainerEventSnapshot
+	// VesselPosition is the last AIS position of the carrying vessel, nil when there is none
+	VesselPosition *VesselPositionSnapshot
+}
+
+// ContainerEventSnapshot is a container event as seen in a ShipmentSnapshot
+type ContainerEventSnapshot struct {
+	ContainerNumber string
+	EventCode       string
+	Description     string
+	LocationName    string
+	Date            time.Time
+	IsActual        bool
+}
+
+// VesselPositionSnapshot is the AIS position of a vessel as seen in a ShipmentSnapshot
+type VesselPositionSnapshot struct {
+	VesselName string
+	Imo        int
+	Mmsi       int
+	Latitude   float64
+	Longitude  float64
+	RecordedAt time.Time
+}
+
+// ShipmentSyncEvent describes a completed sync of a shipment
+type ShipmentSyncEvent struct {
+	ShipmentID uuid.UUID
+	// Before is nil for shipments that were just created
+	Before   *ShipmentSnapshot
+	After    *ShipmentSnapshot
+	SyncedAt time.Time
+}
+
+// HasEvent reports whether the snapshot contains an actual event with the given code for the container
+func (s *ShipmentSnapshot) HasEvent(containerNumber, eventCode string, date time.Time) bool {
+	if s == nil {
+		return false
+	}
+	for _, event := range s.ContainerEvents {
+		if event.IsActual && event.ContainerNumber == containerNumber &&
+			event.EventCode == eventCode && event.Date.Equal(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// ShipmentsRemovedEvent describes shipments a user stopped tracking
+type ShipmentsRemovedEvent struct {
+	UserID      uuid.UUID
+	ShipmentIDs []uuid.UUID
+	RemovedAt   time.Time
+}
+
+// ShipmentEditedEvent describes a user's change to the shared shipment info
+type ShipmentEditedEvent struct {
+	UserID     uuid.UUID
+	ShipmentID uuid.UUID
+	// Fields are the edited fields as named in the update request, nil when all of them were replaced
+	Fields   []string
+	EditedAt time.Time
+}
@@ -0,0 +1,102 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Shipment states on a vessel
+const (
+	// OnBoardStatusOnBoard shipments left the port of loading of their leg on the vessel
+	OnBoardStatusOnBoard = "on_board"
+	// OnBoardStatusBooked shipments are yet to be loaded on the vessel
+	OnBoardStatusBooked = "booked"
+)
+
+// Voyage states
+const (
+	VoyageStatusPlanned   = "planned"
+	VoyageStatusUnderway  = "underway"
+	VoyageStatusCompleted = "completed"
+)
+
+// VesselPositionResponse is the last known position of a vessel
+type VesselPositionResponse struct {
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// VesselSummaryResponse is the master data of a vessel that carries shipments of the user
+type VesselSummaryResponse struct {
+	Imo          int                     `json:"imo"`
+	Mmsi         int                     `json:"mmsi"`
+	Name         string                  `json:"name"`
+	CallSign     string                  `json:"callSign"`
+	Flag         string                  `json:"flag"`
+	LastPosition *VesselPositionResponse `json:"lastPosition"`
+	// CurrentVoyage is the voyage the shipments on board sail on
+	CurrentVoyage string `json:"currentVoyage"`
+	OnBoard       int    `json:"onBoard"`
+	Booked        int    `json:"booked"`
+}
+
+// VesselsListResponse represents the response when listing vessels
+type VesselsListResponse struct {
+	Vessels []VesselSummaryResponse `json:"vessels"`
+	Total   int                     `json:"total"`
+}
+
+// PortResponse is a port of a voyage or a shipment
+type PortResponse struct {
+	Name   string `json:"name"`
+	Locode string `json:"locode"`
+}
+
+// PortCallResponse is a call of a voyage at a port, as far as the user's shipments tell
+type PortCallResponse struct {
+	Port            PortResponse `json:"port"`
+	ArrivalAt       *time.Time   `json:"arrivalAt"`
+	ArrivalActual   bool         `json:"arrivalActual"`
+	DepartureAt     *time.Time   `json:"departureAt"`
+	DepartureActual bool         `json:"departureActual"`
+}
+
+// VoyageResponse is a voyage of a vessel with its port calls in order
+type VoyageResponse struct {
+	Voyage    string             `json:"voyage"`
+	Status    string             `json:"status"`
+	PortCalls []PortCallResponse `json:"portCalls"`
+}
+
+// VesselContainerResponse is a container of a shipment on a vessel
+type VesselContainerResponse struct {
+	Number   string `json:"number"`
+	SizeType string `json:"sizeType"`
+	Status   string `json:"status"`
+}
+
+// VesselShipmentResponse is a shipment of the user that is on board a vessel or booked on it.
+// LoadPort and DischargePort are the ends of its leg on the vessel, Pod the end of the shipment.
+type VesselShipmentResponse struct {
+	ShipmentID     uuid.UUID                 `json:"shipmentId"`
+	ShipmentNumber string                    `json:"shipmentNumber"`
+	ShippingStatus string                    `json:"shippingStatus"`
+	Status         string                    `json:"status"`
+	Voyage         string                    `json:"voyage"`
+	LoadPort       PortResponse              `json:"loadPort"`
+	DischargePort  PortResponse              `json:"dischargePort"`
+	DepartureAt    *time.Time                `json:"departureAt"`
+	ArrivalAt      *time.Time                `json:"arrivalAt"`
+	Pod            *PortResponse             `json:"pod"`
+	PodETA         *time.Time                `json:"podEta"`
+	Containers     []VesselContainerResponse `json:"containers"`
+}
+
+// VesselDetailsResponse is a vessel with its voyages and the user's shipments on board
+type VesselDetailsResponse struct {
+	Vessel    VesselSummaryResponse    `json:"vessel"`
+	Voyages   []VoyageResponse         `json:"voyages"`
+	Shipments []VesselShipmentResponse `json:"shipments"`
+}
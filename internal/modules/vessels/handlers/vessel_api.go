@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"go-starter/internal/modules/auth/services"
+	vesselServices "go-starter/internal/modules/vessels/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type VesselAPIHandler struct {
+	vesselService *vesselServices.VesselService
+}
+
+func NewVesselAPIHandler(vesselService *vesselServices.VesselService) *VesselAPIHandler {
+	return &VesselAPIHandler{
+		vesselService: vesselService,
+	}
+}
+
+// GetVessels handles GET /api/vessels
+func (h *VesselAPIHandler) GetVessels(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	response, err := h.vesselService.GetVessels(ctx, userID)
+	if err != nil {
+		return h.serviceError(c, "Failed to retrieve vessels", err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetVessel handles GET /api/vessels/:imo
+func (h *VesselAPIHandler) GetVessel(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	imo, err := strconv.Atoi(c.Param("imo"))
+	if err != nil || imo <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid IMO",
+		})
+	}
+
+	vessel, err := h.vesselService.GetVessel(ctx, userID, imo)
+	if err != nil {
+		return h.serviceError(c, "Failed to retrieve vessel", err)
+	}
+
+	return c.JSON(http.StatusOK, vessel)
+}
+
+// serviceError maps service errors to status codes
+func (h *VesselAPIHandler) serviceError(c echo.Context, message string, err error) error {
+	if strings.Contains(err.Error(), "not found") {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message + ": " + err.Error(),
+	})
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"go-starter/internal/modules/vessels/views"
+
+	"github.com/labstack/echo/v4"
+)
+
+type VesselWEBHandler struct{}
+
+func NewVesselWEBHandler() *VesselWEBHandler {
+	return &VesselWEBHandler{}
+}
+
+// ViewVesselsPage handles GET /vessels
+func (h *VesselWEBHandler) ViewVesselsPage(c echo.Context) error {
+	component := views.VesselsPage()
+	return component.Render(c.Request().Context(), c.Response().Writer)
+}
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	shipmentModels "go-starter/internal/modules/shipments/models"
+	"go-starter/pkg/db"
+
+	"github.com/google/uuid"
+)
+
+type VesselRepository struct {
+	db *db.Database
+}
+
+func NewVesselRepository(database *db.Database) *VesselRepository {
+	return &VesselRepository{
+		db: database,
+	}
+}
+
+// GetLatestPositions returns the latest recorded position of each of the vessels by IMO
+func (r *VesselRepository) GetLatestPositions(ctx context.Context, imos []int) (map[int]shipmentModels.VesselPosition, error) {
+	positions := map[int]shipmentModels.VesselPosition{}
+	if len(imos) == 0 {
+		return positions, nil
+	}
+
+	var latest []shipmentModels.VesselPosition
+	err := r.db.DB.WithContext(ctx).
+		Select("DISTINCT ON (imo) *").
+		Where("imo IN ?", imos).
+		Order("imo, recorded_at DESC").
+		Find(&latest).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest vessel positions: %w", err)
+	}
+
+	for _, position := range latest {
+		positions[position.Imo] = position
+	}
+	return positions, nil
+}
+
+// VesselSummary is a vessel that carries or carried shipments of a user, with the user's
+// shipments on it. The position is the latest AIS position of the shipments.
+type VesselSummary struct {
+	Imo           int
+	Mmsi          int
+	Name          string
+	CallSign      string
+	Flag          string
+	CurrentVoyage string
+	OnBoard       int
+	Booked        int
+	Latitude      *float64
+	Longitude     *float64
+	RecordedAt    *time.Time
+}
+
+// vesselSummariesQuery groups the user's shipments by the vessels of their voyage legs and
+// latest AIS snapshots. A shipment counts once per vessel, by the first leg on it that isn't
+// completed: on board once departed, booked before. In transit shipments without legs are on
+// board the vessel AIS reports them on. The current voyage is the one of the shipment on board
+// arriving first, else the latest voyage that started and has ports still due.
+const vesselSummariesQuery = `
+WITH latest_ais AS (
+	SELECT DISTINCT ON (ais.shipment_id) ais.shipment_id, ais.vessel_id, ais.last_event_voyage, ais.arrival_port_date,
+		ais.last_vessel_position_lat, ais.last_vessel_position_lng, ais.last_vessel_position_update
+	FROM ais
+	JOIN user_shipments us ON us.shipment_id = ais.shipment_id
+	WHERE us.user_id = @user
+	ORDER BY ais.shipment_id, ais.updated_at DESC
+),
+user_legs AS (
+	SELECT vl.* FROM voyage_legs vl
+	JOIN user_shipments us ON us.shipment_id = vl.shipment_id
+	WHERE us.user_id = @user AND vl.vessel_id IS NOT NULL
+),
+vessel_ids AS (
+	SELECT vessel_id FROM user_legs
+	UNION
+	SELECT vessel_id FROM latest_ais WHERE vessel_id IS NOT NULL
+),
+on_vessel AS (
+	SELECT vessel_id, voyage, departure_actual AS on_board, arrival_at
+	FROM (
+		SELECT DISTINCT ON (shipment_id, vessel_id) shipment_id, vessel_id, voyage, departure_actual, arrival_at
+		FROM user_legs
+		WHERE NOT arrival_actual
+		ORDER BY shipment_id, vessel_id, leg_order
+	) current_legs
+	UNION ALL
+	SELECT a.vessel_id, COALESCE(a.last_event_voyage, ''), TRUE, a.arrival_port_date
+	FROM latest_ais a
+	JOIN shipments s ON s.id = a.shipment_id
+	WHERE a.vessel_id IS NOT NULL AND s.shipping_status = 'IN_TRANSIT'
+		AND NOT EXISTS (SELECT 1 FROM voyage_legs vl WHERE vl.shipment_id = a.shipment_id)
+),
+current_voyages AS (
+	SELECT DISTINCT ON (vessel_id) vessel_id, voyage
+	FROM on_vessel
+	WHERE on_board AND voyage <> ''
+	ORDER BY vessel_id, arrival_at ASC NULLS FIRST
+),
+underway_voyages AS (
+	SELECT DISTINCT ON (vessel_id) vessel_id, voyage
+	FROM (
+		SELECT vessel_id, voyage,
+			bool_or(departure_actual OR arrival_actual) AS started,
+			bool_or(arrival_at IS NOT NULL) AND NOT bool_or(arrival_actual) AS due,
+			MIN(LEAST(arrival_at, departure_at)) AS starts_at
+		FROM user_legs
+		GROUP BY vessel_id, voyage, to_location_id
+	) calls
+	GROUP BY vessel_id, voyage
+	HAVING bool_or(started) AND bool_or(due)
+	ORDER BY vessel_id, MIN(starts_at) DESC NULLS LAST
+),
+positions AS (
+	SELECT DISTINCT ON (vessel_id) vessel_id, last_vessel_position_lat AS latitude,
+		last_vessel_position_lng AS longitude, last_vessel_position_update AS recorded_at
+	FROM latest_ais
+	WHERE vessel_id IS NOT NULL AND last_vessel_position_lat IS NOT NULL
+		AND last_vessel_position_lng IS NOT NULL AND last_vessel_position_update IS NOT NULL
+	ORDER BY vessel_id, last_vessel_position_update DESC
+)
+SELECT v.imo, v.mmsi, v.name, v.call_sign, v.flag,
+	COALESCE(cv.voyage, uv.voyage, '') AS current_voyage,
+	COUNT(o.vessel_id) FILTER (WHERE o.on_board) AS on_board,
+	COUNT(o.vessel_id) FILTER (WHERE NOT o.on_board) AS booked,
+	p.latitude, p.longitude, p.recorded_at
+FROM vessel_ids ids
+JOIN vessels v ON v.id = ids.vessel_id
+LEFT JOIN on_vessel o ON o.vessel_id = ids.vessel_id
+LEFT JOIN current_voyages cv ON cv.vessel_id = ids.vessel_id
+LEFT JOIN underway_voyages uv ON uv.vessel_id = ids.vessel_id
+LEFT JOIN positions p ON p.vessel_id = ids.vessel_id
+WHERE v.imo <> 0
+GROUP BY v.imo, v.mmsi, v.name, v.call_sign, v.flag, cv.voyage, uv.voyage, p.latitude, p.longitude, p.recorded_at`
+
+// GetVesselSummaries lists the vessels of the user's shipments with the shipments on them
+func (r *VesselRepository) GetVesselSummaries(ctx context.Context, userID uuid.UUID) ([]VesselSummary, error) {
+	var summaries []VesselSummary
+	if err := r.db.DB.WithContext(ctx).Raw(vesselSummariesQuery, sql.Named("user", userID)).Scan(&summaries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get vessel summaries: %w", err)
+	}
+	return summaries, nil
+}
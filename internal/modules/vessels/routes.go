@@ -0,0 +1,32 @@
+package vessels
+
+import (
+	"go-starter/internal/modules/auth/middlewares"
+	"go-starter/internal/modules/auth/services"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+	"go-starter/internal/modules/vessels/handlers"
+	"go-starter/internal/modules/vessels/repositories"
+	vesselServices "go-starter/internal/modules/vessels/services"
+	"go-starter/pkg/db"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRoutes(e *echo.Echo, api *echo.Group, database *db.Database, shipmentService shipmentServices.ShipmentService) {
+	// Initialize dependencies
+	vesselRepo := repositories.NewVesselRepository(database)
+	vesselService := vesselServices.NewVesselService(vesselRepo, shipmentService)
+	vesselAPIHandler := handlers.NewVesselAPIHandler(vesselService)
+	vesselWEBHandler := handlers.NewVesselWEBHandler()
+
+	// Create JWT service for middleware
+	jwtService := services.NewJWTService()
+
+	// Create vessels group with JWT middleware
+	vesselsGroup := api.Group("/vessels", middlewares.JWTMiddleware(jwtService))
+
+	vesselsGroup.GET("", vesselAPIHandler.GetVessels)     // GET /api/vessels
+	vesselsGroup.GET("/:imo", vesselAPIHandler.GetVessel) // GET /api/vessels/:imo
+
+	e.GET("/vessels", vesselWEBHandler.ViewVesselsPage, middlewares.WebJWTMiddleware(jwtService))
+}
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+	shipmentModels "go-starter/internal/modules/shipments/models"
+	"go-starter/internal/modules/vessels/dto"
+)
+
+// vesselView collects what the user's shipments tell about one vessel
+type vesselView struct {
+	summary   dto.VesselSummaryResponse
+	voyages   map[string]*voyageView
+	shipments []dto.VesselShipmentResponse
+}
+
+// voyageView collects the port calls of a voyage by port
+type voyageView struct {
+	voyage string
+	calls  map[string]*dto.PortCallResponse
+}
+
+// BuildVessels groups the user's shipments by the vessels that carry them. Vessels come from
+// the voyage legs of the shipments, the AIS vessel stands in for shipments without legs.
+// Vessels without an IMO can't be told apart and are skipped. The last position is the
+// latest AIS position of the shipments, callers may replace it with a fresher one.
+func BuildVessels(rows []shipmentDto.ShipmentDetailsResponse) map[int]*dto.VesselDetailsResponse {
+	views := map[int]*vesselView{}
+	view := func(vessel *shipmentDto.ShipmentVesselResponse) *vesselView {
+		if vessel == nil || vessel.Imo == 0 {
+			return nil
+		}
+		v, ok := views[vessel.Imo]
+		if !ok {
+			v = &vesselView{
+				summary: dto.VesselSummaryResponse{Imo: vessel.Imo},
+				voyages: map[string]*voyageView{},
+			}
+			views[vessel.Imo] = v
+		}
+		mergeMasterData(&v.summary, *vessel)
+		return v
+	}
+
+	for _, row := range rows {
+		onVessel := map[int]bool{}
+		for _, leg := range sortedLegs(row.VoyageLegs) {
+			v := view(leg.Vessel)
+			if v == nil {
+				continue
+			}
+			v.addLeg(leg)
+
+			// A shipment appears once per vessel, with the first leg on it that isn't completed
+			if onVessel[leg.Vessel.Imo] || leg.ArrivalActual {
+				continue
+			}
+			onVessel[leg.Vessel.Imo] = true
+			status := dto.OnBoardStatusBooked
+			if leg.DepartureActual {
+				status = dto.OnBoardStatusOnBoard
+			}
+			v.shipments = append(v.shipments, shipmentOnVessel(row, status, leg))
+		}
+
+		ais := row.RouteData.Ais
+		if v := view(ais.Vessel); v != nil {
+			v.mergePosition(ais)
+			if len(row.VoyageLegs) == 0 && row.ShippingStatus == string(shipmentModels.ShipmentStatusInTransit) {
+				v.shipments = append(v.shipments, aisShipmentOnVessel(row))
+			}
+		}
+
+		for i := range row.Vessels {
+			if v, ok := views[row.Vessels[i].Imo]; ok {
+				mergeMasterData(&v.summary, row.Vessels[i])
+			}
+		}
+	}
+
+	vessels := make(map[int]*dto.VesselDetailsResponse, len(views))
+	for imo, v := range views {
+		vessels[imo] = v.details()
+	}
+	return vessels
+}
+
+// SortSummaries orders the vessels with the most shipments on board first
+func SortSummaries(summaries []dto.VesselSummaryResponse) {
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if a.OnBoard != b.OnBoard {
+			return a.OnBoard > b.OnBoard
+		}
+		if a.Booked != b.Booked {
+			return a.Booked > b.Booked
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Imo < b.Imo
+	})
+}
+
+// mergeMasterData fills in the master data the summary is missing
+func mergeMasterData(summary *dto.VesselSummaryResponse, vessel shipmentDto.ShipmentVesselResponse) {
+	if vessel.Imo != summary.Imo {
+		return
+	}
+	if summary.Name == "" {
+		summary.Name = vessel.Name
+	}
+	if summary.Mmsi == 0 {
+		summary.Mmsi = vessel.Mmsi
+	}
+	if summary.CallSign == "" {
+		summary.CallSign = vessel.CallSign
+	}
+	if summary.Flag == "" {
+		summary.Flag = vessel.Flag
+	}
+}
+
+// mergePosition keeps the latest AIS position of the vessel
+func (v *vesselView) mergePosition(ais shipmentDto.ShipmentAisResponse) {
+	if ais.LastVesselPositionLat == nil || ais.LastVesselPositionLng == nil || ais.LastVesselPositionUpdate == nil {
+		return
+	}
+	if v.summary.LastPosition != nil && !ais.LastVesselPositionUpdate.After(v.summary.LastPosition.RecordedAt) {
+		return
+	}
+	v.summary.LastPosition = &dto.VesselPositionResponse{
+		Latitude:   *ais.LastVesselPositionLat,
+		Longitude:  *ais.LastVesselPositionLng,
+		RecordedAt: *ais.LastVesselPositionUpdate,
+	}
+}
+
+// addLeg records the departure and the arrival of a leg as port calls of its voyage.
+// Shipments on the same voyage share calls, actual times win over estimates.
+func (v *vesselView) addLeg(leg shipmentDto.ShipmentVoyageLegResponse) {
+	voyage, ok := v.voyages[leg.Voyage]
+	if !ok {
+		voyage = &voyageView{voyage: leg.Voyage, calls: map[string]*dto.PortCallResponse{}}
+		v.voyages[leg.Voyage] = voyage
+	}
+
+	from := voyage.call(leg.From)
+	mergeTime(&from.DepartureAt, &from.DepartureActual, leg.DepartureAt, leg.DepartureActual)
+	to := voyage.call(leg.To)
+	mergeTime(&to.ArrivalAt, &to.ArrivalActual, leg.ArrivalAt, leg.ArrivalActual)
+}
+
+func (v *voyageView) call(location shipmentDto.ShipmentLocationResponse) *dto.PortCallResponse {
+	port := portOf(location)
+	key := port.Locode
+	if key == "" {
+		key = strings.ToUpper(port.Name)
+	}
+	call, ok := v.calls[key]
+	if !ok {
+		call = &dto.PortCallResponse{Port: port}
+		v.calls[key] = call
+	}
+	return call
+}
+
+func mergeTime(at **time.Time, actual *bool, candidate *time.Time, candidateActual bool) {
+	if candidate == nil || (*actual && !candidateActual) {
+		return
+	}
+	if *at == nil || candidateActual && !*actual {
+		*at, *actual = candidate, candidateActual
+	}
+}
+
+// details orders the voyages and shipments of the vessel and counts the shipments on board
+func (v *vesselView) details() *dto.VesselDetailsResponse {
+	details := &dto.VesselDetailsResponse{
+		Vessel:    v.summary,
+		Voyages:   []dto.VoyageResponse{},
+		Shipments: v.shipments,
+	}
+	if details.Shipments == nil {
+		details.Shipments = []dto.VesselShipmentResponse{}
+	}
+
+	for _, voyage := range v.voyages {
+		details.Voyages = append(details.Voyages, voyage.response())
+	}
+	sort.Slice(details.Voyages, func(i, j int) bool {
+		a, b := voyageStart(details.Voyages[i]), voyageStart(details.Voyages[j])
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return details.Voyages[i].Voyage < details.Voyages[j].Voyage
+	})
+
+	sort.SliceStable(details.Shipments, func(i, j int) bool {
+		a, b := details.Shipments[i], details.Shipments[j]
+		if a.Status != b.Status {
+			return a.Status == dto.OnBoardStatusOnBoard
+		}
+		return timeOrZero(a.ArrivalAt).Before(timeOrZero(b.ArrivalAt))
+	})
+
+	for _, shipment := range details.Shipments {
+		if shipment.Status == dto.OnBoardStatusOnBoard {
+			details.Vessel.OnBoard++
+			if details.Vessel.CurrentVoyage == "" {
+				details.Vessel.CurrentVoyage = shipment.Voyage
+			}
+		} else {
+			details.Vessel.Booked++
+		}
+	}
+	if details.Vessel.CurrentVoyage == "" {
+		for _, voyage := range details.Voyages {
+			if voyage.Status == dto.VoyageStatusUnderway {
+				details.Vessel.CurrentVoyage = voyage.Voyage
+				break
+			}
+		}
+	}
+
+	return details
+}
+
+// response orders the port calls by time. A voyage is completed when the vessel arrived at
+// every port it was due at, underway from its first actual departure or arrival.
+func (v *voyageView) response() dto.VoyageResponse {
+	response := dto.VoyageResponse{Voyage: v.voyage, Status: dto.VoyageStatusPlanned}
+	for _, call := range v.calls {
+		response.PortCalls = append(response.PortCalls, *call)
+	}
+	sort.Slice(response.PortCalls, func(i, j int) bool {
+		a, b := callTime(response.PortCalls[i]), callTime(response.PortCalls[j])
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return response.PortCalls[i].Port.Name < response.PortCalls[j].Port.Name
+	})
+
+	started, arrived := false, true
+	for _, call := range response.PortCalls {
+		if call.DepartureActual || call.ArrivalActual {
+			started = true
+		}
+		if call.ArrivalAt != nil && !call.ArrivalActual {
+			arrived = false
+		}
+	}
+	switch {
+	case started && arrived:
+		response.Status = dto.VoyageStatusCompleted
+	case started:
+		response.Status = dto.VoyageStatusUnderway
+	}
+	return response
+}
+
+func callTime(call dto.PortCallResponse) time.Time {
+	if call.ArrivalAt != nil {
+		return *call.ArrivalAt
+	}
+	return timeOrZero(call.DepartureAt)
+}
+
+func voyageStart(voyage dto.VoyageResponse) time.Time {
+	if len(voyage.PortCalls) == 0 {
+		return time.Time{}
+	}
+	return callTime(voyage.PortCalls[0])
+}
+
+func shipmentOnVessel(row shipmentDto.ShipmentDetailsResponse, status string, leg shipmentDto.ShipmentVoyageLegResponse) dto.VesselShipmentResponse {
+	shipment := baseShipment(row, status)
+	shipment.Voyage = leg.Voyage
+	shipment.LoadPort = portOf(leg.From)
+	shipment.DischargePort = portOf(leg.To)
+	shipment.DepartureAt = leg.DepartureAt
+	shipment.ArrivalAt = leg.ArrivalAt
+	return shipment
+}
+
+// aisShipmentOnVessel places a shipment without voyage legs on the vessel AIS reports it on
+func aisShipmentOnVessel(row shipmentDto.ShipmentDetailsResponse) dto.VesselShipmentResponse {
+	shipment := baseShipment(row, dto.OnBoardStatusOnBoard)
+	ais := row.RouteData.Ais
+	if ais.LastEventVoyage != nil {
+		shipment.Voyage = *ais.LastEventVoyage
+	}
+	shipment.LoadPort = aisPort(ais.DeparturePortName, ais.DeparturePortCode)
+	shipment.DischargePort = aisPort(ais.ArrivalPortName, ais.ArrivalPortCode)
+	shipment.DepartureAt = ais.DeparturePortDate
+	shipment.ArrivalAt = ais.ArrivalPortDate
+	return shipment
+}
+
+func baseShipment(row shipmentDto.ShipmentDetailsResponse, status string) dto.VesselShipmentResponse {
+	shipment := dto.VesselShipmentResponse{
+		ShipmentID:     row.ID,
+		ShipmentNumber: row.ShipmentNumber,
+		ShippingStatus: row.ShippingStatus,
+		Status:         status,
+		Containers:     make([]dto.VesselContainerResponse, 0, len(row.Containers)),
+	}
+	if pod := row.Route.Pod; pod != nil {
+		port := portOf(pod.Location)
+		shipment.Pod = &port
+		shipment.PodETA = pod.Date
+		if pod.PredictiveETA != nil {
+			shipment.PodETA = pod.PredictiveETA
+		}
+	}
+	for _, container := range row.Containers {
+		shipment.Containers = append(shipment.Containers, dto.VesselContainerResponse{
+			Number:   container.Number,
+			SizeType: container.SizeType,
+			Status:   container.Status,
+		})
+	}
+	return shipment
+}
+
+func portOf(location shipmentDto.ShipmentLocationResponse) dto.PortResponse {
+	return dto.PortResponse{Name: location.Name, Locode: location.Locode}
+}
+
+func aisPort(name, code *string) dto.PortResponse {
+	var port dto.PortResponse
+	if name != nil {
+		port.Name = *name
+	}
+	if code != nil {
+		port.Locode = *code
+	}
+	return port
+}
+
+func sortedLegs(legs []shipmentDto.ShipmentVoyageLegResponse) []shipmentDto.ShipmentVoyageLegResponse {
+	sorted := append([]shipmentDto.ShipmentVoyageLegResponse(nil), legs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Sequence < sorted[j].Sequence
+	})
+	return sorted
+}
+
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
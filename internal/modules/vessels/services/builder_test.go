@@ -0,0 +1,163 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	shipmentDto "go-starter/internal/modules/shipments/dto"
+	"go-starter/internal/modules/vessels/dto"
+
+	"github.com/google/uuid"
+)
+
+var (
+	feeder    = &shipmentDto.ShipmentVesselResponse{Name: "FEEDER", Imo: 9000001}
+	mother    = &shipmentDto.ShipmentVesselResponse{Name: "MOTHER", Imo: 9000002, Mmsi: 255000002}
+	shanghai  = shipmentDto.ShipmentLocationResponse{Name: "Shanghai", Locode: "CNSHA"}
+	singapore = shipmentDto.ShipmentLocationResponse{Name: "Singapore", Locode: "SGSIN"}
+	rotterdam = shipmentDto.ShipmentLocationResponse{Name: "Rotterdam", Locode: "NLRTM"}
+)
+
+func vesselAt(day int) *time.Time {
+	t := time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+// transshippedShipment sails Shanghai to Singapore on the feeder and Singapore to Rotterdam
+// on the mother vessel, it left Shanghai and arrived in Singapore when transshipped is set
+func transshippedShipment(number string, transshipped bool) shipmentDto.ShipmentDetailsResponse {
+	return shipmentDto.ShipmentDetailsResponse{
+		ID:             uuid.New(),
+		ShipmentNumber: number,
+		ShippingStatus: "IN_TRANSIT",
+		Vessels:        []shipmentDto.ShipmentVesselResponse{*feeder, {Name: "MOTHER", Imo: 9000002, Flag: "MT"}},
+		Containers:     []shipmentDto.ShipmentContainerResponse{{Number: number + "0000001", SizeType: "40HC"}},
+		Route: shipmentDto.ShipmentRouteResponse{
+			Pod: &shipmentDto.ShipmentRoutePoint{Location: rotterdam, Date: vesselAt(30), PredictiveETA: vesselAt(31)},
+		},
+		VoyageLegs: []shipmentDto.ShipmentVoyageLegResponse{
+			{
+				Sequence: 2, Vessel: mother, Voyage: "002W", From: singapore, To: rotterdam,
+				DepartureAt: vesselAt(12), DepartureActual: transshipped, ArrivalAt: vesselAt(30),
+			},
+			{
+				Sequence: 1, Vessel: feeder, Voyage: "001S", From: shanghai, To: singapore,
+				DepartureAt: vesselAt(1), DepartureActual: true, ArrivalAt: vesselAt(6), ArrivalActual: transshipped,
+			},
+		},
+	}
+}
+
+func TestBuildVessels(t *testing.T) {
+	onMother := transshippedShipment("A", true)
+	onFeeder := transshippedShipment("B", false)
+	onFeeder.VoyageLegs[0].DepartureAt = vesselAt(18)
+	onFeeder.VoyageLegs[1].Voyage = "002S"
+	onFeeder.VoyageLegs[1].DepartureAt = vesselAt(8)
+	onFeeder.VoyageLegs[1].ArrivalAt = vesselAt(13)
+
+	vessels := BuildVessels([]shipmentDto.ShipmentDetailsResponse{onMother, onFeeder})
+	if len(vessels) != 2 {
+		t.Fatalf("vessels = %d, want 2", len(vessels))
+	}
+
+	m := vessels[mother.Imo]
+	if m.Vessel.Name != "MOTHER" || m.Vessel.Mmsi != 255000002 || m.Vessel.Flag != "MT" {
+		t.Errorf("mother master data = %+v", m.Vessel)
+	}
+	if m.Vessel.OnBoard != 1 || m.Vessel.Booked != 1 || m.Vessel.CurrentVoyage != "002W" {
+		t.Errorf("mother counts = %d on board, %d booked, voyage %q", m.Vessel.OnBoard, m.Vessel.Booked, m.Vessel.CurrentVoyage)
+	}
+	if m.Shipments[0].ShipmentNumber != "A" || m.Shipments[0].Status != dto.OnBoardStatusOnBoard {
+		t.Errorf("first shipment on mother = %+v, want A on board", m.Shipments[0])
+	}
+	first := m.Shipments[0]
+	if first.LoadPort.Locode != "SGSIN" || first.Pod == nil || first.Pod.Locode != "NLRTM" || !first.PodETA.Equal(*vesselAt(31)) {
+		t.Errorf("shipment A ports = %+v, pod %+v, eta %v", first.LoadPort, first.Pod, first.PodETA)
+	}
+	if len(first.Containers) != 1 || first.Containers[0].Number != "A0000001" {
+		t.Errorf("shipment A containers = %+v", first.Containers)
+	}
+
+	if len(m.Voyages) != 1 || m.Voyages[0].Status != dto.VoyageStatusUnderway {
+		t.Fatalf("mother voyages = %+v, want one underway", m.Voyages)
+	}
+	calls := m.Voyages[0].PortCalls
+	if len(calls) != 2 || calls[0].Port.Locode != "SGSIN" || calls[1].Port.Locode != "NLRTM" {
+		t.Fatalf("mother port calls = %+v", calls)
+	}
+	if !calls[0].DepartureActual || !calls[0].DepartureAt.Equal(*vesselAt(12)) {
+		t.Errorf("Singapore departure = %v actual %v, want the actual departure of shipment A", calls[0].DepartureAt, calls[0].DepartureActual)
+	}
+
+	f := vessels[feeder.Imo]
+	if f.Vessel.OnBoard != 1 || f.Vessel.Booked != 0 || f.Shipments[0].ShipmentNumber != "B" {
+		t.Errorf("feeder = %+v with shipments %+v, want only B on board", f.Vessel, f.Shipments)
+	}
+	if f.Vessel.CurrentVoyage != "002S" || len(f.Voyages) != 2 {
+		t.Fatalf("feeder voyage %q with voyages %+v, want 002S and 001S", f.Vessel.CurrentVoyage, f.Voyages)
+	}
+	if f.Voyages[0].Voyage != "002S" || f.Voyages[0].Status != dto.VoyageStatusUnderway || f.Voyages[1].Status != dto.VoyageStatusCompleted {
+		t.Errorf("feeder voyages = %+v, want 002S underway before 001S completed", f.Voyages)
+	}
+}
+
+func TestBuildVesselsCompletedVoyage(t *testing.T) {
+	row := transshippedShipment("A", true)
+	row.VoyageLegs = row.VoyageLegs[1:]
+
+	vessels := BuildVessels([]shipmentDto.ShipmentDetailsResponse{row})
+	f := vessels[feeder.Imo]
+	if len(f.Shipments) != 0 || f.Vessel.OnBoard != 0 {
+		t.Errorf("feeder shipments = %+v, discharged shipments aren't on board", f.Shipments)
+	}
+	if f.Voyages[0].Status != dto.VoyageStatusCompleted {
+		t.Errorf("feeder voyage status = %q, want completed", f.Voyages[0].Status)
+	}
+}
+
+func TestBuildVesselsFromAIS(t *testing.T) {
+	lat, lng := 1.25, 103.8
+	voyage := "123E"
+	row := shipmentDto.ShipmentDetailsResponse{
+		ID:             uuid.New(),
+		ShipmentNumber: "AIS",
+		ShippingStatus: "IN_TRANSIT",
+		RouteData: shipmentDto.ShipmentRouteDataResponse{
+			Ais: shipmentDto.ShipmentAisResponse{
+				Vessel:                   mother,
+				LastEventVoyage:          &voyage,
+				LastVesselPositionLat:    &lat,
+				LastVesselPositionLng:    &lng,
+				LastVesselPositionUpdate: vesselAt(5),
+			},
+		},
+	}
+	unknown := transshippedShipment("X", true)
+	unknown.VoyageLegs[0].Vessel = &shipmentDto.ShipmentVesselResponse{Name: "NO IMO"}
+
+	vessels := BuildVessels([]shipmentDto.ShipmentDetailsResponse{row, unknown})
+	if _, ok := vessels[0]; ok {
+		t.Error("vessels without IMO are listed")
+	}
+	m := vessels[mother.Imo]
+	if m == nil || len(m.Shipments) != 1 || m.Shipments[0].Voyage != "123E" || m.Shipments[0].Status != dto.OnBoardStatusOnBoard {
+		t.Fatalf("mother = %+v, want the AIS shipment on board", m)
+	}
+	if m.Vessel.LastPosition == nil || m.Vessel.LastPosition.Latitude != lat || !m.Vessel.LastPosition.RecordedAt.Equal(*vesselAt(5)) {
+		t.Errorf("last position = %+v", m.Vessel.LastPosition)
+	}
+}
+
+func TestSortSummaries(t *testing.T) {
+	summaries := []dto.VesselSummaryResponse{
+		{Imo: 1, Name: "B", Booked: 3},
+		{Imo: 2, Name: "A", OnBoard: 1},
+		{Imo: 3, Name: "A", Booked: 3},
+	}
+
+	SortSummaries(summaries)
+	if summaries[0].Imo != 2 || summaries[1].Imo != 3 || summaries[2].Imo != 1 {
+		t.Errorf("order = %d, %d, %d, want 2, 3, 1", summaries[0].Imo, summaries[1].Imo, summaries[2].Imo)
+	}
+}
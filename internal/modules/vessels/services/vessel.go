@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	shipmentServices "go-starter/internal/modules/shipments/services"
+	"go-starter/internal/modules/vessels/dto"
+	"go-starter/internal/modules/vessels/repositories"
+
+	"github.com/google/uuid"
+)
+
+type VesselService struct {
+	vesselRepo      *repositories.VesselRepository
+	shipmentService shipmentServices.ShipmentService
+}
+
+func NewVesselService(vesselRepo *repositories.VesselRepository, shipmentService shipmentServices.ShipmentService) *VesselService {
+	return &VesselService{
+		vesselRepo:      vesselRepo,
+		shipmentService: shipmentService,
+	}
+}
+
+// GetVessels lists the vessels that carry or carried shipments of the user
+func (s *VesselService) GetVessels(ctx context.Context, userID uuid.UUID) (*dto.VesselsListResponse, error) {
+	rows, err := s.vesselRepo.GetVesselSummaries(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]dto.VesselSummaryResponse, len(rows))
+	for i, row := range rows {
+		summaries[i] = dto.VesselSummaryResponse{
+			Imo:           row.Imo,
+			Mmsi:          row.Mmsi,
+			Name:          row.Name,
+			CallSign:      row.CallSign,
+			Flag:          row.Flag,
+			CurrentVoyage: row.CurrentVoyage,
+			OnBoard:       row.OnBoard,
+			Booked:        row.Booked,
+		}
+		if row.Latitude != nil && row.Longitude != nil && row.RecordedAt != nil {
+			summaries[i].LastPosition = &dto.VesselPositionResponse{
+				Latitude:   *row.Latitude,
+				Longitude:  *row.Longitude,
+				RecordedAt: *row.RecordedAt,
+			}
+		}
+	}
+	s.applyRecordedPositions(ctx, summaries)
+
+	SortSummaries(summaries)
+	return &dto.VesselsListResponse{
+		Vessels: summaries,
+		Total:   len(summaries),
+	}, nil
+}
+
+// GetVessel retrieves a vessel by IMO with its voyages and the user's shipments on board
+func (s *VesselService) GetVessel(ctx context.Context, userID uuid.UUID, imo int) (*dto.VesselDetailsResponse, error) {
+	rows, err := s.shipmentService.GetShipmentsByVessel(ctx, userID, imo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipments: %w", err)
+	}
+
+	vessel, ok := BuildVessels(rows)[imo]
+	if !ok {
+		return nil, fmt.Errorf("vessel not found")
+	}
+
+	summaries := []dto.VesselSummaryResponse{vessel.Vessel}
+	s.applyRecordedPositions(ctx, summaries)
+	vessel.Vessel = summaries[0]
+	return vessel, nil
+}
+
+// applyRecordedPositions replaces the AIS positions of the shipments with the recorded
+// vessel positions when they are newer
+func (s *VesselService) applyRecordedPositions(ctx context.Context, summaries []dto.VesselSummaryResponse) {
+	imos := make([]int, len(summaries))
+	for i, summary := range summaries {
+		imos[i] = summary.Imo
+	}
+
+	positions, err := s.vesselRepo.GetLatestPositions(ctx, imos)
+	if err != nil {
+		log.Printf("Warning: Failed to load vessel positions: %v", err)
+		return
+	}
+	for i := range summaries {
+		position, ok := positions[summaries[i].Imo]
+		if !ok {
+			continue
+		}
+		current := summaries[i].LastPosition
+		if current != nil && !position.RecordedAt.After(current.RecordedAt) {
+			continue
+		}
+		summaries[i].LastPosition = &dto.VesselPositionResponse{
+			Latitude:   position.Latitude,
+			Longitude:  position.Longitude,
+			RecordedAt: position.RecordedAt,
+		}
+	}
+}
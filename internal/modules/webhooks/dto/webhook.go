@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"go-starter/internal/modules/webhooks/models"
+)
+
+// SaveSubscriptionRequest represents the request to create or update a webhook subscription
+type SaveSubscriptionRequest struct {
+	URL         string   `json:"url" validate:"required,max=2048"`
+	Description string   `json:"description" validate:"max=255"`
+	EventTypes  []string `json:"event_types" validate:"required,min=1"`
+	Enabled     *bool    `json:"enabled"`
+}
+
+// SubscriptionWithSecretResponse is returned when a subscription is created or its secret
+// rotated, it is the only time the signing secret is shown
+type SubscriptionWithSecretResponse struct {
+	models.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// SubscriptionsListResponse represents the response when listing webhook subscriptions
+type SubscriptionsListResponse struct {
+	Subscriptions []models.WebhookSubscription `json:"subscriptions"`
+	EventTypes    []models.EventType           `json:"event_types"`
+	Total         int                          `json:"total"`
+}
+
+// DeliveriesListResponse represents the response when listing webhook deliveries
+type DeliveriesListResponse struct {
+	Deliveries []models.WebhookDelivery `json:"deliveries"`
+	Total      int                      `json:"total"`
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"go-starter/internal/modules/auth/services"
+	"go-starter/internal/modules/webhooks/dto"
+	webhookServices "go-starter/internal/modules/webhooks/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultDeliveriesLimit is how many deliveries the delivery log returns by default
+const defaultDeliveriesLimit = 50
+
+type WebhookAPIHandler struct {
+	webhookService *webhookServices.WebhookService
+	validator      *validator.Validate
+}
+
+func NewWebhookAPIHandler(webhookService *webhookServices.WebhookService) *WebhookAPIHandler {
+	return &WebhookAPIHandler{
+		webhookService: webhookService,
+		validator:      validator.New(),
+	}
+}
+
+// GetSubscriptions handles GET /api/webhooks
+func (h *WebhookAPIHandler) GetSubscriptions(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	response, err := h.webhookService.GetSubscriptions(ctx, userID)
+	if err != nil {
+		return h.serviceError(c, "Failed to retrieve subscriptions", err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// CreateSubscription handles POST /api/webhooks
+func (h *WebhookAPIHandler) CreateSubscription(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.SaveSubscriptionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Validation failed: " + err.Error(),
+		})
+	}
+
+	subscription, err := h.webhookService.CreateSubscription(ctx, userID, &req)
+	if err != nil {
+		return h.serviceError(c, "Failed to create subscription", err)
+	}
+
+	return c.JSON(http.StatusCreated, subscription)
+}
+
+// UpdateSubscription handles PUT /api/webhooks/:id
+func (h *WebhookAPIHandler) UpdateSubscription(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid subscription ID",
+		})
+	}
+
+	var req dto.SaveSubscriptionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Validation failed: " + err.Error(),
+		})
+	}
+
+	subscription, err := h.webhookService.UpdateSubscription(ctx, userID, subscriptionID, &req)
+	if err != nil {
+		return h.serviceError(c, "Failed to update subscription", err)
+	}
+
+	return c.JSON(http.StatusOK, subscription)
+}
+
+// DeleteSubscription handles DELETE /api/webhooks/:id
+func (h *WebhookAPIHandler) DeleteSubscription(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid subscription ID",
+		})
+	}
+
+	if err := h.webhookService.DeleteSubscription(ctx, userID, subscriptionID); err != nil {
+		return h.serviceError(c, "Failed to delete subscription", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Subscription deleted successfully",
+	})
+}
+
+// RotateSecret handles POST /api/webhooks/:id/rotate-secret
+func (h *WebhookAPIHandler) RotateSecret(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid subscription ID",
+		})
+	}
+
+	subscription, err := h.webhookService.RotateSecret(ctx, userID, subscriptionID)
+	if err != nil {
+		return h.serviceError(c, "Failed to rotate secret", err)
+	}
+
+	return c.JSON(http.StatusOK, subscription)
+}
+
+// SendPing handles POST /api/webhooks/:id/ping
+func (h *WebhookAPIHandler) SendPing(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid subscription ID",
+		})
+	}
+
+	delivery, err := h.webhookService.SendPing(ctx, userID, subscriptionID)
+	if err != nil {
+		return h.serviceError(c, "Failed to send ping", err)
+	}
+
+	return c.JSON(http.StatusOK, delivery)
+}
+
+// GetDeliveries handles GET /api/webhooks/:id/deliveries
+func (h *WebhookAPIHandler) GetDeliveries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid subscription ID",
+		})
+	}
+
+	limit := defaultDeliveriesLimit
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 500 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid limit",
+			})
+		}
+	}
+
+	response, err := h.webhookService.GetDeliveries(ctx, userID, subscriptionID, limit)
+	if err != nil {
+		return h.serviceError(c, "Failed to retrieve deliveries", err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Redeliver handles POST /api/webhooks/deliveries/:deliveryId/redeliver
+func (h *WebhookAPIHandler) Redeliver(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := services.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid delivery ID",
+		})
+	}
+
+	delivery, err := h.webhookService.Redeliver(ctx, userID, deliveryID)
+	if err != nil {
+		return h.serviceError(c, "Failed to redeliver webhook", err)
+	}
+
+	return c.JSON(http.StatusOK, delivery)
+}
+
+// serviceError maps service errors to status codes, validation errors are returned as is
+func (h *WebhookAPIHandler) serviceError(c echo.Context, message string, err error) error {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	case strings.HasPrefix(err.Error(), "invalid"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message + ": " + err.Error(),
+	})
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"go-starter/internal/modules/webhooks/views"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookWEBHandler struct{}
+
+func NewWebhookWEBHandler() *WebhookWEBHandler {
+	return &WebhookWEBHandler{}
+}
+
+// ViewWebhooksPage handles GET /webhooks
+func (h *WebhookWEBHandler) ViewWebhooksPage(c echo.Context) error {
+	component := views.WebhooksPage()
+	return component.Render(c.Request().Context(), c.Response().Writer)
+}
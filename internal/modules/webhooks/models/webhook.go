@@ -0,0 +1,122 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// EventType is a shipment event that can be sent to webhook subscriptions
+type EventType string
+
+const (
+	EventShipmentCreated       EventType = "shipment.created"
+	EventShipmentSynced        EventType = "shipment.synced"
+	EventShipmentStatusChanged EventType = "shipment.status_changed"
+	EventShipmentETAChanged    EventType = "shipment.eta_changed"
+	EventContainerEventAdded   EventType = "shipment.container_event_added"
+	EventShipmentDeleted       EventType = "shipment.deleted"
+	EventPing                  EventType = "ping"
+)
+
+// SubscribableEventTypes are the event types a subscription can choose from
+var SubscribableEventTypes = []EventType{
+	EventShipmentCreated,
+	EventShipmentSynced,
+	EventShipmentStatusChanged,
+	EventShipmentETAChanged,
+	EventContainerEventAdded,
+	EventShipmentDeleted,
+}
+
+// IsValid reports whether a subscription can subscribe to the event type
+func (t EventType) IsValid() bool {
+	for _, eventType := range SubscribableEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery statuses, pending deliveries are sent and retried until they are sent or failed
+const (
+	DeliveryStatusPending = "pending"
+	DeliveryStatusSent    = "sent"
+	DeliveryStatusFailed  = "failed"
+)
+
+// WebhookSubscription sends the selected shipment events of a user to a target URL
+type WebhookSubscription struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	URL         string    `json:"url" gorm:"type:varchar(2048);not null"`
+	Description string    `json:"description" gorm:"type:varchar(255)"`
+	// Secret signs the payloads, it is only returned when it is created or rotated
+	Secret     string         `json:"-" gorm:"type:varchar(100);not null"`
+	EventTypes pq.StringArray `json:"event_types" gorm:"type:text[];not null"`
+	Enabled    bool           `json:"enabled" gorm:"not null;default:true"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time      `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for WebhookSubscription
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (s *WebhookSubscription) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// Wants reports whether the subscription receives the event type
+func (s *WebhookSubscription) Wants(eventType EventType) bool {
+	for _, wanted := range s.EventTypes {
+		if wanted == string(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is the delivery log. Each row is one event sent to one subscription,
+// with the payload so it can be retried and redelivered as it was.
+type WebhookDelivery struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	SubscriptionID uuid.UUID  `json:"subscription_id" gorm:"type:uuid;not null;index"`
+	UserID         uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	EventID        uuid.UUID  `json:"event_id" gorm:"type:uuid;not null;index"`
+	EventType      string     `json:"event_type" gorm:"type:varchar(50);not null"`
+	ShipmentID     *uuid.UUID `json:"shipment_id" gorm:"type:uuid"`
+	Payload        string     `json:"payload" gorm:"type:text;not null"`
+	// RedeliveryOf points a manual redelivery to the delivery it repeats
+	RedeliveryOf   *uuid.UUID `json:"redelivery_of" gorm:"type:uuid"`
+	Status         string     `json:"status" gorm:"type:varchar(20);not null;index"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	ResponseStatus int        `json:"response_status"`
+	ResponseBody   string     `json:"response_body" gorm:"type:text"`
+	LastError      string     `json:"last_error" gorm:"type:text"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at" gorm:"type:timestamptz;index"`
+	DeliveredAt    *time.Time `json:"delivered_at" gorm:"type:timestamptz"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"type:timestamptz;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for WebhookDelivery
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// BeforeCreate hook to set UUID if not provided
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
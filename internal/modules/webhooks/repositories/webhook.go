@@ -0,0 +1,265 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	shipmentModels "go-starter/internal/modules/shipments/models"
+	"go-starter/internal/modules/webhooks/models"
+	"go-starter/pkg/db"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository struct {
+	db *db.Database
+}
+
+func NewWebhookRepository(database *db.Database) *WebhookRepository {
+	return &WebhookRepository{
+		db: database,
+	}
+}
+
+// CreateSubscription creates a new webhook subscription
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	return r.db.DB.WithContext(ctx).Create(subscription).Error
+}
+
+// GetSubscriptionByID retrieves a subscription by its ID and user ID
+func (r *WebhookRepository) GetSubscriptionByID(ctx context.Context, subscriptionID, userID uuid.UUID) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	err := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", subscriptionID, userID).
+		First(&subscription).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// GetSubscriptionsByIDs retrieves subscriptions by their IDs, regardless of the user
+func (r *WebhookRepository) GetSubscriptionsByIDs(ctx context.Context, subscriptionIDs []uuid.UUID) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := r.db.DB.WithContext(ctx).
+		Where("id IN ?", subscriptionIDs).
+		Find(&subscriptions).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// GetSubscriptionsByUserID retrieves all subscriptions of a user
+func (r *WebhookRepository) GetSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&subscriptions).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// GetEnabledSubscriptionsByUserID retrieves the enabled subscriptions of a user
+func (r *WebhookRepository) GetEnabledSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := r.db.DB.WithContext(ctx).
+		Where("user_id = ? AND enabled = ?", userID, true).
+		Find(&subscriptions).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// GetSubscriptionsForShipment retrieves the enabled subscriptions of all users tracking the shipment
+func (r *WebhookRepository) GetSubscriptionsForShipment(ctx context.Context, shipmentID uuid.UUID) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := r.db.DB.WithContext(ctx).
+		Where("enabled = ?", true).
+		Where("user_id IN (?)", r.db.DB.Model(&shipmentModels.UserShipment{}).
+			Select("user_id").
+			Where("shipment_id = ?", shipmentID)).
+		Find(&subscriptions).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscriptions for shipment: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// UpdateSubscription updates the settings of an existing subscription
+func (r *WebhookRepository) UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	return r.db.DB.WithContext(ctx).
+		Model(subscription).
+		Where("id = ? AND user_id = ?", subscription.ID, subscription.UserID).
+		Updates(map[string]interface{}{
+			"url":         subscription.URL,
+			"description": subscription.Description,
+			"event_types": subscription.EventTypes,
+			"enabled":     subscription.Enabled,
+			"updated_at":  gorm.Expr("NOW()"),
+		}).Error
+}
+
+// UpdateSubscriptionSecret replaces the signing secret of a subscription
+func (r *WebhookRepository) UpdateSubscriptionSecret(ctx context.Context, subscriptionID, userID uuid.UUID, secret string) error {
+	result := r.db.DB.WithContext(ctx).
+		Model(&models.WebhookSubscription{}).
+		Where("id = ? AND user_id = ?", subscriptionID, userID).
+		Updates(map[string]interface{}{
+			"secret":     secret,
+			"updated_at": gorm.Expr("NOW()"),
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// DeleteSubscription deletes a subscription and its delivery log
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, subscriptionID, userID uuid.UUID) error {
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", subscriptionID, userID).
+			Delete(&models.WebhookSubscription{})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Where("subscription_id = ?", subscriptionID).
+			Delete(&models.WebhookDelivery{}).Error
+	})
+}
+
+// GetShipmentNumbers retrieves the numbers of the given shipments keyed by shipment ID
+func (r *WebhookRepository) GetShipmentNumbers(ctx context.Context, shipmentIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	var shipments []shipmentModels.Shipment
+	err := r.db.DB.WithContext(ctx).
+		Select("id", "shipment_number").
+		Where("id IN ?", shipmentIDs).
+		Find(&shipments).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	numbers := make(map[uuid.UUID]string, len(shipments))
+	for _, shipment := range shipments {
+		numbers[shipment.ID] = shipment.ShipmentNumber
+	}
+
+	return numbers, nil
+}
+
+// CreateDelivery adds a delivery to the log
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return r.db.DB.WithContext(ctx).Create(delivery).Error
+}
+
+// CreateDeliveries adds deliveries to the log
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return r.db.DB.WithContext(ctx).Create(&deliveries).Error
+}
+
+// GetDeliveryByID retrieves a delivery by its ID and user ID
+func (r *WebhookRepository) GetDeliveryByID(ctx context.Context, deliveryID, userID uuid.UUID) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.db.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", deliveryID, userID).
+		First(&delivery).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// GetDeliveriesBySubscriptionID retrieves the most recent deliveries of a subscription
+func (r *WebhookRepository) GetDeliveriesBySubscriptionID(ctx context.Context, subscriptionID, userID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.DB.WithContext(ctx).
+		Where("subscription_id = ? AND user_id = ?", subscriptionID, userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// ClaimDueDeliveries picks pending deliveries that are due and leases them so other
+// instances running the delivery job skip them while they are being sent
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	err := r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.DeliveryStatusPending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+		return tx.Model(&models.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			UpdateColumn("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// UpdateDeliveryFields updates the given columns of a delivery
+func (r *WebhookRepository) UpdateDeliveryFields(ctx context.Context, deliveryID uuid.UUID, updates map[string]interface{}) error {
+	updates["updated_at"] = gorm.Expr("NOW()")
+
+	return r.db.DB.WithContext(ctx).
+		Model(&models.WebhookDelivery{}).
+		Where("id = ?", deliveryID).
+		Updates(updates).Error
+}
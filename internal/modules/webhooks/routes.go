@@ -0,0 +1,46 @@
+package webhooks
+
+import (
+	"go-starter/internal/modules/auth/middlewares"
+	"go-starter/internal/modules/auth/services"
+	shipmentServices "go-starter/internal/modules/shipments/services"
+	"go-starter/internal/modules/webhooks/handlers"
+	"go-starter/internal/modules/webhooks/repositories"
+	webhookServices "go-starter/internal/modules/webhooks/services"
+	"go-starter/pkg/config"
+	"go-starter/pkg/db"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRoutes(e *echo.Echo, api *echo.Group, database *db.Database, cfg *config.Config) {
+	// Initialize dependencies
+	webhookRepo := repositories.NewWebhookRepository(database)
+	webhookService := webhookServices.NewWebhookService(webhookRepo, webhookServices.NewDeliverySettingsFromConfig(cfg))
+	webhookAPIHandler := handlers.NewWebhookAPIHandler(webhookService)
+	webhookWEBHandler := handlers.NewWebhookWEBHandler()
+
+	// Queue webhooks for shipment changes, they are sent by the webhook delivery job
+	shipmentServices.RegisterSyncListener(webhookService.HandleShipmentSync)
+	shipmentServices.RegisterRemoveListener(webhookService.HandleShipmentsRemoved)
+
+	// Create JWT service for middleware
+	jwtService := services.NewJWTService()
+
+	// Create webhooks group with JWT middleware
+	webhooksGroup := api.Group("/webhooks", middlewares.JWTMiddleware(jwtService))
+
+	// Subscription routes
+	webhooksGroup.GET("", webhookAPIHandler.GetSubscriptions)                // GET /api/webhooks
+	webhooksGroup.POST("", webhookAPIHandler.CreateSubscription)             // POST /api/webhooks
+	webhooksGroup.PUT("/:id", webhookAPIHandler.UpdateSubscription)          // PUT /api/webhooks/:id
+	webhooksGroup.DELETE("/:id", webhookAPIHandler.DeleteSubscription)       // DELETE /api/webhooks/:id
+	webhooksGroup.POST("/:id/rotate-secret", webhookAPIHandler.RotateSecret) // POST /api/webhooks/:id/rotate-secret
+	webhooksGroup.POST("/:id/ping", webhookAPIHandler.SendPing)              // POST /api/webhooks/:id/ping
+
+	// Delivery log routes
+	webhooksGroup.GET("/:id/deliveries", webhookAPIHandler.GetDeliveries)                // GET /api/webhooks/:id/deliveries
+	webhooksGroup.POST("/deliveries/:deliveryId/redeliver", webhookAPIHandler.Redeliver) // POST /api/webhooks/deliveries/:deliveryId/redeliver
+
+	e.GET("/webhooks", webhookWEBHandler.ViewWebhooksPage, middlewares.WebJWTMiddleware(jwtService))
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"time"
+
+	"go-starter/internal/modules/shipments/types"
+	"go-starter/internal/modules/webhooks/models"
+
+	"github.com/google/uuid"
+)
+
+// Payload is the JSON body of a webhook. Every subscription that receives an event gets
+// the same payload, ID identifies the event so receivers can drop duplicates.
+type Payload struct {
+	ID        uuid.UUID        `json:"id"`
+	Type      models.EventType `json:"type"`
+	CreatedAt time.Time        `json:"created_at"`
+	Data      PayloadData      `json:"data"`
+}
+
+// PayloadData describes the shipment an event is about
+type PayloadData struct {
+	ShipmentID     uuid.UUID  `json:"shipment_id,omitempty"`
+	ShipmentNumber string     `json:"shipment_number,omitempty"`
+	ShippingStatus string     `json:"shipping_status,omitempty"`
+	PodETA         *time.Time `json:"pod_eta,omitempty"`
+	// Previous values are set on status and ETA changes
+	PreviousShippingStatus string                 `json:"previous_shipping_status,omitempty"`
+	PreviousPodETA         *time.Time             `json:"previous_pod_eta,omitempty"`
+	ContainerEvent         *PayloadContainerEvent `json:"container_event,omitempty"`
+}
+
+// PayloadContainerEvent is the container event of a shipment.container_event_added event
+type PayloadContainerEvent struct {
+	ContainerNumber string    `json:"container_number"`
+	EventCode       string    `json:"event_code"`
+	Description     string    `json:"description"`
+	LocationName    string    `json:"location_name"`
+	Date            time.Time `json:"date"`
+}
+
+func newPayload(eventType models.EventType, now time.Time, data PayloadData) Payload {
+	return Payload{
+		ID:        uuid.New(),
+		Type:      eventType,
+		CreatedAt: now,
+		Data:      data,
+	}
+}
+
+// PayloadsFromSync derives the webhook events of a completed sync. A new shipment only
+// produces shipment.created, the changes are relative to the previous sync otherwise.
+func PayloadsFromSync(event types.ShipmentSyncEvent, now time.Time) []Payload {
+	after := event.After
+	if after == nil {
+		return nil
+	}
+
+	base := PayloadData{
+		ShipmentID:     event.ShipmentID,
+		ShipmentNumber: after.ShipmentNumber,
+		ShippingStatus: after.ShippingStatus,
+		PodETA:         after.PodETA,
+	}
+
+	before := event.Before
+	if before == nil {
+		return []Payload{newPayload(models.EventShipmentCreated, now, base)}
+	}
+
+	payloads := []Payload{newPayload(models.EventShipmentSynced, now, base)}
+
+	if before.ShippingStatus != after.ShippingStatus {
+		data := base
+		data.PreviousShippingStatus = before.ShippingStatus
+		payloads = append(payloads, newPayload(models.EventShipmentStatusChanged, now, data))
+	}
+
+	if !sameTime(before.PodETA, after.PodETA) {
+		data := base
+		data.PreviousPodETA = before.PodETA
+		payloads = append(payloads, newPayload(models.EventShipmentETAChanged, now, data))
+	}
+
+	for _, containerEvent := range after.ContainerEvents {
+		if !containerEvent.IsActual || before.HasEvent(containerEvent.ContainerNumber, containerEvent.EventCode, containerEvent.Date) {
+			continue
+		}
+		data := base
+		data.ContainerEvent = &PayloadContainerEvent{
+			ContainerNumber: containerEvent.ContainerNumber,
+			EventCode:       containerEvent.EventCode,
+			Description:     containerEvent.Description,
+			LocationName:    containerEvent.LocationName,
+			Date:            containerEvent.Date,
+		}
+		payloads = append(payloads, newPayload(models.EventContainerEventAdded, now, data))
+	}
+
+	return payloads
+}
+
+// PayloadsFromRemoval derives a shipment.deleted event for every shipment a user removed
+func PayloadsFromRemoval(event types.ShipmentsRemovedEvent, shipmentNumbers map[uuid.UUID]string) []Payload {
+	payloads := make([]Payload, 0, len(event.ShipmentIDs))
+	for _, shipmentID := range event.ShipmentIDs {
+		payloads = append(payloads, newPayload(models.EventShipmentDeleted, event.RemovedAt, PayloadData{
+			ShipmentID:     shipmentID,
+			ShipmentNumber: shipmentNumbers[shipmentID],
+		}))
+	}
+	return payloads
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
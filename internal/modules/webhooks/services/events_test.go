@@ -0,0 +1,164 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"go-starter/internal/modules/shipments/types"
+	"go-starter/internal/modules/webhooks/models"
+
+	"github.com/google/uuid"
+)
+
+func TestPayloadsFromSync(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	eta := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	laterETA := eta.Add(72 * time.Hour)
+	gateOut := types.ContainerEventSnapshot{
+		ContainerNumber: "MSCU1234567",
+		EventCode:       "GTOT",
+		Date:            time.Date(2025, 5, 20, 8, 0, 0, 0, time.UTC),
+		IsActual:        true,
+	}
+	loaded := types.ContainerEventSnapshot{
+		ContainerNumber: "MSCU1234567",
+		EventCode:       "LOAD",
+		Date:            time.Date(2025, 5, 22, 8, 0, 0, 0, time.UTC),
+		IsActual:        true,
+	}
+	plannedDischarge := types.ContainerEventSnapshot{
+		ContainerNumber: "MSCU1234567",
+		EventCode:       "DISC",
+		Date:            eta,
+	}
+
+	snapshot := func(status string, podETA *time.Time, events ...types.ContainerEventSnapshot) *types.ShipmentSnapshot {
+		return &types.ShipmentSnapshot{
+			ShipmentNumber:  "MEDU1234567",
+			ShippingStatus:  status,
+			PodETA:          podETA,
+			ContainerEvents: events,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		before *types.ShipmentSnapshot
+		after  *types.ShipmentSnapshot
+		want   []models.EventType
+	}{
+		{
+			name:  "new shipment",
+			after: snapshot("IN_TRANSIT", &eta, gateOut),
+			want:  []models.EventType{models.EventShipmentCreated},
+		},
+		{
+			name:   "nothing changed",
+			before: snapshot("IN_TRANSIT", &eta, gateOut),
+			after:  snapshot("IN_TRANSIT", &eta, gateOut),
+			want:   []models.EventType{models.EventShipmentSynced},
+		},
+		{
+			name:   "status changed",
+			before: snapshot("PLANNED", &eta),
+			after:  snapshot("IN_TRANSIT", &eta),
+			want:   []models.EventType{models.EventShipmentSynced, models.EventShipmentStatusChanged},
+		},
+		{
+			name:   "ETA changed",
+			before: snapshot("IN_TRANSIT", &eta),
+			after:  snapshot("IN_TRANSIT", &laterETA),
+			want:   []models.EventType{models.EventShipmentSynced, models.EventShipmentETAChanged},
+		},
+		{
+			name:   "ETA became known",
+			before: snapshot("IN_TRANSIT", nil),
+			after:  snapshot("IN_TRANSIT", &eta),
+			want:   []models.EventType{models.EventShipmentSynced, models.EventShipmentETAChanged},
+		},
+		{
+			name:   "new actual container event, planned ones are ignored",
+			before: snapshot("IN_TRANSIT", &eta, gateOut),
+			after:  snapshot("IN_TRANSIT", &eta, gateOut, loaded, plannedDischarge),
+			want:   []models.EventType{models.EventShipmentSynced, models.EventContainerEventAdded},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payloads := PayloadsFromSync(types.ShipmentSyncEvent{
+				ShipmentID: uuid.New(),
+				Before:     tt.before,
+				After:      tt.after,
+				SyncedAt:   now,
+			}, now)
+
+			if len(payloads) != len(tt.want) {
+				t.Fatalf("Expected %d payloads, got %d: %+v", len(tt.want), len(payloads), payloads)
+			}
+			for i, payload := range payloads {
+				if payload.Type != tt.want[i] {
+					t.Errorf("Payload %d: expected type %s, got %s", i, tt.want[i], payload.Type)
+				}
+				if payload.Data.ShipmentNumber != "MEDU1234567" {
+					t.Errorf("Payload %d: expected shipment number, got %q", i, payload.Data.ShipmentNumber)
+				}
+			}
+		})
+	}
+}
+
+func TestPayloadsFromSync_ChangeDetails(t *testing.T) {
+	now := time.Now()
+	eta := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	laterETA := eta.Add(48 * time.Hour)
+
+	payloads := PayloadsFromSync(types.ShipmentSyncEvent{
+		ShipmentID: uuid.New(),
+		Before:     &types.ShipmentSnapshot{ShippingStatus: "PLANNED", PodETA: &eta},
+		After:      &types.ShipmentSnapshot{ShippingStatus: "IN_TRANSIT", PodETA: &laterETA},
+	}, now)
+
+	ids := make(map[uuid.UUID]bool)
+	for _, payload := range payloads {
+		ids[payload.ID] = true
+
+		switch payload.Type {
+		case models.EventShipmentStatusChanged:
+			if payload.Data.PreviousShippingStatus != "PLANNED" || payload.Data.ShippingStatus != "IN_TRANSIT" {
+				t.Errorf("Unexpected status change data %+v", payload.Data)
+			}
+		case models.EventShipmentETAChanged:
+			if payload.Data.PreviousPodETA == nil || !payload.Data.PreviousPodETA.Equal(eta) || !payload.Data.PodETA.Equal(laterETA) {
+				t.Errorf("Unexpected ETA change data %+v", payload.Data)
+			}
+		}
+	}
+
+	if len(ids) != len(payloads) {
+		t.Errorf("Expected every event to get its own ID")
+	}
+}
+
+func TestPayloadsFromRemoval(t *testing.T) {
+	known, unknown := uuid.New(), uuid.New()
+	removedAt := time.Now()
+
+	payloads := PayloadsFromRemoval(types.ShipmentsRemovedEvent{
+		UserID:      uuid.New(),
+		ShipmentIDs: []uuid.UUID{known, unknown},
+		RemovedAt:   removedAt,
+	}, map[uuid.UUID]string{known: "MEDU1234567"})
+
+	if len(payloads) != 2 {
+		t.Fatalf("Expected 2 payloads, got %d", len(payloads))
+	}
+	for _, payload := range payloads {
+		if payload.Type != models.EventShipmentDeleted || !payload.CreatedAt.Equal(removedAt) {
+			t.Errorf("Unexpected payload %+v", payload)
+		}
+	}
+	if payloads[0].Data.ShipmentNumber != "MEDU1234567" || payloads[1].Data.ShipmentNumber != "" {
+		t.Errorf("Unexpected shipment numbers %q, %q", payloads[0].Data.ShipmentNumber, payloads[1].Data.ShipmentNumber)
+	}
+}
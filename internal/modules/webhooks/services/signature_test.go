@@ -0,0 +1,34 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	got := Sign("whsec_test", time.Unix(1700000000, 0), []byte(`{"id":"1"}`))
+	want := "t=1700000000,v1=11bf4466ea17c3df3fd743af0b435368e16b7a05eb8eced85e8c4670767bdec5"
+
+	if got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret failed: %v", err)
+	}
+	second, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret failed: %v", err)
+	}
+
+	if !strings.HasPrefix(first, secretPrefix) || len(first) != len(secretPrefix)+48 {
+		t.Errorf("Unexpected secret format %q", first)
+	}
+	if first == second {
+		t.Errorf("Expected different secrets, got %q twice", first)
+	}
+}
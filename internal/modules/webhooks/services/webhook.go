@@ -0,0 +1,487 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-starter/internal/modules/shipments/types"
+	"go-starter/internal/modules/webhooks/dto"
+	"go-starter/internal/modules/webhooks/models"
+	"go-starter/internal/modules/webhooks/repositories"
+	"go-starter/pkg/config"
+	"go-starter/pkg/deliveryqueue"
+	"go-starter/pkg/outbound"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// maxResponseBody is how much of a receiver's response is kept in the delivery log
+const maxResponseBody = 2048
+
+// userAgent identifies webhook requests to receivers
+const userAgent = "ShipmentTracker-Webhooks/1.0"
+
+// DeliverySettings controls how webhooks are sent and how often failed ones are retried
+type DeliverySettings struct {
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	Timeout      time.Duration
+}
+
+// NewDeliverySettingsFromConfig creates delivery settings from the application config
+func NewDeliverySettingsFromConfig(cfg *config.Config) DeliverySettings {
+	return DeliverySettings{
+		MaxAttempts:  cfg.BackgroundJobs.WebhookMaxAttempts,
+		RetryBackoff: cfg.BackgroundJobs.WebhookRetryBackoff,
+		Timeout:      cfg.BackgroundJobs.WebhookTimeout,
+	}
+}
+
+type WebhookService struct {
+	webhookRepo *repositories.WebhookRepository
+	httpClient  *http.Client
+	settings    DeliverySettings
+}
+
+func NewWebhookService(webhookRepo *repositories.WebhookRepository, settings DeliverySettings) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		httpClient:  outbound.NewClient(settings.Timeout),
+		settings:    settings,
+	}
+}
+
+// HandleShipmentSync queues webhooks for the events of a completed sync.
+// It is registered as a shipment sync listener.
+func (s *WebhookService) HandleShipmentSync(ctx context.Context, event types.ShipmentSyncEvent) {
+	payloads := PayloadsFromSync(event, time.Now())
+	if len(payloads) == 0 {
+		return
+	}
+
+	subscriptions, err := s.webhookRepo.GetSubscriptionsForShipment(ctx, event.ShipmentID)
+	if err != nil {
+		log.Printf("Warning: Failed to get webhook subscriptions for shipment %s: %v", event.ShipmentID, err)
+		return
+	}
+
+	if err := s.enqueue(ctx, subscriptions, payloads); err != nil {
+		log.Printf("Warning: Failed to queue webhooks for shipment %s: %v", event.ShipmentID, err)
+	}
+}
+
+// HandleShipmentsRemoved queues shipment.deleted webhooks for the user that removed the shipments.
+// It is registered as a shipment remove listener.
+func (s *WebhookService) HandleShipmentsRemoved(ctx context.Context, event types.ShipmentsRemovedEvent) {
+	subscriptions, err := s.webhookRepo.GetEnabledSubscriptionsByUserID(ctx, event.UserID)
+	if err != nil {
+		log.Printf("Warning: Failed to get webhook subscriptions of user %s: %v", event.UserID, err)
+		return
+	}
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	shipmentNumbers, err := s.webhookRepo.GetShipmentNumbers(ctx, event.ShipmentIDs)
+	if err != nil {
+		log.Printf("Warning: Failed to get shipment numbers for removed shipments: %v", err)
+		return
+	}
+
+	if err := s.enqueue(ctx, subscriptions, PayloadsFromRemoval(event, shipmentNumbers)); err != nil {
+		log.Printf("Warning: Failed to queue shipment.deleted webhooks for user %s: %v", event.UserID, err)
+	}
+}
+
+// enqueue creates a pending delivery for every subscription that wants a payload's event type
+func (s *WebhookService) enqueue(ctx context.Context, subscriptions []models.WebhookSubscription, payloads []Payload) error {
+	now := time.Now()
+
+	var deliveries []models.WebhookDelivery
+	for _, payload := range payloads {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("error encoding payload: %w", err)
+		}
+
+		for _, subscription := range subscriptions {
+			if !subscription.Wants(payload.Type) {
+				continue
+			}
+			deliveries = append(deliveries, newDelivery(subscription, payload, body, now))
+		}
+	}
+
+	return s.webhookRepo.CreateDeliveries(ctx, deliveries)
+}
+
+func newDelivery(subscription models.WebhookSubscription, payload Payload, body []byte, now time.Time) models.WebhookDelivery {
+	delivery := models.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		UserID:         subscription.UserID,
+		EventID:        payload.ID,
+		EventType:      string(payload.Type),
+		Payload:        string(body),
+		Status:         models.DeliveryStatusPending,
+		NextAttemptAt:  &now,
+	}
+	if payload.Data.ShipmentID != uuid.Nil {
+		shipmentID := payload.Data.ShipmentID
+		delivery.ShipmentID = &shipmentID
+	}
+	return delivery
+}
+
+// DeliverDue sends pending deliveries that are due. Failed attempts are retried with
+// exponential backoff until MaxAttempts is reached. It returns how many were sent.
+func (s *WebhookService) DeliverDue(ctx context.Context, limit int) (int, error) {
+	deliveries, err := s.webhookRepo.ClaimDueDeliveries(ctx, time.Now(), limit, deliveryqueue.Lease)
+	if err != nil {
+		return 0, err
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	subscriptionIDs := make([]uuid.UUID, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		subscriptionIDs = append(subscriptionIDs, delivery.SubscriptionID)
+	}
+	subscriptions, err := s.webhookRepo.GetSubscriptionsByIDs(ctx, subscriptionIDs)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving subscriptions: %w", err)
+	}
+	subscriptionsByID := make(map[uuid.UUID]models.WebhookSubscription, len(subscriptions))
+	for _, subscription := range subscriptions {
+		subscriptionsByID[subscription.ID] = subscription
+	}
+
+	sent := 0
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			// Claimed deliveries become due again once their lease runs out
+			break
+		}
+
+		subscription, ok := subscriptionsByID[delivery.SubscriptionID]
+		if !ok || !subscription.Enabled {
+			s.recordOutcome(ctx, delivery, delivery.Attempts, 0, "", fmt.Errorf("subscription is disabled"), true)
+			continue
+		}
+
+		if s.deliver(ctx, subscription, delivery) {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// deliver posts one delivery to its subscription and records the outcome,
+// it reports whether the receiver accepted it
+func (s *WebhookService) deliver(ctx context.Context, subscription models.WebhookSubscription, delivery models.WebhookDelivery) bool {
+	attempts := delivery.Attempts + 1
+	statusCode, responseBody, err := s.post(ctx, subscription, delivery)
+
+	giveUp := attempts >= s.settings.MaxAttempts
+	s.recordOutcome(ctx, delivery, attempts, statusCode, responseBody, err, giveUp)
+
+	return err == nil
+}
+
+// post sends the signed payload and returns the response status and the start of the response body
+func (s *WebhookService) post(ctx context.Context, subscription models.WebhookSubscription, delivery models.WebhookDelivery) (int, string, error) {
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderEventID, delivery.EventID.String())
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, time.Now(), body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(responseBody), fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, string(responseBody), nil
+}
+
+// recordOutcome stores the result of a delivery attempt and schedules the next one unless
+// the delivery succeeded or giveUp is set
+func (s *WebhookService) recordOutcome(ctx context.Context, delivery models.WebhookDelivery, attempts, statusCode int, responseBody string, err error, giveUp bool) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"attempts":        attempts,
+		"response_status": statusCode,
+		"response_body":   responseBody,
+	}
+	switch {
+	case err == nil:
+		updates["status"] = models.DeliveryStatusSent
+		updates["delivered_at"] = now
+		updates["next_attempt_at"] = nil
+		updates["last_error"] = ""
+	case giveUp:
+		updates["status"] = models.DeliveryStatusFailed
+		updates["next_attempt_at"] = nil
+		updates["last_error"] = err.Error()
+		log.Printf("Giving up on webhook %s (%s) after %d attempts: %v", delivery.ID, delivery.EventType, attempts, err)
+	default:
+		updates["next_attempt_at"] = now.Add(deliveryqueue.RetryDelay(s.settings.RetryBackoff, attempts))
+		updates["last_error"] = err.Error()
+		log.Printf("Webhook %s (%s) failed (attempt %d), retrying: %v", delivery.ID, delivery.EventType, attempts, err)
+	}
+
+	// Record the outcome even when the job is being stopped
+	if updateErr := s.webhookRepo.UpdateDeliveryFields(context.WithoutCancel(ctx), delivery.ID, updates); updateErr != nil {
+		log.Printf("Warning: Failed to record outcome of webhook %s: %v", delivery.ID, updateErr)
+	}
+}
+
+// CreateSubscription validates and creates a webhook subscription with a new signing secret
+func (s *WebhookService) CreateSubscription(ctx context.Context, userID uuid.UUID, req *dto.SaveSubscriptionRequest) (*dto.SubscriptionWithSecretResponse, error) {
+	subscription := &models.WebhookSubscription{UserID: userID}
+	if err := applySubscriptionRequest(ctx, subscription, req); err != nil {
+		return nil, err
+	}
+
+	secret, err := GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	subscription.Secret = secret
+
+	if err := s.webhookRepo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("error creating subscription: %w", err)
+	}
+
+	return &dto.SubscriptionWithSecretResponse{
+		WebhookSubscription: *subscription,
+		Secret:              secret,
+	}, nil
+}
+
+// GetSubscriptions retrieves all webhook subscriptions of a user
+func (s *WebhookService) GetSubscriptions(ctx context.Context, userID uuid.UUID) (*dto.SubscriptionsListResponse, error) {
+	subscriptions, err := s.webhookRepo.GetSubscriptionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving subscriptions: %w", err)
+	}
+
+	return &dto.SubscriptionsListResponse{
+		Subscriptions: subscriptions,
+		EventTypes:    models.SubscribableEventTypes,
+		Total:         len(subscriptions),
+	}, nil
+}
+
+// UpdateSubscription validates and updates an existing webhook subscription
+func (s *WebhookService) UpdateSubscription(ctx context.Context, userID, subscriptionID uuid.UUID, req *dto.SaveSubscriptionRequest) (*models.WebhookSubscription, error) {
+	subscription, err := s.getSubscription(ctx, userID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applySubscriptionRequest(ctx, subscription, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.webhookRepo.UpdateSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("error updating subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// DeleteSubscription deletes a webhook subscription and its delivery log
+func (s *WebhookService) DeleteSubscription(ctx context.Context, userID, subscriptionID uuid.UUID) error {
+	if err := s.webhookRepo.DeleteSubscription(ctx, subscriptionID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("subscription not found")
+		}
+		return fmt.Errorf("error deleting subscription: %w", err)
+	}
+
+	return nil
+}
+
+// RotateSecret replaces the signing secret of a subscription. Deliveries sent from now on,
+// including retries of earlier events, are signed with the new secret.
+func (s *WebhookService) RotateSecret(ctx context.Context, userID, subscriptionID uuid.UUID) (*dto.SubscriptionWithSecretResponse, error) {
+	subscription, err := s.getSubscription(ctx, userID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.UpdateSubscriptionSecret(ctx, subscriptionID, userID, secret); err != nil {
+		return nil, fmt.Errorf("error rotating secret: %w", err)
+	}
+	subscription.Secret = secret
+
+	return &dto.SubscriptionWithSecretResponse{
+		WebhookSubscription: *subscription,
+		Secret:              secret,
+	}, nil
+}
+
+// SendPing sends a ping event to the subscription right away to check the receiver.
+// A failed ping is retried like any other delivery.
+func (s *WebhookService) SendPing(ctx context.Context, userID, subscriptionID uuid.UUID) (*models.WebhookDelivery, error) {
+	subscription, err := s.getSubscription(ctx, userID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := newPayload(models.EventPing, time.Now(), PayloadData{})
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding payload: %w", err)
+	}
+
+	delivery := newDelivery(*subscription, payload, body, time.Now())
+	return s.sendNow(ctx, *subscription, delivery)
+}
+
+// Redeliver sends the payload of an earlier delivery again as a new delivery, keeping the
+// event ID so receivers can recognize it
+func (s *WebhookService) Redeliver(ctx context.Context, userID, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	original, err := s.webhookRepo.GetDeliveryByID(ctx, deliveryID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("delivery not found")
+		}
+		return nil, fmt.Errorf("error retrieving delivery: %w", err)
+	}
+
+	subscription, err := s.getSubscription(ctx, userID, original.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	originalID := original.ID
+	delivery := models.WebhookDelivery{
+		SubscriptionID: original.SubscriptionID,
+		UserID:         original.UserID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		ShipmentID:     original.ShipmentID,
+		Payload:        original.Payload,
+		RedeliveryOf:   &originalID,
+		Status:         models.DeliveryStatusPending,
+		NextAttemptAt:  &now,
+	}
+	return s.sendNow(ctx, *subscription, delivery)
+}
+
+// sendNow stores a delivery and makes its first attempt right away
+func (s *WebhookService) sendNow(ctx context.Context, subscription models.WebhookSubscription, delivery models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	if err := s.webhookRepo.CreateDelivery(ctx, &delivery); err != nil {
+		return nil, fmt.Errorf("error creating delivery: %w", err)
+	}
+
+	s.deliver(ctx, subscription, delivery)
+
+	updated, err := s.webhookRepo.GetDeliveryByID(ctx, delivery.ID, delivery.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving delivery: %w", err)
+	}
+
+	return updated, nil
+}
+
+// GetDeliveries retrieves the most recent deliveries of a subscription
+func (s *WebhookService) GetDeliveries(ctx context.Context, userID, subscriptionID uuid.UUID, limit int) (*dto.DeliveriesListResponse, error) {
+	if _, err := s.getSubscription(ctx, userID, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.webhookRepo.GetDeliveriesBySubscriptionID(ctx, subscriptionID, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving deliveries: %w", err)
+	}
+
+	return &dto.DeliveriesListResponse{
+		Deliveries: deliveries,
+		Total:      len(deliveries),
+	}, nil
+}
+
+func (s *WebhookService) getSubscription(ctx context.Context, userID, subscriptionID uuid.UUID) (*models.WebhookSubscription, error) {
+	subscription, err := s.webhookRepo.GetSubscriptionByID(ctx, subscriptionID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("subscription not found")
+		}
+		return nil, fmt.Errorf("error retrieving subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// applySubscriptionRequest validates the request and copies it onto the subscription.
+// The URL has to point at a public address.
+func applySubscriptionRequest(ctx context.Context, subscription *models.WebhookSubscription, req *dto.SaveSubscriptionRequest) error {
+	targetURL := strings.TrimSpace(req.URL)
+	parsed, err := url.Parse(targetURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid url: must be an absolute http or https URL")
+	}
+	if err := outbound.ValidateHost(ctx, parsed.Hostname()); err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+
+	eventTypes := pq.StringArray{}
+	seen := make(map[string]bool)
+	for _, eventType := range req.EventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if !models.EventType(eventType).IsValid() {
+			return fmt.Errorf("invalid event type '%s'", eventType)
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	if len(eventTypes) == 0 {
+		return fmt.Errorf("invalid subscription: at least one event type is required")
+	}
+
+	subscription.URL = targetURL
+	subscription.Description = strings.TrimSpace(req.Description)
+	subscription.EventTypes = eventTypes
+	subscription.Enabled = true
+	if req.Enabled != nil {
+		subscription.Enabled = *req.Enabled
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go-starter/internal/modules/webhooks/dto"
+	"go-starter/internal/modules/webhooks/models"
+)
+
+func TestApplySubscriptionRequestRejectsInternalURLs(t *testing.T) {
+	urls := []string{
+		"http://127.0.0.1:8080/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"https://10.1.2.3/hook",
+		"http://[::1]/hook",
+		"http://localhost/hook",
+	}
+
+	for _, targetURL := range urls {
+		req := &dto.SaveSubscriptionRequest{URL: targetURL, EventTypes: []string{"shipment.synced"}}
+		err := applySubscriptionRequest(context.Background(), &models.WebhookSubscription{}, req)
+		if err == nil || !strings.HasPrefix(err.Error(), "invalid url") {
+			t.Errorf("Expected %s to be rejected as an invalid url, got %v", targetURL, err)
+		}
+	}
+}
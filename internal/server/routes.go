@@ -1,10 +1,21 @@
 package server
 
 import (
+	"go-starter/internal/modules/alerts"
 	"go-starter/internal/modules/auth"
+	"go-starter/internal/modules/containers"
+	"go-starter/internal/modules/demurrage"
+	"go-starter/internal/modules/digests"
+	"go-starter/internal/modules/emissions"
 	"go-starter/internal/modules/filters"
+	"go-starter/internal/modules/geoexport"
+	"go-starter/internal/modules/geofences"
 	"go-starter/internal/modules/jobs"
+	"go-starter/internal/modules/live"
+	"go-starter/internal/modules/notifications"
 	"go-starter/internal/modules/shipments"
+	"go-starter/internal/modules/vessels"
+	"go-starter/internal/modules/webhooks"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -20,6 +31,17 @@ func (s *Server) initRoutes() {
 	auth.RegisterRoutes(s.Echo, api, s.DB, s.Config)
 	filters.RegisterRoutes(api, s.DB, s.Config)
 	shipments.RegisterRoutes(s.Echo, api, s.DB, s.Config)
-	jobs.RegisterRoutes(s.Echo, api, s.DB, s.Config, s.JobScheduler)
+	live.RegisterRoutes(api, s.LiveBroker)
+	alerts.RegisterRoutes(api, s.DB, s.Config)
+	notifications.RegisterRoutes(s.Echo, api, s.DB, s.Config)
+	webhooks.RegisterRoutes(s.Echo, api, s.DB, s.Config)
+	digests.RegisterRoutes(api, s.DB, s.Config, s.shipmentService, notifications.NewMailer(s.Config))
+	demurrage.RegisterRoutes(api, s.DB, s.shipmentService)
+	geoexport.RegisterRoutes(api, s.DB, s.shipmentService)
+	geofences.RegisterRoutes(api, s.DB)
+	emissions.RegisterRoutes(api, s.DB, s.shipmentService)
+	vessels.RegisterRoutes(s.Echo, api, s.DB, s.shipmentService)
+	containers.RegisterRoutes(api, s.shipmentService)
+	jobs.RegisterRoutes(s.Echo, api, s.Config, s.JobScheduler, s.ShipmentRefreshJob, s.RefreshSettings)
 
 }
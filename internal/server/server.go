@@ -4,8 +4,21 @@ import (
 	"context"
 	"fmt"
 	"go-starter/internal/jobs"
+	alertRepositories "go-starter/internal/modules/alerts/repositories"
+	alertServices "go-starter/internal/modules/alerts/services"
+	demurrageRepositories "go-starter/internal/modules/demurrage/repositories"
+	demurrageServices "go-starter/internal/modules/demurrage/services"
+	digestRepositories "go-starter/internal/modules/digests/repositories"
+	digestServices "go-starter/internal/modules/digests/services"
+	liveRepositories "go-starter/internal/modules/live/repositories"
+	liveServices "go-starter/internal/modules/live/services"
+	"go-starter/internal/modules/notifications"
+	notificationRepositories "go-starter/internal/modules/notifications/repositories"
+	notificationServices "go-starter/internal/modules/notifications/services"
 	shipmentRepositories "go-starter/internal/modules/shipments/repositories"
 	shipmentServices "go-starter/internal/modules/shipments/services"
+	webhookRepositories "go-starter/internal/modules/webhooks/repositories"
+	webhookServices "go-starter/internal/modules/webhooks/services"
 	"go-starter/pkg/config"
 	"go-starter/pkg/db"
 	"go-starter/pkg/ratelimiter"
@@ -13,7 +26,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"time"
+	"syscall"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -25,6 +38,14 @@ type Server struct {
 	DB           *db.Database
 	Config       *config.Config
 	JobScheduler *jobs.JobScheduler
+
+	// ShipmentRefreshJob is kept so its configuration can be changed at runtime
+	ShipmentRefreshJob *jobs.ShipmentRefreshJob
+	RefreshSettings    *jobs.RefreshSettingsStore
+	LiveBroker         *liveServices.Broker
+
+	// shipmentService is used to drain in-flight syncs on shutdown
+	shipmentService shipmentServices.ShipmentService
 }
 
 func New(cfg *config.Config, database *db.Database) *Server {
@@ -48,8 +69,9 @@ func New(cfg *config.Config, database *db.Database) *Server {
 		JobScheduler: jobScheduler,
 	}
 
-	server.initRoutes()
+	// Jobs first, job management routes need the registered jobs
 	server.initBackgroundJobs()
+	server.initRoutes()
 
 	return server
 }
@@ -78,24 +100,37 @@ func (s *Server) Start() {
 	log.Printf("Server started on %s", addr)
 
 	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
-	<-quit
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	sig := <-quit
 
-	log.Println("Shutting down server...")
+	log.Printf("Received %v, shutting down server...", sig)
+	s.shutdown()
 
-	// Stop background jobs first
-	if err := s.JobScheduler.Stop(); err != nil {
+	log.Println("Server stopped")
+}
+
+// shutdown stops the server in order: background jobs stop picking up work, new syncs are
+// refused and in-flight syncs get SyncDrainTimeout to commit (anything still running is
+// rolled back and resumed on the next start), then open HTTP requests are finished.
+func (s *Server) shutdown() {
+	// Stop background jobs first so they don't queue more syncs
+	if err := s.JobScheduler.StopWithTimeout(s.Config.Server.JobsShutdownTimeout); err != nil {
 		log.Printf("Error stopping job scheduler: %v", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), s.Config.Server.SyncDrainTimeout)
+	defer cancelDrain()
+
+	if err := s.shipmentService.DrainSyncs(drainCtx); err != nil {
+		log.Printf("Error draining shipment syncs: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.Config.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := s.Echo.Shutdown(ctx); err != nil {
-		log.Fatalf("Failed to gracefully shutdown server: %v", err)
+		log.Printf("Failed to gracefully shutdown server: %v", err)
 	}
-
-	log.Println("Server stopped")
 }
 
 // initBackgroundJobs initializes and registers background jobs
@@ -110,7 +145,14 @@ func (s *Server) initBackgroundJobs() {
 		rateLimiter,
 	)
 	shipmentRepository := shipmentRepositories.NewShipmentRepository(s.DB)
-	shipmentService := shipmentServices.NewShipmentService(shipmentRepository, safeCubeAPIService)
+	shipmentService := shipmentServices.NewShipmentService(
+		shipmentRepository,
+		safeCubeAPIService,
+		shipmentServices.NewRefreshPolicyFromConfig(s.Config.BackgroundJobs),
+		shipmentServices.NewTrackingPolicyFromConfig(s.Config.BackgroundJobs),
+	)
+
+	s.shipmentService = shipmentService
 
 	// Configure shipment refresh job
 	refreshConfig := jobs.ShipmentRefreshConfig{
@@ -120,18 +162,106 @@ func (s *Server) initBackgroundJobs() {
 		SkipRecentlyUpdated: s.Config.BackgroundJobs.ShipmentSkipRecentlyUpdated,
 	}
 
+	// Settings changed at runtime through the admin API win over the environment
+	s.RefreshSettings = jobs.NewRefreshSettingsStore(s.DB)
+	persistedConfig, err := s.RefreshSettings.Load(context.Background())
+	if err != nil {
+		log.Printf("Failed to load persisted shipment refresh settings: %v", err)
+	} else if persistedConfig != nil {
+		if err := persistedConfig.Validate(); err != nil {
+			log.Printf("Ignoring invalid persisted shipment refresh settings: %v", err)
+		} else {
+			refreshConfig = *persistedConfig
+			log.Printf("Using persisted shipment refresh settings")
+		}
+	}
+
 	// Create and register shipment refresh job
 	shipmentRefreshJob := jobs.NewShipmentRefreshJob(
 		shipmentRepository,
 		shipmentService,
 		rateLimiter,
+		s.RefreshSettings,
 		refreshConfig,
 	)
+	s.ShipmentRefreshJob = shipmentRefreshJob
 
 	if err := s.JobScheduler.RegisterJob("shipment_refresh", &ShipmentRefreshJobWrapper{job: shipmentRefreshJob}); err != nil {
 		log.Fatalf("Failed to register shipment refresh job: %v", err)
 	}
 
+	// Create and register notification delivery job
+	notificationService := notificationServices.NewNotificationService(
+		notificationRepositories.NewNotificationRepository(s.DB),
+		notifications.NewMailer(s.Config),
+		notifications.NewChatSender(s.Config),
+		notificationServices.NewDeliverySettingsFromConfig(s.Config),
+	)
+	notificationDeliveryJob := jobs.NewNotificationDeliveryJob(
+		notificationService,
+		s.Config.BackgroundJobs.NotificationDeliveryInterval,
+		s.Config.BackgroundJobs.NotificationBatchInterval,
+	)
+
+	if err := s.JobScheduler.RegisterJob("notification_delivery", &NotificationDeliveryJobWrapper{job: notificationDeliveryJob}); err != nil {
+		log.Fatalf("Failed to register notification delivery job: %v", err)
+	}
+
+	// Create and register inbox cleanup job
+	inboxService := notificationServices.NewInboxService(notificationRepositories.NewInboxRepository(s.DB))
+	inboxCleanupJob := jobs.NewInboxCleanupJob(
+		inboxService,
+		s.Config.BackgroundJobs.InboxCleanupInterval,
+		s.Config.BackgroundJobs.InboxRetention,
+	)
+
+	if err := s.JobScheduler.RegisterJob("inbox_cleanup", &InboxCleanupJobWrapper{job: inboxCleanupJob}); err != nil {
+		log.Fatalf("Failed to register inbox cleanup job: %v", err)
+	}
+
+	// Create and register webhook delivery job
+	webhookService := webhookServices.NewWebhookService(
+		webhookRepositories.NewWebhookRepository(s.DB),
+		webhookServices.NewDeliverySettingsFromConfig(s.Config),
+	)
+	webhookDeliveryJob := jobs.NewWebhookDeliveryJob(webhookService, s.Config.BackgroundJobs.WebhookDeliveryInterval)
+
+	if err := s.JobScheduler.RegisterJob("webhook_delivery", &WebhookDeliveryJobWrapper{job: webhookDeliveryJob}); err != nil {
+		log.Fatalf("Failed to register webhook delivery job: %v", err)
+	}
+
+	// Create and register digest delivery job
+	digestService := digestServices.NewDigestService(
+		digestRepositories.NewDigestRepository(s.DB),
+		shipmentService,
+		notifications.NewMailer(s.Config),
+		digestServices.NewDigestSettingsFromConfig(s.Config),
+	)
+	digestDeliveryJob := jobs.NewDigestDeliveryJob(digestService, s.Config.BackgroundJobs.DigestCheckInterval)
+
+	if err := s.JobScheduler.RegisterJob("digest_delivery", &DigestDeliveryJobWrapper{job: digestDeliveryJob}); err != nil {
+		log.Fatalf("Failed to register digest delivery job: %v", err)
+	}
+
+	// Create and register free time alert job
+	freeTimeService := demurrageServices.NewFreeTimeService(
+		demurrageRepositories.NewAgreementRepository(s.DB),
+		shipmentService,
+		alertServices.NewAlertService(alertRepositories.NewAlertRepository(s.DB)),
+	)
+	freeTimeAlertJob := jobs.NewFreeTimeAlertJob(freeTimeService, s.Config.BackgroundJobs.FreeTimeCheckInterval)
+
+	if err := s.JobScheduler.RegisterJob("free_time_alerts", &FreeTimeAlertJobWrapper{job: freeTimeAlertJob}); err != nil {
+		log.Fatalf("Failed to register free time alert job: %v", err)
+	}
+
+	liveBroker := liveServices.NewBroker(liveRepositories.NewLiveRepository(s.DB), s.Config.GetDBConnString())
+	s.LiveBroker = liveBroker
+
+	if err := s.JobScheduler.RegisterJob("live_updates", &LiveBrokerJobWrapper{broker: liveBroker}); err != nil {
+		log.Fatalf("Failed to register live updates job: %v", err)
+	}
+
 	log.Printf("Background jobs initialized successfully")
 	log.Printf("Shipment refresh configured: interval=%v, workers=%d, max_per_run=%d, skip_recently_updated=%v",
 		refreshConfig.RefreshInterval,
@@ -152,3 +282,81 @@ func (w *ShipmentRefreshJobWrapper) Start(ctx context.Context) {
 func (w *ShipmentRefreshJobWrapper) GetName() string {
 	return "shipment_refresh"
 }
+
+// NotificationDeliveryJobWrapper adapts NotificationDeliveryJob to implement the Job interface
+type NotificationDeliveryJobWrapper struct {
+	job *jobs.NotificationDeliveryJob
+}
+
+func (w *NotificationDeliveryJobWrapper) Start(ctx context.Context) {
+	w.job.Start(ctx)
+}
+
+func (w *NotificationDeliveryJobWrapper) GetName() string {
+	return "notification_delivery"
+}
+
+// InboxCleanupJobWrapper adapts InboxCleanupJob to implement the Job interface
+type InboxCleanupJobWrapper struct {
+	job *jobs.InboxCleanupJob
+}
+
+func (w *InboxCleanupJobWrapper) Start(ctx context.Context) {
+	w.job.Start(ctx)
+}
+
+func (w *InboxCleanupJobWrapper) GetName() string {
+	return "inbox_cleanup"
+}
+
+// WebhookDeliveryJobWrapper adapts WebhookDeliveryJob to implement the Job interface
+type WebhookDeliveryJobWrapper struct {
+	job *jobs.WebhookDeliveryJob
+}
+
+func (w *WebhookDeliveryJobWrapper) Start(ctx context.Context) {
+	w.job.Start(ctx)
+}
+
+func (w *WebhookDeliveryJobWrapper) GetName() string {
+	return "webhook_delivery"
+}
+
+// FreeTimeAlertJobWrapper adapts FreeTimeAlertJob to implement the Job interface
+type FreeTimeAlertJobWrapper struct {
+	job *jobs.FreeTimeAlertJob
+}
+
+func (w *FreeTimeAlertJobWrapper) Start(ctx context.Context) {
+	w.job.Start(ctx)
+}
+
+func (w *FreeTimeAlertJobWrapper) GetName() string {
+	return "free_time_alerts"
+}
+
+// DigestDeliveryJobWrapper adapts DigestDeliveryJob to implement the Job interface
+type DigestDeliveryJobWrapper struct {
+	job *jobs.DigestDeliveryJob
+}
+
+func (w *DigestDeliveryJobWrapper) Start(ctx context.Context) {
+	w.job.Start(ctx)
+}
+
+func (w *DigestDeliveryJobWrapper) GetName() string {
+	return "digest_delivery"
+}
+
+// LiveBrokerJobWrapper runs the live updates broker as a job
+type LiveBrokerJobWrapper struct {
+	broker *liveServices.Broker
+}
+
+func (w *LiveBrokerJobWrapper) Start(ctx context.Context) {
+	w.broker.Run(ctx)
+}
+
+func (w *LiveBrokerJobWrapper) GetName() string {
+	return "live_updates"
+}
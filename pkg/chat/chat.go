@@ -0,0 +1,112 @@
+// Package chat formats messages for Slack and Microsoft Teams incoming webhooks and posts them
+package chat
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-starter/pkg/outbound"
+)
+
+// Chat platforms a message can be formatted for
+const (
+	PlatformSlack = "slack"
+	PlatformTeams = "teams"
+)
+
+// maxItems limits how many items a message lists, both platforms cap the size of a message
+const maxItems = 20
+
+// Message is a platform independent chat message. Items are listed below the summary,
+// links are shown as buttons at the end.
+type Message struct {
+	Title   string
+	Summary string
+	Items   []Item
+	Links   []Link
+}
+
+// Item is one entry of a message, e.g. an alert
+type Item struct {
+	Title string
+	Text  string
+	// URL makes the title a link, e.g. to the shipment's details
+	URL string
+}
+
+// Link is a button that opens a URL
+type Link struct {
+	Text string
+	URL  string
+}
+
+// Format returns the JSON payload of the message for the platform
+func Format(platform string, msg Message) ([]byte, error) {
+	switch platform {
+	case PlatformSlack:
+		return SlackPayload(msg)
+	case PlatformTeams:
+		return TeamsPayload(msg)
+	}
+	return nil, fmt.Errorf("unsupported chat platform '%s'", platform)
+}
+
+// visibleItems returns the items that fit into a message and how many were left out
+func visibleItems(items []Item) ([]Item, int) {
+	if len(items) <= maxItems {
+		return items, 0
+	}
+	return items[:maxItems], len(items) - maxItems
+}
+
+// truncate shortens s to at most max runes, marking the cut with an ellipsis
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+// Sender posts formatted payloads to incoming webhook URLs
+type Sender interface {
+	Send(ctx context.Context, webhookURL string, payload []byte) error
+}
+
+// HTTPSender posts payloads with a client that can't reach the server's own network
+type HTTPSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender creates a sender whose requests time out after timeout. The options change
+// which addresses it may post to, see outbound.AllowLoopback.
+func NewHTTPSender(timeout time.Duration, opts ...outbound.Option) *HTTPSender {
+	return &HTTPSender{
+		client: outbound.NewClient(timeout, opts...),
+	}
+}
+
+// Send posts the payload, any non 2xx response is an error. The response body is left out
+// of the error, the error is shown to the channel's owner.
+func (s *HTTPSender) Send(ctx context.Context, webhookURL string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("invalid chat webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("chat webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
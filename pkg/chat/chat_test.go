@@ -0,0 +1,192 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-starter/pkg/chat/chattest"
+	"go-starter/pkg/outbound"
+)
+
+func testMessage() Message {
+	return Message{
+		Title:   "ETA of MEDU1234567 slipped by 3 days",
+		Summary: "The ETA moved from 2025-06-01 to 2025-06-04 <via transshipment>.",
+		Items: []Item{
+			{Title: "MEDU1234567", Text: "Arriving in Hamburg", URL: "https://tracker.example.com/shipments?shipment=1"},
+		},
+		Links: []Link{{Text: "Open shipments", URL: "https://tracker.example.com/shipments"}},
+	}
+}
+
+func TestSlackPayload(t *testing.T) {
+	payload, err := SlackPayload(testMessage())
+	if err != nil {
+		t.Fatalf("SlackPayload failed: %v", err)
+	}
+
+	var decoded struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Type string `json:"type"`
+			Text *struct {
+				Text string `json:"text"`
+			} `json:"text"`
+			Elements []struct {
+				URL string `json:"url"`
+			} `json:"elements"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	if decoded.Text != "ETA of MEDU1234567 slipped by 3 days" {
+		t.Errorf("Unexpected fallback text %q", decoded.Text)
+	}
+
+	var types []string
+	for _, block := range decoded.Blocks {
+		types = append(types, block.Type)
+	}
+	if got := strings.Join(types, ","); got != "header,section,divider,section,actions" {
+		t.Errorf("Unexpected blocks %s", got)
+	}
+	if summary := decoded.Blocks[1].Text.Text; !strings.Contains(summary, "&lt;via transshipment&gt;") {
+		t.Errorf("Expected summary to be escaped, got %q", summary)
+	}
+	if item := decoded.Blocks[3].Text.Text; !strings.HasPrefix(item, "*<https://tracker.example.com/shipments?shipment=1|MEDU1234567>*") {
+		t.Errorf("Expected item title to link to the shipment, got %q", item)
+	}
+	if url := decoded.Blocks[4].Elements[0].URL; url != "https://tracker.example.com/shipments" {
+		t.Errorf("Unexpected button URL %q", url)
+	}
+}
+
+func TestSlackPayload_LimitsItems(t *testing.T) {
+	msg := Message{Title: strings.Repeat("x", 200)}
+	for i := 0; i < maxItems+5; i++ {
+		msg.Items = append(msg.Items, Item{Title: "alert"})
+	}
+
+	payload, err := SlackPayload(msg)
+	if err != nil {
+		t.Fatalf("SlackPayload failed: %v", err)
+	}
+
+	if !strings.Contains(string(payload), "_and 5 more_") {
+		t.Errorf("Expected hidden items to be counted")
+	}
+
+	var decoded struct {
+		Blocks []struct {
+			Text struct {
+				Text string `json:"text"`
+			} `json:"text"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if header := []rune(decoded.Blocks[0].Text.Text); len(header) != slackMaxHeader {
+		t.Errorf("Expected header to be truncated to %d characters, got %d", slackMaxHeader, len(header))
+	}
+}
+
+func TestTeamsPayload(t *testing.T) {
+	payload, err := TeamsPayload(testMessage())
+	if err != nil {
+		t.Fatalf("TeamsPayload failed: %v", err)
+	}
+
+	var decoded struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type    string `json:"type"`
+				Body    []json.RawMessage
+				Actions []struct {
+					Type string `json:"type"`
+					URL  string `json:"url"`
+				} `json:"actions"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	if decoded.Type != "message" || len(decoded.Attachments) != 1 {
+		t.Fatalf("Unexpected envelope %s", payload)
+	}
+	card := decoded.Attachments[0]
+	if card.ContentType != "application/vnd.microsoft.card.adaptive" || card.Content.Type != "AdaptiveCard" {
+		t.Errorf("Unexpected attachment %s", payload)
+	}
+	if len(card.Content.Body) != 3 {
+		t.Errorf("Expected title, summary and one item, got %d elements", len(card.Content.Body))
+	}
+	if !strings.Contains(string(payload), "[MEDU1234567](https://tracker.example.com/shipments?shipment=1)") {
+		t.Errorf("Expected item title to link to the shipment, got %s", payload)
+	}
+	if len(card.Content.Actions) != 1 || card.Content.Actions[0].Type != "Action.OpenUrl" {
+		t.Errorf("Unexpected actions %+v", card.Content.Actions)
+	}
+}
+
+func TestHTTPSender_Send(t *testing.T) {
+	server := chattest.NewServer()
+	defer server.Close()
+
+	payload, _ := SlackPayload(testMessage())
+	sender := NewHTTPSender(5*time.Second, server.SenderOptions()...)
+
+	if err := sender.Send(context.Background(), server.URL("/slack"), payload); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(requests))
+	}
+	if requests[0].Path != "/slack" || requests[0].ContentType != "application/json" || string(requests[0].Body) != string(payload) {
+		t.Errorf("Unexpected request %+v", requests[0])
+	}
+}
+
+func TestHTTPSender_SendRejected(t *testing.T) {
+	server := chattest.NewServer()
+	defer server.Close()
+	server.FailNext(1)
+
+	sender := NewHTTPSender(5*time.Second, server.SenderOptions()...)
+	err := sender.Send(context.Background(), server.URL("/teams"), []byte(`{}`))
+	if err == nil || !strings.Contains(err.Error(), "status 500") {
+		t.Fatalf("Expected status error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "temporarily unavailable") {
+		t.Errorf("Expected the response body to be left out of the error, got %v", err)
+	}
+	if len(server.Requests()) != 0 {
+		t.Errorf("Expected the rejected request not to be recorded")
+	}
+}
+
+func TestHTTPSender_RefusesLoopback(t *testing.T) {
+	server := chattest.NewServer()
+	defer server.Close()
+
+	sender := NewHTTPSender(5 * time.Second)
+	err := sender.Send(context.Background(), server.URL("/slack"), []byte(`{}`))
+	if !errors.Is(err, outbound.ErrBlockedAddress) {
+		t.Fatalf("Expected the blocked address error, got %v", err)
+	}
+	if len(server.Requests()) != 0 {
+		t.Errorf("Expected nothing to be posted to the loopback server")
+	}
+}
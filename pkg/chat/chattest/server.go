@@ -0,0 +1,86 @@
+// Package chattest provides a local HTTP stand-in for Slack and Teams incoming webhooks
+package chattest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"go-starter/pkg/outbound"
+)
+
+// Request is a request received by the server
+type Request struct {
+	Path        string
+	ContentType string
+	Body        []byte
+}
+
+// Server accepts every posted message and keeps it in memory.
+// FailNext makes the next n requests be answered with 500.
+type Server struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	requests []Request
+	failNext int
+}
+
+// NewServer starts a server on a random local port
+func NewServer() *Server {
+	s := &Server{}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// SenderOptions let a sender post to the server, senders refuse loopback addresses otherwise
+func (s *Server) SenderOptions() []outbound.Option {
+	return []outbound.Option{outbound.AllowLoopback()}
+}
+
+// URL returns the webhook URL for a path, e.g. "/slack"
+func (s *Server) URL(path string) string {
+	return s.server.URL + path
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// FailNext makes the next n requests fail
+func (s *Server) FailNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failNext = n
+}
+
+// Close stops the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	if s.failNext > 0 {
+		s.failNext--
+		s.mu.Unlock()
+		http.Error(w, "temporarily unavailable", http.StatusInternalServerError)
+		return
+	}
+	s.requests = append(s.requests, Request{
+		Path:        r.URL.Path,
+		ContentType: r.Header.Get("Content-Type"),
+		Body:        body,
+	})
+	s.mu.Unlock()
+
+	// Slack answers "ok", Teams workflows answer 202 without a body
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
@@ -0,0 +1,92 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// slackMaxHeader is the longest text Slack accepts in a header block
+const slackMaxHeader = 150
+
+// slackEscaper escapes the characters Slack's mrkdwn treats as control characters
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackElement struct {
+	Type string    `json:"type"`
+	Text slackText `json:"text"`
+	URL  string    `json:"url,omitempty"`
+}
+
+type slackBlock struct {
+	Type     string         `json:"type"`
+	Text     *slackText     `json:"text,omitempty"`
+	Elements []slackElement `json:"elements,omitempty"`
+}
+
+type slackPayload struct {
+	// Text is the fallback shown in notifications and by clients without Block Kit
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// SlackPayload formats the message as Slack Block Kit
+func SlackPayload(msg Message) ([]byte, error) {
+	payload := slackPayload{
+		Text: msg.Title,
+		Blocks: []slackBlock{
+			{Type: "header", Text: &slackText{Type: "plain_text", Text: truncate(msg.Title, slackMaxHeader)}},
+		},
+	}
+
+	if msg.Summary != "" {
+		payload.Blocks = append(payload.Blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: slackEscaper.Replace(msg.Summary)},
+		})
+	}
+
+	items, hidden := visibleItems(msg.Items)
+	if len(items) > 0 {
+		payload.Blocks = append(payload.Blocks, slackBlock{Type: "divider"})
+	}
+	for _, item := range items {
+		title := "*" + slackEscaper.Replace(item.Title) + "*"
+		if item.URL != "" {
+			title = fmt.Sprintf("*<%s|%s>*", item.URL, slackEscaper.Replace(item.Title))
+		}
+		text := title
+		if item.Text != "" {
+			text += "\n" + slackEscaper.Replace(item.Text)
+		}
+		payload.Blocks = append(payload.Blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: text},
+		})
+	}
+	if hidden > 0 {
+		payload.Blocks = append(payload.Blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf("_and %d more_", hidden)},
+		})
+	}
+
+	if len(msg.Links) > 0 {
+		actions := slackBlock{Type: "actions"}
+		for _, link := range msg.Links {
+			actions.Elements = append(actions.Elements, slackElement{
+				Type: "button",
+				Text: slackText{Type: "plain_text", Text: link.Text},
+				URL:  link.URL,
+			})
+		}
+		payload.Blocks = append(payload.Blocks, actions)
+	}
+
+	return json.Marshal(payload)
+}
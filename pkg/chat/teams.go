@@ -0,0 +1,98 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// teamsEscaper escapes the characters adaptive card markdown would interpret in link texts
+var teamsEscaper = strings.NewReplacer("[", "\\[", "]", "\\]")
+
+type teamsElement struct {
+	Type      string         `json:"type"`
+	Text      string         `json:"text,omitempty"`
+	Weight    string         `json:"weight,omitempty"`
+	Size      string         `json:"size,omitempty"`
+	IsSubtle  bool           `json:"isSubtle,omitempty"`
+	Wrap      bool           `json:"wrap,omitempty"`
+	Separator bool           `json:"separator,omitempty"`
+	Spacing   string         `json:"spacing,omitempty"`
+	Items     []teamsElement `json:"items,omitempty"`
+}
+
+type teamsAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+type teamsCard struct {
+	Schema  string         `json:"$schema"`
+	Type    string         `json:"type"`
+	Version string         `json:"version"`
+	Body    []teamsElement `json:"body"`
+	Actions []teamsAction  `json:"actions,omitempty"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	ContentURL  *string   `json:"contentUrl"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsPayload struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+// TeamsPayload formats the message as an adaptive card for Teams incoming webhooks and workflows
+func TeamsPayload(msg Message) ([]byte, error) {
+	card := teamsCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []teamsElement{
+			{Type: "TextBlock", Text: msg.Title, Weight: "Bolder", Size: "Medium", Wrap: true},
+		},
+	}
+
+	if msg.Summary != "" {
+		card.Body = append(card.Body, teamsElement{Type: "TextBlock", Text: msg.Summary, Wrap: true})
+	}
+
+	items, hidden := visibleItems(msg.Items)
+	for i, item := range items {
+		title := item.Title
+		if item.URL != "" {
+			title = fmt.Sprintf("[%s](%s)", teamsEscaper.Replace(item.Title), item.URL)
+		}
+		container := teamsElement{
+			Type:      "Container",
+			Separator: i == 0,
+			Spacing:   "Medium",
+			Items: []teamsElement{
+				{Type: "TextBlock", Text: title, Weight: "Bolder", Wrap: true},
+			},
+		}
+		if item.Text != "" {
+			container.Items = append(container.Items, teamsElement{Type: "TextBlock", Text: item.Text, IsSubtle: true, Wrap: true, Spacing: "None"})
+		}
+		card.Body = append(card.Body, container)
+	}
+	if hidden > 0 {
+		card.Body = append(card.Body, teamsElement{Type: "TextBlock", Text: fmt.Sprintf("and %d more", hidden), IsSubtle: true, Wrap: true})
+	}
+
+	for _, link := range msg.Links {
+		card.Actions = append(card.Actions, teamsAction{Type: "Action.OpenUrl", Title: link.Text, URL: link.URL})
+	}
+
+	return json.Marshal(teamsPayload{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content:     card,
+		}},
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -12,7 +13,12 @@ type Config struct {
 	Database         DatabaseConfig
 	SafeCubeAPI      SafeCubeAPIConfig
 	BackgroundJobs   BackgroundJobsConfig
+	Mail             MailConfig
 	MaxAvailableUser int
+	// AdminEmails lists users allowed to use admin endpoints
+	AdminEmails []string
+	// AppBaseURL is the public URL of the app, used for links in notifications
+	AppBaseURL string
 }
 
 type ServerConfig struct {
@@ -20,6 +26,11 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+
+	// Shutdown budgets, applied one after another on SIGINT/SIGTERM
+	SyncDrainTimeout    time.Duration
+	JobsShutdownTimeout time.Duration
+	ShutdownTimeout     time.Duration
 }
 
 type DatabaseConfig struct {
@@ -36,11 +47,61 @@ type SafeCubeAPIConfig struct {
 	APIKey  string
 }
 
+type MailConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	FromName string
+	// TLSMode is "none", "starttls" or "tls"
+	TLSMode string
+	Timeout time.Duration
+}
+
 type BackgroundJobsConfig struct {
 	ShipmentRefreshInterval     time.Duration
 	ShipmentRefreshWorkers      int
 	ShipmentMaxPerRun           int
 	ShipmentSkipRecentlyUpdated time.Duration
+
+	// Adaptive refresh scheduling: each shipment carries its own next_refresh_at
+	ShipmentRefreshNearArrival time.Duration
+	ShipmentRefreshActive      time.Duration
+	ShipmentRefreshMidOcean    time.Duration
+	ShipmentRefreshDormant     time.Duration
+	ShipmentRefreshRetry       time.Duration
+	ShipmentArrivalWindow      time.Duration
+	ShipmentDormantAfter       time.Duration
+	ShipmentRefreshTerminal    time.Duration
+
+	// Tracking stops once a shipment has been in a terminal status for this many days (0 = never)
+	ShipmentStopTrackingAfterDays int
+
+	// Notification delivery: pending emails are sent every interval, batched ones are combined every batch interval
+	NotificationDeliveryInterval time.Duration
+	NotificationBatchInterval    time.Duration
+	NotificationMaxAttempts      int
+	NotificationRetryBackoff     time.Duration
+	// Hour of the day (UTC) daily chat summaries are sent at
+	NotificationDailySummaryHour int
+	NotificationChatTimeout      time.Duration
+
+	// In-app inbox: notifications older than the retention are deleted every cleanup interval
+	InboxRetention       time.Duration
+	InboxCleanupInterval time.Duration
+
+	// Webhook delivery: pending webhooks are sent every interval and retried with exponential backoff
+	WebhookDeliveryInterval time.Duration
+	WebhookMaxAttempts      int
+	WebhookRetryBackoff     time.Duration
+	WebhookTimeout          time.Duration
+
+	// Shipment digests: schedules are checked every interval and sent once their hour has passed
+	DigestCheckInterval time.Duration
+
+	// Demurrage and detention: free time is checked every interval and alerted before it runs out
+	FreeTimeCheckInterval time.Duration
 }
 
 func New() *Config {
@@ -50,6 +111,10 @@ func New() *Config {
 			ReadTimeout:  getEnvAsDuration("SERVER_READ_TIMEOUT", 10*time.Second),
 			WriteTimeout: getEnvAsDuration("SERVER_WRITE_TIMEOUT", 10*time.Second),
 			IdleTimeout:  getEnvAsDuration("SERVER_IDLE_TIMEOUT", 60*time.Second),
+
+			SyncDrainTimeout:    getEnvAsDuration("SERVER_SYNC_DRAIN_TIMEOUT", 20*time.Second),
+			JobsShutdownTimeout: getEnvAsDuration("SERVER_JOBS_SHUTDOWN_TIMEOUT", 5*time.Second),
+			ShutdownTimeout:     getEnvAsDuration("SERVER_SHUTDOWN_TIMEOUT", 15*time.Second),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -64,12 +129,53 @@ func New() *Config {
 			APIKey:  getEnv("SAFECUBE_API_KEY", ""),
 		},
 		BackgroundJobs: BackgroundJobsConfig{
-			ShipmentRefreshInterval:     getEnvAsDuration("SHIPMENT_REFRESH_INTERVAL", 3*time.Hour),
+			ShipmentRefreshInterval:     getEnvAsDuration("SHIPMENT_REFRESH_INTERVAL", 15*time.Minute),
 			ShipmentRefreshWorkers:      getEnvAsInt("SHIPMENT_REFRESH_WORKERS", 5),
 			ShipmentMaxPerRun:           getEnvAsInt("SHIPMENT_MAX_PER_RUN", 0),
 			ShipmentSkipRecentlyUpdated: getEnvAsDuration("SHIPMENT_SKIP_RECENTLY_UPDATED", 30*time.Minute),
+			ShipmentRefreshNearArrival:  getEnvAsDuration("SHIPMENT_REFRESH_NEAR_ARRIVAL", 1*time.Hour),
+			ShipmentRefreshActive:       getEnvAsDuration("SHIPMENT_REFRESH_ACTIVE", 6*time.Hour),
+			ShipmentRefreshMidOcean:     getEnvAsDuration("SHIPMENT_REFRESH_MID_OCEAN", 24*time.Hour),
+			ShipmentRefreshDormant:      getEnvAsDuration("SHIPMENT_REFRESH_DORMANT", 7*24*time.Hour),
+			ShipmentRefreshRetry:        getEnvAsDuration("SHIPMENT_REFRESH_RETRY", 1*time.Hour),
+			ShipmentArrivalWindow:       getEnvAsDuration("SHIPMENT_ARRIVAL_WINDOW", 72*time.Hour),
+			ShipmentDormantAfter:        getEnvAsDuration("SHIPMENT_DORMANT_AFTER", 21*24*time.Hour),
+			ShipmentRefreshTerminal:     getEnvAsDuration("SHIPMENT_REFRESH_TERMINAL", 24*time.Hour),
+
+			ShipmentStopTrackingAfterDays: getEnvAsInt("SHIPMENT_STOP_TRACKING_AFTER_DAYS", 14),
+
+			NotificationDeliveryInterval: getEnvAsDuration("NOTIFICATION_DELIVERY_INTERVAL", 1*time.Minute),
+			NotificationBatchInterval:    getEnvAsDuration("NOTIFICATION_BATCH_INTERVAL", 1*time.Hour),
+			NotificationMaxAttempts:      getEnvAsInt("NOTIFICATION_MAX_ATTEMPTS", 5),
+			NotificationRetryBackoff:     getEnvAsDuration("NOTIFICATION_RETRY_BACKOFF", 1*time.Minute),
+			NotificationDailySummaryHour: getEnvAsInt("NOTIFICATION_DAILY_SUMMARY_HOUR", 8),
+			NotificationChatTimeout:      getEnvAsDuration("NOTIFICATION_CHAT_TIMEOUT", 10*time.Second),
+
+			InboxRetention:       getEnvAsDuration("INBOX_RETENTION", 90*24*time.Hour),
+			InboxCleanupInterval: getEnvAsDuration("INBOX_CLEANUP_INTERVAL", 6*time.Hour),
+
+			WebhookDeliveryInterval: getEnvAsDuration("WEBHOOK_DELIVERY_INTERVAL", 30*time.Second),
+			WebhookMaxAttempts:      getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			WebhookRetryBackoff:     getEnvAsDuration("WEBHOOK_RETRY_BACKOFF", 30*time.Second),
+			WebhookTimeout:          getEnvAsDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+
+			DigestCheckInterval: getEnvAsDuration("DIGEST_CHECK_INTERVAL", 15*time.Minute),
+
+			FreeTimeCheckInterval: getEnvAsDuration("FREE_TIME_CHECK_INTERVAL", time.Hour),
+		},
+		Mail: MailConfig{
+			Host:     getEnv("SMTP_HOST", "localhost"),
+			Port:     getEnvAsInt("SMTP_PORT", 1025),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", "notifications@go-starter.local"),
+			FromName: getEnv("SMTP_FROM_NAME", "Shipment Tracker"),
+			TLSMode:  getEnv("SMTP_TLS_MODE", "none"),
+			Timeout:  getEnvAsDuration("SMTP_TIMEOUT", 10*time.Second),
 		},
 		MaxAvailableUser: getEnvAsInt("MAX_AVAILABLE_USER", 0),
+		AdminEmails:      getEnvAsSlice("ADMIN_EMAILS", nil),
+		AppBaseURL:       strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:8080"), "/"),
 	}
 }
 
@@ -108,3 +214,18 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
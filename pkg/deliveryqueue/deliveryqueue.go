@@ -0,0 +1,27 @@
+// Package deliveryqueue holds the claim lease and retry schedule shared by the queued outbound
+// deliveries, e.g. notifications and webhooks
+package deliveryqueue
+
+import "time"
+
+// Lease is how long a claimed delivery is hidden from other delivery runs while it is sent
+const Lease = 5 * time.Minute
+
+// MaxRetryDelay caps the exponential backoff between delivery attempts
+const MaxRetryDelay = 6 * time.Hour
+
+// RetryDelay returns how long to wait before the given attempt, starting at backoff and
+// doubling with every attempt
+func RetryDelay(backoff time.Duration, attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := backoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= MaxRetryDelay {
+			return MaxRetryDelay
+		}
+	}
+	return delay
+}
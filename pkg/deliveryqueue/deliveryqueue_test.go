@@ -0,0 +1,26 @@
+package deliveryqueue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: time.Minute},
+		{attempt: 1, want: time.Minute},
+		{attempt: 2, want: 2 * time.Minute},
+		{attempt: 3, want: 4 * time.Minute},
+		{attempt: 5, want: 16 * time.Minute},
+		{attempt: 20, want: MaxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		if got := RetryDelay(time.Minute, tt.attempt); got != tt.want {
+			t.Errorf("RetryDelay(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
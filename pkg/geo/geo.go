@@ -0,0 +1,291 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Point is a WGS84 position in degrees
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// BBox is a bounding box in degrees. A box crossing the antimeridian has MinLng > MaxLng.
+type BBox struct {
+	MinLat float64
+	MinLng float64
+	MaxLat float64
+	MaxLng float64
+}
+
+// World is the bounding box of the whole map
+var World = BBox{MinLat: -90, MinLng: -180, MaxLat: 90, MaxLng: 180}
+
+// ParseBBox parses "minLng,minLat,maxLng,maxLat", the order of GeoJSON and Leaflet's
+// toBBoxString. Longitudes of a map panned across the antimeridian are wrapped into
+// -180..180, a box spanning the whole world is World.
+func ParseBBox(s string) (BBox, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return BBox{}, fmt.Errorf("invalid bbox '%s', expected minLng,minLat,maxLng,maxLat", s)
+	}
+
+	values := make([]float64, 4)
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return BBox{}, fmt.Errorf("invalid bbox '%s', expected minLng,minLat,maxLng,maxLat", s)
+		}
+		values[i] = value
+	}
+
+	minLng, minLat, maxLng, maxLat := values[0], values[1], values[2], values[3]
+	if minLat > maxLat || minLng > maxLng {
+		return BBox{}, fmt.Errorf("invalid bbox '%s', min must not exceed max", s)
+	}
+
+	box := BBox{MinLat: math.Max(minLat, -90), MaxLat: math.Min(maxLat, 90)}
+	if maxLng-minLng >= 360 {
+		box.MinLng, box.MaxLng = -180, 180
+		return box, nil
+	}
+	box.MinLng, box.MaxLng = WrapLng(minLng), WrapLng(maxLng)
+	return box, nil
+}
+
+// WrapLng wraps a longitude into -180..180
+func WrapLng(lng float64) float64 {
+	if lng >= -180 && lng <= 180 {
+		return lng
+	}
+	return math.Mod(math.Mod(lng+180, 360)+360, 360) - 180
+}
+
+// CrossesAntimeridian reports whether the box wraps around at 180 degrees
+func (b BBox) CrossesAntimeridian() bool {
+	return b.MinLng > b.MaxLng
+}
+
+// Contains reports whether a point lies inside the box
+func (b BBox) Contains(p Point) bool {
+	if p.Lat < b.MinLat || p.Lat > b.MaxLat {
+		return false
+	}
+	if b.CrossesAntimeridian() {
+		return p.Lng >= b.MinLng || p.Lng <= b.MaxLng
+	}
+	return p.Lng >= b.MinLng && p.Lng <= b.MaxLng
+}
+
+// Pixel projects a point to Web Mercator pixel coordinates at a zoom level with 256 pixel tiles
+func Pixel(p Point, zoom int) (float64, float64) {
+	scale := 256 * math.Exp2(float64(zoom))
+	lat := math.Max(math.Min(p.Lat, 85.05112878), -85.05112878)
+	sin := math.Sin(lat * math.Pi / 180)
+
+	x := (p.Lng + 180) / 360 * scale
+	y := (0.5 - math.Log((1+sin)/(1-sin))/(4*math.Pi)) * scale
+	return x, y
+}
+
+// DegreesPerPixel is the longitude span of a pixel at a zoom level
+func DegreesPerPixel(zoom int) float64 {
+	return 360 / (256 * math.Exp2(float64(zoom)))
+}
+
+// Cluster is a group of points that are close together on screen
+type Cluster struct {
+	// Center is the mean position of the members
+	Center Point
+	// Members are the indexes of the clustered points
+	Members []int
+}
+
+// ClusterPoints groups points that fall into the same grid cell of cellPixels at a zoom
+// level. Clusters are returned in the order of their first member.
+func ClusterPoints(points []Point, zoom int, cellPixels float64) []Cluster {
+	type cell struct{ x, y int64 }
+	byCell := map[cell]int{}
+	var clusters []Cluster
+
+	for i, p := range points {
+		x, y := Pixel(p, zoom)
+		key := cell{int64(math.Floor(x / cellPixels)), int64(math.Floor(y / cellPixels))}
+
+		index, ok := byCell[key]
+		if !ok {
+			index = len(clusters)
+			byCell[key] = index
+			clusters = append(clusters, Cluster{})
+		}
+		clusters[index].Members = append(clusters[index].Members, i)
+	}
+
+	for i := range clusters {
+		var lat, lng float64
+		for _, member := range clusters[i].Members {
+			lat += points[member].Lat
+			lng += points[member].Lng
+		}
+		n := float64(len(clusters[i].Members))
+		clusters[i].Center = Point{Lat: lat / n, Lng: lng / n}
+	}
+
+	return clusters
+}
+
+// Simplify reduces a line with the Douglas-Peucker algorithm, dropping points closer than
+// tolerance degrees to the simplified line. The first and the last point are always kept.
+func Simplify(points []Point, tolerance float64) []Point {
+	if len(points) < 3 || tolerance <= 0 {
+		return points
+	}
+
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+
+	type span struct{ first, last int }
+	stack := []span{{0, len(points) - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		farthest, maxDistance := -1, tolerance
+		for i := s.first + 1; i < s.last; i++ {
+			if d := segmentDistance(points[i], points[s.first], points[s.last]); d > maxDistance {
+				farthest, maxDistance = i, d
+			}
+		}
+		if farthest < 0 {
+			continue
+		}
+		keep[farthest] = true
+		stack = append(stack, span{s.first, farthest}, span{farthest, s.last})
+	}
+
+	simplified := make([]Point, 0, len(points))
+	for i, p := range points {
+		if keep[i] {
+			simplified = append(simplified, p)
+		}
+	}
+	return simplified
+}
+
+// segmentDistance is the planar distance in degrees of p to the segment a-b
+func segmentDistance(p, a, b Point) float64 {
+	dx, dy := b.Lng-a.Lng, b.Lat-a.Lat
+	if dx == 0 && dy == 0 {
+		return math.Hypot(p.Lng-a.Lng, p.Lat-a.Lat)
+	}
+
+	t := ((p.Lng-a.Lng)*dx + (p.Lat-a.Lat)*dy) / (dx*dx + dy*dy)
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(p.Lng-(a.Lng+t*dx), p.Lat-(a.Lat+t*dy))
+}
+
+// earthRadiusMeters is the mean earth radius
+const earthRadiusMeters = 6371008.8
+
+// Distance is the great-circle distance in meters between two points
+func Distance(a, b Point) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// InPolygon reports whether a point lies inside a polygon with the even-odd rule. The
+// polygon doesn't need to be closed and must not cross the antimeridian.
+func InPolygon(p Point, polygon []Point) bool {
+	if len(polygon) < 3 {
+		return false
+	}
+
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lng < (b.Lng-a.Lng)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// LineLength is the length in meters of a line along great circles between its points
+func LineLength(line []Point) float64 {
+	var length float64
+	for i := 1; i < len(line); i++ {
+		length += Distance(line[i-1], line[i])
+	}
+	return length
+}
+
+// LineLocation is where a point is closest to a line
+type LineLocation struct {
+	// Along is the distance in meters from the start of the line to the closest point
+	Along float64
+	// Offset is the distance in meters of the point from the line
+	Offset float64
+	// Closest is the point on the line closest to the point
+	Closest Point
+}
+
+// LocateOnLine finds the point of a line closest to p. Segments are treated as straight in
+// an equirectangular projection around each segment, which is accurate for the densely
+// sampled lines of routes, and may cross the antimeridian.
+func LocateOnLine(line []Point, p Point) (LineLocation, bool) {
+	if len(line) == 0 {
+		return LineLocation{}, false
+	}
+	if len(line) == 1 {
+		return LineLocation{Offset: Distance(line[0], p), Closest: line[0]}, true
+	}
+
+	best := LineLocation{Offset: math.Inf(1)}
+	var along float64
+	for i := 1; i < len(line); i++ {
+		a, b := line[i-1], line[i]
+		segmentLength := Distance(a, b)
+
+		closest := closestOnSegment(a, b, p)
+		if offset := Distance(closest, p); offset < best.Offset {
+			best = LineLocation{Along: along + Distance(a, closest), Offset: offset, Closest: closest}
+		}
+		along += segmentLength
+	}
+	return best, true
+}
+
+// closestOnSegment projects p onto the segment a-b in an equirectangular projection at the
+// segment's latitude
+func closestOnSegment(a, b, p Point) Point {
+	scale := math.Cos((a.Lat + b.Lat) / 2 * math.Pi / 180)
+	bx, px := unwrapLng(a.Lng, b.Lng), unwrapLng(a.Lng, p.Lng)
+
+	dx, dy := (bx-a.Lng)*scale, b.Lat-a.Lat
+	if dx == 0 && dy == 0 {
+		return a
+	}
+
+	t := ((px-a.Lng)*scale*dx + (p.Lat-a.Lat)*dy) / (dx*dx + dy*dy)
+	t = math.Max(0, math.Min(1, t))
+	return Point{Lat: a.Lat + t*(b.Lat-a.Lat), Lng: WrapLng(a.Lng + t*(bx-a.Lng))}
+}
+
+// unwrapLng shifts lng by whole turns to within 180 degrees of ref
+func unwrapLng(ref, lng float64) float64 {
+	for lng-ref > 180 {
+		lng -= 360
+	}
+	for lng-ref < -180 {
+		lng += 360
+	}
+	return lng
+}
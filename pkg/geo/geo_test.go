@@ -0,0 +1,166 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseBBox(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    BBox
+		wantErr bool
+	}{
+		{"-10,40,20,60", BBox{MinLat: 40, MinLng: -10, MaxLat: 60, MaxLng: 20}, false},
+		{"170,-10,200,10", BBox{MinLat: -10, MinLng: 170, MaxLat: 10, MaxLng: -160}, false},
+		{"-540,-100,540,100", World, false},
+		{"1,2,3", BBox{}, true},
+		{"10,0,5,10", BBox{}, true},
+		{"a,0,5,10", BBox{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseBBox(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseBBox(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseBBox(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestBBoxContains(t *testing.T) {
+	europe := BBox{MinLat: 40, MinLng: -10, MaxLat: 60, MaxLng: 20}
+	if !europe.Contains(Point{Lat: 51.9, Lng: 4.1}) || europe.Contains(Point{Lat: 1.3, Lng: 103.8}) {
+		t.Errorf("Unexpected containment for %+v", europe)
+	}
+
+	pacific := BBox{MinLat: -10, MinLng: 170, MaxLat: 10, MaxLng: -160}
+	if !pacific.CrossesAntimeridian() || !pacific.Contains(Point{Lat: 0, Lng: 179}) || !pacific.Contains(Point{Lat: 0, Lng: -170}) {
+		t.Errorf("Expected points on both sides of the antimeridian inside %+v", pacific)
+	}
+	if pacific.Contains(Point{Lat: 0, Lng: 0}) {
+		t.Errorf("Expected Greenwich outside %+v", pacific)
+	}
+}
+
+func TestClusterPoints(t *testing.T) {
+	points := []Point{
+		{Lat: 51.90, Lng: 4.10},
+		{Lat: 51.95, Lng: 4.15},
+		{Lat: 1.30, Lng: 103.80},
+	}
+
+	clusters := ClusterPoints(points, 3, 60)
+	if len(clusters) != 2 {
+		t.Fatalf("Expected 2 clusters at zoom 3, got %d", len(clusters))
+	}
+	if len(clusters[0].Members) != 2 || math.Abs(clusters[0].Center.Lat-51.925) > 1e-9 {
+		t.Errorf("Expected Rotterdam points clustered around their mean, got %+v", clusters[0])
+	}
+
+	if clusters := ClusterPoints(points, 14, 60); len(clusters) != 3 {
+		t.Errorf("Expected no clustering at zoom 14, got %d clusters", len(clusters))
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	line := []Point{
+		{Lat: 0, Lng: 0},
+		{Lat: 0.001, Lng: 1},
+		{Lat: 0, Lng: 2},
+		{Lat: 5, Lng: 3},
+		{Lat: 0, Lng: 4},
+	}
+
+	got := Simplify(line, 0.1)
+	want := []Point{line[0], line[2], line[3], line[4]}
+	if len(got) != len(want) {
+		t.Fatalf("Simplify kept %d points, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("point %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if got := Simplify(line, 0); len(got) != len(line) {
+		t.Errorf("Expected a zero tolerance to keep every point")
+	}
+}
+
+func TestDistance(t *testing.T) {
+	rotterdam := Point{Lat: 51.9225, Lng: 4.47917}
+	hamburg := Point{Lat: 53.5511, Lng: 9.9937}
+
+	if d := Distance(rotterdam, hamburg); math.Abs(d-413000) > 3000 {
+		t.Errorf("Distance(Rotterdam, Hamburg) = %.0f m, want about 413 km", d)
+	}
+	if d := Distance(rotterdam, rotterdam); d != 0 {
+		t.Errorf("Expected no distance to the same point, got %f", d)
+	}
+}
+
+func TestInPolygon(t *testing.T) {
+	// An L shaped anchorage
+	polygon := []Point{
+		{Lat: 0, Lng: 0},
+		{Lat: 0, Lng: 2},
+		{Lat: 1, Lng: 2},
+		{Lat: 1, Lng: 1},
+		{Lat: 2, Lng: 1},
+		{Lat: 2, Lng: 0},
+	}
+
+	tests := []struct {
+		point Point
+		want  bool
+	}{
+		{Point{Lat: 0.5, Lng: 0.5}, true},
+		{Point{Lat: 0.5, Lng: 1.5}, true},
+		{Point{Lat: 1.5, Lng: 0.5}, true},
+		{Point{Lat: 1.5, Lng: 1.5}, false},
+		{Point{Lat: -1, Lng: 1}, false},
+	}
+	for _, tt := range tests {
+		if got := InPolygon(tt.point, polygon); got != tt.want {
+			t.Errorf("InPolygon(%+v) = %v, want %v", tt.point, got, tt.want)
+		}
+	}
+
+	if InPolygon(Point{}, polygon[:2]) {
+		t.Errorf("Expected a degenerate polygon to contain nothing")
+	}
+}
+
+func TestLocateOnLine(t *testing.T) {
+	// Along the equator a degree is about 111.2 km
+	line := []Point{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 1}, {Lat: 0, Lng: 2}}
+
+	location, ok := LocateOnLine(line, Point{Lat: 0.1, Lng: 1.5})
+	if !ok {
+		t.Fatalf("Expected a location")
+	}
+	if math.Abs(location.Along-166800) > 500 || math.Abs(location.Offset-11120) > 100 {
+		t.Errorf("Unexpected location %+v", location)
+	}
+	if math.Abs(LineLength(line)-222390) > 500 {
+		t.Errorf("LineLength = %f, want about 222.4 km", LineLength(line))
+	}
+
+	if location, _ := LocateOnLine(line, Point{Lat: 0, Lng: -3}); location.Along != 0 || location.Closest != line[0] {
+		t.Errorf("Expected a point before the line to locate at its start, got %+v", location)
+	}
+
+	pacific := []Point{{Lat: 0, Lng: 179}, {Lat: 0, Lng: -179}}
+	location, _ = LocateOnLine(pacific, Point{Lat: 0, Lng: 180})
+	if math.Abs(location.Along-111200) > 500 || location.Offset > 1 {
+		t.Errorf("Expected the antimeridian halfway along the segment, got %+v", location)
+	}
+
+	if _, ok := LocateOnLine(nil, Point{}); ok {
+		t.Errorf("Expected no location on an empty line")
+	}
+}
@@ -0,0 +1,320 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TLS modes supported by the SMTP mailer
+const (
+	TLSModeNone     = "none"
+	TLSModeStartTLS = "starttls"
+	TLSModeTLS      = "tls"
+)
+
+// base64LineLength is the maximum line length of base64 encoded attachments
+const base64LineLength = 76
+
+// Message is an email with a plain-text and an optional HTML body
+type Message struct {
+	To          []string
+	Subject     string
+	TextBody    string
+	HTMLBody    string
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to a message
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Mailer sends email messages
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config holds the SMTP connection settings
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	FromName string
+	TLSMode  string
+	Timeout  time.Duration
+}
+
+// SMTPMailer sends messages through an SMTP server, one connection per message
+type SMTPMailer struct {
+	config Config
+}
+
+// NewSMTPMailer creates a new SMTP mailer
+func NewSMTPMailer(config Config) *SMTPMailer {
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.TLSMode == "" {
+		config.TLSMode = TLSModeNone
+	}
+
+	return &SMTPMailer{
+		config: config,
+	}
+}
+
+// Send delivers the message. The whole SMTP conversation is bound to the context deadline
+// or the configured timeout, whichever comes first.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if len(msg.To) == 0 {
+		return errors.New("message has no recipients")
+	}
+
+	body, err := m.build(msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	conn, err := m.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(m.config.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("failed to set SMTP deadline: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if m.config.TLSMode == TLSModeStartTLS {
+		if err := client.StartTLS(&tls.Config{ServerName: m.config.Host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	if m.config.Username != "" {
+		auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.config.From); err != nil {
+		return fmt.Errorf("failed to set sender: %w", err)
+	}
+	for _, recipient := range msg.To {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("failed to add recipient %s: %w", recipient, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to start message data: %w", err)
+	}
+	if _, err := writer.Write(body); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+func (m *SMTPMailer) dial(ctx context.Context) (net.Conn, error) {
+	address := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	dialer := &net.Dialer{Timeout: m.config.Timeout}
+
+	if m.config.TLSMode == TLSModeTLS {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: m.config.Host}}
+		return tlsDialer.DialContext(ctx, "tcp", address)
+	}
+	return dialer.DialContext(ctx, "tcp", address)
+}
+
+// build renders the message as MIME. The body is multipart/alternative when it has an
+// HTML part, and wrapped in multipart/mixed together with the attachments if there are any.
+func (m *SMTPMailer) build(msg Message, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+
+	from := mail.Address{Name: m.config.FromName, Address: m.config.From}
+	headers := []struct{ key, value string }{
+		{"From", from.String()},
+		{"To", strings.Join(msg.To, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", msg.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", m.messageID()},
+		{"MIME-Version", "1.0"},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", header.key, header.value)
+	}
+
+	bodyHeader, body, err := buildBody(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(msg.Attachments) == 0 {
+		writeHeader(&buf, bodyHeader)
+		buf.Write(body)
+		return buf.Bytes(), nil
+	}
+
+	writer := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", writer.Boundary())
+
+	bodyWriter, err := writer.CreatePart(bodyHeader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create message part: %w", err)
+	}
+	if _, err := bodyWriter.Write(body); err != nil {
+		return nil, fmt.Errorf("failed to write message body: %w", err)
+	}
+
+	for _, attachment := range msg.Attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"name": attachment.Filename})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create attachment part: %w", err)
+		}
+		if err := writeBase64(partWriter, attachment.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish message: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// buildBody renders the text and HTML bodies, it returns the headers of the body part
+func buildBody(msg Message) (textproto.MIMEHeader, []byte, error) {
+	var buf bytes.Buffer
+
+	if msg.HTMLBody == "" {
+		if err := writeQuotedPrintable(&buf, msg.TextBody); err != nil {
+			return nil, nil, err
+		}
+		return textproto.MIMEHeader{
+			"Content-Type":              {"text/plain; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		}, buf.Bytes(), nil
+	}
+
+	writer := multipart.NewWriter(&buf)
+
+	parts := []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.TextBody},
+		{"text/html; charset=utf-8", msg.HTMLBody},
+	}
+	for _, part := range parts {
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create message part: %w", err)
+		}
+		if err := writeQuotedPrintable(partWriter, part.body); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to finish message: %w", err)
+	}
+
+	return textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + writer.Boundary()},
+	}, buf.Bytes(), nil
+}
+
+// writeHeader writes the MIME header in a stable order followed by the blank line that ends it
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+		}
+	}
+	buf.WriteString("\r\n")
+}
+
+func (m *SMTPMailer) messageID() string {
+	random := make([]byte, 12)
+	_, _ = rand.Read(random)
+
+	domain := "localhost"
+	if at := strings.LastIndex(m.config.From, "@"); at >= 0 {
+		domain = m.config.From[at+1:]
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(random), domain)
+}
+
+// writeBase64 encodes data as base64 in lines of 76 characters
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for start := 0; start < len(encoded); start += base64LineLength {
+		line := encoded[start:min(start+base64LineLength, len(encoded))]
+		if _, err := io.WriteString(w, line+"\r\n"); err != nil {
+			return fmt.Errorf("failed to encode attachment: %w", err)
+		}
+	}
+	return nil
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return fmt.Errorf("failed to encode message body: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return fmt.Errorf("failed to encode message body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package mailer
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"go-starter/pkg/mailer/mailertest"
+)
+
+func newTestMailer(t *testing.T) (*SMTPMailer, *mailertest.Server) {
+	t.Helper()
+
+	server, err := mailertest.NewServer()
+	if err != nil {
+		t.Fatalf("Failed to start SMTP server: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	mailer := NewSMTPMailer(Config{
+		Host:     server.Host(),
+		Port:     server.Port(),
+		From:     "notifications@example.com",
+		FromName: "Shipment Tracker",
+		Timeout:  5 * time.Second,
+	})
+	return mailer, server
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	mailer, server := newTestMailer(t)
+
+	err := mailer.Send(context.Background(), Message{
+		To:       []string{"ops@example.com"},
+		Subject:  "ETA of MSCU1234567 slipped by 3 days",
+		TextBody: "The ETA moved from 2025-06-01 to 2025-06-04.",
+		HTMLBody: "<p>The ETA moved from <b>2025-06-01</b> to <b>2025-06-04</b>.</p>",
+	})
+	if err != nil {
+		t.Fatalf("Expected message to be sent, got %v", err)
+	}
+
+	messages := server.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(messages))
+	}
+	msg := messages[0]
+	if msg.From != "notifications@example.com" {
+		t.Errorf("Expected sender notifications@example.com, got %s", msg.From)
+	}
+	if len(msg.To) != 1 || msg.To[0] != "ops@example.com" {
+		t.Errorf("Expected recipient ops@example.com, got %v", msg.To)
+	}
+
+	for _, want := range []string{
+		"Subject: ETA of MSCU1234567 slipped by 3 days",
+		"Content-Type: multipart/alternative",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Type: text/html; charset=utf-8",
+		"The ETA moved from 2025-06-01 to 2025-06-04.",
+	} {
+		if !strings.Contains(msg.Data, want) {
+			t.Errorf("Expected message to contain %q, got:\n%s", want, msg.Data)
+		}
+	}
+}
+
+func TestSMTPMailer_SendPlainText(t *testing.T) {
+	mailer, server := newTestMailer(t)
+
+	err := mailer.Send(context.Background(), Message{
+		To:       []string{"ops@example.com"},
+		Subject:  "Plain",
+		TextBody: "Just text",
+	})
+	if err != nil {
+		t.Fatalf("Expected message to be sent, got %v", err)
+	}
+
+	messages := server.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(messages))
+	}
+	if strings.Contains(messages[0].Data, "multipart") {
+		t.Errorf("Expected a single part message, got:\n%s", messages[0].Data)
+	}
+}
+
+func TestSMTPMailer_SendRejected(t *testing.T) {
+	mailer, server := newTestMailer(t)
+	server.FailNext(1)
+
+	err := mailer.Send(context.Background(), Message{
+		To:       []string{"ops@example.com"},
+		Subject:  "Rejected",
+		TextBody: "Rejected",
+	})
+	if err == nil {
+		t.Fatal("Expected an error for a rejected message")
+	}
+	if len(server.Messages()) != 0 {
+		t.Errorf("Expected no stored messages, got %d", len(server.Messages()))
+	}
+}
+
+func TestSMTPMailer_NoRecipients(t *testing.T) {
+	mailer, _ := newTestMailer(t)
+
+	if err := mailer.Send(context.Background(), Message{Subject: "Nobody"}); err == nil {
+		t.Fatal("Expected an error for a message without recipients")
+	}
+}
+
+func TestSMTPMailer_SendWithAttachment(t *testing.T) {
+	mailer, server := newTestMailer(t)
+
+	err := mailer.Send(context.Background(), Message{
+		To:          []string{"ops@example.com"},
+		Subject:     "Daily digest",
+		TextBody:    "3 shipments arrive this week.",
+		HTMLBody:    "<p>3 shipments arrive this week.</p>",
+		Attachments: []Attachment{{Filename: "digest.csv", ContentType: "text/csv", Data: []byte("Shipment,ETA\nMSCU1234567,2025-06-04\n")}},
+	})
+	if err != nil {
+		t.Fatalf("Expected message to be sent, got %v", err)
+	}
+
+	messages := server.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(messages))
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(messages[0].Data))
+	if err != nil {
+		t.Fatalf("Failed to parse message: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Expected a multipart/mixed message, got %q (%v)", mediaType, err)
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	body, err := reader.NextPart()
+	if err != nil {
+		t.Fatalf("Expected a body part: %v", err)
+	}
+	if !strings.HasPrefix(body.Header.Get("Content-Type"), "multipart/alternative") {
+		t.Errorf("Expected the body to be multipart/alternative, got %q", body.Header.Get("Content-Type"))
+	}
+
+	attachment, err := reader.NextPart()
+	if err != nil {
+		t.Fatalf("Expected an attachment part: %v", err)
+	}
+	if attachment.FileName() != "digest.csv" {
+		t.Errorf("Expected filename digest.csv, got %q", attachment.FileName())
+	}
+	// The multipart reader decodes quoted-printable but not base64
+	encoded, _ := io.ReadAll(attachment)
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	if err != nil {
+		t.Fatalf("Failed to decode attachment: %v", err)
+	}
+	if string(data) != "Shipment,ETA\nMSCU1234567,2025-06-04\n" {
+		t.Errorf("Unexpected attachment content %q", data)
+	}
+}
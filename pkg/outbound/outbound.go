@@ -0,0 +1,113 @@
+// Package outbound sends requests to URLs users configure, e.g. webhook receivers and chat
+// channels, without letting them reach the server's own network
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrBlockedAddress is returned when a URL points at an address of the server's own network
+var ErrBlockedAddress = errors.New("receivers on loopback, link-local, private or unspecified addresses are not allowed")
+
+// Option changes which addresses a client may connect to
+type Option func(*policy)
+
+type policy struct {
+	allowLoopback bool
+}
+
+// AllowLoopback lets a client connect to loopback addresses. It is meant for tests that
+// send to a local server, never for user configured URLs.
+func AllowLoopback() Option {
+	return func(p *policy) {
+		p.allowLoopback = true
+	}
+}
+
+// IsBlockedAddr reports whether requests must not be sent to the address. Receivers are
+// reached from inside the server's network, these addresses would let users probe it.
+func IsBlockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsMulticast() ||
+		addr.IsPrivate() ||
+		addr.IsUnspecified()
+}
+
+// ValidateHost checks that a host resolves to public addresses only. Names can resolve
+// differently later, clients from NewClient check the address again when they connect.
+func ValidateHost(ctx context.Context, host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrBlockedAddress
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if IsBlockedAddr(addr) {
+			return ErrBlockedAddress
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("host %s could not be resolved", host)
+	}
+	for _, addr := range addrs {
+		if IsBlockedAddr(addr) {
+			return ErrBlockedAddress
+		}
+	}
+	return nil
+}
+
+// control refuses connections to blocked addresses. It runs after name resolution for
+// every connection, which covers redirects and DNS rebinding.
+func (p *policy) control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid receiver address %s: %w", address, err)
+	}
+	addr := addrPort.Addr().Unmap()
+	if p.allowLoopback && addr.IsLoopback() {
+		return nil
+	}
+	if IsBlockedAddr(addr) {
+		return ErrBlockedAddress
+	}
+	return nil
+}
+
+// NewClient creates an HTTP client that refuses to connect to blocked addresses. It doesn't
+// use a proxy, the address check would only see the proxy.
+func NewClient(timeout time.Duration, opts ...Option) *http.Client {
+	p := &policy{}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   p.control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
@@ -0,0 +1,78 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestIsBlockedAddr(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1":        true,
+		"::1":              true,
+		"169.254.169.254":  true,
+		"fe80::1":          true,
+		"10.0.0.8":         true,
+		"172.16.4.1":       true,
+		"192.168.1.10":     true,
+		"fd00::1":          true,
+		"0.0.0.0":          true,
+		"::":               true,
+		"::ffff:127.0.0.1": true,
+		"8.8.8.8":          false,
+		"2606:4700::1111":  false,
+	}
+
+	for address, want := range tests {
+		if got := IsBlockedAddr(netip.MustParseAddr(address)); got != want {
+			t.Errorf("IsBlockedAddr(%s) = %v, want %v", address, got, want)
+		}
+	}
+}
+
+func TestValidateHost(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1":       true,
+		"169.254.169.254": true,
+		"localhost":       true,
+		"api.localhost.":  true,
+		"::1":             true,
+		"8.8.8.8":         false,
+	}
+
+	for host, blocked := range tests {
+		err := ValidateHost(context.Background(), host)
+		if blocked && !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("ValidateHost(%s) = %v, want the blocked address error", host, err)
+		}
+		if !blocked && err != nil {
+			t.Errorf("ValidateHost(%s) = %v, want no error", host, err)
+		}
+	}
+}
+
+func TestNewClient_RefusesInternalReceivers(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal secret"))
+	}))
+	defer receiver.Close()
+
+	resp, err := NewClient(5*time.Second).Post(receiver.URL, "application/json", nil)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("Expected the request to a loopback receiver to fail")
+	}
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Expected the blocked address error, got %v", err)
+	}
+
+	resp, err = NewClient(5*time.Second, AllowLoopback()).Post(receiver.URL, "application/json", nil)
+	if err != nil {
+		t.Fatalf("Expected the loopback receiver to be reachable when allowed, got %v", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,116 @@
+package singleflight
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Group collapses concurrent calls with the same key into a single execution.
+// Callers arriving while a call for their key is running wait for it and receive
+// the same result instead of doing the work again. It wraps golang.org/x/sync/singleflight
+// and counts the calls made through it.
+type Group struct {
+	group singleflight.Group
+
+	mu       sync.Mutex
+	inFlight map[string]struct{}
+
+	executed  atomic.Int64
+	collapsed atomic.Int64
+	waiting   atomic.Int64
+}
+
+// Stats contains counters about calls made through a Group
+type Stats struct {
+	// Executed is the number of times a function was actually run
+	Executed int64 `json:"executed"`
+	// Collapsed is the number of calls that received the result of another call
+	Collapsed int64 `json:"collapsed"`
+	// InFlight is the number of keys currently being executed
+	InFlight int `json:"in_flight"`
+	// Waiting is the number of calls waiting for a result, running ones included
+	Waiting int64 `json:"waiting"`
+}
+
+// panicked carries a panic of fn to the callers, x/sync would raise it where it can't be
+// recovered
+type panicked struct {
+	value interface{}
+}
+
+// NewGroup creates a new single-flight group
+func NewGroup() *Group {
+	return &Group{
+		inFlight: make(map[string]struct{}),
+	}
+}
+
+// Do executes fn for the given key, making sure only one execution is in flight per key.
+// shared reports whether the result was given to more than one caller. If fn panics,
+// the panic is raised in every caller waiting for the key.
+func (g *Group) Do(key string, fn func() (interface{}, error)) (v interface{}, err error, shared bool) {
+	executed := false
+	ch := g.group.DoChan(key, func() (interface{}, error) {
+		executed = true
+		g.executed.Add(1)
+		g.mu.Lock()
+		g.inFlight[key] = struct{}{}
+		g.mu.Unlock()
+
+		defer func() {
+			g.mu.Lock()
+			delete(g.inFlight, key)
+			g.mu.Unlock()
+		}()
+		return run(fn)
+	})
+
+	g.waiting.Add(1)
+	result := <-ch
+	g.waiting.Add(-1)
+
+	// executed is only set by the function this call ran, the result is sent after it returns
+	if !executed {
+		g.collapsed.Add(1)
+	}
+	if p, ok := result.Val.(panicked); ok {
+		panic(p.value)
+	}
+	return result.Val, result.Err, result.Shared
+}
+
+// run calls fn and returns a panic of it as the value
+func run(fn func() (interface{}, error)) (v interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			v, err = panicked{value: r}, nil
+		}
+	}()
+	return fn()
+}
+
+// InFlight reports whether a call for the given key is currently running
+func (g *Group) InFlight(key string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	_, ok := g.inFlight[key]
+	return ok
+}
+
+// Stats returns a snapshot of the group counters. Calls count as executed when their
+// function starts and as collapsed once they received the result of another call.
+func (g *Group) Stats() Stats {
+	g.mu.Lock()
+	inFlight := len(g.inFlight)
+	g.mu.Unlock()
+
+	return Stats{
+		Executed:  g.executed.Load(),
+		Collapsed: g.collapsed.Load(),
+		InFlight:  inFlight,
+		Waiting:   g.waiting.Load(),
+	}
+}
@@ -0,0 +1,132 @@
+package singleflight
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup_Do(t *testing.T) {
+	g := NewGroup()
+
+	v, err, shared := g.Do("key", func() (interface{}, error) {
+		return "value", nil
+	})
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if v.(string) != "value" {
+		t.Errorf("Expected value, got %v", v)
+	}
+	if shared {
+		t.Error("Expected single call not to be shared")
+	}
+}
+
+func TestGroup_DoCollapsesConcurrentCalls(t *testing.T) {
+	g := NewGroup()
+
+	var executions atomic.Int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	fn := func() (interface{}, error) {
+		if executions.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return 42, nil
+	}
+
+	const callers = 5
+	var wg sync.WaitGroup
+	results := make([]interface{}, callers)
+	sharedFlags := make([]bool, callers)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _, sharedFlags[0] = g.Do("shipment", fn)
+	}()
+	<-started
+
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _, sharedFlags[i] = g.Do("shipment", fn)
+		}(i)
+	}
+
+	// Wait until all followers joined the in-flight call
+	deadline := time.Now().Add(time.Second)
+	for g.Stats().Waiting < callers {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d waiting calls, got %d", callers, g.Stats().Waiting)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if collapsed := g.Stats().Collapsed; collapsed != 0 {
+		t.Errorf("Expected no collapsed calls before the result, got %d", collapsed)
+	}
+	close(release)
+	wg.Wait()
+
+	if executions.Load() != 1 {
+		t.Errorf("Expected function to run once, ran %d times", executions.Load())
+	}
+	for i := 0; i < callers; i++ {
+		if results[i].(int) != 42 {
+			t.Errorf("Caller %d: expected 42, got %v", i, results[i])
+		}
+		if !sharedFlags[i] {
+			t.Errorf("Caller %d: expected result to be shared", i)
+		}
+	}
+
+	stats := g.Stats()
+	if stats.Executed != 1 || stats.Collapsed != callers-1 || stats.InFlight != 0 || stats.Waiting != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestGroup_DoSharesErrors(t *testing.T) {
+	g := NewGroup()
+	wantErr := errors.New("provider unavailable")
+
+	_, err, _ := g.Do("key", func() (interface{}, error) {
+		return nil, wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Errorf("Expected %v, got %v", wantErr, err)
+	}
+
+	// A failed call must not stick, the next call runs again
+	v, err, _ := g.Do("key", func() (interface{}, error) {
+		return "ok", nil
+	})
+	if err != nil || v.(string) != "ok" {
+		t.Errorf("Expected retry to succeed, got %v, %v", v, err)
+	}
+}
+
+func TestGroup_DoReleasesKeyOnPanic(t *testing.T) {
+	g := NewGroup()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected panic to propagate")
+			}
+		}()
+		g.Do("key", func() (interface{}, error) {
+			panic("boom")
+		})
+	}()
+
+	if g.InFlight("key") {
+		t.Error("Expected key to be released after panic")
+	}
+}
@@ -0,0 +1,147 @@
+package unlocode
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Entry is a location of the code list
+type Entry struct {
+	// Locode is the country code followed by the location code, e.g. NLRTM
+	Locode           string
+	CountryCode      string
+	LocationCode     string
+	Name             string
+	NameWoDiacritics string
+	// Subdivision is the ISO 3166-2 subdivision code without the country, e.g. ZH
+	Subdivision string
+	Status      string
+	// Function are the function classifiers, e.g. 1-3----- for a port with a rail terminal
+	Function  string
+	Date      string
+	IATA      string
+	Latitude  *float64
+	Longitude *float64
+	Remarks   string
+}
+
+// IsPort reports whether the location is classified as a port
+func (e Entry) IsPort() bool {
+	return strings.HasPrefix(e.Function, "1")
+}
+
+// Columns of the release CSV, which has no header row
+const (
+	colChange = iota
+	colCountry
+	colLocation
+	colName
+	colNameWoDiacritics
+	colSubdivision
+	colStatus
+	colFunction
+	colDate
+	colIATA
+	colCoordinates
+	colRemarks
+	columnCount
+)
+
+var coordinatesPattern = regexp.MustCompile(`^(\d{2})(\d{2})([NS])\s+(\d{3})(\d{2})([EW])$`)
+
+// Parse reads the entries of a UN/LOCODE release CSV, e.g. one of the CodeListPart1.csv to
+// CodeListPart3.csv files of a release. Country heading rows and entries marked for
+// removal are skipped. Releases are encoded in ISO 8859-1, UTF-8 files are read as they are.
+func Parse(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var entries []Entry
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid UN/LOCODE CSV at line %d: %w", line, err)
+		}
+		if len(record) < columnCount-1 {
+			return nil, fmt.Errorf("invalid UN/LOCODE CSV at line %d: expected %d columns, got %d", line, columnCount, len(record))
+		}
+
+		for i := range record {
+			record[i] = strings.TrimSpace(decode(record[i]))
+		}
+		if record[colLocation] == "" || record[colChange] == "X" {
+			continue
+		}
+
+		entry := Entry{
+			Locode:           strings.ToUpper(record[colCountry] + record[colLocation]),
+			CountryCode:      strings.ToUpper(record[colCountry]),
+			LocationCode:     strings.ToUpper(record[colLocation]),
+			Name:             record[colName],
+			NameWoDiacritics: record[colNameWoDiacritics],
+			Subdivision:      record[colSubdivision],
+			Status:           record[colStatus],
+			Function:         record[colFunction],
+			Date:             record[colDate],
+			IATA:             record[colIATA],
+		}
+		if len(record) > colRemarks {
+			entry.Remarks = record[colRemarks]
+		}
+		if len(entry.Locode) != 5 {
+			return nil, fmt.Errorf("invalid UN/LOCODE CSV at line %d: invalid locode '%s'", line, entry.Locode)
+		}
+		entry.Latitude, entry.Longitude = ParseCoordinates(record[colCoordinates])
+
+		entries = append(entries, entry)
+	}
+}
+
+// ParseCoordinates parses degrees and minutes like 5155N 00430E, nil for missing or
+// malformed coordinates
+func ParseCoordinates(value string) (*float64, *float64) {
+	match := coordinatesPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return nil, nil
+	}
+
+	lat := degrees(match[1], match[2], match[3] == "S")
+	lng := degrees(match[4], match[5], match[6] == "W")
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return nil, nil
+	}
+	return &lat, &lng
+}
+
+func degrees(deg, min string, negative bool) float64 {
+	d, _ := strconv.Atoi(deg)
+	m, _ := strconv.Atoi(min)
+	value := float64(d) + float64(m)/60
+	if negative {
+		return -value
+	}
+	return value
+}
+
+// decode converts ISO 8859-1 text to UTF-8, every byte is the code point of its rune
+func decode(value string) string {
+	if utf8.ValidString(value) {
+		return value
+	}
+
+	runes := make([]rune, len(value))
+	for i := 0; i < len(value); i++ {
+		runes[i] = rune(value[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,56 @@
+package unlocode
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	// Göteborg is encoded in ISO 8859-1 like in the official release
+	release := ",\"NL\",\"\",\".NETHERLANDS\",\"\",\"\",\"\",\"\",\"\",\"\",\"\",\"\"\n" +
+		",\"NL\",\"RTM\",\"Rotterdam\",\"Rotterdam\",\"ZH\",\"AI\",\"12345---\",\"0501\",\"\",\"5155N 00430E\",\"\"\n" +
+		"X,\"NL\",\"OLD\",\"Removed\",\"Removed\",\"\",\"\",\"1-------\",\"\",\"\",\"\",\"\"\n" +
+		",\"SE\",\"GOT\",\"G\xf6teborg\",\"Goteborg\",\"O\",\"AI\",\"12345---\",\"0001\",\"GOT\",\"5742N 01157E\",\"\"\n" +
+		",\"AR\",\"USH\",\"Ushuaia\",\"Ushuaia\",\"V\",\"AI\",\"1--4----\",\"\",\"\",\"5448S 06818W\",\"\"\n"
+
+	entries, err := Parse(strings.NewReader(release))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries without the heading and the removed entry, got %d", len(entries))
+	}
+
+	rotterdam := entries[0]
+	if rotterdam.Locode != "NLRTM" || rotterdam.Subdivision != "ZH" || !rotterdam.IsPort() {
+		t.Errorf("Unexpected entry %+v", rotterdam)
+	}
+	if rotterdam.Latitude == nil || math.Abs(*rotterdam.Latitude-51.9167) > 0.001 || math.Abs(*rotterdam.Longitude-4.5) > 0.001 {
+		t.Errorf("Unexpected coordinates %v, %v", rotterdam.Latitude, rotterdam.Longitude)
+	}
+
+	if entries[1].Name != "Göteborg" || entries[1].IATA != "GOT" {
+		t.Errorf("Expected the ISO 8859-1 name to be decoded, got %q", entries[1].Name)
+	}
+	if *entries[2].Latitude >= 0 || *entries[2].Longitude >= 0 {
+		t.Errorf("Expected southern and western coordinates, got %v, %v", *entries[2].Latitude, *entries[2].Longitude)
+	}
+}
+
+func TestParseCoordinates(t *testing.T) {
+	for _, value := range []string{"", "5155N", "5155X 00430E", "9500N 00430E"} {
+		if lat, lng := ParseCoordinates(value); lat != nil || lng != nil {
+			t.Errorf("ParseCoordinates(%q) = %v, %v, want nil", value, lat, lng)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	if _, err := Parse(strings.NewReader(",\"NL\",\"RTM\"\n")); err == nil {
+		t.Error("Expected an error for missing columns")
+	}
+	if _, err := Parse(strings.NewReader(",\"NLX\",\"RTM\",\"Rotterdam\",\"\",\"\",\"\",\"\",\"\",\"\",\"\",\"\"\n")); err == nil {
+		t.Error("Expected an error for an invalid locode")
+	}
+}
@@ -63,6 +63,24 @@ export function actionCellRenderer(params) {
   });
 
   container.appendChild(refreshBtn);
+
+  // Tracking was stopped by the terminal status policy, allow resuming it
+  if (params.data?.trackingState === "completed") {
+    const resumeBtn = document.createElement("button");
+    resumeBtn.className = "text-green-600 hover:text-green-900 text-sm";
+    resumeBtn.title = "Resume tracking";
+    resumeBtn.innerHTML = `
+		<svg class="w-4 h-4" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+			<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M14.752 11.168l-3.197-2.132A1 1 0 0010 9.87v4.263a1 1 0 001.555.832l3.197-2.132a1 1 0 000-1.664z"></path>
+			<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M21 12a9 9 0 11-18 0 9 9 0 0118 0z"></path>
+		</svg>
+    `;
+    resumeBtn.addEventListener("click", () =>
+      resumeTracking(gridApi, params.data.id),
+    );
+    container.appendChild(resumeBtn);
+  }
+
   container.appendChild(deleteBtn);
   container.appendChild(detailsBtn);
 
@@ -83,6 +101,22 @@ function refreshShipment(gridApi, id) {
     });
 }
 
+function resumeTracking(gridApi, id) {
+  fetch(`/api/shipments/${id}/resume-tracking`, { method: "POST" })
+    .then((response) => {
+      if (!response.ok) throw new Error("Failed to resume tracking");
+      return response.json();
+    })
+    .then((data) => {
+      gridApi.applyTransaction({ update: [data.shipment] });
+      showToast("Tracking resumed", "info");
+    })
+    .catch((err) => {
+      showToast("Error resuming tracking", "error");
+      console.error("Resume tracking error:", err);
+    });
+}
+
 function deleteShipment(gridApi, id) {
   fetch(`/api/shipments/${id}`, { method: "DELETE" })
     .then((response) => {
@@ -1,3 +1,5 @@
+import { getVisibleShipments } from "./get-visible-shipments.js";
+
 export function deleteSelectedBtnEvent(gridApi) {
   const selectedRows = gridApi.getSelectedRows();
   if (selectedRows.length === 0) {
@@ -23,3 +25,30 @@ export function deleteSelectedBtnEvent(gridApi) {
       alert("Delete failed");
     });
 }
+
+/**
+ * Download the selected shipments, or all shipments passing the grid filters, as GeoJSON, KML or GPX
+ */
+export function exportMapDataEvent(gridApi, format) {
+  const selectedRows = gridApi.getSelectedRows();
+  const rows =
+    selectedRows.length > 0
+      ? selectedRows
+      : getVisibleShipments(gridApi, { debug: false });
+  if (rows.length === 0) {
+    alert("No shipments to export!");
+    return;
+  }
+
+  const params = new URLSearchParams({
+    format,
+    ids: rows.map((row) => row.id).join(","),
+  });
+
+  const link = document.createElement("a");
+  link.href = `/api/exports/shipments?${params}`;
+  link.download = "";
+  document.body.appendChild(link);
+  link.click();
+  link.remove();
+}
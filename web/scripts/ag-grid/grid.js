@@ -1,5 +1,8 @@
 import { actionCellRenderer } from "./action-cell-renderer.js";
-import { deleteSelectedBtnEvent } from "./ag-grid-toolbar.js";
+import {
+  deleteSelectedBtnEvent,
+  exportMapDataEvent,
+} from "./ag-grid-toolbar.js";
 import { mapDataService } from "/scripts/map/map-data-service.js";
 import { FilterManager } from "./filter-manager.js";
 import { getVisibleShipments } from "./get-visible-shipments.js";
@@ -13,6 +16,112 @@ const rowSelection = {
   // enableClickSelection: false,
 };
 
+const findMilestone = (data, milestone) =>
+  data?.milestones?.find((m) => m.milestone === milestone);
+
+// Standardized milestones of the shipment, actual dates win over estimated ones
+const milestoneColumn = (colId, milestone, headerName) => ({
+  colId,
+  headerName,
+  width: 130,
+  minWidth: 110,
+  filter: "agDateColumnFilter",
+  valueGetter: (params) => {
+    const entry = findMilestone(params.data, milestone);
+    const date = entry?.actualAt || entry?.estimatedAt;
+    return date ? new Date(date) : null;
+  },
+  cellRenderer: (params) => {
+    if (!params.value) return "N/A";
+
+    const entry = findMilestone(params.data, milestone);
+    const date = params.value.toLocaleDateString("en-US", {
+      month: "short",
+      day: "numeric",
+    });
+    if (entry?.actualAt) return date;
+    return `<span class="text-gray-500" title="Estimated">${date} (est.)</span>`;
+  },
+});
+
+// Days of demurrage and detention free time left on the container closest to running out,
+// negative once charges are accruing
+const freeTimeLeft = (data) => {
+  const running = (data?.freeTime || []).filter((f) => f.status !== "closed");
+  if (running.length === 0) return null;
+
+  return Math.min(
+    ...running.map((f) =>
+      f.status === "overdue" ? -f.chargeableDays : f.daysRemaining,
+    ),
+  );
+};
+
+// Latest actual event of a container, the next planned one when nothing happened yet
+const lastContainerEvent = (container) => {
+  const events = container?.events || [];
+  const actual = events.filter((e) => e.isActual);
+  if (actual.length > 0) {
+    return actual.reduce((a, b) =>
+      new Date(b.date) > new Date(a.date) ? b : a,
+    );
+  }
+  if (events.length === 0) return null;
+  return events.reduce((a, b) =>
+    new Date(b.date) < new Date(a.date) ? b : a,
+  );
+};
+
+const formatShortDate = (value) =>
+  new Date(value).toLocaleDateString("en-US", {
+    month: "short",
+    day: "numeric",
+  });
+
+// Child rows of a shipment, one per container with its status and last event
+const containerDetailColumnDefs = [
+  {
+    field: "number",
+    headerName: "Container",
+    width: 150,
+    cellRenderer: (params) =>
+      `<span class="font-mono">${params.value || ""}</span>`,
+  },
+  { field: "sizeType", headerName: "Size/Type", width: 110 },
+  { field: "status", headerName: "Status", width: 150 },
+  {
+    colId: "lastEvent",
+    headerName: "Last Event",
+    flex: 1,
+    minWidth: 200,
+    valueGetter: (params) =>
+      lastContainerEvent(params.data)?.description || "",
+  },
+  {
+    colId: "lastEventLocation",
+    headerName: "Location",
+    width: 180,
+    valueGetter: (params) =>
+      lastContainerEvent(params.data)?.location?.name || "",
+  },
+  {
+    colId: "lastEventDate",
+    headerName: "Date",
+    width: 130,
+    valueGetter: (params) => {
+      const event = lastContainerEvent(params.data);
+      return event ? new Date(event.date) : null;
+    },
+    cellRenderer: (params) => {
+      if (!params.value) return "N/A";
+
+      const date = formatShortDate(params.value);
+      if (lastContainerEvent(params.data)?.isActual) return date;
+      return `<span class="text-gray-500" title="Estimated">${date} (est.)</span>`;
+    },
+  },
+];
+
 const columnDefs = [
   {
     field: "shipmentNumber",
@@ -20,10 +129,13 @@ const columnDefs = [
     filter: "agTextColumnFilter",
     width: 200,
     minWidth: 180,
-    cellRenderer: (params) => {
-      if (!params.value) return "";
+    // The group renderer adds the toggle for the container child rows
+    cellRenderer: "agGroupCellRenderer",
+    cellRendererParams: {
+      innerRenderer: (params) => {
+        if (!params.value) return "";
 
-      return `
+        return `
         <button
           class="text-blue-600 dark:text-blue-300 hover:underline font-medium"
           onclick="openModalFetchDetails('${params.data.id}')"
@@ -31,6 +143,7 @@ const columnDefs = [
           ${params.value} - ${params.data.shipmentType}
         </button>
       `;
+      },
     },
   },
   {
@@ -43,6 +156,8 @@ const columnDefs = [
       const statusMap = {
         IN_TRANSIT: "In Transit",
         DELIVERED: "Delivered",
+        EMPTY_RETURNED: "Empty Returned",
+        CANCELLED: "Cancelled",
         PLANNED: "Planned",
         UNKNOWN: "Unknown",
       };
@@ -51,13 +166,29 @@ const columnDefs = [
       const statusClasses = {
         "In Transit": "bg-blue-100 text-blue-800",
         Delivered: "bg-green-100 text-green-800",
+        "Empty Returned": "bg-green-100 text-green-800",
+        Cancelled: "bg-red-100 text-red-800",
         Planned: "bg-yellow-100 text-yellow-800",
         Unknown: "bg-gray-100 text-gray-800",
       };
       const classes = statusClasses[status] || statusClasses["Unknown"];
-      return `<span class="px-2 py-1 text-xs font-semibold rounded-full ${classes}">${status}</span>`;
+      const completed =
+        params.data?.trackingState === "completed"
+          ? `<span class="ml-1 text-xs text-gray-500" title="Tracking stopped">&#9632;</span>`
+          : "";
+      return `<span class="px-2 py-1 text-xs font-semibold rounded-full ${classes}">${status}</span>${completed}`;
     },
   },
+  {
+    field: "carrier",
+    headerName: "Carrier",
+    filter: "agSetColumnFilter",
+    width: 150,
+    minWidth: 120,
+    // Canonical carrier name from the carrier registry
+    valueGetter: (params) =>
+      params.data?.sealineName || params.data?.sealineCode || "",
+  },
   {
     field: "originPort",
     headerName: "Origin",
@@ -114,6 +245,15 @@ const columnDefs = [
       return "0";
     },
   },
+  {
+    colId: "containerNumbers",
+    headerName: "Container Numbers",
+    width: 200,
+    minWidth: 150,
+    filter: "agTextColumnFilter",
+    valueGetter: (params) =>
+      (params.data?.containers || []).map((c) => c.number).join(", "),
+  },
   {
     field: "nextETA",
     headerName: "Next ETA",
@@ -143,6 +283,147 @@ const columnDefs = [
       return "N/A";
     },
   },
+  {
+    colId: "progressPercent",
+    headerName: "Progress",
+    width: 130,
+    minWidth: 110,
+    filter: "agNumberColumnFilter",
+    valueGetter: (params) => params.data?.routeProgress?.progressPercent ?? null,
+    cellRenderer: (params) => {
+      if (params.value === null || params.value === undefined) return "N/A";
+
+      const method = params.data.routeProgress.method;
+      const title =
+        method === "route" ? "Along the planned route" : "Great-circle estimate";
+      return `
+        <div class="flex items-center gap-2" title="${title}">
+          <div class="w-12 h-1.5 bg-gray-200 rounded-full overflow-hidden">
+            <div class="h-full bg-blue-600" style="width: ${params.value}%"></div>
+          </div>
+          <span>${params.value.toFixed(0)}%</span>
+        </div>
+      `;
+    },
+  },
+  {
+    colId: "remainingKm",
+    headerName: "Remaining",
+    width: 120,
+    minWidth: 100,
+    filter: "agNumberColumnFilter",
+    valueGetter: (params) => params.data?.routeProgress?.remainingKm ?? null,
+    cellRenderer: (params) => {
+      if (params.value === null || params.value === undefined) return "N/A";
+      return `${Math.round(params.value).toLocaleString("en-US")} km`;
+    },
+  },
+  {
+    colId: "estimatedETA",
+    headerName: "Our ETA",
+    width: 120,
+    minWidth: 100,
+    filter: "agDateColumnFilter",
+    valueGetter: (params) => {
+      const eta = params.data?.routeProgress?.estimatedEta;
+      return eta ? new Date(eta) : null;
+    },
+    cellRenderer: (params) => {
+      if (!params.value) return "N/A";
+      return params.value.toLocaleDateString("en-US", {
+        month: "short",
+        day: "numeric",
+      });
+    },
+  },
+  {
+    colId: "etaDeltaHours",
+    headerName: "ETA vs Carrier",
+    width: 130,
+    minWidth: 110,
+    filter: "agNumberColumnFilter",
+    valueGetter: (params) => params.data?.routeProgress?.etaDeltaHours ?? null,
+    cellRenderer: (params) => {
+      if (params.value === null || params.value === undefined) return "N/A";
+
+      // Positive deltas mean we expect the vessel later than the carrier does
+      const hours = params.value;
+      if (Math.abs(hours) < 12) {
+        return `<span class="text-gray-600">${hours > 0 ? "+" : ""}${hours.toFixed(1)}h</span>`;
+      }
+      const classes =
+        hours > 0 ? "bg-red-100 text-red-800" : "bg-green-100 text-green-800";
+      return `<span class="px-2 py-1 text-xs font-semibold rounded-full ${classes}">${hours > 0 ? "+" : ""}${hours.toFixed(1)}h</span>`;
+    },
+  },
+  milestoneColumn("emptyPickupAt", "empty_pickup", "Empty Pickup"),
+  milestoneColumn("gateInAt", "gate_in", "Gate In"),
+  milestoneColumn("loadedAt", "loaded", "Loaded"),
+  milestoneColumn("departedAt", "departed", "Departed"),
+  milestoneColumn("transshipmentAt", "transshipment", "Transshipment"),
+  milestoneColumn("arrivedAt", "arrived", "Arrived"),
+  milestoneColumn("dischargedAt", "discharged", "Discharged"),
+  milestoneColumn("gateOutAt", "gate_out", "Gate Out"),
+  milestoneColumn("emptyReturnAt", "empty_return", "Empty Return"),
+  {
+    colId: "freeTimeLeft",
+    headerName: "Free Time",
+    width: 130,
+    minWidth: 110,
+    filter: "agNumberColumnFilter",
+    valueGetter: (params) => freeTimeLeft(params.data),
+    cellRenderer: (params) => {
+      if (params.value === null || params.value === undefined) return "N/A";
+
+      if (params.value < 0) {
+        return `<span class="px-2 py-1 text-xs font-semibold rounded-full bg-red-100 text-red-800">${-params.value}d over</span>`;
+      }
+      const expiring = (params.data?.freeTime || []).some(
+        (f) => f.status === "expiring",
+      );
+      const classes = expiring
+        ? "bg-yellow-100 text-yellow-800"
+        : "bg-green-100 text-green-800";
+      return `<span class="px-2 py-1 text-xs font-semibold rounded-full ${classes}">${params.value}d left</span>`;
+    },
+  },
+  {
+    colId: "ddCharges",
+    headerName: "D&D Charges",
+    width: 130,
+    minWidth: 110,
+    filter: "agNumberColumnFilter",
+    valueGetter: (params) => {
+      const freeTime = params.data?.freeTime || [];
+      if (freeTime.length === 0) return null;
+      return freeTime.reduce((sum, f) => sum + f.accruedCharges, 0);
+    },
+    cellRenderer: (params) => {
+      if (params.value === null || params.value === undefined) return "N/A";
+
+      const currency = params.data.freeTime[0].currency;
+      return `${params.value.toFixed(2)} ${currency}`;
+    },
+  },
+  {
+    colId: "co2eKg",
+    headerName: "CO2e",
+    width: 120,
+    minWidth: 100,
+    filter: "agNumberColumnFilter",
+    valueGetter: (params) => params.data?.emissions?.totalKg ?? null,
+    cellRenderer: (params) => {
+      if (params.value === null || params.value === undefined) return "N/A";
+
+      const { teu, distanceKm } = params.data.emissions;
+      const title = `${teu} TEU over ${Math.round(distanceKm).toLocaleString("en-US")} km, well-to-wheel`;
+      const value =
+        params.value >= 1000
+          ? `${(params.value / 1000).toFixed(2)} t`
+          : `${params.value.toFixed(0)} kg`;
+      return `<span title="${title}">${value}</span>`;
+    },
+  },
   {
     field: "consignee",
     headerName: "Consignee",
@@ -529,6 +810,25 @@ const gridOptions = {
     minWidth: 120, // Minimum width for readability
   },
   rowSelection,
+
+  // Containers of a shipment as child rows
+  masterDetail: true,
+  isRowMaster: (data) => (data?.containers || []).length > 0,
+  detailRowAutoHeight: true,
+  detailCellRendererParams: {
+    detailGridOptions: {
+      columnDefs: containerDetailColumnDefs,
+      defaultColDef: {
+        resizable: true,
+        sortable: true,
+      },
+      suppressCellFocus: true,
+    },
+    getDetailRowData: (params) => {
+      params.successCallback(params.data.containers || []);
+    },
+  },
+
   pagination: true,
   paginationPageSize: 20, // Increased due to more columns
   paginationPageSizeSelector: [20, 50, 100],
@@ -595,6 +895,14 @@ const gridOptions = {
       "placeOfLoading",
       "placeOfDelivery",
       "containerType",
+      "containerNumbers",
+      "emptyPickupAt",
+      "gateInAt",
+      "loadedAt",
+      "departedAt",
+      "transshipmentAt",
+      "arrivedAt",
+      "emptyReturnAt",
     ];
     params.api.setColumnsVisible(columnsToHide, false);
   },
@@ -641,6 +949,14 @@ export function handleToolbar(gridApi) {
     deleteSelectedBtnEvent(gridApi),
   );
 
+  const exportMapDataSelect = document.getElementById("exportMapDataSelect");
+  exportMapDataSelect.addEventListener("change", (event) => {
+    if (event.target.value) {
+      exportMapDataEvent(gridApi, event.target.value);
+    }
+    event.target.value = "";
+  });
+
   const refreshGridBtn = document.getElementById("refreshGridBtn");
   refreshGridBtn.addEventListener("click", () => {
     console.log("Refreshing grid data and updating map...");
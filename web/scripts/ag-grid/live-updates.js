@@ -0,0 +1,69 @@
+import { loadShipments } from "./grid.js";
+
+// Keeps the grid in sync with changes made by the background refresh job and
+// other users. The server streams which shipments changed, the rows are then
+// fetched and patched in place. The map follows through the grid's
+// rowDataUpdated event.
+export function initLiveUpdates(gridApi) {
+  if (!window.EventSource) {
+    console.warn("Live updates are not supported by this browser");
+    return null;
+  }
+
+  const source = new EventSource("/api/shipments/events");
+  let disconnected = false;
+
+  source.addEventListener("shipment.changed", (event) => {
+    const data = JSON.parse(event.data);
+    data.shipment_ids.forEach((shipmentID) => patchRow(gridApi, shipmentID));
+  });
+
+  source.addEventListener("shipment.removed", (event) => {
+    const data = JSON.parse(event.data);
+    gridApi.applyTransaction({
+      remove: data.shipment_ids.map((id) => ({ id })),
+    });
+  });
+
+  // Changes may have been missed, reload everything
+  source.addEventListener("resync", () => loadShipments(gridApi));
+
+  source.addEventListener("error", () => {
+    disconnected = true;
+  });
+
+  source.addEventListener("open", () => {
+    if (disconnected) {
+      disconnected = false;
+      loadShipments(gridApi);
+    }
+  });
+
+  return source;
+}
+
+function patchRow(gridApi, shipmentID) {
+  fetch(`/api/shipments/${shipmentID}/details`, {
+    method: "GET",
+    headers: {
+      "Content-Type": "application/json",
+    },
+  })
+    .then((response) => {
+      if (!response.ok) {
+        throw new Error(`HTTP error! status: ${response.status}`);
+      }
+      return response.json();
+    })
+    .then((data) => {
+      const row = data.shipment_details;
+      if (gridApi.getRowNode(String(row.id))) {
+        gridApi.applyTransaction({ update: [row] });
+      } else {
+        gridApi.applyTransaction({ add: [row] });
+      }
+    })
+    .catch((error) => {
+      console.error(`Error refreshing shipment ${shipmentID}:`, error);
+    });
+}
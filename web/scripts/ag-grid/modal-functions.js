@@ -40,6 +40,20 @@ function openModalFetchDetails(shipmentID) {
   htmx.ajax("GET", `/api/shipments/${shipmentID}/details-html`, "#modal-body");
 }
 
+// Opens the details modal of the shipment in the ?shipment= query parameter, used by
+// links in notifications. The parameter is removed so a reload doesn't reopen it.
+export function openModalFromURL() {
+  const url = new URL(window.location.href);
+  const shipmentID = url.searchParams.get("shipment");
+  if (!shipmentID) {
+    return;
+  }
+
+  url.searchParams.delete("shipment");
+  window.history.replaceState(null, "", url);
+  openModalFetchDetails(shipmentID);
+}
+
 function openFilterManagementPanel() {
   const panel = document.getElementById("filter-management-panel");
   const panelContent = document.getElementById("filter-panel-content");
@@ -1,4 +1,8 @@
-import { initModalFunctions } from "./ag-grid/modal-functions.js";
+import {
+  initModalFunctions,
+  openModalFromURL,
+} from "./ag-grid/modal-functions.js";
+import { initLiveUpdates } from "./ag-grid/live-updates.js";
 import { handleNewShipment } from "./form/handle-new-shipment.js";
 import { initEnhancedMap } from "./map/handle-map-enhanced.js";
 import { mapDataService } from "./map/map-data-service.js";
@@ -29,6 +33,12 @@ function main() {
     // Load initial shipments data
     loadShipments(gridApi);
 
+    // Patch rows when shipments change on the server
+    initLiveUpdates(gridApi);
+
+    // Open the shipment linked from a notification
+    openModalFromURL();
+
     // Set up new shipment handler
     window.handleNewShipment = (e) => handleNewShipment(e, gridApi);
 
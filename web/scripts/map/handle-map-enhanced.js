@@ -193,6 +193,8 @@ function setupGridEventListeners(gridApi) {
     "paginationChanged",
     "sortChanged",
     "selectionChanged",
+    // Rows patched by live updates
+    "rowDataUpdated",
   ];
 
   gridEvents.forEach((eventName) => {
@@ -384,6 +386,8 @@ export function createStatusIcon(status) {
   const iconColors = {
     IN_TRANSIT: "#3B82F6", // Blue
     DELIVERED: "#10B981", // Green
+    EMPTY_RETURNED: "#10B981", // Green
+    CANCELLED: "#EF4444", // Red
     PLANNED: "#F59E0B", // Yellow
     UNKNOWN: "#6B7280", // Gray
   };
@@ -421,6 +425,8 @@ export function createHighlightedIcon(status) {
   const iconColors = {
     IN_TRANSIT: "#3B82F6",
     DELIVERED: "#10B981",
+    EMPTY_RETURNED: "#10B981",
+    CANCELLED: "#EF4444",
     PLANNED: "#F59E0B",
     UNKNOWN: "#6B7280",
   };
@@ -842,6 +848,8 @@ export function createShipmentPopup(shipment) {
       const statusConfig = {
         IN_TRANSIT: { label: "In Transit", color: "blue" },
         DELIVERED: { label: "Delivered", color: "green" },
+        EMPTY_RETURNED: { label: "Empty Returned", color: "green" },
+        CANCELLED: { label: "Cancelled", color: "red" },
         PLANNED: { label: "Planned", color: "yellow" },
         UNKNOWN: { label: "Unknown", color: "gray" },
       };
@@ -891,6 +899,14 @@ export function createShipmentPopup(shipment) {
                 style="background: #2563eb; color: white; border: none; padding: 4px 8px; border-radius: 4px; cursor: pointer; font-size: 11px;">
           View Details
         </button>
+        ${
+          window.playShipmentTrack
+            ? `<button onclick="playShipmentTrack('${shipment.id}', '${shipment.shipmentNumber || ""}')"
+                style="background: #059669; color: white; border: none; padding: 4px 8px; border-radius: 4px; cursor: pointer; font-size: 11px; margin-left: 4px;">
+          Play Track
+        </button>`
+            : ""
+        }
       </div>
     </div>
   `;
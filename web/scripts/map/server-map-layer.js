@@ -0,0 +1,200 @@
+/**
+ * Server Map Layer
+ * Loads clustered positions, ports and simplified routes for the visible map area
+ * from /api/shipments/map instead of the full grid payload
+ */
+
+import { createStatusIcon } from "./handle-map-enhanced.js";
+
+const RELOAD_DELAY_MS = 300;
+
+let serverMap = null;
+let layer = null;
+let reloadTimer = null;
+let abortController = null;
+let onUpdate = null;
+
+/**
+ * Render the map data of the visible area and reload it whenever the map moves
+ * @param {Object} map - Leaflet map instance
+ * @param {Object} options - { onUpdate(data) } is called after every load
+ */
+export function enableServerMapData(map, options = {}) {
+  if (serverMap) return;
+
+  serverMap = map;
+  onUpdate = options.onUpdate || null;
+  layer = L.layerGroup().addTo(map);
+  map.on("moveend", scheduleReload);
+
+  loadMapData();
+}
+
+/**
+ * Stop loading map data for the visible area and remove its layer,
+ * e.g. once the grid page provides filtered shipments
+ */
+export function disableServerMapData() {
+  if (!serverMap) return;
+
+  serverMap.off("moveend", scheduleReload);
+  clearTimeout(reloadTimer);
+  abortController?.abort();
+  layer.remove();
+
+  serverMap = null;
+  layer = null;
+}
+
+export function isServerMapDataEnabled() {
+  return serverMap !== null;
+}
+
+function scheduleReload() {
+  clearTimeout(reloadTimer);
+  reloadTimer = setTimeout(loadMapData, RELOAD_DELAY_MS);
+}
+
+async function loadMapData() {
+  if (!serverMap) return;
+
+  abortController?.abort();
+  abortController = new AbortController();
+
+  const params = new URLSearchParams({
+    bbox: serverMap.getBounds().toBBoxString(),
+    zoom: String(Math.round(serverMap.getZoom())),
+  });
+
+  try {
+    const response = await fetch(`/api/shipments/map?${params}`, {
+      signal: abortController.signal,
+    });
+    if (!response.ok) {
+      console.error("Failed to load map data:", response.status);
+      return;
+    }
+
+    const data = await response.json();
+    render(data);
+    onUpdate?.(data);
+  } catch (error) {
+    if (error.name !== "AbortError") {
+      console.error("Error loading map data:", error);
+    }
+  }
+}
+
+function render(data) {
+  if (!layer) return;
+  layer.clearLayers();
+
+  (data.routes || []).forEach((route) => {
+    splitAtDateLine(route.path).forEach((path) => {
+      L.polyline(path, {
+        color: route.routeType === "SEA" ? "#3B82F6" : "#F59E0B",
+        weight: 2,
+        opacity: 0.6,
+      }).addTo(layer);
+    });
+  });
+
+  (data.ports || []).forEach((port) => {
+    if (port.count > 1) {
+      clusterMarker(port, "#6B7280").addTo(layer);
+      return;
+    }
+    L.circleMarker([port.latitude, port.longitude], {
+      radius: 5,
+      color: "#374151",
+      weight: 1,
+      fillColor: "#9CA3AF",
+      fillOpacity: 0.9,
+    })
+      .bindTooltip(`${port.name || ""}${port.locode ? ` (${port.locode})` : ""}`)
+      .addTo(layer);
+  });
+
+  (data.positions || []).forEach((position) => {
+    if (position.count > 1) {
+      clusterMarker(position, "#2563EB").addTo(layer);
+      return;
+    }
+    L.marker([position.latitude, position.longitude], {
+      icon: createStatusIcon(position.status),
+    })
+      .bindPopup(positionPopup(position))
+      .addTo(layer);
+  });
+}
+
+function clusterMarker(marker, color) {
+  const size = marker.count < 10 ? 30 : marker.count < 100 ? 36 : 42;
+  const icon = L.divIcon({
+    className: "custom-marker",
+    html: `
+      <div style="
+        background-color: ${color};
+        color: white;
+        width: ${size}px;
+        height: ${size}px;
+        border-radius: 50%;
+        border: 3px solid white;
+        box-shadow: 0 2px 5px rgba(0,0,0,0.3);
+        display: flex;
+        align-items: center;
+        justify-content: center;
+        font-size: 12px;
+        font-weight: bold;
+      ">${marker.count}</div>
+    `,
+    iconSize: [size, size],
+    iconAnchor: [size / 2, size / 2],
+  });
+
+  // Zooming in on a cluster splits it up
+  return L.marker([marker.latitude, marker.longitude], { icon }).on(
+    "click",
+    () => serverMap?.setView([marker.latitude, marker.longitude], serverMap.getZoom() + 2),
+  );
+}
+
+function positionPopup(position) {
+  return `
+    <div style="min-width: 180px;">
+      <div style="font-weight: bold; margin-bottom: 8px; color: #2563eb;">
+        ${position.shipmentNumber || "N/A"}
+      </div>
+      <div style="margin-bottom: 8px;">
+        <strong>Status:</strong> ${position.status || "UNKNOWN"}
+      </div>
+      <button onclick="openModalFetchDetails('${position.shipmentId}')"
+              style="background: #2563eb; color: white; border: none; padding: 4px 8px; border-radius: 4px; cursor: pointer; font-size: 11px;">
+        View Details
+      </button>
+      ${
+        window.playShipmentTrack
+          ? `<button onclick="playShipmentTrack('${position.shipmentId}', '${position.shipmentNumber || ""}')"
+              style="background: #059669; color: white; border: none; padding: 4px 8px; border-radius: 4px; cursor: pointer; font-size: 11px; margin-left: 4px;">
+        Play Track
+      </button>`
+          : ""
+      }
+    </div>
+  `;
+}
+
+// Split a [lat, lng] path where it crosses the International Date Line
+function splitAtDateLine(path) {
+  const parts = [];
+  let current = [];
+  (path || []).forEach((point, i) => {
+    if (i > 0 && Math.abs(point[1] - path[i - 1][1]) > 180) {
+      parts.push(current);
+      current = [];
+    }
+    current.push(point);
+  });
+  parts.push(current);
+  return parts.filter((part) => part.length >= 2);
+}
@@ -0,0 +1,231 @@
+/**
+ * Voyage Track Playback
+ * Replays the recorded vessel positions of a shipment on the standalone map
+ */
+
+const SPEEDS = [1, 4, 16];
+const FRAME_MS = 250;
+
+let playbackMap = null;
+let control = null;
+let trackLayer = null;
+let vesselMarker = null;
+let points = [];
+let shipmentId = null;
+let frame = 0;
+let timer = null;
+let speed = SPEEDS[0];
+
+/**
+ * Add the playback control to a map and expose window.playShipmentTrack
+ * @param {Object} map - Leaflet map instance
+ */
+export function initTrackPlayback(map) {
+  playbackMap = map;
+
+  const PlaybackControl = L.Control.extend({
+    options: { position: "bottomright" },
+    onAdd() {
+      const container = L.DomUtil.create("div", "leaflet-bar");
+      container.style.cssText =
+        "display: none; background: white; padding: 8px 10px; min-width: 280px; font-size: 12px;";
+      container.innerHTML = `
+        <div style="display: flex; justify-content: space-between; align-items: center; margin-bottom: 6px;">
+          <strong data-role="title">Voyage track</strong>
+          <a href="#" data-role="close" title="Close" style="border: none; width: auto; height: auto; line-height: 1;">&times;</a>
+        </div>
+        <div style="display: flex; gap: 4px; margin-bottom: 6px;">
+          <input type="date" data-role="from" style="flex: 1; font-size: 11px;">
+          <input type="date" data-role="to" style="flex: 1; font-size: 11px;">
+          <button type="button" data-role="load" style="font-size: 11px; padding: 0 6px;">Load</button>
+        </div>
+        <input type="range" data-role="slider" min="0" max="0" value="0" style="width: 100%;">
+        <div style="display: flex; justify-content: space-between; align-items: center; margin-top: 4px;">
+          <button type="button" data-role="play" style="background: #2563eb; color: white; border: none; padding: 2px 10px; border-radius: 4px; cursor: pointer;">Play</button>
+          <button type="button" data-role="speed" style="font-size: 11px; padding: 0 6px;">${speed}x</button>
+          <span data-role="time" style="color: #666;">No positions</span>
+        </div>
+      `;
+      L.DomEvent.disableClickPropagation(container);
+      L.DomEvent.disableScrollPropagation(container);
+      return container;
+    },
+  });
+
+  control = new PlaybackControl();
+  control.addTo(map);
+
+  const el = (role) =>
+    control.getContainer().querySelector(`[data-role="${role}"]`);
+
+  el("close").addEventListener("click", (event) => {
+    event.preventDefault();
+    closePlayback();
+  });
+  el("play").addEventListener("click", togglePlay);
+  el("speed").addEventListener("click", () => {
+    speed = SPEEDS[(SPEEDS.indexOf(speed) + 1) % SPEEDS.length];
+    el("speed").textContent = `${speed}x`;
+  });
+  el("slider").addEventListener("input", (event) => {
+    pause();
+    showFrame(Number(event.target.value));
+  });
+  el("load").addEventListener("click", () => {
+    if (shipmentId) {
+      loadTrack(shipmentId, el("from").value, el("to").value);
+    }
+  });
+
+  window.playShipmentTrack = (id, label) => {
+    shipmentId = id;
+    el("title").textContent = label ? `Voyage track ${label}` : "Voyage track";
+    el("from").value = "";
+    el("to").value = "";
+    control.getContainer().style.display = "block";
+    playbackMap.closePopup();
+    loadTrack(id);
+  };
+}
+
+/**
+ * Fetch the recorded track of a shipment, from and to are optional yyyy-mm-dd dates
+ */
+async function loadTrack(id, from = "", to = "") {
+  pause();
+
+  const params = new URLSearchParams();
+  if (from) params.set("from", new Date(`${from}T00:00:00Z`).toISOString());
+  if (to) params.set("to", new Date(`${to}T23:59:59Z`).toISOString());
+
+  try {
+    const response = await fetch(`/api/shipments/${id}/track?${params}`);
+    if (!response.ok) {
+      const body = await response.json().catch(() => ({}));
+      console.error("Failed to load track:", body.error || response.status);
+      setPoints([]);
+      return;
+    }
+    const track = await response.json();
+    setPoints(track.points || []);
+  } catch (error) {
+    console.error("Error loading track:", error);
+    setPoints([]);
+  }
+}
+
+/**
+ * Draw a track and reset playback to its first position
+ */
+function setPoints(trackPoints) {
+  clearTrack();
+  points = trackPoints;
+  frame = 0;
+
+  const slider = control.getContainer().querySelector('[data-role="slider"]');
+  slider.max = Math.max(points.length - 1, 0);
+  slider.value = 0;
+
+  if (points.length === 0) {
+    control.getContainer().querySelector('[data-role="time"]').textContent =
+      "No positions";
+    return;
+  }
+
+  const latLngs = unwrapLongitudes(points);
+  trackLayer = L.polyline(latLngs, {
+    color: "#2563eb",
+    weight: 3,
+    opacity: 0.7,
+  }).addTo(playbackMap);
+  vesselMarker = L.circleMarker(latLngs[0], {
+    radius: 7,
+    color: "#ffffff",
+    weight: 2,
+    fillColor: "#dc2626",
+    fillOpacity: 1,
+  }).addTo(playbackMap);
+
+  playbackMap.fitBounds(trackLayer.getBounds(), { padding: [40, 40] });
+  showFrame(0);
+}
+
+/**
+ * Keep consecutive longitudes within 180 degrees so tracks crossing the date line stay continuous
+ */
+function unwrapLongitudes(trackPoints) {
+  let offset = 0;
+  return trackPoints.map((point, i) => {
+    if (i > 0) {
+      const delta = point.longitude - trackPoints[i - 1].longitude;
+      if (delta > 180) offset -= 360;
+      if (delta < -180) offset += 360;
+    }
+    return [point.latitude, point.longitude + offset];
+  });
+}
+
+function showFrame(index) {
+  if (points.length === 0) return;
+
+  frame = Math.min(Math.max(index, 0), points.length - 1);
+  const point = points[frame];
+  vesselMarker.setLatLng(trackLayer.getLatLngs()[frame]);
+  vesselMarker.bindTooltip(point.vessel || "Unknown vessel");
+
+  const container = control.getContainer();
+  container.querySelector('[data-role="slider"]').value = frame;
+  container.querySelector('[data-role="time"]').textContent = new Date(
+    point.recordedAt,
+  ).toLocaleString("en-US", {
+    month: "short",
+    day: "numeric",
+    hour: "2-digit",
+    minute: "2-digit",
+  });
+}
+
+function togglePlay() {
+  if (timer) {
+    pause();
+    return;
+  }
+  if (points.length === 0) return;
+  if (frame >= points.length - 1) showFrame(0);
+
+  control.getContainer().querySelector('[data-role="play"]').textContent =
+    "Pause";
+  timer = setInterval(() => {
+    if (frame >= points.length - 1) {
+      pause();
+      return;
+    }
+    showFrame(frame + speed);
+  }, FRAME_MS);
+}
+
+function pause() {
+  if (timer) {
+    clearInterval(timer);
+    timer = null;
+  }
+  if (control) {
+    control.getContainer().querySelector('[data-role="play"]').textContent =
+      "Play";
+  }
+}
+
+function clearTrack() {
+  if (trackLayer) playbackMap.removeLayer(trackLayer);
+  if (vesselMarker) playbackMap.removeLayer(vesselMarker);
+  trackLayer = null;
+  vesselMarker = null;
+}
+
+function closePlayback() {
+  pause();
+  clearTrack();
+  points = [];
+  shipmentId = null;
+  control.getContainer().style.display = "none";
+}